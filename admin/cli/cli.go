@@ -0,0 +1,51 @@
+package cli
+
+import (
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+	"gophr.v2/config"
+	"gophr.v2/image"
+	imagerepo "gophr.v2/image/repository"
+	imageservice "gophr.v2/image/service"
+	userrepo "gophr.v2/user/repository"
+	userservice "gophr.v2/user/service"
+	"time"
+)
+
+var olderThan time.Duration
+
+func init() {
+	AdminCmd.AddCommand(purgeCmd, restoreCmd)
+	restoreCmd.AddCommand(restoreUserCmd, restoreImageCmd)
+
+	purgeCmd.Flags().DurationVar(&olderThan, "older-than", 0, "Purge what was deleted before this long ago (default is the account deletion grace period)")
+}
+
+var AdminCmd = &cobra.Command{
+	Use:   "admin",
+	Short: "A subcommand for maintaining the data of the gophr services",
+}
+
+// services holds the services the admin commands work with. They
+// use the repositories of the web app directly.
+type services struct {
+	users   *userservice.Service
+	images  image.Service
+	closers []func() error
+}
+
+func newServices(conf *config.Config, opts ...userservice.Option) *services {
+	userRepo, closeUsers := userrepo.Get(conf, userrepo.SQLRepo(conf))
+	imageRepo, closeImages := imagerepo.Get(conf, imagerepo.SQLRepo(conf))
+	return &services{
+		users:   userservice.New(userRepo, opts...),
+		images:  imageservice.New(imageRepo, afero.NewOsFs(), nil),
+		closers: []func() error{closeUsers, closeImages},
+	}
+}
+
+func (s *services) Close() {
+	for _, closer := range s.closers {
+		_ = closer()
+	}
+}
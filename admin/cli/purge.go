@@ -0,0 +1,71 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"github.com/spf13/cobra"
+	"gophr.v2/config/configutil"
+	userservice "gophr.v2/user/service"
+	"log"
+	"time"
+)
+
+var purgeCmd = &cobra.Command{
+	Use:   "purge",
+	Short: "purge permanently deletes the soft deleted users and images",
+	Long: `
+DESCRIPTION:
+  purge permanently deletes the users and the images deleted before
+  --older-than ago, along with the image files. The records the other
+  services hold about the purged users are deleted by the web app once
+  it relays their events.
+
+EXAMPLE:
+  gophr admin purge
+  gophr admin purge --older-than 168h
+`,
+	Run: func(cmd *cobra.Command, args []string) {
+		conf := configutil.Initialize()
+		period := olderThan
+		if period <= 0 {
+			period = conf.Account.DeletionGracePeriod
+		}
+		if period <= 0 {
+			period = userservice.DefaultDeletionGracePeriod
+		}
+
+		svc := newServices(conf, userservice.WithDeletionGracePeriod(period))
+		defer svc.Close()
+
+		ctx := context.Background()
+		users, err := purgeAll(func() (int, error) {
+			return svc.users.Purge(ctx)
+		})
+		fmt.Println("Purged users:", users)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		before := time.Now().UTC().Add(-period)
+		images, err := purgeAll(func() (int, error) {
+			return svc.images.PurgeDeleted(ctx, before)
+		})
+		fmt.Println("Purged images:", images)
+		if err != nil {
+			log.Fatal(err)
+		}
+	},
+}
+
+// purgeAll runs purge until there's nothing left to purge and returns
+// the total purged.
+func purgeAll(purge func() (int, error)) (int, error) {
+	total := 0
+	for {
+		n, err := purge()
+		total += n
+		if err != nil || n == 0 {
+			return total, err
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"github.com/spf13/cobra"
+	"gophr.v2/config/configutil"
+)
+
+var restoreCmd = &cobra.Command{
+	Use:   "restore",
+	Short: "restore cancels the deletion of users or images not purged yet",
+}
+
+var restoreUserCmd = &cobra.Command{
+	Use:   "user",
+	Short: "restore the users with the given user ids",
+	Long: `
+EXAMPLE:
+  gophr admin restore user id1 id2
+`,
+	Args: cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		svc := newServices(configutil.Initialize())
+		defer svc.Close()
+		for _, id := range args {
+			if err := svc.users.Restore(context.Background(), id); err != nil {
+				fmt.Println(id, err)
+			}
+		}
+	},
+}
+
+var restoreImageCmd = &cobra.Command{
+	Use:   "image",
+	Short: "restore the images with the given image ids",
+	Long: `
+EXAMPLE:
+  gophr admin restore image id1 id2
+`,
+	Args: cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		svc := newServices(configutil.Initialize())
+		defer svc.Close()
+		for _, id := range args {
+			if err := svc.images.Restore(context.Background(), id); err != nil {
+				fmt.Println(id, err)
+			}
+		}
+	},
+}
@@ -1,32 +1,55 @@
 package cli
 
 import (
+	"context"
 	"fmt"
-	"github.com/jayvib/golog"
 	"github.com/mitchellh/go-homedir"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
+	"gophr.v2/config"
+	"gophr.v2/log"
+	"gophr.v2/tracing"
 	"os"
 )
 
 var cfgFile string
 var debug bool
 
+// shutdownTracing flushes the spans of the command once it's done.
+var shutdownTracing = func(context.Context) error { return nil }
+
 var GophrApp = &cobra.Command{
 	Use:   "gophr",
 	Short: "gophr is an CLI for interacting gophr services",
 	Long:  "gophr is a CLI for interacting gophr services",
 	PersistentPreRun: func(cmd *cobra.Command, args []string) {
 		if debug {
-			golog.SetLevel(golog.DebugLevel)
-			golog.Warning("GOPHR: DEBUG MODE")
+			log.SetLevel(log.DebugLevel)
+			log.Warn("GOPHR: DEBUG MODE")
+		}
+
+		// The calls to the APIs are traced like in the servers when the
+		// config file has a tracing section
+		var conf config.Tracing
+		if err := viper.UnmarshalKey("tracing", &conf); err != nil {
+			log.Fatal(err)
+		}
+		shutdown, err := tracing.Setup(context.Background(), "gophr-cli", conf)
+		if err != nil {
+			log.Fatal(err)
+		}
+		shutdownTracing = shutdown
+	},
+	PersistentPostRun: func(cmd *cobra.Command, args []string) {
+		if err := shutdownTracing(context.Background()); err != nil {
+			log.WithError(err).Warn("could not flush the spans")
 		}
 	},
 }
 
 func Execute() {
 	if err := GophrApp.Execute(); err != nil {
-		golog.Fatal(err)
+		log.Fatal(err)
 	}
 }
 
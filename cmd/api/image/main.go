@@ -1,16 +1,27 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"github.com/gin-gonic/gin"
 	"github.com/spf13/afero"
 	"gophr.v2/config/configutil"
+	mysqldriver "gophr.v2/driver/mysql"
+	"gophr.v2/image"
 	"gophr.v2/image/api/v1"
 	imagerepo "gophr.v2/image/repository"
 	imageservice "gophr.v2/image/service"
+	imageinstrumenting "gophr.v2/image/service/decorators/instrumenting"
+	imagetracing "gophr.v2/image/service/decorators/tracing"
+	"gophr.v2/log"
+	"gophr.v2/metrics"
+	"gophr.v2/tracing"
+	"gophr.v2/user"
 	userrepo "gophr.v2/user/repository"
 	userservice "gophr.v2/user/service"
+	userinstrumenting "gophr.v2/user/service/decorators/instrumenting"
+	usertracing "gophr.v2/user/service/decorators/tracing"
 )
 
 var port = flag.String("port", "4402", "Port")
@@ -18,19 +29,34 @@ var port = flag.String("port", "4402", "Port")
 func main() {
 	flag.Parse()
 	conf := configutil.Initialize()
+	log.SetDefault(configutil.NewLogger(conf))
+	shutdown, err := tracing.Setup(context.Background(), "gophr-image-api", conf.Tracing)
+	if err != nil {
+		panic(err)
+	}
+	defer noOpCloser(func() error { return shutdown(context.Background()) })
 
 	userRepo, closer := userrepo.Get(conf, userrepo.MySQLRepo)
 	defer closer()
 
-	userService := userservice.New(userRepo)
+	reg := metrics.NewRegistry()
+	db, err := mysqldriver.Initialize(conf)
+	if err != nil {
+		panic(err)
+	}
+	reg.MustRegister(metrics.DBStats("mysql", db))
+
+	userService := user.ApplyDecorators(userservice.New(userRepo), userinstrumenting.New(reg), usertracing.Apply)
 
 	imageRepo, closer := imagerepo.Get(conf, imagerepo.MySQLRepo)
 	defer closer()
 
 	fs := afero.NewOsFs()
-	imageService := imageservice.New(imageRepo, fs, nil)
+	imageService := image.ApplyDecorators(imageservice.New(imageRepo, fs, nil), imageinstrumenting.New(reg), imagetracing.Apply)
 
-	e := gin.Default()
+	e := gin.New()
+	e.Use(log.Middleware(log.Default()), tracing.Middleware("gophr-image-api"), metrics.Middleware(reg), gin.Recovery())
+	e.GET(metrics.Path, metrics.Handler(reg))
 	v1.RegisterRoutes(e, imageService, userService)
 
 	if err := e.Run(fmt.Sprintf(":%v", *port)); err != nil {
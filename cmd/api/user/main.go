@@ -1,13 +1,24 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"github.com/gin-gonic/gin"
 	"gophr.v2/config/configutil"
+	mysqldriver "gophr.v2/driver/mysql"
+	"gophr.v2/event/bus"
+	"gophr.v2/log"
+	"gophr.v2/metrics"
+	sessionrepo "gophr.v2/session/repository"
+	sessionservice "gophr.v2/session/service"
+	"gophr.v2/tracing"
+	"gophr.v2/user"
 	"gophr.v2/user/api/v1/http"
 	"gophr.v2/user/repository"
 	"gophr.v2/user/service"
+	"gophr.v2/user/service/decorators/instrumenting"
+	usertracing "gophr.v2/user/service/decorators/tracing"
 )
 
 var port = flag.String("port", "4401", "Port")
@@ -15,13 +26,39 @@ var port = flag.String("port", "4401", "Port")
 func main() {
 	flag.Parse()
 	conf := configutil.Initialize()
+	log.SetDefault(configutil.NewLogger(conf))
+	shutdown, err := tracing.Setup(context.Background(), "gophr-user-api", conf.Tracing)
+	if err != nil {
+		panic(err)
+	}
+	defer noOpCloser(func() error { return shutdown(context.Background()) })
 
 	repo, closer := repository.Get(conf, repository.MySQLRepo)
 	defer noOpCloser(closer)
 
-	svc := service.New(repo)
+	opts, err := service.PasswordOptions(conf.Password)
+	if err != nil {
+		panic(err)
+	}
+	reg := metrics.NewRegistry()
+	db, err := mysqldriver.Initialize(conf)
+	if err != nil {
+		panic(err)
+	}
+	reg.MustRegister(metrics.DBStats("mysql", db))
+	// The sessions of the web app are revoked when the password changes,
+	// through their service so that their end is published
+	events := bus.New()
+	defer events.Close()
+	sessions := sessionservice.New(sessionrepo.Get(conf, sessionrepo.RedisRepo),
+		sessionservice.WithPolicy(sessionservice.PolicyFromConfig(conf.Session)),
+		sessionservice.WithEvents(events))
+	opts = append(opts, service.WithSessions(sessions))
+	svc := user.ApplyDecorators(service.New(repo, opts...), instrumenting.New(reg), usertracing.Apply)
 
-	r := gin.Default()
+	r := gin.New()
+	r.Use(log.Middleware(log.Default()), tracing.Middleware("gophr-user-api"), metrics.Middleware(reg), gin.Recovery())
+	r.GET(metrics.Path, metrics.Handler(reg))
 	http.RegisterHandlers(r, svc)
 
 	if err := r.Run(fmt.Sprintf(":%s", *port)); err != nil {
@@ -1,13 +1,15 @@
 package main
 
 import (
+	admincli "gophr.v2/admin/cli"
 	"gophr.v2/cli"
+	migrationcli "gophr.v2/migration/cli"
 	usercli "gophr.v2/user/cli"
 	"log"
 )
 
 func main() {
-	cli.GophrApp.AddCommand(usercli.UserCmd)
+	cli.GophrApp.AddCommand(usercli.UserCmd, admincli.AdminCmd, migrationcli.MigrateCmd)
 	if err := cli.GophrApp.Execute(); err != nil {
 		log.Fatal(err)
 	}
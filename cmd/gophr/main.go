@@ -1,21 +1,65 @@
 package main
 
 import (
+	"context"
+	"database/sql"
 	"flag"
 	"github.com/gin-gonic/gin"
+	goredis "github.com/go-redis/redis/v8"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/spf13/afero"
 	"gophr.v2/config"
 	"gophr.v2/config/configutil"
+	mysqldriver "gophr.v2/driver/mysql"
+	postgresdriver "gophr.v2/driver/postgres"
+	"gophr.v2/driver/redis"
+	sqlitedriver "gophr.v2/driver/sqlite"
+	"gophr.v2/event/bus"
+	"gophr.v2/event/outbox"
+	exportrepo "gophr.v2/export/repository"
+	feedcache "gophr.v2/feed/cache/redis"
+	followrepo "gophr.v2/follow/repository"
+	"gophr.v2/image"
 	imagerepo "gophr.v2/image/repository"
+	imageinstrumenting "gophr.v2/image/service/decorators/instrumenting"
+	imagetracing "gophr.v2/image/service/decorators/tracing"
+	"gophr.v2/log"
+	"gophr.v2/metrics"
+	"gophr.v2/migration"
+	notificationrepo "gophr.v2/notification/repository"
+	"gophr.v2/realtime"
+	"gophr.v2/realtime/hub"
+	realtimeredis "gophr.v2/realtime/redis"
+	"gophr.v2/session"
 	sessionrepo "gophr.v2/session/repository"
+	sessionredis "gophr.v2/session/repository/redis"
+	sessioninstrumenting "gophr.v2/session/service/decorators/instrumenting"
+	sessiontracing "gophr.v2/session/service/decorators/tracing"
+	"gophr.v2/tracing"
+	"gophr.v2/user"
+	"gophr.v2/user/cache/lru"
+	usercache "gophr.v2/user/cache/redis"
+	"gophr.v2/user/lockout"
+	lockoutmemory "gophr.v2/user/lockout/memory"
+	lockoutstore "gophr.v2/user/lockout/redis"
 	userrepo "gophr.v2/user/repository"
 	"gophr.v2/user/service"
+	cachedecorator "gophr.v2/user/service/decorators/cache"
+	userinstrumenting "gophr.v2/user/service/decorators/instrumenting"
+	usertracing "gophr.v2/user/service/decorators/tracing"
+	"gophr.v2/util/randutil"
 	"gophr.v2/view"
 	"gophr.v2/view/middleware"
-	"log"
+	webhookrepo "gophr.v2/webhook/repository"
 
+	exportservice "gophr.v2/export/service"
+	feedservice "gophr.v2/feed/service"
+	followservice "gophr.v2/follow/service"
 	imageservice "gophr.v2/image/service"
+	notificationservice "gophr.v2/notification/service"
 	sessionservice "gophr.v2/session/service"
+	"gophr.v2/session/sessionutil"
+	webhookservice "gophr.v2/webhook/service"
 )
 
 var (
@@ -25,36 +69,285 @@ var (
 func init() {
 	flag.Parse()
 	conf = configutil.Initialize()
+	log.SetDefault(configutil.NewLogger(conf))
 
 	if conf.Gophr.Environment == "PROD" {
 		gin.SetMode(gin.ReleaseMode)
 	}
 }
 
+// migrate applies the pending migrations to db.
+func migrate(ctx context.Context, db *sql.DB, dialect migration.Dialect, migrations []*migration.Migration) {
+	applied, err := migration.New(db, dialect, migrations).Up(ctx)
+	if err != nil {
+		log.Fatal(err)
+	}
+	for _, m := range applied {
+		log.WithField("migration", m).Info("applied migration")
+	}
+}
+
+// newRedis creates a Redis client whose pool stats are collected in
+// reg under name.
+func newRedis(reg prometheus.Registerer, name string) *goredis.Client {
+	client := redis.New(conf)
+	reg.MustRegister(metrics.RedisStats(name, client))
+	return client
+}
+
+// serveMetrics serves the metrics gathered by reg on the listener
+// configured for them, or along with the public routes of r when
+// there's none.
+func serveMetrics(r *gin.Engine, reg *prometheus.Registry) {
+	if conf.Metrics.Address == "" {
+		r.GET(metrics.Path, metrics.Handler(reg))
+		return
+	}
+
+	admin := gin.New()
+	admin.Use(gin.Recovery())
+	admin.GET(metrics.Path, metrics.Handler(reg))
+	go func() {
+		if err := admin.Run(conf.Metrics.Address); err != nil {
+			log.Fatal(err)
+		}
+	}()
+}
+
+// relay publishes the events of the outbox until ctx is done.
+func relay(ctx context.Context, r *outbox.Relay) {
+	if err := r.Run(ctx, outbox.DefaultInterval); err != nil && err != context.Canceled {
+		log.Fatal(err)
+	}
+}
+
 func main() {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Side effects subscribe to the domain events. The events stored
+	// in the outbox along with their change are relayed to the bus.
+	events := bus.New()
+	defer events.Close()
+	reg := metrics.NewRegistry()
+	shutdown, err := tracing.Setup(ctx, "gophr", conf.Tracing)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer noOpClose(func() error { return shutdown(context.Background()) })
+	// Standalone deployments store the users, images and sessions in
+	// SQLite and keep the rest in memory so that they need no external
+	// service at all
+	standalone := conf.Gophr.Database == config.SQLiteDatabase
+	webhookRT, notificationRT := webhookrepo.MySQLRepo, notificationrepo.MySQLRepo
+	followRT, exportRT := followrepo.MySQLRepo, exportrepo.MySQLRepo
+
+	if standalone {
+		log.Warn("gophr is standalone, the follows, notifications, webhooks and exports are kept in memory")
+		webhookRT, notificationRT = webhookrepo.MemoryRepo, notificationrepo.MemoryRepo
+		followRT, exportRT = followrepo.MemoryRepo, exportrepo.MemoryRepo
+
+		db, err := sqlitedriver.Initialize(conf)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer noOpClose(db.Close)
+		reg.MustRegister(metrics.DBStats("sqlite", db))
+		migrate(ctx, db, migration.SQLiteDialect{}, migration.SQLite())
+		go relay(ctx, outbox.NewRelay(db, events, outbox.WithSQLite()))
+	} else {
+		db, err := mysqldriver.Initialize(conf)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer noOpClose(db.Close)
+		reg.MustRegister(metrics.DBStats("mysql", db))
+		migrate(ctx, db, migration.MySQLDialect{}, migration.MySQL())
+		go relay(ctx, outbox.NewRelay(db, events))
+	}
+
+	// The users and images may be stored in PostgreSQL instead, along
+	// with the outbox of their events
+	if conf.Gophr.Database == config.PostgresDatabase {
+		pg, err := postgresdriver.Initialize(conf)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer noOpClose(pg.Close)
+		reg.MustRegister(metrics.DBStats("postgres", pg))
+		migrate(ctx, pg, migration.PostgresDialect{}, migration.Postgres())
+		go relay(ctx, outbox.NewRelay(pg, events, outbox.WithPostgres()))
+	}
+
+	webhookRepo, closer := webhookrepo.Get(conf, webhookRT)
+	defer noOpClose(closer)
+	webhookService := webhookservice.New(webhookRepo, webhookservice.OptionsFromConfig(conf.Webhook)...)
+	pollInterval := webhookservice.DefaultPollInterval
+	if conf.Webhook.PollInterval > 0 {
+		pollInterval = conf.Webhook.PollInterval
+	}
+	webhookService.Subscribe(events)
+	go func() {
+		if err := webhookService.Run(ctx, pollInterval); err != nil && err != context.Canceled {
+			log.Fatal(err)
+		}
+	}()
+
+	// The sessions are kept in Redis unless gophr is standalone
+	var sessionRepo session.Repository
+	if standalone {
+		sessionRepo = sessionrepo.Get(conf, sessionrepo.SQLiteRepo)
+	} else {
+		sessionRepo = sessionredis.New(newRedis(reg, "sessions"))
+	}
+	sessionService := session.ApplyDecorators(sessionservice.New(sessionRepo,
+		sessionservice.WithPolicy(sessionservice.PolicyFromConfig(conf.Session)),
+		sessionservice.WithEvents(events)),
+		sessioninstrumenting.New(reg), sessiontracing.Apply)
+	cookie := sessionutil.CookieFromConfig(conf.Session.Cookie)
 
-	userRepo, closer := userrepo.Get(conf, userrepo.MySQLRepo)
+	userRepo, closer := userrepo.Get(conf, userrepo.SQLRepo(conf))
 	defer noOpClose(closer)
-	userService := service.New(userRepo)
+	var lockoutStore lockout.Store = lockoutmemory.New()
+	if !standalone {
+		lockoutStore = lockoutstore.New(newRedis(reg, "lockout"))
+	}
+	guard := lockout.New(lockoutStore, lockout.DefaultAccountPolicy, lockout.DefaultIPPolicy)
+	passwordOpts, err := service.PasswordOptions(conf.Password)
+	if err != nil {
+		log.Fatal(err)
+	}
+	userOpts := append(passwordOpts,
+		service.WithLockout(guard),
+		service.WithEvents(events),
+		service.WithSessions(sessionService))
+	if conf.Account.DeletionGracePeriod > 0 {
+		userOpts = append(userOpts, service.WithDeletionGracePeriod(conf.Account.DeletionGracePeriod))
+	}
+	accountService := service.New(userRepo, userOpts...)
+	purgeInterval := service.DefaultPurgeInterval
+	if conf.Account.PurgeInterval > 0 {
+		purgeInterval = conf.Account.PurgeInterval
+	}
+	go func() {
+		if err := accountService.Run(ctx, purgeInterval); err != nil && err != context.Canceled {
+			log.Fatal(err)
+		}
+	}()
 
-	sessionRepo := sessionrepo.Get(conf, sessionrepo.RedisRepo)
-	sessionService := sessionservice.New(sessionRepo)
+	// The user lookups of every page are cached, in Redis unless
+	// gophr is standalone
+	var userService user.Service = accountService
+	if conf.UserCache.TTL > 0 {
+		var userCache user.Cache = lru.New(conf.UserCache.Size)
+		if !standalone {
+			userCache = usercache.New(newRedis(reg, "users"))
+		}
+		cached := cachedecorator.New(userCache, cachedecorator.WithTTL(conf.UserCache.TTL))
+		cached.Subscribe(events)
+		reg.MustRegister(metrics.CacheStats("user", func() (uint64, uint64) {
+			stats := cached.Stats()
+			return stats.Hits, stats.Misses
+		}))
+		userService = user.ApplyDecorators(userService, cached.Apply)
+	}
+	// The lookups served from the cache are measured and traced too
+	userService = user.ApplyDecorators(userService, userinstrumenting.New(reg), usertracing.Apply)
+
+	// Events are shared with the other instances through Redis
+	var broker realtime.Broker = hub.New(hub.DefaultHistorySize)
+	if !standalone {
+		redisBroker := realtimeredis.New(newRedis(reg, "realtime"), hub.New(hub.DefaultHistorySize))
+		go func() {
+			if err := redisBroker.Listen(ctx); err != nil && err != context.Canceled {
+				log.Fatal(err)
+			}
+		}()
+		broker = redisBroker
+	}
+	realtime.Subscribe(events, broker)
 
-	imageRepo, closer := imagerepo.Get(conf, imagerepo.MySQLRepo)
+	imageRepo, closer := imagerepo.Get(conf, imagerepo.SQLRepo(conf))
 	defer noOpClose(closer)
 
 	fs := afero.NewOsFs()
-	imageService := imageservice.New(imageRepo, fs, nil)
+	imageService := image.ApplyDecorators(imageservice.New(imageRepo, fs, nil,
+		imageservice.WithEvents(events)),
+		imageinstrumenting.New(reg), imagetracing.Apply)
+	imageservice.Subscribe(events, imageService)
+	imageGracePeriod := service.DefaultDeletionGracePeriod
+	if conf.Account.DeletionGracePeriod > 0 {
+		imageGracePeriod = conf.Account.DeletionGracePeriod
+	}
+	go func() {
+		if err := imageservice.RunPurge(ctx, imageService, purgeInterval, imageGracePeriod); err != nil && err != context.Canceled {
+			log.Fatal(err)
+		}
+	}()
+
+	notificationRepo, closer := notificationrepo.Get(conf, notificationRT)
+	defer noOpClose(closer)
+	notificationService := notificationservice.New(notificationRepo,
+		notificationservice.WithPublisher(broker))
+	notificationService.Subscribe(events)
+
+	followRepo, closer := followrepo.Get(conf, followRT)
+	defer noOpClose(closer)
+	followService := followservice.New(followRepo, userService,
+		followservice.WithNotifications(notificationService))
+	followService.Subscribe(events)
+
+	var feedOpts []feedservice.Option
+	if conf.Feed.CacheTTL > 0 && !standalone {
+		feedOpts = append(feedOpts, feedservice.WithCache(feedcache.New(newRedis(reg, "feed")), conf.Feed.CacheTTL))
+	}
+	feedService := feedservice.New(followService, imageService, feedOpts...)
+
+	exportRepo, closer := exportrepo.Get(conf, exportRT)
+	defer noOpClose(closer)
+	exportStore, err := exportservice.NewStore(fs, conf.Export)
+	if err != nil {
+		log.Fatal(err)
+	}
+	exportSecret := conf.Export.Secret
+	if exportSecret == "" {
+		log.Warn("export: no secret is configured, the download links won't survive a restart")
+		exportSecret = randutil.GenerateToken(32)
+	}
+	exportOpts := append(exportservice.OptionsFromConfig(conf.Export),
+		exportservice.WithEvents(events),
+		exportservice.WithImages(imageService, afero.NewBasePathFs(fs, imageservice.DefaultImagePathLocation)),
+		exportservice.WithSessions(sessionService),
+		exportservice.WithFollows(followService),
+		exportservice.WithNotifications(notificationService),
+		exportservice.WithWebhooks(webhookService))
+	exportService := exportservice.New(exportRepo, userService, exportStore, exportSecret, exportOpts...)
+	exportService.Subscribe(events)
+	go func() {
+		if err := exportService.Run(ctx, exportservice.DefaultCleanupInterval); err != nil && err != context.Canceled {
+			log.Fatal(err)
+		}
+	}()
 
-	r := gin.Default()
+	// The requests are logged and traced before recovering from the
+	// panics so that they're recorded as failed. The changes of the
+	// session are saved once per request
+	r := gin.New()
+	r.Use(log.Middleware(log.Default()), tracing.Middleware("gophr"), metrics.Middleware(reg), gin.Recovery(),
+		middleware.SaveSession(sessionService))
+	serveMetrics(r, reg)
 	v1Routers := r.Group("/v1")
-	securedRouter := v1Routers.Use(middleware.RequireLogin(sessionService))
+	securedRouter := v1Routers.Use(middleware.RequireLogin(sessionService, cookie))
 
-	view.RegisterRoutes(r, securedRouter, userService, sessionService, imageService,
+	view.RegisterRoutes(r, securedRouter, userService, sessionService, imageService, followService, feedService, notificationService,
 		"v2/templates/**/*.html",
 		"v2/templates/layout.html",
 		"v2/assets/",
-		"data/images/")
+		"data/images/",
+		view.WithCookie(cookie),
+		view.WithEvents(broker),
+		view.WithWebhooks(webhookService, conf.Webhook.Admins),
+		view.WithExports(exportService))
 
 	if err := r.Run(":8080"); err != nil {
 		log.Fatal(err)
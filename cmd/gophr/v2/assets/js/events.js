@@ -0,0 +1,24 @@
+(function ($) {
+    "use strict";
+
+    /*==================================================================
+    [ Real-time updates ]*/
+    if (!window.EventSource) {
+        return;
+    }
+
+    // The browser reconnects by itself and sends the Last-Event-ID
+    // header so the events missed meanwhile are replayed.
+    var source = new EventSource('/events');
+
+    source.addEventListener('image', function () {
+        $('#new-images').show();
+    });
+
+    source.addEventListener('notification', function () {
+        var badge = $('#notification-badge');
+        var unread = parseInt(badge.text(), 10) || 0;
+        badge.text(unread + 1).show();
+    });
+
+})(jQuery);
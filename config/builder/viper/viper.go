@@ -1,9 +1,9 @@
 package viper
 
 import (
-	"github.com/jayvib/golog"
 	"github.com/spf13/viper"
 	"gophr.v2/config"
+	"gophr.v2/log"
 	"strconv"
 )
 
@@ -92,8 +92,8 @@ func initializeDebugging() {
 	v := viper.Get("debug")
 	isDebug, _ := strconv.ParseBool(v.(string))
 	if isDebug {
-		golog.SetLevel(golog.DebugLevel)
-		golog.Warning("GOPHER IS IN DEBUGGING MODE!")
+		log.SetLevel(log.DebugLevel)
+		log.Warn("GOPHER IS IN DEBUGGING MODE!")
 	}
 	viper.GetViper()
 }
@@ -1,9 +1,10 @@
 package config
 
 import (
-	"github.com/jayvib/golog"
 	"github.com/jinzhu/copier"
+	"gophr.v2/log"
 	"sync"
+	"time"
 )
 
 type Env int
@@ -49,17 +50,28 @@ func getConfigName(env Env) string {
 }
 
 type Config struct {
-	rwmu  sync.RWMutex
-	Gophr Gophr `json:"gophr"`
-	MySQL MySQL `json:"mysql"`
-	Redis Redis `json:"redis"`
-	Debug bool  `json:"debug"`
+	rwmu      sync.RWMutex
+	Gophr     Gophr     `json:"gophr"`
+	MySQL     MySQL     `json:"mysql"`
+	Postgres  Postgres  `json:"postgres"`
+	SQLite    SQLite    `json:"sqlite"`
+	Redis     Redis     `json:"redis"`
+	Password  Password  `json:"password"`
+	Session   Session   `json:"session"`
+	Feed      Feed      `json:"feed"`
+	UserCache UserCache `json:"userCache"`
+	Account   Account   `json:"account"`
+	Export    Export    `json:"export"`
+	Webhook   Webhook   `json:"webhook"`
+	Tracing   Tracing   `json:"tracing"`
+	Metrics   Metrics   `json:"metrics"`
+	Debug     bool      `json:"debug"`
 }
 
 func (c *Config) init() {
 	if c.Debug {
-		golog.Warning("Gopher is in debug mode!")
-		golog.SetLevel(golog.DebugLevel)
+		log.Warn("Gopher is in debug mode!")
+		log.SetLevel(log.DebugLevel)
 	}
 }
 
@@ -83,10 +95,23 @@ func (c *Config) Clone() (*Config, error) {
 	return clonedConfig, nil
 }
 
+// The databases the user and image repositories can be stored in.
+const (
+	MySQLDatabase    = "mysql"
+	PostgresDatabase = "postgres"
+	// SQLiteDatabase runs gophr without any external service. The
+	// sessions are stored in SQLite too and the other records are
+	// kept in memory.
+	SQLiteDatabase = "sqlite"
+)
+
 type Gophr struct {
 	Port        string `json:"port"`
 	Environment string `json:"env"`
 	Debug       bool   `json:"debug"`
+	// Database is where the users and images are stored, either
+	// MySQLDatabase, the default, PostgresDatabase or SQLiteDatabase.
+	Database string `json:"database"`
 }
 
 type MySQL struct {
@@ -95,6 +120,26 @@ type MySQL struct {
 	Host     string
 	Port     string
 	Database string
+	// Location is the time zone of the datetime columns. It
+	// defaults to UTC.
+	Location string
+}
+
+type Postgres struct {
+	User     string
+	Password string
+	Host     string
+	Port     string
+	Database string
+	// SSLMode is the sslmode of the connections. It defaults
+	// to disable.
+	SSLMode string
+}
+
+type SQLite struct {
+	// Path is the file of the database, or :memory: for a database
+	// lost on exit. It defaults to data/gophr.db.
+	Path string
 }
 
 type Redis struct {
@@ -103,3 +148,106 @@ type Redis struct {
 	Password string
 	Database int
 }
+
+// Password configures the password policy and hashing of the users.
+// Zero values fall back to the defaults of the user package.
+type Password struct {
+	Cost       int
+	MinLength  int
+	MinClasses int
+	// BreachList is the path of the breached password hash list.
+	BreachList string
+}
+
+// Session configures the lifecycle of the sessions and their cookie.
+// Zero values fall back to the defaults of the session package.
+type Session struct {
+	IdleTimeout      time.Duration
+	AbsoluteLifetime time.Duration
+	Cookie           Cookie
+}
+
+type Cookie struct {
+	Path   string
+	Domain string
+	Secure bool
+	// SameSite is either "lax", "strict" or "none".
+	SameSite string
+}
+
+// Feed configures the personalized feeds.
+type Feed struct {
+	// CacheTTL is how long a built feed page is cached in Redis.
+	// Feeds are not cached when zero.
+	CacheTTL time.Duration
+}
+
+// UserCache configures the caching of the user lookups.
+type UserCache struct {
+	// TTL is how long a looked up user is cached. Lookups are
+	// not cached when zero.
+	TTL time.Duration
+	// Size is how many users the in-process cache of the standalone
+	// deployments keeps. The others share a Redis cache.
+	Size int
+}
+
+// Account configures the deletion of the user accounts.
+// Zero values fall back to the defaults of the user service.
+type Account struct {
+	// DeletionGracePeriod is how long a deleted account can be
+	// restored before it's purged along with its records.
+	DeletionGracePeriod time.Duration
+	// PurgeInterval is how often the accounts past their grace
+	// period are purged.
+	PurgeInterval time.Duration
+}
+
+// Export configures the personal data exports.
+// Zero values fall back to the defaults of the export service.
+type Export struct {
+	// Secret signs the download links. Links signed with a random
+	// secret when none is set don't survive a restart.
+	Secret string
+	// TTL is how long an archive can be downloaded.
+	TTL time.Duration
+	// Directory is where the archives are stored.
+	Directory string
+}
+
+// Webhook configures the outbound webhooks.
+// Zero values fall back to the defaults of the webhook service.
+type Webhook struct {
+	// Admins are the usernames allowed to register global hooks
+	// receiving the events about every user.
+	Admins []string
+	// MaxAttempts is how many times a delivery is tried.
+	MaxAttempts int
+	// Timeout is how long a hook has to respond.
+	Timeout time.Duration
+	// PollInterval is how often the due deliveries are sent.
+	PollInterval time.Duration
+}
+
+// Metrics configures where the metrics are served.
+type Metrics struct {
+	// Address is the host:port of the listener serving the metrics
+	// apart from the public routes. They're served along with the
+	// public routes when empty.
+	Address string
+}
+
+// Tracing configures the export of the traces.
+type Tracing struct {
+	// Exporter is where the spans are sent, either "otlp" or
+	// "stdout". Tracing is disabled when empty.
+	Exporter string
+	// Endpoint is the host:port of the OTLP/HTTP collector. It
+	// defaults to localhost:4318.
+	Endpoint string
+	// Insecure sends the spans to the collector without TLS.
+	Insecure bool
+	// SampleRatio is the fraction of the traces sampled, between 0
+	// and 1. Every trace is sampled when zero.
+	SampleRatio float64
+}
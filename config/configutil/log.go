@@ -0,0 +1,20 @@
+package configutil
+
+import (
+	"gophr.v2/config"
+	"gophr.v2/log"
+	"os"
+)
+
+// NewLogger creates the logger configured by conf. The entries are
+// written as JSON in production so that they can be indexed.
+func NewLogger(conf *config.Config) *log.Logger {
+	var opts []log.Option
+	if conf.Gophr.Environment == "PROD" {
+		opts = append(opts, log.WithJSON())
+	}
+	if conf.Debug {
+		opts = append(opts, log.WithLevel(log.DebugLevel))
+	}
+	return log.New(os.Stderr, opts...)
+}
@@ -1,10 +1,10 @@
 package configutil
 
 import (
-	"github.com/jayvib/golog"
 	"github.com/spf13/viper"
 	"gophr.v2/config"
 	builderviper "gophr.v2/config/builder/viper"
+	"gophr.v2/log"
 )
 
 func Initialize() *config.Config {
@@ -16,7 +16,7 @@ func LoadDefault() (*config.Config, error) {
 }
 
 func initializeConfig() *config.Config {
-	golog.Debug("Environment:", viper.Get("env"))
+	log.WithField("env", viper.Get("env")).Debug("loading config")
 	conf, err := LoadDefault()
 	if err != nil {
 		panic(err)
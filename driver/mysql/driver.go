@@ -4,43 +4,58 @@ import (
 	"database/sql"
 	"fmt"
 	_ "github.com/go-sql-driver/mysql"
-	"github.com/jayvib/golog"
 	"gophr.v2/config"
+	"gophr.v2/log"
+	"gophr.v2/tracing"
 	"net/url"
 	"sync"
 )
 
+// DefaultLocation is the time zone of the datetime columns when
+// none is configured.
+const DefaultLocation = "UTC"
+
 var (
-	db   *sql.DB
-	once sync.Once
+	mu  sync.Mutex
+	dbs = make(map[string]*sql.DB)
 )
 
+// Initialize returns the connection pool of the database in conf.
+// The repositories of the same database share a single pool.
 func Initialize(conf *config.Config) (*sql.DB, error) {
-	var err error
-
-	// Using Singleton Pattern
-	once.Do(func() {
-		var e error
-		format := fmt.Sprintf("%s:%s@tcp(%s:%s)/%s",
-			conf.MySQL.User, conf.MySQL.Password, conf.MySQL.Host, conf.MySQL.Port, conf.MySQL.Database)
-		golog.Info(format)
-		val := url.Values{}
-		val.Add("parseTime", "1")
-		val.Add("loc", "Asia/Manila")
-		dsn := fmt.Sprintf("%s?%s", format, val.Encode())
-		golog.Debug("DSN:", dsn)
-		db, e = sql.Open("mysql", dsn)
-		if e != nil {
-			err = e
-		}
-		if e := db.Ping(); e != nil {
-			err = e
-		}
-	})
+	dsn := DSN(conf.MySQL)
+
+	mu.Lock()
+	defer mu.Unlock()
+	if db, ok := dbs[dsn]; ok {
+		return db, nil
+	}
 
+	log.With(log.Fields{"user": conf.MySQL.User, "host": conf.MySQL.Host, "database": conf.MySQL.Database}).Info("mysql: connecting")
+	db, err := tracing.OpenDB("mysql", dsn, "mysql")
 	if err != nil {
 		return nil, err
 	}
-
+	if err := db.Ping(); err != nil {
+		_ = db.Close()
+		return nil, err
+	}
+	dbs[dsn] = db
 	return db, nil
 }
+
+// DSN returns the data source name of the database in conf.
+func DSN(conf config.MySQL) string {
+	loc := conf.Location
+	if loc == "" {
+		loc = DefaultLocation
+	}
+
+	format := fmt.Sprintf("%s:%s@tcp(%s:%s)/%s",
+		conf.User, conf.Password, conf.Host, conf.Port, conf.Database)
+	val := url.Values{}
+	val.Add("parseTime", "1")
+	val.Add("loc", loc)
+	dsn := fmt.Sprintf("%s?%s", format, val.Encode())
+	return dsn
+}
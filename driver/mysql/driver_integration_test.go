@@ -3,14 +3,14 @@
 package mysql_test
 
 import (
-	"github.com/jayvib/golog"
 	"github.com/stretchr/testify/require"
 	"gophr.v2/config"
+	"gophr.v2/log"
 	"testing"
 )
 
 func TestInitializeDriver(t *testing.T) {
-	golog.SetLevel(golog.DebugLevel)
+	log.SetLevel(log.DebugLevel)
 	conf, err := config.LoadDefault(config.DevelopmentEnv)
 	require.NoError(t, err)
 	db, err := driver.InitializeDriver(conf)
@@ -0,0 +1,81 @@
+package postgres
+
+import (
+	"database/sql"
+	_ "github.com/lib/pq"
+	"gophr.v2/config"
+	"gophr.v2/log"
+	"gophr.v2/tracing"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DefaultSSLMode is the sslmode of the connections when none
+// is configured.
+const DefaultSSLMode = "disable"
+
+var (
+	mu  sync.Mutex
+	dbs = make(map[string]*sql.DB)
+)
+
+// Initialize returns the connection pool of the database in conf.
+// The repositories of the same database share a single pool.
+func Initialize(conf *config.Config) (*sql.DB, error) {
+	dsn := DSN(conf.Postgres)
+
+	mu.Lock()
+	defer mu.Unlock()
+	if db, ok := dbs[dsn]; ok {
+		return db, nil
+	}
+
+	db, err := tracing.OpenDB("postgres", dsn, "postgresql")
+	if err != nil {
+		return nil, err
+	}
+	if err := db.Ping(); err != nil {
+		_ = db.Close()
+		return nil, err
+	}
+	dbs[dsn] = db
+	return db, nil
+}
+
+// DSN returns the connection URL of the database in conf.
+func DSN(conf config.Postgres) string {
+	sslMode := conf.SSLMode
+	if sslMode == "" {
+		sslMode = DefaultSSLMode
+	}
+
+	u := url.URL{
+		Scheme:   "postgres",
+		User:     url.UserPassword(conf.User, conf.Password),
+		Host:     conf.Host + ":" + conf.Port,
+		Path:     "/" + conf.Database,
+		RawQuery: url.Values{"sslmode": {sslMode}}.Encode(),
+	}
+	log.With(log.Fields{"user": conf.User, "host": u.Host, "database": conf.Database}).Info("postgres: connecting")
+	return u.String()
+}
+
+// Rebind rewrites the ? placeholders of query to the numbered
+// placeholders of PostgreSQL so that the queries written for MySQL
+// can be shared.
+func Rebind(query string) string {
+	var b strings.Builder
+	n := 0
+	for _, r := range query {
+		if r != '?' {
+			b.WriteRune(r)
+			continue
+		}
+		n++
+		b.WriteString("$")
+		b.WriteString(strconv.Itoa(n))
+	}
+	return b.String()
+}
@@ -3,8 +3,9 @@ package redis
 import (
 	"context"
 	"github.com/go-redis/redis/v8"
-	"github.com/jayvib/golog"
 	"gophr.v2/config"
+	"gophr.v2/log"
+	"gophr.v2/tracing"
 )
 
 // New initializes redis client using the passed conf configuration.
@@ -16,6 +17,7 @@ func New(conf *config.Config) *redis.Client {
 		Password: conf.Redis.Password,
 		DB:       conf.Redis.Database,
 	})
+	client.AddHook(tracing.RedisHook{})
 
 	ctx := context.Background()
 
@@ -23,7 +25,7 @@ func New(conf *config.Config) *redis.Client {
 	if err != nil {
 		panic(err)
 	}
-	golog.Debugf("REDIS: %s: PING...", pong)
+	log.WithField("pong", pong).Debug("redis: connected")
 
 	return client
 }
@@ -0,0 +1,80 @@
+package sqlite
+
+import (
+	"database/sql"
+	"gophr.v2/config"
+	"gophr.v2/log"
+	"gophr.v2/tracing"
+	_ "modernc.org/sqlite"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// DefaultPath is the file of the database when none is configured.
+const DefaultPath = "data/gophr.db"
+
+// Memory is the path of a database living in memory.
+const Memory = ":memory:"
+
+var (
+	mu  sync.Mutex
+	dbs = make(map[string]*sql.DB)
+)
+
+// Initialize returns the connection pool of the database in conf.
+// The repositories of the same database share a single pool.
+func Initialize(conf *config.Config) (*sql.DB, error) {
+	path := conf.SQLite.Path
+	if path == "" {
+		path = DefaultPath
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if db, ok := dbs[path]; ok {
+		return db, nil
+	}
+
+	db, err := Open(path)
+	if err != nil {
+		return nil, err
+	}
+	dbs[path] = db
+	return db, nil
+}
+
+// Open opens the database at path, creating it when missing. Every
+// call to Open with Memory opens a new database.
+//
+// The pool holds a single connection since SQLite has a single writer
+// anyway, and since every connection to Memory is a database of
+// its own. The times are stored as text that sorts chronologically
+// as long as they're in the same time zone, so the repositories store
+// them in UTC.
+func Open(path string) (*sql.DB, error) {
+	if path != Memory {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return nil, err
+		}
+	}
+	log.WithField("path", path).Info("sqlite: opening")
+
+	val := url.Values{}
+	val.Add("_time_format", "sqlite")
+	val.Add("_pragma", "busy_timeout(5000)")
+	if path != Memory {
+		val.Add("_pragma", "journal_mode(WAL)")
+	}
+	db, err := tracing.OpenDB("sqlite", "file:"+path+"?"+val.Encode(), "sqlite")
+	if err != nil {
+		return nil, err
+	}
+	db.SetMaxOpenConns(1)
+	if err := db.Ping(); err != nil {
+		_ = db.Close()
+		return nil, err
+	}
+	return db, nil
+}
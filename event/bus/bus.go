@@ -0,0 +1,145 @@
+package bus
+
+import (
+	"context"
+	"gophr.v2/event"
+	"gophr.v2/log"
+	"sync"
+)
+
+// Defaults of the asynchronous dispatch.
+const (
+	DefaultWorkers   = 4
+	DefaultQueueSize = 256
+)
+
+var _ event.Publisher = (*Bus)(nil)
+
+// Option configures the Bus.
+type Option func(b *Bus)
+
+// WithWorkers sets how many asynchronous handlers may run at once.
+func WithWorkers(n int) Option {
+	return func(b *Bus) {
+		b.workers = n
+	}
+}
+
+// WithQueueSize sets how many asynchronous handler calls may wait for
+// a worker before Publish blocks.
+func WithQueueSize(n int) Option {
+	return func(b *Bus) {
+		b.queueSize = n
+	}
+}
+
+// New creates a bus and starts its workers. Close must be called to
+// let the pending asynchronous handlers finish.
+func New(opts ...Option) *Bus {
+	b := &Bus{
+		sync:      make(map[string][]event.Handler),
+		async:     make(map[string][]event.Handler),
+		workers:   DefaultWorkers,
+		queueSize: DefaultQueueSize,
+	}
+	for _, opt := range opts {
+		opt(b)
+	}
+
+	b.queue = make(chan job, b.queueSize)
+	b.wg.Add(b.workers)
+	for i := 0; i < b.workers; i++ {
+		go b.work()
+	}
+	return b
+}
+
+// Bus dispatches the events in process. Synchronous handlers run
+// within Publish, in the order they subscribed, and their errors are
+// returned to the publisher. Asynchronous handlers run on a worker
+// pool once Publish returns and their errors are only logged.
+type Bus struct {
+	mu        sync.RWMutex
+	sync      map[string][]event.Handler
+	async     map[string][]event.Handler
+	closed    bool
+	workers   int
+	queueSize int
+	queue     chan job
+	wg        sync.WaitGroup
+}
+
+type job struct {
+	handler event.Handler
+	event   event.Event
+	logger  *log.Logger
+}
+
+// Subscribe runs h within Publish for every event called name.
+func (b *Bus) Subscribe(name string, h event.Handler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.sync[name] = append(b.sync[name], h)
+}
+
+// SubscribeAsync runs h in the background for every event called name.
+func (b *Bus) SubscribeAsync(name string, h event.Handler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.async[name] = append(b.async[name], h)
+}
+
+// Publish dispatches events to their handlers. Every synchronous handler
+// runs even when one fails, the first error being returned.
+func (b *Bus) Publish(ctx context.Context, events ...event.Event) error {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	var firstErr error
+	for _, e := range events {
+		for _, h := range b.sync[e.EventName()] {
+			if err := h(ctx, e); err != nil {
+				log.FromContext(ctx).WithError(err).WithField("event", e.EventName()).Error("event: failed handling event")
+				if firstErr == nil {
+					firstErr = err
+				}
+			}
+		}
+
+		if b.closed {
+			continue
+		}
+		for _, h := range b.async[e.EventName()] {
+			b.queue <- job{handler: h, event: e, logger: log.FromContext(ctx)}
+		}
+	}
+	return firstErr
+}
+
+func (b *Bus) work() {
+	defer b.wg.Done()
+	for j := range b.queue {
+		// The publisher's context may be canceled as soon as
+		// Publish returns, asynchronous handlers outlive it. They
+		// still log along with the publisher's request.
+		ctx := log.NewContext(context.Background(), j.logger)
+		if err := j.handler(ctx, j.event); err != nil {
+			log.FromContext(ctx).WithError(err).WithField("event", j.event.EventName()).Error("event: failed handling event asynchronously")
+		}
+	}
+}
+
+// Close stops accepting asynchronous work and waits for the queued
+// handlers to finish.
+func (b *Bus) Close() {
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		return
+	}
+	b.closed = true
+	close(b.queue)
+	b.mu.Unlock()
+
+	b.wg.Wait()
+}
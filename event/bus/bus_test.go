@@ -0,0 +1,95 @@
+//+build unit
+
+package bus
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"gophr.v2/event"
+	"sync"
+	"testing"
+)
+
+type pinged struct {
+	N int
+}
+
+func (*pinged) EventName() string { return "test.pinged" }
+
+func TestBus_Publish(t *testing.T) {
+	t.Run("Synchronous handlers run in order", func(t *testing.T) {
+		b := New()
+		defer b.Close()
+
+		var calls []string
+		b.Subscribe("test.pinged", func(ctx context.Context, e event.Event) error {
+			calls = append(calls, "first")
+			return nil
+		})
+		b.Subscribe("test.pinged", func(ctx context.Context, e event.Event) error {
+			calls = append(calls, "second")
+			return nil
+		})
+		b.Subscribe("test.other", func(ctx context.Context, e event.Event) error {
+			calls = append(calls, "other")
+			return nil
+		})
+
+		assert.NoError(t, b.Publish(context.Background(), &pinged{}))
+		assert.Equal(t, []string{"first", "second"}, calls)
+	})
+
+	t.Run("First error is returned", func(t *testing.T) {
+		b := New()
+		defer b.Close()
+
+		wantErr := errors.New("failed")
+		var called bool
+		b.Subscribe("test.pinged", func(ctx context.Context, e event.Event) error {
+			return wantErr
+		})
+		b.Subscribe("test.pinged", func(ctx context.Context, e event.Event) error {
+			called = true
+			return errors.New("failed too")
+		})
+
+		assert.Equal(t, wantErr, b.Publish(context.Background(), &pinged{}))
+		assert.True(t, called)
+	})
+
+	t.Run("Asynchronous handlers finish before Close returns", func(t *testing.T) {
+		b := New(WithWorkers(2), WithQueueSize(1))
+
+		var mu sync.Mutex
+		var sum int
+		b.SubscribeAsync("test.pinged", func(ctx context.Context, e event.Event) error {
+			mu.Lock()
+			defer mu.Unlock()
+			sum += e.(*pinged).N
+			return errors.New("only logged")
+		})
+
+		ctx, cancel := context.WithCancel(context.Background())
+		for i := 1; i <= 10; i++ {
+			assert.NoError(t, b.Publish(ctx, &pinged{N: i}))
+		}
+		cancel()
+		b.Close()
+
+		assert.Equal(t, 55, sum)
+	})
+
+	t.Run("Asynchronous handlers are skipped once closed", func(t *testing.T) {
+		b := New()
+		var called bool
+		b.SubscribeAsync("test.pinged", func(ctx context.Context, e event.Event) error {
+			called = true
+			return nil
+		})
+		b.Close()
+
+		assert.NoError(t, b.Publish(context.Background(), &pinged{}))
+		assert.False(t, called)
+	})
+}
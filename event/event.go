@@ -0,0 +1,61 @@
+package event
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// ErrUnknownEvent is returned when decoding an event whose name was
+// never registered.
+var ErrUnknownEvent = errors.New("event: unknown event")
+
+// Event is something that happened in a domain. Events are plain
+// structs encoded as JSON when they go through the outbox.
+type Event interface {
+	EventName() string
+}
+
+// Handler reacts to an event.
+type Handler func(ctx context.Context, e Event) error
+
+//go:generate mockery --name=Publisher
+
+// Publisher dispatches the events to their handlers.
+type Publisher interface {
+	Publish(ctx context.Context, events ...Event) error
+}
+
+// Subscriber registers the handlers of the events.
+type Subscriber interface {
+	// Subscribe runs h for every event called name as part of
+	// its publication.
+	Subscribe(name string, h Handler)
+	// SubscribeAsync runs h for every event called name in the
+	// background.
+	SubscribeAsync(name string, h Handler)
+}
+
+var (
+	mu       sync.RWMutex
+	registry = make(map[string]func() Event)
+)
+
+// Register makes the events called name decodable. The domain
+// packages register their events when initialized.
+func Register(name string, factory func() Event) {
+	mu.Lock()
+	defer mu.Unlock()
+	registry[name] = factory
+}
+
+// New returns a zero event called name ready to be decoded into.
+func New(name string) (Event, error) {
+	mu.RLock()
+	defer mu.RUnlock()
+	factory, ok := registry[name]
+	if !ok {
+		return nil, ErrUnknownEvent
+	}
+	return factory(), nil
+}
@@ -0,0 +1,83 @@
+//+build integration
+
+package outbox_test
+
+import (
+	"context"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gophr.v2/config"
+	"gophr.v2/config/builder/viper"
+	mysqldriver "gophr.v2/driver/mysql"
+	"gophr.v2/event/bus"
+	"gophr.v2/event/outbox"
+	"gophr.v2/image"
+	"gophr.v2/image/imageutil"
+	imagemysql "gophr.v2/image/repository/mysql"
+	imageservice "gophr.v2/image/service"
+	"gophr.v2/migration"
+	"gophr.v2/softdelete"
+	"gophr.v2/user"
+	usermysql "gophr.v2/user/repository/mysql"
+	userservice "gophr.v2/user/service"
+	"gophr.v2/user/userutil"
+	"gophr.v2/util/valueutil"
+	"testing"
+	"time"
+)
+
+// The handlers of the relayed events store events of their own while
+// the relay holds the rows it publishes, like the live images purged
+// along with their owner.
+func TestRelay_RelayPending_MySQL(t *testing.T) {
+	builder := viper.NewViperBuilder(
+		viper.SetViperConfigName("config-dev.yaml"),
+		viper.SetViperConfigPath("testdata"))
+	conf, err := config.New(builder)
+	require.NoError(t, err)
+	db, err := mysqldriver.Initialize(conf)
+	require.NoError(t, err)
+	_, err = migration.New(db, migration.MySQLDialect{}, migration.MySQL()).Up(context.Background())
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	relay := outbox.NewRelay(db, bus.New())
+	// The events stored by the other tests are out of the way
+	for {
+		n, err := relay.RelayPending(ctx)
+		require.NoError(t, err)
+		if n == 0 {
+			break
+		}
+	}
+
+	users := usermysql.New(db)
+	usr := &user.User{UserID: userutil.GenerateID(), Username: "outbox-luffy", Email: "outbox-luffy@onepiece.com",
+		Password: "hash", CreatedAt: valueutil.TimePointer(time.Now().UTC())}
+	require.NoError(t, users.Save(ctx, usr))
+	require.NoError(t, users.SoftDelete(ctx, usr.UserID, time.Now().UTC().Add(-time.Hour)))
+
+	images := imagemysql.New(db)
+	img := &image.Image{ImageID: imageutil.GenerateID(), UserID: usr.UserID, Name: "luffy.png", Location: "luffy.png",
+		CreatedAt: valueutil.TimePointer(time.Now().UTC())}
+	require.NoError(t, images.Save(ctx, img))
+
+	events := bus.New()
+	defer events.Close()
+	imageservice.Subscribe(events, imageservice.New(images, afero.NewMemMapFs(), nil))
+	relay = outbox.NewRelay(db, events)
+	// The image created
+	_, err = relay.RelayPending(ctx)
+	require.NoError(t, err)
+
+	n, err := userservice.New(users, userservice.WithDeletionGracePeriod(time.Minute)).Purge(ctx)
+	require.NoError(t, err)
+	require.NotZero(t, n)
+
+	_, err = relay.RelayPending(ctx)
+	require.NoError(t, err)
+	_, err = images.Find(softdelete.Include(ctx), img.ImageID)
+	assert.Equal(t, image.ErrNotFound, err, "the live images are purged along with their owner")
+}
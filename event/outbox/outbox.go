@@ -0,0 +1,211 @@
+// Package outbox implements a transactional outbox in MySQL,
+// PostgreSQL or SQLite.
+//
+// The events of a change are written to the outbox table in the same
+// transaction as the change, then a Relay publishes them. Events are
+// therefore never lost when the process stops between the commit and
+// the publication, but they may be published more than once.
+package outbox
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"gophr.v2/driver/postgres"
+	"gophr.v2/event"
+	"gophr.v2/log"
+	"time"
+)
+
+// DefaultInterval is how often the relay looks for new events.
+const DefaultInterval = time.Second
+
+// batchSize is the number of events published per transaction.
+const batchSize = 100
+
+// Store writes the events recorded in ctx to the outbox within tx.
+// The events are then left to the relay rather than to event.Dispatch.
+func Store(ctx context.Context, tx *sql.Tx) error {
+	return store(ctx, tx, "INSERT INTO outbox(name, payload, created_at) VALUES(?,?,?)")
+}
+
+// StorePostgres is Store within a transaction of PostgreSQL.
+func StorePostgres(ctx context.Context, tx *sql.Tx) error {
+	return store(ctx, tx, postgres.Rebind("INSERT INTO outbox(name, payload, created_at) VALUES(?,?,?)"))
+}
+
+func store(ctx context.Context, tx *sql.Tx, query string) error {
+	events := event.Recorded(ctx)
+	if len(events) == 0 {
+		return nil
+	}
+
+	now := time.Now().UTC()
+	for _, e := range events {
+		payload, err := json.Marshal(e)
+		if err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, query, e.EventName(), string(payload), now); err != nil {
+			return fmt.Errorf("outbox: storing %s: %w", e.EventName(), err)
+		}
+	}
+	event.MarkStored(ctx)
+	return nil
+}
+
+// Option configures the Relay.
+type Option func(r *Relay)
+
+// WithPostgres relays the outbox of a PostgreSQL database.
+func WithPostgres() Option {
+	return func(r *Relay) {
+		r.bind = postgres.Rebind
+	}
+}
+
+// WithSQLite relays the outbox of an SQLite database. The rows aren't
+// locked since SQLite locks the whole database while writing, and
+// they're published outside of a transaction: the pool holds a single
+// connection that the handlers of the events need too.
+func WithSQLite() Option {
+	return func(r *Relay) {
+		r.lock = ""
+	}
+}
+
+// NewRelay creates a relay publishing the events of the outbox in db
+// to publisher.
+func NewRelay(db *sql.DB, publisher event.Publisher, opts ...Option) *Relay {
+	r := &Relay{
+		db:        db,
+		publisher: publisher,
+		bind:      func(query string) string { return query },
+		lock:      "FOR UPDATE",
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+type Relay struct {
+	db        *sql.DB
+	publisher event.Publisher
+	// bind rewrites the placeholders of the queries for the database
+	bind func(query string) string
+	// lock is the clause locking the rows being published
+	lock string
+}
+
+// Run publishes the new events every interval until ctx is done.
+func (r *Relay) Run(ctx context.Context, interval time.Duration) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		for {
+			n, err := r.RelayPending(ctx)
+			if err != nil {
+				log.FromContext(ctx).WithError(err).Error("outbox: failed relaying events")
+			}
+			// Keep going while there's a backlog
+			if err != nil || n < batchSize {
+				break
+			}
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// RelayPending publishes a batch of unpublished events in the order
+// they were stored and returns how many were published. The rows are
+// locked while they're published so that concurrent relays don't
+// publish them too.
+//
+// The transaction reads committed rows only so that the rows are
+// locked alone: the handlers of the events storing events of their
+// own would otherwise wait on the gaps MySQL locks around them.
+func (r *Relay) RelayPending(ctx context.Context) (n int, err error) {
+	if r.lock == "" {
+		return r.relay(ctx, r.db)
+	}
+
+	tx, err := r.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelReadCommitted})
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+			return
+		}
+		err = tx.Commit()
+	}()
+	return r.relay(ctx, tx)
+}
+
+// querier is a *sql.DB or a *sql.Tx.
+type querier interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
+// relay publishes a batch of unpublished events read with q and marks
+// them published with q.
+func (r *Relay) relay(ctx context.Context, q querier) (n int, err error) {
+	query := `SELECT id, name, payload
+						FROM outbox
+						WHERE published_at IS NULL
+						ORDER BY id
+						LIMIT ?
+						` + r.lock
+	rows, err := q.QueryContext(ctx, r.bind(query), batchSize)
+	if err != nil {
+		return 0, err
+	}
+	type row struct {
+		id            int64
+		name, payload string
+	}
+	var pending []row
+	for rows.Next() {
+		var rw row
+		if err := rows.Scan(&rw.id, &rw.name, &rw.payload); err != nil {
+			_ = rows.Close()
+			return 0, err
+		}
+		pending = append(pending, rw)
+	}
+	if err := rows.Close(); err != nil {
+		return 0, err
+	}
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	update := r.bind("UPDATE outbox SET published_at = ? WHERE id = ?")
+	for _, rw := range pending {
+		e, err := event.New(rw.name)
+		if err == nil {
+			err = json.Unmarshal([]byte(rw.payload), e)
+		}
+		if err != nil {
+			// Undecodable events would block the outbox forever
+			log.FromContext(ctx).WithError(err).WithField("eventId", rw.id).Error("outbox: skipping undecodable event")
+		} else if err := r.publisher.Publish(ctx, e); err != nil {
+			return n, err
+		}
+
+		if _, err := q.ExecContext(ctx, update, time.Now().UTC(), rw.id); err != nil {
+			return n, err
+		}
+		n++
+	}
+	return n, nil
+}
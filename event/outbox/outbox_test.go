@@ -0,0 +1,75 @@
+//+build unit
+
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gophr.v2/event"
+	"gophr.v2/event/mocks"
+	"gophr.v2/user"
+	"testing"
+)
+
+func TestStore(t *testing.T) {
+	db, sqlMock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	e := &user.Registered{User: &user.User{UserID: "luffy123", Username: "luffy"}}
+	payload, err := json.Marshal(e)
+	require.NoError(t, err)
+
+	sqlMock.ExpectBegin()
+	sqlMock.ExpectExec("INSERT INTO outbox").
+		WithArgs(user.EventRegistered, string(payload), sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	sqlMock.ExpectCommit()
+
+	ctx := event.Record(context.Background(), e)
+	tx, err := db.Begin()
+	require.NoError(t, err)
+	require.NoError(t, Store(ctx, tx))
+	require.NoError(t, tx.Commit())
+
+	assert.Empty(t, event.Recorded(ctx), "stored events are left to the relay")
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
+
+func TestRelay_RelayPending(t *testing.T) {
+	db, sqlMock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	payload, err := json.Marshal(&user.Registered{User: &user.User{UserID: "luffy123"}})
+	require.NoError(t, err)
+
+	rows := sqlmock.NewRows([]string{"id", "name", "payload"}).
+		AddRow(1, user.EventRegistered, string(payload)).
+		AddRow(2, "test.unknown", "{}")
+
+	sqlMock.ExpectBegin()
+	sqlMock.ExpectQuery("SELECT (.+) FROM outbox").WithArgs(batchSize).WillReturnRows(rows)
+	sqlMock.ExpectExec("UPDATE outbox SET published_at").
+		WithArgs(sqlmock.AnyArg(), 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	sqlMock.ExpectExec("UPDATE outbox SET published_at").
+		WithArgs(sqlmock.AnyArg(), 2).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	sqlMock.ExpectCommit()
+
+	publisher := new(mocks.Publisher)
+	publisher.On("Publish", mock.Anything, mock.MatchedBy(func(e *user.Registered) bool {
+		return e.User.UserID == "luffy123"
+	})).Return(nil).Once()
+
+	n, err := NewRelay(db, publisher).RelayPending(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 2, n, "undecodable events are skipped")
+	publisher.AssertExpectations(t)
+	assert.NoError(t, sqlMock.ExpectationsWereMet())
+}
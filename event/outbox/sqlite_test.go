@@ -0,0 +1,66 @@
+//+build unit
+
+package outbox_test
+
+import (
+	"context"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	sqlitedriver "gophr.v2/driver/sqlite"
+	"gophr.v2/event/bus"
+	"gophr.v2/event/outbox"
+	"gophr.v2/image"
+	imagesqlite "gophr.v2/image/repository/sqlite"
+	imageservice "gophr.v2/image/service"
+	"gophr.v2/migration"
+	"gophr.v2/softdelete"
+	"gophr.v2/user"
+	usersqlite "gophr.v2/user/repository/sqlite"
+	userservice "gophr.v2/user/service"
+	"gophr.v2/util/valueutil"
+	"testing"
+	"time"
+)
+
+// The handlers of the relayed events use the only connection of the
+// SQLite pool too, like the images purged along with their owner.
+func TestRelay_RelayPending_SQLite(t *testing.T) {
+	db, err := sqlitedriver.Open(sqlitedriver.Memory)
+	require.NoError(t, err)
+	defer db.Close()
+	_, err = migration.New(db, migration.SQLiteDialect{}, migration.SQLite()).Up(context.Background())
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	users := usersqlite.New(db)
+	usr := &user.User{UserID: "luffy123", Username: "luffy", Email: "luffy@onepiece.com", Password: "hash",
+		CreatedAt: valueutil.TimePointer(time.Now().UTC())}
+	require.NoError(t, users.Save(ctx, usr))
+	require.NoError(t, users.SoftDelete(ctx, usr.UserID, time.Now().UTC().Add(-time.Hour)))
+
+	images := imagesqlite.New(db)
+	img := &image.Image{ImageID: "img1", UserID: usr.UserID, Name: "luffy.png", Location: "img1.png",
+		CreatedAt: valueutil.TimePointer(time.Now().UTC())}
+	require.NoError(t, images.Save(ctx, img))
+
+	events := bus.New()
+	defer events.Close()
+	imageservice.Subscribe(events, imageservice.New(images, afero.NewMemMapFs(), nil))
+	relay := outbox.NewRelay(db, events, outbox.WithSQLite())
+	// The image created
+	_, err = relay.RelayPending(ctx)
+	require.NoError(t, err)
+
+	n, err := userservice.New(users, userservice.WithDeletionGracePeriod(time.Minute)).Purge(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 1, n)
+
+	n, err = relay.RelayPending(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+	_, err = images.Find(softdelete.Include(ctx), img.ImageID)
+	assert.Equal(t, image.ErrNotFound, err, "the images are purged along with their owner")
+}
@@ -0,0 +1,70 @@
+package event
+
+import (
+	"context"
+	"gophr.v2/log"
+	"sync"
+)
+
+type recordingKey struct{}
+
+// recording holds the events of an operation until it's committed.
+type recording struct {
+	mu     sync.Mutex
+	events []Event
+	stored bool
+}
+
+// Record attaches events to the operation carried by ctx. Repositories
+// able to store them in the same transaction as the change do so, the
+// others leave them to be published by Dispatch once the change is
+// committed.
+func Record(ctx context.Context, events ...Event) context.Context {
+	return context.WithValue(ctx, recordingKey{}, &recording{events: events})
+}
+
+// Recorded returns the events recorded in ctx that were not stored yet.
+func Recorded(ctx context.Context) []Event {
+	rec, ok := ctx.Value(recordingKey{}).(*recording)
+	if !ok {
+		return nil
+	}
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	if rec.stored {
+		return nil
+	}
+	return rec.events
+}
+
+// MarkStored tells that the events recorded in ctx were stored along
+// with the change and will be published from there.
+func MarkStored(ctx context.Context) {
+	rec, ok := ctx.Value(recordingKey{}).(*recording)
+	if !ok {
+		return
+	}
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	rec.stored = true
+}
+
+// Dispatch publishes the events recorded in ctx unless they were
+// stored by the repository. It must be called once the change the
+// events are about is committed.
+func Dispatch(ctx context.Context, publisher Publisher) error {
+	events := Recorded(ctx)
+	if publisher == nil || len(events) == 0 {
+		return nil
+	}
+	return publisher.Publish(ctx, events...)
+}
+
+// DispatchCommitted is Dispatch for the services whose change is
+// committed already: failing to publish its events doesn't undo it,
+// so the failure is only logged.
+func DispatchCommitted(ctx context.Context, publisher Publisher) {
+	if err := Dispatch(ctx, publisher); err != nil {
+		log.FromContext(ctx).WithError(err).Error("failed dispatching events")
+	}
+}
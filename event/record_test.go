@@ -0,0 +1,55 @@
+//+build unit
+
+package event_test
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gophr.v2/event"
+	"gophr.v2/event/mocks"
+	"testing"
+)
+
+type pinged struct{}
+
+func (*pinged) EventName() string { return "test.pinged" }
+
+func TestDispatch(t *testing.T) {
+	t.Run("Publishes recorded events", func(t *testing.T) {
+		e := &pinged{}
+		ctx := event.Record(context.Background(), e)
+
+		publisher := new(mocks.Publisher)
+		publisher.On("Publish", mock.Anything, e).Return(nil).Once()
+
+		assert.NoError(t, event.Dispatch(ctx, publisher))
+		publisher.AssertExpectations(t)
+	})
+
+	t.Run("Skips stored events", func(t *testing.T) {
+		ctx := event.Record(context.Background(), &pinged{})
+		event.MarkStored(ctx)
+
+		publisher := new(mocks.Publisher)
+		assert.Empty(t, event.Recorded(ctx))
+		assert.NoError(t, event.Dispatch(ctx, publisher))
+		publisher.AssertNotCalled(t, "Publish", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Nothing recorded", func(t *testing.T) {
+		assert.NoError(t, event.Dispatch(context.Background(), new(mocks.Publisher)))
+		assert.NoError(t, event.Dispatch(event.Record(context.Background(), &pinged{}), nil))
+	})
+}
+
+func TestNew(t *testing.T) {
+	event.Register("test.pinged", func() event.Event { return new(pinged) })
+
+	e, err := event.New("test.pinged")
+	assert.NoError(t, err)
+	assert.IsType(t, &pinged{}, e)
+
+	_, err = event.New("test.unknown")
+	assert.Equal(t, event.ErrUnknownEvent, err)
+}
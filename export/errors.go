@@ -0,0 +1,11 @@
+package export
+
+import "errors"
+
+var (
+	ErrNotFound    = errors.New("export: item not found")
+	ErrNotReady    = errors.New("export: archive is not ready")
+	ErrInProgress  = errors.New("export: an export is already in progress")
+	ErrInvalidLink = errors.New("export: download link is invalid or expired")
+	ErrTimedOut    = errors.New("export: timed out before the archive was written")
+)
@@ -0,0 +1,18 @@
+package export
+
+import "gophr.v2/event"
+
+const EventRequested = "export.requested"
+
+func init() {
+	event.Register(EventRequested, func() event.Event { return new(Requested) })
+}
+
+// Requested is emitted once an export is requested. The archive is
+// generated when handling it.
+type Requested struct {
+	ExportID string `json:"exportId"`
+	UserID   string `json:"userId"`
+}
+
+func (*Requested) EventName() string { return EventRequested }
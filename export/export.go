@@ -0,0 +1,55 @@
+package export
+
+import (
+	"gophr.v2/util/randutil"
+	"time"
+)
+
+// Status is the progress of an export.
+type Status string
+
+const (
+	StatusPending Status = "pending"
+	StatusReady   Status = "ready"
+	StatusFailed  Status = "failed"
+)
+
+// Export is an archive of the personal data held about the user
+// identified by UserID. It's downloaded through a signed link until
+// ExpiresAt.
+type Export struct {
+	ID          string     `json:"id"`
+	UserID      string     `json:"userId"`
+	Status      Status     `json:"status"`
+	Error       string     `json:"error,omitempty"`
+	Size        int64      `json:"size"`
+	CreatedAt   *time.Time `json:"createdAt,omitempty"`
+	CompletedAt *time.Time `json:"completedAt,omitempty"`
+	ExpiresAt   *time.Time `json:"expiresAt,omitempty"`
+}
+
+func (e *Export) IsPending() bool {
+	return e.Status == StatusPending
+}
+
+func (e *Export) IsReady() bool {
+	return e.Status == StatusReady
+}
+
+// IsExpired tells whether the archive is gone at now.
+func (e *Export) IsExpired(now time.Time) bool {
+	return e.ExpiresAt != nil && !now.Before(*e.ExpiresAt)
+}
+
+// Filename is the name the archive is downloaded as.
+func (e *Export) Filename() string {
+	name := "gophr-export"
+	if e.CreatedAt != nil {
+		name += "-" + e.CreatedAt.Format("2006-01-02")
+	}
+	return name + ".zip"
+}
+
+func GenerateID() string {
+	return randutil.GenerateID("export")
+}
@@ -0,0 +1,23 @@
+package export
+
+import (
+	"context"
+	"time"
+)
+
+//go:generate mockery --name=Repository
+
+type Repository interface {
+	Save(ctx context.Context, e *Export) error
+	Update(ctx context.Context, e *Export) error
+	Find(ctx context.Context, id string) (*Export, error)
+	// FindByUser returns the exports of userID, newest first.
+	FindByUser(ctx context.Context, userID string) ([]*Export, error)
+	// FindExpired returns up to num exports that expired before
+	// the given time.
+	FindExpired(ctx context.Context, before time.Time, num int) ([]*Export, error)
+	// FindPending returns up to num pending exports created before
+	// the given time.
+	FindPending(ctx context.Context, before time.Time, num int) ([]*Export, error)
+	Delete(ctx context.Context, id string) error
+}
@@ -0,0 +1,105 @@
+package memory
+
+import (
+	"context"
+	"gophr.v2/export"
+	"sort"
+	"sync"
+	"time"
+)
+
+var _ export.Repository = (*Repository)(nil)
+
+// New creates an export repository kept in memory. It's meant
+// for tests and single instance deployments.
+func New() *Repository {
+	return &Repository{exports: make(map[string]*export.Export)}
+}
+
+type Repository struct {
+	mu      sync.RWMutex
+	exports map[string]*export.Export
+}
+
+func (r *Repository) Save(ctx context.Context, e *export.Export) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	cpy := *e
+	r.exports[e.ID] = &cpy
+	return nil
+}
+
+func (r *Repository) Update(ctx context.Context, e *export.Export) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.exports[e.ID]; !ok {
+		return export.ErrNotFound
+	}
+	cpy := *e
+	r.exports[e.ID] = &cpy
+	return nil
+}
+
+func (r *Repository) Find(ctx context.Context, id string) (*export.Export, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	e, ok := r.exports[id]
+	if !ok {
+		return nil, export.ErrNotFound
+	}
+	cpy := *e
+	return &cpy, nil
+}
+
+func (r *Repository) FindByUser(ctx context.Context, userID string) ([]*export.Export, error) {
+	exports := r.find(func(e *export.Export) bool {
+		return e.UserID == userID
+	})
+	sort.SliceStable(exports, func(i, j int) bool {
+		return exports[i].CreatedAt.After(*exports[j].CreatedAt)
+	})
+	return exports, nil
+}
+
+func (r *Repository) FindExpired(ctx context.Context, before time.Time, num int) ([]*export.Export, error) {
+	exports := r.find(func(e *export.Export) bool {
+		return e.ExpiresAt != nil && e.ExpiresAt.Before(before)
+	})
+	if len(exports) > num {
+		exports = exports[:num]
+	}
+	return exports, nil
+}
+
+func (r *Repository) FindPending(ctx context.Context, before time.Time, num int) ([]*export.Export, error) {
+	exports := r.find(func(e *export.Export) bool {
+		return e.IsPending() && e.CreatedAt != nil && e.CreatedAt.Before(before)
+	})
+	if len(exports) > num {
+		exports = exports[:num]
+	}
+	return exports, nil
+}
+
+func (r *Repository) find(match func(e *export.Export) bool) []*export.Export {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	exports := make([]*export.Export, 0)
+	for _, e := range r.exports {
+		if match(e) {
+			cpy := *e
+			exports = append(exports, &cpy)
+		}
+	}
+	return exports
+}
+
+func (r *Repository) Delete(ctx context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.exports[id]; !ok {
+		return export.ErrNotFound
+	}
+	delete(r.exports, id)
+	return nil
+}
@@ -0,0 +1,152 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"gophr.v2/event/outbox"
+	"gophr.v2/export"
+	"gophr.v2/log"
+	"time"
+)
+
+var _ export.Repository = (*Repository)(nil)
+
+func New(conn *sql.DB) *Repository {
+	return &Repository{conn: conn}
+}
+
+type Repository struct {
+	conn *sql.DB
+}
+
+const columns = "exportId, userId, status, error, size, created_at, completed_at, expires_at"
+
+func (r *Repository) Save(ctx context.Context, e *export.Export) (err error) {
+	tx, err := r.conn.BeginTx(ctx, nil)
+	if err != nil {
+		return r.checkError(err)
+	}
+	defer func() {
+		if err != nil {
+			if e := tx.Rollback(); e != nil {
+				log.FromContext(ctx).WithError(e).Error("failed rolling back transaction")
+			}
+			return
+		}
+		err = tx.Commit()
+	}()
+
+	query := "INSERT INTO exports(" + columns + ") VALUES(?,?,?,?,?,?,?,?)"
+	_, err = tx.ExecContext(ctx, query,
+		e.ID,
+		e.UserID,
+		e.Status,
+		e.Error,
+		e.Size,
+		e.CreatedAt,
+		e.CompletedAt,
+		e.ExpiresAt,
+	)
+	if err != nil {
+		return r.checkError(err)
+	}
+
+	// The archive is generated when the request is relayed
+	return outbox.Store(ctx, tx)
+}
+
+func (r *Repository) Update(ctx context.Context, e *export.Export) error {
+	query := `UPDATE exports
+						SET status = ?, error = ?, size = ?, completed_at = ?, expires_at = ?
+						WHERE exportId = ?`
+	res, err := r.conn.ExecContext(ctx, query, e.Status, e.Error, e.Size, e.CompletedAt, e.ExpiresAt, e.ID)
+	if err != nil {
+		return r.checkError(err)
+	}
+	return r.checkAffected(res)
+}
+
+func (r *Repository) Find(ctx context.Context, id string) (*export.Export, error) {
+	query := "SELECT " + columns + " FROM exports WHERE exportId = ?"
+	exports, err := r.doQuery(ctx, query, id)
+	if err != nil {
+		return nil, err
+	}
+	if len(exports) == 0 {
+		return nil, export.ErrNotFound
+	}
+	return exports[0], nil
+}
+
+func (r *Repository) FindByUser(ctx context.Context, userID string) ([]*export.Export, error) {
+	query := "SELECT " + columns + " FROM exports WHERE userId = ? ORDER BY created_at DESC"
+	return r.doQuery(ctx, query, userID)
+}
+
+func (r *Repository) FindExpired(ctx context.Context, before time.Time, num int) ([]*export.Export, error) {
+	query := "SELECT " + columns + " FROM exports WHERE expires_at < ? ORDER BY expires_at LIMIT ?"
+	return r.doQuery(ctx, query, before, num)
+}
+
+func (r *Repository) FindPending(ctx context.Context, before time.Time, num int) ([]*export.Export, error) {
+	query := "SELECT " + columns + " FROM exports WHERE status = ? AND created_at < ? ORDER BY created_at LIMIT ?"
+	return r.doQuery(ctx, query, export.StatusPending, before, num)
+}
+
+func (r *Repository) Delete(ctx context.Context, id string) error {
+	query := "DELETE FROM exports WHERE exportId = ?"
+	res, err := r.conn.ExecContext(ctx, query, id)
+	if err != nil {
+		return r.checkError(err)
+	}
+	return r.checkAffected(res)
+}
+
+func (r *Repository) checkAffected(res sql.Result) error {
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return r.checkError(err)
+	}
+	if affected == 0 {
+		return export.ErrNotFound
+	}
+	return nil
+}
+
+func (r *Repository) checkError(err error) error {
+	switch err {
+	case nil:
+		return nil
+	case sql.ErrNoRows:
+		return export.ErrNotFound
+	default:
+		return fmt.Errorf("mysql: unexpected error %w", err)
+	}
+}
+
+func (r *Repository) doQuery(ctx context.Context, query string, args ...interface{}) (exports []*export.Export, err error) {
+	rows, err := r.conn.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, r.checkError(err)
+	}
+	defer func() {
+		if e := rows.Close(); err == nil && e != nil {
+			err = e
+		}
+	}()
+
+	exports = make([]*export.Export, 0)
+	for rows.Next() {
+		var e export.Export
+		err = rows.Scan(&e.ID, &e.UserID, &e.Status, &e.Error, &e.Size, &e.CreatedAt, &e.CompletedAt, &e.ExpiresAt)
+		if err != nil {
+			return nil, r.checkError(err)
+		}
+		exports = append(exports, &e)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, r.checkError(err)
+	}
+	return exports, nil
+}
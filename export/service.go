@@ -0,0 +1,21 @@
+package export
+
+import (
+	"context"
+	"io"
+)
+
+//go:generate mockery --name=Service
+
+type Service interface {
+	// Request schedules the export of the personal data of userID.
+	// The archive is generated in the background.
+	Request(ctx context.Context, userID string) (*Export, error)
+	// List returns the exports of userID, newest first.
+	List(ctx context.Context, userID string) ([]*Export, error)
+	// Link returns the signed download link of e.
+	Link(e *Export) string
+	// Open returns the archive of the export with id when the
+	// signature of its link is valid. The caller closes the archive.
+	Open(ctx context.Context, id string, expires int64, signature string) (*Export, io.ReadCloser, error)
+}
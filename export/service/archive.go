@@ -0,0 +1,193 @@
+package service
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"gophr.v2/image"
+	"gophr.v2/notification"
+	"gophr.v2/user"
+	"io"
+	"path"
+	"time"
+)
+
+// notificationBatchSize is the number of notifications read at once.
+const notificationBatchSize = 100
+
+// sessionRecord is an exported session. The ID and CSRF token are
+// left out since they are credentials.
+type sessionRecord struct {
+	IP        string    `json:"ip,omitempty"`
+	UserAgent string    `json:"userAgent,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+	LastSeen  time.Time `json:"lastSeen"`
+	Expiry    time.Time `json:"expiry"`
+}
+
+// followRecord is the exported follow graph of a user.
+type followRecord struct {
+	Following []string `json:"following"`
+	Followers []string `json:"followers"`
+}
+
+// write writes the zip archive of the personal data of usr to w.
+func (s *Service) write(ctx context.Context, w io.Writer, usr *user.User) error {
+	zw := zip.NewWriter(w)
+
+	profile := *usr
+	profile.Password = ""
+	if err := writeJSON(zw, "profile.json", profile); err != nil {
+		return err
+	}
+
+	sections := []func(ctx context.Context, zw *zip.Writer, userID string) error{
+		s.writeImages,
+		s.writeSessions,
+		s.writeFollows,
+		s.writeNotifications,
+		s.writeWebhooks,
+	}
+	for _, section := range sections {
+		if err := section(ctx, zw, usr.UserID); err != nil {
+			return err
+		}
+	}
+
+	return zw.Close()
+}
+
+func (s *Service) writeImages(ctx context.Context, zw *zip.Writer, userID string) error {
+	if s.images == nil {
+		return nil
+	}
+
+	images := []*image.Image{}
+	for {
+		page, err := s.images.FindAllByUser(ctx, userID, len(images))
+		if err != nil {
+			return err
+		}
+		if len(page) == 0 {
+			break
+		}
+		images = append(images, page...)
+	}
+	if err := writeJSON(zw, "images.json", images); err != nil {
+		return err
+	}
+
+	if s.imageFiles == nil {
+		return nil
+	}
+	for _, img := range images {
+		if err := s.writeImageFile(zw, img); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Service) writeImageFile(zw *zip.Writer, img *image.Image) error {
+	f, err := s.imageFiles.Open(img.Location)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	w, err := zw.Create(path.Join("images", path.Base(img.Location)))
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(w, f)
+	return err
+}
+
+func (s *Service) writeSessions(ctx context.Context, zw *zip.Writer, userID string) error {
+	if s.sessions == nil {
+		return nil
+	}
+
+	sessions, err := s.sessions.FindByUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+	records := make([]sessionRecord, 0, len(sessions))
+	for _, sess := range sessions {
+		records = append(records, sessionRecord{
+			IP:        sess.IP,
+			UserAgent: sess.UserAgent,
+			CreatedAt: sess.CreatedAt,
+			LastSeen:  sess.LastSeen,
+			Expiry:    sess.Expiry,
+		})
+	}
+	return writeJSON(zw, "sessions.json", records)
+}
+
+func (s *Service) writeFollows(ctx context.Context, zw *zip.Writer, userID string) error {
+	if s.follows == nil {
+		return nil
+	}
+
+	following, err := s.follows.Following(ctx, userID)
+	if err != nil {
+		return err
+	}
+	followers, err := s.follows.Followers(ctx, userID)
+	if err != nil {
+		return err
+	}
+	return writeJSON(zw, "follows.json", followRecord{
+		Following: nonNil(following),
+		Followers: nonNil(followers),
+	})
+}
+
+func (s *Service) writeNotifications(ctx context.Context, zw *zip.Writer, userID string) error {
+	if s.notifications == nil {
+		return nil
+	}
+
+	notifications := []*notification.Notification{}
+	for {
+		page, err := s.notifications.List(ctx, userID, len(notifications), notificationBatchSize)
+		if err != nil {
+			return err
+		}
+		notifications = append(notifications, page...)
+		if len(page) < notificationBatchSize {
+			break
+		}
+	}
+	return writeJSON(zw, "notifications.json", notifications)
+}
+
+func (s *Service) writeWebhooks(ctx context.Context, zw *zip.Writer, userID string) error {
+	if s.hooks == nil {
+		return nil
+	}
+
+	hooks, err := s.hooks.ListHooks(ctx, userID)
+	if err != nil {
+		return err
+	}
+	return writeJSON(zw, "webhooks.json", hooks)
+}
+
+func writeJSON(zw *zip.Writer, name string, v interface{}) error {
+	w, err := zw.Create(name)
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+func nonNil(ids []string) []string {
+	if ids == nil {
+		return []string{}
+	}
+	return ids
+}
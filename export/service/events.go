@@ -0,0 +1,38 @@
+package service
+
+import (
+	"context"
+	"gophr.v2/event"
+	"gophr.v2/export"
+	"gophr.v2/user"
+)
+
+// Subscribe generates the requested exports in the background and
+// deletes the exports of the purged users.
+func (s *Service) Subscribe(sub event.Subscriber) {
+	sub.SubscribeAsync(export.EventRequested, s.handleEvent)
+	sub.Subscribe(user.EventPurged, s.handleEvent)
+}
+
+func (s *Service) handleEvent(ctx context.Context, e event.Event) error {
+	switch e := e.(type) {
+	case *export.Requested:
+		return s.Generate(ctx, e.ExportID)
+	case *user.Purged:
+		return s.deleteAllForUser(ctx, e.UserID)
+	}
+	return nil
+}
+
+func (s *Service) deleteAllForUser(ctx context.Context, userID string) error {
+	exports, err := s.repo.FindByUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+	for _, e := range exports {
+		if err := s.delete(ctx, e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,414 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"github.com/spf13/afero"
+	"gophr.v2/config"
+	"gophr.v2/event"
+	"gophr.v2/export"
+	"gophr.v2/follow"
+	"gophr.v2/image"
+	"gophr.v2/log"
+	"gophr.v2/notification"
+	"gophr.v2/session"
+	"gophr.v2/user"
+	"gophr.v2/util/valueutil"
+	"gophr.v2/webhook"
+	"io"
+	"net/url"
+	"os"
+	"strconv"
+	"time"
+)
+
+// Defaults of the service.
+const (
+	DefaultTTL             = 7 * 24 * time.Hour
+	DefaultCleanupInterval = time.Hour
+	DefaultDirectory       = "data/exports"
+	// DefaultPendingTimeout is how long an export stays pending before
+	// it's given up on, like when the process stopped generating it.
+	DefaultPendingTimeout = time.Hour
+)
+
+// batchSize is the number of expired exports deleted at once.
+const batchSize = 100
+
+var _ export.Service = (*Service)(nil)
+
+// Option configures the optional collaborators of the Service. The
+// data of the collaborators that aren't set is left out of the archives.
+type Option func(s *Service)
+
+// WithEvents publishes the export events to publisher.
+func WithEvents(publisher event.Publisher) Option {
+	return func(s *Service) {
+		s.events = publisher
+	}
+}
+
+// WithImages exports the images of the users along with their files
+// found in files by their location.
+func WithImages(images image.Service, files afero.Fs) Option {
+	return func(s *Service) {
+		s.images = images
+		s.imageFiles = files
+	}
+}
+
+// WithSessions exports the sessions of the users.
+func WithSessions(sessions session.UserFinder) Option {
+	return func(s *Service) {
+		s.sessions = sessions
+	}
+}
+
+// WithFollows exports the follow graph of the users.
+func WithFollows(follows follow.Service) Option {
+	return func(s *Service) {
+		s.follows = follows
+	}
+}
+
+// WithNotifications exports the notifications of the users and
+// notifies them when their archive is ready.
+func WithNotifications(notifications notification.Service) Option {
+	return func(s *Service) {
+		s.notifications = notifications
+	}
+}
+
+// WithWebhooks exports the webhooks of the users.
+func WithWebhooks(hooks webhook.Service) Option {
+	return func(s *Service) {
+		s.hooks = hooks
+	}
+}
+
+// WithTTL replaces the DefaultTTL.
+func WithTTL(ttl time.Duration) Option {
+	return func(s *Service) {
+		s.ttl = ttl
+	}
+}
+
+// WithPendingTimeout replaces the DefaultPendingTimeout.
+func WithPendingTimeout(timeout time.Duration) Option {
+	return func(s *Service) {
+		s.pendingTimeout = timeout
+	}
+}
+
+// OptionsFromConfig creates the options of the service from conf.
+// Zero values fall back to the defaults.
+func OptionsFromConfig(conf config.Export) []Option {
+	ttl := DefaultTTL
+	if conf.TTL > 0 {
+		ttl = conf.TTL
+	}
+	return []Option{WithTTL(ttl)}
+}
+
+// NewStore returns the store of the archives in the directory of
+// conf on fs, creating it when missing.
+func NewStore(fs afero.Fs, conf config.Export) (afero.Fs, error) {
+	dir := DefaultDirectory
+	if conf.Directory != "" {
+		dir = conf.Directory
+	}
+	if err := fs.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+	return afero.NewBasePathFs(fs, dir), nil
+}
+
+// New creates the export service. The archives are written to store
+// and their download links are signed with secret.
+func New(repo export.Repository, users user.GetterByUserID, store afero.Fs, secret string, opts ...Option) *Service {
+	s := &Service{
+		repo:   repo,
+		users:  users,
+		store:  store,
+		secret: secret,
+		ttl:    DefaultTTL,
+		now:    time.Now,
+
+		pendingTimeout: DefaultPendingTimeout,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+type Service struct {
+	repo          export.Repository
+	users         user.GetterByUserID
+	store         afero.Fs
+	secret        string
+	ttl           time.Duration
+	events        event.Publisher
+	images        image.Service
+	imageFiles    afero.Fs
+	sessions      session.UserFinder
+	follows       follow.Service
+	notifications notification.Service
+	hooks         webhook.Service
+	now           func() time.Time
+
+	pendingTimeout time.Duration
+}
+
+func (s *Service) Request(ctx context.Context, userID string) (*export.Export, error) {
+	e, err := s.newExport(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx = event.Record(ctx, &export.Requested{ExportID: e.ID, UserID: userID})
+	if err := s.repo.Save(ctx, e); err != nil {
+		return nil, err
+	}
+	if err := event.Dispatch(ctx, s.events); err != nil {
+		log.FromContext(ctx).WithError(err).Error("failed dispatching export events")
+	}
+	return e, nil
+}
+
+// Export generates the export of the personal data of userID right
+// away rather than in the background.
+func (s *Service) Export(ctx context.Context, userID string) (*export.Export, error) {
+	e, err := s.newExport(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.Save(ctx, e); err != nil {
+		return nil, err
+	}
+	if err := s.Generate(ctx, e.ID); err != nil {
+		return nil, err
+	}
+	return s.repo.Find(ctx, e.ID)
+}
+
+// newExport creates a pending export of userID. Users have a single
+// export in progress at a time, the stale ones aside.
+func (s *Service) newExport(ctx context.Context, userID string) (*export.Export, error) {
+	if _, err := s.users.GetByUserID(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	exports, err := s.repo.FindByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range exports {
+		if e.IsPending() && !s.isStale(e) {
+			return nil, export.ErrInProgress
+		}
+	}
+
+	return &export.Export{
+		ID:        export.GenerateID(),
+		UserID:    userID,
+		Status:    export.StatusPending,
+		CreatedAt: valueutil.TimePointer(s.now().UTC()),
+	}, nil
+}
+
+func (s *Service) List(ctx context.Context, userID string) ([]*export.Export, error) {
+	return s.repo.FindByUser(ctx, userID)
+}
+
+// Link returns the download link of e. It expires along with the
+// archive and can be used without signing in.
+func (s *Service) Link(e *export.Export) string {
+	if e.ExpiresAt == nil {
+		return ""
+	}
+	expires := e.ExpiresAt.Unix()
+	query := url.Values{}
+	query.Set("expires", strconv.FormatInt(expires, 10))
+	query.Set("signature", export.Sign(s.secret, e.ID, expires))
+	return "/exports/" + url.PathEscape(e.ID) + "?" + query.Encode()
+}
+
+func (s *Service) Open(ctx context.Context, id string, expires int64, signature string) (*export.Export, io.ReadCloser, error) {
+	if !export.Verify(s.secret, id, expires, signature) || !s.now().Before(time.Unix(expires, 0)) {
+		return nil, nil, export.ErrInvalidLink
+	}
+
+	return s.Archive(ctx, id)
+}
+
+// Archive returns the archive of the ready export with id without
+// checking any link. The caller closes the archive.
+func (s *Service) Archive(ctx context.Context, id string) (*export.Export, io.ReadCloser, error) {
+	e, err := s.repo.Find(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !e.IsReady() {
+		return nil, nil, export.ErrNotReady
+	}
+
+	f, err := s.store.Open(archiveName(e.ID))
+	if err != nil {
+		return nil, nil, err
+	}
+	return e, f, nil
+}
+
+// Generate writes the archive of the pending export with id. The
+// export is marked as failed when the archive can't be written.
+func (s *Service) Generate(ctx context.Context, id string) error {
+	e, err := s.repo.Find(ctx, id)
+	if err != nil {
+		return err
+	}
+	// The request may be handled more than once
+	if !e.IsPending() {
+		return nil
+	}
+
+	size, err := s.writeArchive(ctx, e)
+	if err != nil {
+		log.FromContext(ctx).WithError(err).WithField("exportId", e.ID).Error("export: failed generating export")
+		e.Status = export.StatusFailed
+		e.Error = err.Error()
+	} else {
+		e.Status = export.StatusReady
+		e.Size = size
+	}
+
+	now := s.now().UTC()
+	e.CompletedAt = valueutil.TimePointer(now)
+	e.ExpiresAt = valueutil.TimePointer(now.Add(s.ttl))
+	if err := s.repo.Update(ctx, e); err != nil {
+		return err
+	}
+
+	if e.IsReady() && s.notifications != nil {
+		err := s.notifications.Notify(ctx, &notification.Notification{
+			UserID: e.UserID,
+			Type:   notification.TypeExport,
+		})
+		if err != nil {
+			log.FromContext(ctx).WithError(err).Error("failed notifying ready export")
+		}
+	}
+	return nil
+}
+
+// writeArchive writes the archive of e to the store and returns
+// its size.
+func (s *Service) writeArchive(ctx context.Context, e *export.Export) (size int64, err error) {
+	usr, err := s.users.GetByUserID(ctx, e.UserID)
+	if err != nil {
+		return 0, err
+	}
+
+	name := archiveName(e.ID)
+	f, err := s.store.Create(name)
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		if e := f.Close(); err == nil && e != nil {
+			err = e
+		}
+		// Partial archives are useless
+		if err != nil {
+			_ = s.store.Remove(name)
+		}
+	}()
+
+	if err := s.write(ctx, f, usr); err != nil {
+		return 0, fmt.Errorf("export: writing archive: %w", err)
+	}
+
+	info, err := f.Stat()
+	if err != nil {
+		return 0, err
+	}
+	return info.Size(), nil
+}
+
+// Run fails the stale exports and deletes the expired ones every
+// interval until ctx is done.
+func (s *Service) Run(ctx context.Context, interval time.Duration) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if _, err := s.FailStale(ctx); err != nil {
+			log.FromContext(ctx).WithError(err).Error("export: failed failing stale exports")
+		}
+		if _, err := s.DeleteExpired(ctx); err != nil {
+			log.FromContext(ctx).WithError(err).Error("export: failed deleting expired exports")
+		}
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// FailStale marks a batch of the exports pending for longer than the
+// pending timeout as failed and returns how many were marked. Their
+// generation was lost, like when the process stopped before it.
+func (s *Service) FailStale(ctx context.Context) (int, error) {
+	now := s.now().UTC()
+	exports, err := s.repo.FindPending(ctx, now.Add(-s.pendingTimeout), batchSize)
+	if err != nil {
+		return 0, err
+	}
+	for i, e := range exports {
+		e.Status = export.StatusFailed
+		e.Error = export.ErrTimedOut.Error()
+		e.CompletedAt = valueutil.TimePointer(now)
+		e.ExpiresAt = valueutil.TimePointer(now.Add(s.ttl))
+		if err := s.repo.Update(ctx, e); err != nil && err != export.ErrNotFound {
+			return i, err
+		}
+	}
+	return len(exports), nil
+}
+
+// isStale tells whether the pending export e is pending for longer
+// than the pending timeout.
+func (s *Service) isStale(e *export.Export) bool {
+	return e.CreatedAt != nil && e.CreatedAt.Before(s.now().Add(-s.pendingTimeout))
+}
+
+// DeleteExpired deletes a batch of expired exports along with their
+// archive and returns how many were deleted.
+func (s *Service) DeleteExpired(ctx context.Context) (int, error) {
+	exports, err := s.repo.FindExpired(ctx, s.now().UTC(), batchSize)
+	if err != nil {
+		return 0, err
+	}
+	for i, e := range exports {
+		if err := s.delete(ctx, e); err != nil {
+			return i, err
+		}
+	}
+	return len(exports), nil
+}
+
+func (s *Service) delete(ctx context.Context, e *export.Export) error {
+	err := s.store.Remove(archiveName(e.ID))
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	err = s.repo.Delete(ctx, e.ID)
+	if err != nil && err != export.ErrNotFound {
+		return err
+	}
+	return nil
+}
+
+func archiveName(id string) string {
+	return id + ".zip"
+}
@@ -0,0 +1,242 @@
+//+build unit
+
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gophr.v2/event/bus"
+	"gophr.v2/export"
+	"gophr.v2/export/repository/memory"
+	"gophr.v2/image"
+	imagemocks "gophr.v2/image/mocks"
+	"gophr.v2/notification"
+	notificationrepo "gophr.v2/notification/repository/memory"
+	notificationservice "gophr.v2/notification/service"
+	"gophr.v2/session"
+	"gophr.v2/user"
+	"io/ioutil"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+)
+
+var dummyCtx = context.Background()
+
+type users map[string]*user.User
+
+func (u users) GetByUserID(ctx context.Context, userID string) (*user.User, error) {
+	usr, ok := u[userID]
+	if !ok {
+		return nil, user.ErrNotFound
+	}
+	return usr, nil
+}
+
+type sessions []*session.Session
+
+func (s sessions) FindByUser(ctx context.Context, userID string) ([]*session.Session, error) {
+	return s, nil
+}
+
+var luffy = &user.User{UserID: "luffy", Username: "luffy", Email: "luffy@gophr.com", Password: "$2a$10$hash"}
+
+func newTestService(now *time.Time, opts ...Option) (*Service, afero.Fs) {
+	store := afero.NewMemMapFs()
+	svc := New(memory.New(), users{"luffy": luffy}, store, "s3cr3t", opts...)
+	svc.now = func() time.Time { return *now }
+	return svc, store
+}
+
+// readArchive returns the files of the archive of e by name.
+func readArchive(t *testing.T, store afero.Fs, e *export.Export) map[string][]byte {
+	content, err := afero.ReadFile(store, archiveName(e.ID))
+	require.NoError(t, err)
+	zr, err := zip.NewReader(bytes.NewReader(content), int64(len(content)))
+	require.NoError(t, err)
+
+	files := map[string][]byte{}
+	for _, f := range zr.File {
+		r, err := f.Open()
+		require.NoError(t, err)
+		files[f.Name], err = ioutil.ReadAll(r)
+		require.NoError(t, err)
+		r.Close()
+	}
+	return files
+}
+
+func TestService_Export(t *testing.T) {
+	now := time.Now()
+	images := new(imagemocks.Service)
+	images.On("FindAllByUser", mock.Anything, "luffy", 0).Return([]*image.Image{{ImageID: "img1", UserID: "luffy", Location: "img1.png"}}, nil)
+	images.On("FindAllByUser", mock.Anything, "luffy", 1).Return([]*image.Image{}, nil)
+	imageFiles := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(imageFiles, "img1.png", []byte("png"), 0644))
+
+	svc, store := newTestService(&now,
+		WithImages(images, imageFiles),
+		WithSessions(sessions{{ID: "session1", UserID: "luffy", IP: "127.0.0.1", CSRFToken: "token"}}))
+
+	e, err := svc.Export(dummyCtx, "luffy")
+	require.NoError(t, err)
+	assert.True(t, e.IsReady())
+	assert.WithinDuration(t, now.Add(DefaultTTL), *e.ExpiresAt, time.Second)
+
+	files := readArchive(t, store, e)
+	assert.Equal(t, []byte("png"), files["images/img1.png"])
+	assert.Contains(t, string(files["images.json"]), `"img1"`)
+
+	var profile user.User
+	require.NoError(t, json.Unmarshal(files["profile.json"], &profile))
+	assert.Equal(t, "luffy@gophr.com", profile.Email)
+	assert.Empty(t, profile.Password)
+
+	assert.Contains(t, string(files["sessions.json"]), "127.0.0.1")
+	assert.NotContains(t, string(files["sessions.json"]), "session1")
+	assert.NotContains(t, string(files["sessions.json"]), "token")
+
+	t.Run("Missing image file fails the export", func(t *testing.T) {
+		require.NoError(t, imageFiles.Remove("img1.png"))
+		e, err := svc.Export(dummyCtx, "luffy")
+		require.NoError(t, err)
+		assert.Equal(t, export.StatusFailed, e.Status)
+		assert.NotEmpty(t, e.Error)
+
+		_, err = store.Stat(archiveName(e.ID))
+		assert.Error(t, err)
+	})
+
+	t.Run("Unknown user", func(t *testing.T) {
+		_, err := svc.Export(dummyCtx, "zoro")
+		assert.Equal(t, user.ErrNotFound, err)
+	})
+}
+
+func TestService_Request(t *testing.T) {
+	now := time.Now()
+	notifications := notificationservice.New(notificationrepo.New())
+	events := bus.New()
+	svc, store := newTestService(&now, WithEvents(events), WithNotifications(notifications))
+	svc.Subscribe(events)
+
+	e, err := svc.Request(dummyCtx, "luffy")
+	require.NoError(t, err)
+	assert.True(t, e.IsPending())
+
+	// Closing the bus waits for the export to be generated
+	events.Close()
+
+	got, err := svc.repo.Find(dummyCtx, e.ID)
+	require.NoError(t, err)
+	assert.True(t, got.IsReady())
+	assert.Contains(t, readArchive(t, store, got), "profile.json")
+
+	list, err := notifications.List(dummyCtx, "luffy", 0, 10)
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	assert.Equal(t, notification.TypeExport, list[0].Type)
+
+	t.Run("In progress", func(t *testing.T) {
+		svc, _ := newTestService(&now)
+		_, err := svc.Request(dummyCtx, "luffy")
+		require.NoError(t, err)
+
+		_, err = svc.Request(dummyCtx, "luffy")
+		assert.Equal(t, export.ErrInProgress, err)
+	})
+}
+
+func TestService_Open(t *testing.T) {
+	now := time.Now()
+	svc, _ := newTestService(&now)
+	e, err := svc.Export(dummyCtx, "luffy")
+	require.NoError(t, err)
+
+	link, err := url.Parse(svc.Link(e))
+	require.NoError(t, err)
+	assert.Equal(t, "/exports/"+e.ID, link.Path)
+	expires, err := strconv.ParseInt(link.Query().Get("expires"), 10, 64)
+	require.NoError(t, err)
+	signature := link.Query().Get("signature")
+
+	got, archive, err := svc.Open(dummyCtx, e.ID, expires, signature)
+	require.NoError(t, err)
+	defer archive.Close()
+	assert.Equal(t, e.ID, got.ID)
+
+	t.Run("Invalid signature", func(t *testing.T) {
+		_, _, err := svc.Open(dummyCtx, e.ID, expires+1, signature)
+		assert.Equal(t, export.ErrInvalidLink, err)
+	})
+
+	t.Run("Expired", func(t *testing.T) {
+		later := now.Add(DefaultTTL + time.Second)
+		svc.now = func() time.Time { return later }
+		_, _, err := svc.Open(dummyCtx, e.ID, expires, signature)
+		assert.Equal(t, export.ErrInvalidLink, err)
+	})
+}
+
+func TestService_DeleteExpired(t *testing.T) {
+	now := time.Now()
+	svc, store := newTestService(&now)
+	e, err := svc.Export(dummyCtx, "luffy")
+	require.NoError(t, err)
+
+	n, err := svc.DeleteExpired(dummyCtx)
+	require.NoError(t, err)
+	assert.Equal(t, 0, n)
+
+	now = now.Add(DefaultTTL + time.Second)
+	n, err = svc.DeleteExpired(dummyCtx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+
+	_, err = svc.repo.Find(dummyCtx, e.ID)
+	assert.Equal(t, export.ErrNotFound, err)
+	_, err = store.Stat(archiveName(e.ID))
+	assert.Error(t, err)
+}
+
+func TestService_FailStale(t *testing.T) {
+	now := time.Now()
+	svc, _ := newTestService(&now)
+	// The request is never handled
+	e, err := svc.Request(dummyCtx, "luffy")
+	require.NoError(t, err)
+	_, err = svc.Request(dummyCtx, "luffy")
+	assert.Equal(t, export.ErrInProgress, err)
+
+	n, err := svc.FailStale(dummyCtx)
+	require.NoError(t, err)
+	assert.Equal(t, 0, n)
+
+	// A stale export doesn't block the new requests
+	now = now.Add(DefaultPendingTimeout + time.Second)
+	retried, err := svc.Request(dummyCtx, "luffy")
+	require.NoError(t, err)
+	require.NoError(t, svc.Generate(dummyCtx, retried.ID))
+
+	n, err = svc.FailStale(dummyCtx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+	got, err := svc.repo.Find(dummyCtx, e.ID)
+	require.NoError(t, err)
+	assert.Equal(t, export.StatusFailed, got.Status)
+	assert.Equal(t, export.ErrTimedOut.Error(), got.Error)
+	assert.NotNil(t, got.ExpiresAt, "the failed exports are deleted once expired")
+
+	// Handling the request late leaves it failed
+	require.NoError(t, svc.Generate(dummyCtx, e.ID))
+	got, err = svc.repo.Find(dummyCtx, e.ID)
+	require.NoError(t, err)
+	assert.Equal(t, export.StatusFailed, got.Status)
+}
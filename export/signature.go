@@ -0,0 +1,23 @@
+package export
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+)
+
+// Sign returns the signature of the download link of the export with
+// id expiring at the given unix time. It's the hex encoded
+// HMAC-SHA256 of both keyed with secret.
+func Sign(secret, id string, expires int64) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(id + "." + strconv.FormatInt(expires, 10)))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify tells whether signature is the valid signature of the
+// download link of the export with id expiring at expires.
+func Verify(secret, id string, expires int64, signature string) bool {
+	return hmac.Equal([]byte(Sign(secret, id, expires)), []byte(signature))
+}
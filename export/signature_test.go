@@ -0,0 +1,25 @@
+//+build unit
+
+package export
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestSign(t *testing.T) {
+	expires := time.Now().Add(time.Hour).Unix()
+	signature := Sign("s3cr3t", "export1", expires)
+
+	assert.True(t, Verify("s3cr3t", "export1", expires, signature))
+	assert.False(t, Verify("other", "export1", expires, signature))
+	assert.False(t, Verify("s3cr3t", "export2", expires, signature))
+	assert.False(t, Verify("s3cr3t", "export1", expires+1, signature))
+}
+
+func TestExport_Filename(t *testing.T) {
+	createdAt := time.Date(2020, 7, 4, 12, 0, 0, 0, time.UTC)
+	assert.Equal(t, "gophr-export-2020-07-04.zip", (&Export{CreatedAt: &createdAt}).Filename())
+	assert.Equal(t, "gophr-export.zip", (&Export{}).Filename())
+}
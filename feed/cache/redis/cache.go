@@ -0,0 +1,44 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/go-redis/redis/v8"
+	"gophr.v2/feed"
+	"time"
+)
+
+var _ feed.Cache = (*Cache)(nil)
+
+// New creates a feed cache stored in Redis.
+func New(client *redis.Client) *Cache {
+	return &Cache{client: client}
+}
+
+type Cache struct {
+	client *redis.Client
+}
+
+func (c *Cache) Get(ctx context.Context, key string) (*feed.Page, error) {
+	payload, err := c.client.Get(ctx, key).Bytes()
+	if err != nil {
+		if err == redis.Nil {
+			return nil, feed.ErrCacheMiss
+		}
+		return nil, err
+	}
+
+	page := new(feed.Page)
+	if err := json.Unmarshal(payload, page); err != nil {
+		return nil, err
+	}
+	return page, nil
+}
+
+func (c *Cache) Set(ctx context.Context, key string, page *feed.Page, ttl time.Duration) error {
+	payload, err := json.Marshal(page)
+	if err != nil {
+		return err
+	}
+	return c.client.Set(ctx, key, payload, ttl).Err()
+}
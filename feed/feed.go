@@ -0,0 +1,33 @@
+package feed
+
+import (
+	"context"
+	"errors"
+	"gophr.v2/image"
+	"time"
+)
+
+var ErrCacheMiss = errors.New("feed: cache miss")
+
+// Page is a page of a feed. NextCursor is empty on the last page.
+type Page struct {
+	Images     []*image.Image `json:"images"`
+	NextCursor string         `json:"nextCursor,omitempty"`
+}
+
+//go:generate mockery --name=Service
+
+type Service interface {
+	// Following returns the images of the users followed by
+	// userID, newest first, starting after cursor.
+	Following(ctx context.Context, userID, cursor string, num int) (*Page, error)
+}
+
+//go:generate mockery --name=Cache
+
+// Cache keeps recently built feed pages.
+type Cache interface {
+	// Get returns ErrCacheMiss when there is no page under key.
+	Get(ctx context.Context, key string) (*Page, error)
+	Set(ctx context.Context, key string, page *Page, ttl time.Duration) error
+}
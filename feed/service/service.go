@@ -0,0 +1,101 @@
+package service
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"gophr.v2/feed"
+	"gophr.v2/follow"
+	"gophr.v2/image"
+	"gophr.v2/log"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var _ feed.Service = (*Service)(nil)
+
+// DefaultPageSize is the number of images per page when none is given.
+const DefaultPageSize = image.PageSize
+
+// Option configures the Service.
+type Option func(s *Service)
+
+// WithCache keeps the built pages in cache for ttl.
+func WithCache(cache feed.Cache, ttl time.Duration) Option {
+	return func(s *Service) {
+		s.cache = cache
+		s.ttl = ttl
+	}
+}
+
+// New creates the feed service. Feeds are built on read by merging
+// the images of the followed users.
+func New(follows follow.Service, images image.Service, opts ...Option) *Service {
+	s := &Service{follows: follows, images: images}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+type Service struct {
+	follows follow.Service
+	images  image.Service
+	cache   feed.Cache
+	ttl     time.Duration
+}
+
+func (s *Service) Following(ctx context.Context, userID, cursor string, num int) (*feed.Page, error) {
+	if num <= 0 {
+		num = DefaultPageSize
+	}
+
+	following, err := s.follows.Following(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if len(following) == 0 {
+		return &feed.Page{Images: []*image.Image{}}, nil
+	}
+
+	if s.cache == nil {
+		return s.build(ctx, following, cursor, num)
+	}
+
+	key := cacheKey(userID, following, cursor, num)
+	page, err := s.cache.Get(ctx, key)
+	if err == nil {
+		return page, nil
+	}
+	if err != feed.ErrCacheMiss {
+		log.FromContext(ctx).WithError(err).Error("failed reading cached feed")
+	}
+
+	page, err = s.build(ctx, following, cursor, num)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.cache.Set(ctx, key, page, s.ttl); err != nil {
+		log.FromContext(ctx).WithError(err).Error("failed caching feed")
+	}
+	return page, nil
+}
+
+func (s *Service) build(ctx context.Context, following []string, cursor string, num int) (*feed.Page, error) {
+	images, next, err := s.images.FindAllByUsers(ctx, following, cursor, num)
+	if err != nil {
+		return nil, err
+	}
+	return &feed.Page{Images: images, NextCursor: next}, nil
+}
+
+// cacheKey includes a digest of the followed users so following or
+// unfollowing someone never serves a stale page.
+func cacheKey(userID string, following []string, cursor string, num int) string {
+	ids := append([]string(nil), following...)
+	sort.Strings(ids)
+	sum := sha1.Sum([]byte(strings.Join(ids, ",")))
+	return strings.Join([]string{"feed", userID, hex.EncodeToString(sum[:8]), strconv.Itoa(num), cursor}, ":")
+}
@@ -0,0 +1,79 @@
+//+build unit
+
+package service
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gophr.v2/feed"
+	feedmocks "gophr.v2/feed/mocks"
+	followmocks "gophr.v2/follow/mocks"
+	"gophr.v2/image"
+	imagemocks "gophr.v2/image/mocks"
+	"testing"
+	"time"
+)
+
+func TestService_Following(t *testing.T) {
+	images := []*image.Image{{ImageID: "image1", UserID: "zoro"}}
+
+	t.Run("Built On Read", func(t *testing.T) {
+		follows := new(followmocks.Service)
+		follows.On("Following", mock.Anything, "luffy").Return([]string{"zoro", "nami"}, nil).Once()
+		imageSvc := new(imagemocks.Service)
+		imageSvc.On("FindAllByUsers", mock.Anything, []string{"zoro", "nami"}, "cursor", DefaultPageSize).
+			Return(images, "next", nil).Once()
+
+		page, err := New(follows, imageSvc).Following(context.Background(), "luffy", "cursor", 0)
+		assert.NoError(t, err)
+		assert.Equal(t, &feed.Page{Images: images, NextCursor: "next"}, page)
+		imageSvc.AssertExpectations(t)
+	})
+
+	t.Run("Following Nobody", func(t *testing.T) {
+		follows := new(followmocks.Service)
+		follows.On("Following", mock.Anything, "luffy").Return([]string{}, nil).Once()
+		imageSvc := new(imagemocks.Service)
+
+		page, err := New(follows, imageSvc).Following(context.Background(), "luffy", "", 10)
+		assert.NoError(t, err)
+		assert.Empty(t, page.Images)
+		imageSvc.AssertExpectations(t)
+	})
+
+	t.Run("Cache Miss", func(t *testing.T) {
+		follows := new(followmocks.Service)
+		follows.On("Following", mock.Anything, "luffy").Return([]string{"zoro"}, nil).Once()
+		imageSvc := new(imagemocks.Service)
+		imageSvc.On("FindAllByUsers", mock.Anything, []string{"zoro"}, "", 10).Return(images, "", nil).Once()
+		cache := new(feedmocks.Cache)
+		cache.On("Get", mock.Anything, mock.AnythingOfType("string")).Return(nil, feed.ErrCacheMiss).Once()
+		cache.On("Set", mock.Anything, mock.AnythingOfType("string"), &feed.Page{Images: images}, time.Minute).Return(nil).Once()
+
+		page, err := New(follows, imageSvc, WithCache(cache, time.Minute)).Following(context.Background(), "luffy", "", 10)
+		assert.NoError(t, err)
+		assert.Equal(t, images, page.Images)
+		cache.AssertExpectations(t)
+	})
+
+	t.Run("Cache Hit", func(t *testing.T) {
+		follows := new(followmocks.Service)
+		follows.On("Following", mock.Anything, "luffy").Return([]string{"zoro"}, nil).Once()
+		imageSvc := new(imagemocks.Service)
+		cache := new(feedmocks.Cache)
+		cache.On("Get", mock.Anything, mock.AnythingOfType("string")).Return(&feed.Page{Images: images}, nil).Once()
+
+		page, err := New(follows, imageSvc, WithCache(cache, time.Minute)).Following(context.Background(), "luffy", "", 10)
+		assert.NoError(t, err)
+		assert.Equal(t, images, page.Images)
+		imageSvc.AssertExpectations(t)
+	})
+}
+
+func TestCacheKey(t *testing.T) {
+	key := cacheKey("luffy", []string{"zoro", "nami"}, "", 10)
+	assert.Equal(t, key, cacheKey("luffy", []string{"nami", "zoro"}, "", 10))
+	assert.NotEqual(t, key, cacheKey("luffy", []string{"zoro"}, "", 10))
+	assert.NotEqual(t, key, cacheKey("luffy", []string{"zoro", "nami"}, "cursor", 10))
+}
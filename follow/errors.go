@@ -0,0 +1,7 @@
+package follow
+
+import "errors"
+
+var (
+	ErrSelfFollow = errors.New("follow: users can't follow themselves")
+)
@@ -0,0 +1,18 @@
+package follow
+
+import "time"
+
+// Follow is the edge of the follow graph from the follower to
+// the followee.
+type Follow struct {
+	FollowerID string     `json:"followerId,omitempty"`
+	FolloweeID string     `json:"followeeId,omitempty"`
+	CreatedAt  *time.Time `json:"createdAt,omitempty"`
+}
+
+// Counts holds the number of followers of a user and the number
+// of users the user follows.
+type Counts struct {
+	Followers int `json:"followers"`
+	Following int `json:"following"`
+}
@@ -0,0 +1,19 @@
+package follow
+
+import "context"
+
+//go:generate mockery --name=Repository
+
+type Repository interface {
+	// Save stores f. Saving an existing edge is a no-op.
+	Save(ctx context.Context, f *Follow) error
+	Delete(ctx context.Context, followerID, followeeID string) error
+	Exists(ctx context.Context, followerID, followeeID string) (bool, error)
+	// FindFollowing returns the IDs of the users followed by userID.
+	FindFollowing(ctx context.Context, userID string) ([]string, error)
+	// FindFollowers returns the IDs of the users following userID.
+	FindFollowers(ctx context.Context, userID string) ([]string, error)
+	Count(ctx context.Context, userID string) (*Counts, error)
+	// DeleteAllForUser removes the edges from and to userID.
+	DeleteAllForUser(ctx context.Context, userID string) error
+}
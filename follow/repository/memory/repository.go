@@ -0,0 +1,107 @@
+package memory
+
+import (
+	"context"
+	"gophr.v2/follow"
+	"sync"
+)
+
+var _ follow.Repository = (*Repository)(nil)
+
+// New creates a follow repository kept in memory. It's meant
+// for tests and single instance deployments.
+func New() *Repository {
+	return &Repository{
+		following: make(map[string]map[string]*follow.Follow),
+		followers: make(map[string]map[string]*follow.Follow),
+	}
+}
+
+type Repository struct {
+	mu        sync.RWMutex
+	following map[string]map[string]*follow.Follow
+	followers map[string]map[string]*follow.Follow
+}
+
+func (r *Repository) Save(ctx context.Context, f *follow.Follow) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.following[f.FollowerID][f.FolloweeID]; ok {
+		return nil
+	}
+	cpy := *f
+	add(r.following, f.FollowerID, f.FolloweeID, &cpy)
+	add(r.followers, f.FolloweeID, f.FollowerID, &cpy)
+	return nil
+}
+
+func (r *Repository) Delete(ctx context.Context, followerID, followeeID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.following[followerID], followeeID)
+	delete(r.followers[followeeID], followerID)
+	return nil
+}
+
+func (r *Repository) DeleteAllForUser(ctx context.Context, userID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for followeeID := range r.following[userID] {
+		delete(r.followers[followeeID], userID)
+	}
+	for followerID := range r.followers[userID] {
+		delete(r.following[followerID], userID)
+	}
+	delete(r.following, userID)
+	delete(r.followers, userID)
+	return nil
+}
+
+func (r *Repository) Exists(ctx context.Context, followerID, followeeID string) (bool, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	_, ok := r.following[followerID][followeeID]
+	return ok, nil
+}
+
+func (r *Repository) FindFollowing(ctx context.Context, userID string) ([]string, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return keys(r.following[userID]), nil
+}
+
+func (r *Repository) FindFollowers(ctx context.Context, userID string) ([]string, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return keys(r.followers[userID]), nil
+}
+
+func (r *Repository) Count(ctx context.Context, userID string) (*follow.Counts, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return &follow.Counts{
+		Followers: len(r.followers[userID]),
+		Following: len(r.following[userID]),
+	}, nil
+}
+
+func add(edges map[string]map[string]*follow.Follow, from, to string, f *follow.Follow) {
+	m, ok := edges[from]
+	if !ok {
+		m = make(map[string]*follow.Follow)
+		edges[from] = m
+	}
+	m[to] = f
+}
+
+func keys(m map[string]*follow.Follow) []string {
+	ids := make([]string, 0, len(m))
+	for id := range m {
+		ids = append(ids, id)
+	}
+	return ids
+}
@@ -0,0 +1,58 @@
+//+build unit
+
+package memory
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gophr.v2/follow"
+	"testing"
+)
+
+func TestRepository(t *testing.T) {
+	ctx := context.Background()
+	repo := New()
+
+	require.NoError(t, repo.Save(ctx, &follow.Follow{FollowerID: "luffy", FolloweeID: "zoro"}))
+	require.NoError(t, repo.Save(ctx, &follow.Follow{FollowerID: "luffy", FolloweeID: "nami"}))
+	require.NoError(t, repo.Save(ctx, &follow.Follow{FollowerID: "nami", FolloweeID: "zoro"}))
+
+	t.Run("Saving Twice Is A No-op", func(t *testing.T) {
+		require.NoError(t, repo.Save(ctx, &follow.Follow{FollowerID: "luffy", FolloweeID: "zoro"}))
+		counts, err := repo.Count(ctx, "zoro")
+		require.NoError(t, err)
+		assert.Equal(t, &follow.Counts{Followers: 2, Following: 0}, counts)
+	})
+
+	t.Run("Following And Followers", func(t *testing.T) {
+		following, err := repo.FindFollowing(ctx, "luffy")
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{"zoro", "nami"}, following)
+
+		followers, err := repo.FindFollowers(ctx, "zoro")
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{"luffy", "nami"}, followers)
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		require.NoError(t, repo.Delete(ctx, "luffy", "zoro"))
+		exists, err := repo.Exists(ctx, "luffy", "zoro")
+		require.NoError(t, err)
+		assert.False(t, exists)
+
+		counts, err := repo.Count(ctx, "luffy")
+		require.NoError(t, err)
+		assert.Equal(t, &follow.Counts{Followers: 0, Following: 1}, counts)
+	})
+	t.Run("Delete All For User", func(t *testing.T) {
+		require.NoError(t, repo.Save(ctx, &follow.Follow{FollowerID: "zoro", FolloweeID: "nami"}))
+		require.NoError(t, repo.DeleteAllForUser(ctx, "nami"))
+
+		for _, id := range []string{"luffy", "zoro"} {
+			counts, err := repo.Count(ctx, id)
+			require.NoError(t, err)
+			assert.Equal(t, &follow.Counts{}, counts, id)
+		}
+	})
+}
@@ -0,0 +1,100 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"gophr.v2/follow"
+)
+
+var _ follow.Repository = (*Repository)(nil)
+
+func New(conn *sql.DB) *Repository {
+	return &Repository{conn: conn}
+}
+
+type Repository struct {
+	conn *sql.DB
+}
+
+func (r *Repository) Save(ctx context.Context, f *follow.Follow) error {
+	query := "INSERT IGNORE INTO follows(followerId, followeeId, created_at) VALUES(?,?,?)"
+	_, err := r.conn.ExecContext(ctx, query, f.FollowerID, f.FolloweeID, f.CreatedAt)
+	return r.checkError(err)
+}
+
+func (r *Repository) Delete(ctx context.Context, followerID, followeeID string) error {
+	query := "DELETE FROM follows WHERE followerId = ? AND followeeId = ?"
+	_, err := r.conn.ExecContext(ctx, query, followerID, followeeID)
+	return r.checkError(err)
+}
+
+func (r *Repository) DeleteAllForUser(ctx context.Context, userID string) error {
+	query := "DELETE FROM follows WHERE followerId = ? OR followeeId = ?"
+	_, err := r.conn.ExecContext(ctx, query, userID, userID)
+	return r.checkError(err)
+}
+
+func (r *Repository) Exists(ctx context.Context, followerID, followeeID string) (bool, error) {
+	query := "SELECT COUNT(*) FROM follows WHERE followerId = ? AND followeeId = ?"
+	var n int
+	err := r.conn.QueryRowContext(ctx, query, followerID, followeeID).Scan(&n)
+	if err != nil {
+		return false, r.checkError(err)
+	}
+	return n > 0, nil
+}
+
+func (r *Repository) FindFollowing(ctx context.Context, userID string) ([]string, error) {
+	query := "SELECT followeeId FROM follows WHERE followerId = ?"
+	return r.doQueryIDs(ctx, query, userID)
+}
+
+func (r *Repository) FindFollowers(ctx context.Context, userID string) ([]string, error) {
+	query := "SELECT followerId FROM follows WHERE followeeId = ?"
+	return r.doQueryIDs(ctx, query, userID)
+}
+
+func (r *Repository) Count(ctx context.Context, userID string) (*follow.Counts, error) {
+	query := `SELECT
+							(SELECT COUNT(*) FROM follows WHERE followeeId = ?),
+							(SELECT COUNT(*) FROM follows WHERE followerId = ?)`
+	var counts follow.Counts
+	err := r.conn.QueryRowContext(ctx, query, userID, userID).Scan(&counts.Followers, &counts.Following)
+	if err != nil {
+		return nil, r.checkError(err)
+	}
+	return &counts, nil
+}
+
+func (r *Repository) doQueryIDs(ctx context.Context, query string, args ...interface{}) (ids []string, err error) {
+	rows, err := r.conn.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, r.checkError(err)
+	}
+	defer func() {
+		if e := rows.Close(); err == nil && e != nil {
+			err = e
+		}
+	}()
+
+	ids = make([]string, 0)
+	for rows.Next() {
+		var id string
+		if err = rows.Scan(&id); err != nil {
+			return nil, r.checkError(err)
+		}
+		ids = append(ids, id)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, r.checkError(err)
+	}
+	return ids, nil
+}
+
+func (r *Repository) checkError(err error) error {
+	if err == nil {
+		return nil
+	}
+	return fmt.Errorf("mysql: unexpected error %w", err)
+}
@@ -0,0 +1,14 @@
+package follow
+
+import "context"
+
+//go:generate mockery --name=Service
+
+type Service interface {
+	Follow(ctx context.Context, followerID, followeeID string) error
+	Unfollow(ctx context.Context, followerID, followeeID string) error
+	IsFollowing(ctx context.Context, followerID, followeeID string) (bool, error)
+	Following(ctx context.Context, userID string) ([]string, error)
+	Followers(ctx context.Context, userID string) ([]string, error)
+	Counts(ctx context.Context, userID string) (*Counts, error)
+}
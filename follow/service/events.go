@@ -0,0 +1,20 @@
+package service
+
+import (
+	"context"
+	"gophr.v2/event"
+	"gophr.v2/user"
+)
+
+// Subscribe removes the follows of the purged users.
+func (s *Service) Subscribe(sub event.Subscriber) {
+	sub.Subscribe(user.EventPurged, s.handleEvent)
+}
+
+func (s *Service) handleEvent(ctx context.Context, e event.Event) error {
+	purged, ok := e.(*user.Purged)
+	if !ok {
+		return nil
+	}
+	return s.repo.DeleteAllForUser(ctx, purged.UserID)
+}
@@ -0,0 +1,99 @@
+package service
+
+import (
+	"context"
+	"gophr.v2/follow"
+	"gophr.v2/log"
+	"gophr.v2/notification"
+	"gophr.v2/user"
+	"gophr.v2/util/valueutil"
+	"time"
+)
+
+var _ follow.Service = (*Service)(nil)
+
+// Option configures the Service.
+type Option func(s *Service)
+
+// WithNotifications notifies users of their new followers.
+func WithNotifications(notifications notification.Service) Option {
+	return func(s *Service) {
+		s.notifications = notifications
+	}
+}
+
+// New creates the follow service. users is used to check
+// that followed users exist.
+func New(repo follow.Repository, users user.GetterByUserID, opts ...Option) *Service {
+	s := &Service{repo: repo, users: users}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+type Service struct {
+	repo          follow.Repository
+	users         user.GetterByUserID
+	notifications notification.Service
+}
+
+func (s *Service) Follow(ctx context.Context, followerID, followeeID string) error {
+	if followerID == followeeID {
+		return follow.ErrSelfFollow
+	}
+
+	if _, err := s.users.GetByUserID(ctx, followeeID); err != nil {
+		return err
+	}
+
+	// Following again must not notify again
+	exists, err := s.repo.Exists(ctx, followerID, followeeID)
+	if err != nil || exists {
+		return err
+	}
+
+	err = s.repo.Save(ctx, &follow.Follow{
+		FollowerID: followerID,
+		FolloweeID: followeeID,
+		CreatedAt:  valueutil.TimePointer(time.Now().UTC()),
+	})
+	if err != nil {
+		return err
+	}
+
+	if s.notifications != nil {
+		err := s.notifications.Notify(ctx, &notification.Notification{
+			UserID:  followeeID,
+			ActorID: followerID,
+			Type:    notification.TypeFollow,
+		})
+		if err != nil {
+			log.FromContext(ctx).WithError(err).Error("failed notifying new follower")
+		}
+	}
+	return nil
+}
+
+func (s *Service) Unfollow(ctx context.Context, followerID, followeeID string) error {
+	return s.repo.Delete(ctx, followerID, followeeID)
+}
+
+func (s *Service) IsFollowing(ctx context.Context, followerID, followeeID string) (bool, error) {
+	if followerID == "" || followerID == followeeID {
+		return false, nil
+	}
+	return s.repo.Exists(ctx, followerID, followeeID)
+}
+
+func (s *Service) Following(ctx context.Context, userID string) ([]string, error) {
+	return s.repo.FindFollowing(ctx, userID)
+}
+
+func (s *Service) Followers(ctx context.Context, userID string) ([]string, error) {
+	return s.repo.FindFollowers(ctx, userID)
+}
+
+func (s *Service) Counts(ctx context.Context, userID string) (*follow.Counts, error) {
+	return s.repo.Count(ctx, userID)
+}
@@ -0,0 +1,100 @@
+//+build unit
+
+package service
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gophr.v2/follow"
+	"gophr.v2/follow/mocks"
+	"gophr.v2/notification"
+	notificationmocks "gophr.v2/notification/mocks"
+	"gophr.v2/user"
+	usermocks "gophr.v2/user/mocks"
+	"testing"
+)
+
+func TestService_Follow(t *testing.T) {
+	t.Run("Follow", func(t *testing.T) {
+		repo := new(mocks.Repository)
+		repo.On("Exists", mock.Anything, "luffy", "zoro").Return(false, nil).Once()
+		repo.On("Save", mock.Anything, mock.MatchedBy(func(f *follow.Follow) bool {
+			return f.FollowerID == "luffy" && f.FolloweeID == "zoro" && f.CreatedAt != nil
+		})).Return(nil).Once()
+		users := new(usermocks.Service)
+		users.On("GetByUserID", mock.Anything, "zoro").Return(&user.User{UserID: "zoro"}, nil).Once()
+
+		err := New(repo, users).Follow(context.Background(), "luffy", "zoro")
+		assert.NoError(t, err)
+		repo.AssertExpectations(t)
+		users.AssertExpectations(t)
+	})
+
+	t.Run("Notifies Followee", func(t *testing.T) {
+		repo := new(mocks.Repository)
+		repo.On("Exists", mock.Anything, "luffy", "zoro").Return(false, nil).Once()
+		repo.On("Save", mock.Anything, mock.AnythingOfType("*follow.Follow")).Return(nil).Once()
+		users := new(usermocks.Service)
+		users.On("GetByUserID", mock.Anything, "zoro").Return(&user.User{UserID: "zoro"}, nil).Once()
+		notifications := new(notificationmocks.Service)
+		notifications.On("Notify", mock.Anything, &notification.Notification{
+			UserID:  "zoro",
+			ActorID: "luffy",
+			Type:    notification.TypeFollow,
+		}).Return(nil).Once()
+
+		err := New(repo, users, WithNotifications(notifications)).Follow(context.Background(), "luffy", "zoro")
+		assert.NoError(t, err)
+		notifications.AssertExpectations(t)
+	})
+
+	t.Run("Already Following", func(t *testing.T) {
+		repo := new(mocks.Repository)
+		repo.On("Exists", mock.Anything, "luffy", "zoro").Return(true, nil).Once()
+		users := new(usermocks.Service)
+		users.On("GetByUserID", mock.Anything, "zoro").Return(&user.User{UserID: "zoro"}, nil).Once()
+		notifications := new(notificationmocks.Service)
+
+		err := New(repo, users, WithNotifications(notifications)).Follow(context.Background(), "luffy", "zoro")
+		assert.NoError(t, err)
+		repo.AssertExpectations(t)
+		notifications.AssertExpectations(t)
+	})
+
+	t.Run("Self Follow", func(t *testing.T) {
+		repo := new(mocks.Repository)
+		users := new(usermocks.Service)
+
+		err := New(repo, users).Follow(context.Background(), "luffy", "luffy")
+		assert.Equal(t, follow.ErrSelfFollow, err)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("Unknown User", func(t *testing.T) {
+		repo := new(mocks.Repository)
+		users := new(usermocks.Service)
+		users.On("GetByUserID", mock.Anything, "buggy").Return(nil, user.ErrNotFound).Once()
+
+		err := New(repo, users).Follow(context.Background(), "luffy", "buggy")
+		assert.Equal(t, user.ErrNotFound, err)
+		repo.AssertExpectations(t)
+	})
+}
+
+func TestService_IsFollowing(t *testing.T) {
+	repo := new(mocks.Repository)
+	repo.On("Exists", mock.Anything, "luffy", "zoro").Return(true, nil).Once()
+	svc := New(repo, nil)
+
+	following, err := svc.IsFollowing(context.Background(), "luffy", "zoro")
+	assert.NoError(t, err)
+	assert.True(t, following)
+
+	t.Run("Anonymous", func(t *testing.T) {
+		following, err := svc.IsFollowing(context.Background(), "", "zoro")
+		assert.NoError(t, err)
+		assert.False(t, following)
+	})
+	repo.AssertExpectations(t)
+}
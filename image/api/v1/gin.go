@@ -2,8 +2,8 @@ package v1
 
 import (
 	"github.com/gin-gonic/gin"
-	"github.com/jayvib/golog"
 	"gophr.v2/image"
+	"gophr.v2/log"
 	"gophr.v2/user"
 	"net/http"
 	"strconv"
@@ -37,7 +37,7 @@ func (h *handlers) Find(c *gin.Context) {
 		if err == image.ErrNotFound {
 			c.Writer.WriteHeader(http.StatusNotFound)
 		} else {
-			golog.Error("failed finding image:", err)
+			log.FromContext(c.Request.Context()).WithError(err).Error("failed finding image")
 			c.Writer.WriteHeader(http.StatusInternalServerError)
 		}
 		return
@@ -56,14 +56,14 @@ func (h *handlers) FindAll(c *gin.Context) {
 	offset, err := strconv.Atoi(offsetStr)
 	if err != nil {
 		http.Error(c.Writer, err.Error(), http.StatusBadRequest)
-		golog.Error("unable to parse offset:", err)
+		log.FromContext(c.Request.Context()).WithError(err).Error("unable to parse offset")
 		return
 	}
 
 	res, err := h.imageSvc.FindAll(c.Request.Context(), offset)
 	if err != nil {
 		http.Error(c.Writer, err.Error(), http.StatusInternalServerError)
-		golog.Error(err)
+		log.FromContext(c.Request.Context()).WithError(err).Error("failed finding images")
 		return
 	}
 	c.JSON(http.StatusOK, res)
@@ -79,7 +79,7 @@ func (h *handlers) FindAllByUser(c *gin.Context) {
 	res, err := h.imageSvc.FindAllByUser(c.Request.Context(), userId, offset)
 	if err != nil {
 		c.Writer.WriteHeader(http.StatusInternalServerError)
-		golog.Error("failed finding images by user:", err)
+		log.FromContext(c.Request.Context()).WithError(err).Error("failed finding images by user")
 		return
 	}
 
@@ -94,14 +94,14 @@ func (h *handlers) CreateImageFromURL(c *gin.Context) {
 	usr, err := h.userSvc.GetByUsername(c.Request.Context(), userName)
 	if err != nil {
 		c.Writer.WriteHeader(http.StatusBadRequest)
-		golog.Error("unable to create image:", err)
+		log.FromContext(c.Request.Context()).WithError(err).Error("unable to create image")
 		return
 	}
 
 	img, err := h.imageSvc.CreateImageFromURL(c.Request.Context(), url, usr.UserID, desc)
 	if err != nil {
 		c.Writer.WriteHeader(http.StatusInternalServerError)
-		golog.Error("unable to create image from url:", err)
+		log.FromContext(c.Request.Context()).WithError(err).Error("unable to create image from url")
 		return
 	}
 
@@ -116,21 +116,21 @@ func (h *handlers) CreateImageFromFile(c *gin.Context) {
 	usr, err := h.userSvc.GetByUsername(c.Request.Context(), userName)
 	if err != nil {
 		c.Writer.WriteHeader(http.StatusBadRequest)
-		golog.Error("unable to create image:", err)
+		log.FromContext(c.Request.Context()).WithError(err).Error("unable to create image")
 		return
 	}
 
 	formFile, err := c.FormFile("file")
 	if err != nil {
 		c.Writer.WriteHeader(http.StatusBadRequest)
-		golog.Error("file not exist in the multipart form:", err)
+		log.FromContext(c.Request.Context()).WithError(err).Error("file not exist in the multipart form")
 		return
 	}
 
 	f, err := formFile.Open()
 	if err != nil {
 		c.Writer.WriteHeader(http.StatusInternalServerError)
-		golog.Error("unable to open form file:", err)
+		log.FromContext(c.Request.Context()).WithError(err).Error("unable to open form file")
 		return
 	}
 	defer f.Close()
@@ -138,7 +138,7 @@ func (h *handlers) CreateImageFromFile(c *gin.Context) {
 	img, err := h.imageSvc.CreateImageFromFile(c.Request.Context(), f, formFile.Filename, desc, usr.UserID)
 	if err != nil {
 		c.Writer.WriteHeader(http.StatusInternalServerError)
-		golog.Error("failed to create image from file:", err)
+		log.FromContext(c.Request.Context()).WithError(err).Error("failed to create image from file")
 	}
 
 	c.JSON(http.StatusCreated, img)
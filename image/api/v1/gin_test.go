@@ -26,7 +26,7 @@ import (
 	"gophr.v2/user/userutil"
 
 	"github.com/gin-gonic/gin"
-	"github.com/jayvib/golog"
+	"gophr.v2/log"
 	gophrtesting "gophr.v2/testing"
 )
 
@@ -35,8 +35,8 @@ var debug = flag.Bool("debug", false, "Debugging")
 func TestMain(m *testing.M) {
 	flag.Parse()
 	if *debug {
-		golog.Info("Debugging Mode!")
-		golog.SetLevel(golog.DebugLevel)
+		log.Info("Debugging Mode!")
+		log.SetLevel(log.DebugLevel)
 	}
 	gin.SetMode(gin.TestMode)
 	os.Exit(m.Run())
@@ -4,6 +4,7 @@ import "github.com/pkg/errors"
 
 var (
 	ErrNotFound           = errors.New("image: item not found")
+	ErrExists             = errors.New("image: item already exists")
 	ErrInvalidImageType   = errors.New("image: invalid image type")
 	ErrInvalidImageURL    = errors.New("image: invalid image url")
 	ErrFailedRequest      = errors.New("image: failed while do a client request")
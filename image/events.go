@@ -0,0 +1,27 @@
+package image
+
+import "gophr.v2/event"
+
+const (
+	EventCreated = "image.created"
+	EventDeleted = "image.deleted"
+)
+
+func init() {
+	event.Register(EventCreated, func() event.Event { return new(Created) })
+	event.Register(EventDeleted, func() event.Event { return new(Deleted) })
+}
+
+// Created is emitted once an image is saved.
+type Created struct {
+	Image *Image `json:"image"`
+}
+
+func (*Created) EventName() string { return EventCreated }
+
+// Deleted is emitted once an image is deleted.
+type Deleted struct {
+	Image *Image `json:"image"`
+}
+
+func (*Deleted) EventName() string { return EventDeleted }
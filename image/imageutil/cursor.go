@@ -0,0 +1,45 @@
+package imageutil
+
+import (
+	"encoding/base64"
+	"errors"
+	"gophr.v2/image"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ErrInvalidCursor = errors.New("imageutil: invalid cursor")
+
+// EncodeCursor encodes the position right after img in a list of
+// images ordered by creation time. The ID breaks ties between
+// images created at the same time.
+func EncodeCursor(img *image.Image) string {
+	var createdAt time.Time
+	if img.CreatedAt != nil {
+		createdAt = *img.CreatedAt
+	}
+	raw := createdAt.UTC().Format(time.RFC3339Nano) + "|" + strconv.FormatUint(uint64(img.ID), 10)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// DecodeCursor decodes a cursor created by EncodeCursor.
+func DecodeCursor(cursor string) (createdAt time.Time, id uint, err error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return createdAt, 0, ErrInvalidCursor
+	}
+	parts := strings.SplitN(string(raw), "|", 2)
+	if len(parts) != 2 {
+		return createdAt, 0, ErrInvalidCursor
+	}
+	createdAt, err = time.Parse(time.RFC3339Nano, parts[0])
+	if err != nil {
+		return createdAt, 0, ErrInvalidCursor
+	}
+	n, err := strconv.ParseUint(parts[1], 10, 64)
+	if err != nil {
+		return createdAt, 0, ErrInvalidCursor
+	}
+	return createdAt, uint(n), nil
+}
@@ -0,0 +1,27 @@
+//+build unit
+
+package imageutil
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gophr.v2/image"
+	"gophr.v2/util/valueutil"
+	"testing"
+	"time"
+)
+
+func TestCursor(t *testing.T) {
+	createdAt := time.Date(2020, 6, 1, 10, 30, 0, 0, time.UTC)
+	cursor := EncodeCursor(&image.Image{ID: 42, CreatedAt: valueutil.TimePointer(createdAt)})
+
+	gotTime, gotID, err := DecodeCursor(cursor)
+	require.NoError(t, err)
+	assert.True(t, createdAt.Equal(gotTime))
+	assert.Equal(t, uint(42), gotID)
+
+	t.Run("Invalid", func(t *testing.T) {
+		_, _, err := DecodeCursor("notacursor")
+		assert.Equal(t, ErrInvalidCursor, err)
+	})
+}
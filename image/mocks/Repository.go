@@ -8,6 +8,8 @@ import (
 	image "gophr.v2/image"
 
 	mock "github.com/stretchr/testify/mock"
+
+	time "time"
 )
 
 // Repository is an autogenerated mock type for the Repository type
@@ -15,6 +17,20 @@ type Repository struct {
 	mock.Mock
 }
 
+// Delete provides a mock function with given fields: ctx, id
+func (_m *Repository) Delete(ctx context.Context, id string) error {
+	ret := _m.Called(ctx, id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Find provides a mock function with given fields: ctx, id
 func (_m *Repository) Find(ctx context.Context, id string) (*image.Image, error) {
 	ret := _m.Called(ctx, id)
@@ -84,6 +100,87 @@ func (_m *Repository) FindAllByUser(ctx context.Context, userId string, offset i
 	return r0, r1
 }
 
+// FindAllByUsers provides a mock function with given fields: ctx, userIds, cursor, num
+func (_m *Repository) FindAllByUsers(ctx context.Context, userIds []string, cursor string, num int) ([]*image.Image, string, error) {
+	ret := _m.Called(ctx, userIds, cursor, num)
+
+	var r0 []*image.Image
+	if rf, ok := ret.Get(0).(func(context.Context, []string, string, int) []*image.Image); ok {
+		r0 = rf(ctx, userIds, cursor, num)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*image.Image)
+		}
+	}
+
+	var r1 string
+	if rf, ok := ret.Get(1).(func(context.Context, []string, string, int) string); ok {
+		r1 = rf(ctx, userIds, cursor, num)
+	} else {
+		r1 = ret.Get(1).(string)
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(context.Context, []string, string, int) error); ok {
+		r2 = rf(ctx, userIds, cursor, num)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// FindAllDeleted provides a mock function with given fields: ctx, before, num
+func (_m *Repository) FindAllDeleted(ctx context.Context, before time.Time, num int) ([]*image.Image, error) {
+	ret := _m.Called(ctx, before, num)
+
+	var r0 []*image.Image
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, int) []*image.Image); ok {
+		r0 = rf(ctx, before, num)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*image.Image)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time, int) error); ok {
+		r1 = rf(ctx, before, num)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Purge provides a mock function with given fields: ctx, id
+func (_m *Repository) Purge(ctx context.Context, id string) error {
+	ret := _m.Called(ctx, id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Restore provides a mock function with given fields: ctx, id
+func (_m *Repository) Restore(ctx context.Context, id string) error {
+	ret := _m.Called(ctx, id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Save provides a mock function with given fields: ctx, _a1
 func (_m *Repository) Save(ctx context.Context, _a1 *image.Image) error {
 	ret := _m.Called(ctx, _a1)
@@ -2,6 +2,10 @@ package image
 
 import "time"
 
+// PageSize is the number of images returned per page
+// by the FindAll and FindAllByUser queries.
+const PageSize = 25
+
 var MimeExtensions = map[string]string{
 	"image/png":  ".png",
 	"image/jpeg": ".jpg",
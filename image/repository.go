@@ -1,12 +1,27 @@
 package image
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 //go:generate mockery --name=Repository
 
+// Repository stores the images. The reads leave the soft deleted images
+// out unless the context includes them with softdelete.Include.
 type Repository interface {
 	Save(ctx context.Context, image *Image) error
 	Find(ctx context.Context, id string) (*Image, error)
 	FindAll(ctx context.Context, offset int) ([]*Image, error)
 	FindAllByUser(ctx context.Context, userId string, offset int) ([]*Image, error)
+	FindAllByUsers(ctx context.Context, userIds []string, cursor string, num int) (images []*Image, nextCursor string, err error)
+	// Delete soft deletes the image with id. It's kept until purged.
+	Delete(ctx context.Context, id string) error
+	// Restore removes the deletion mark of the image with id.
+	Restore(ctx context.Context, id string) error
+	// Purge permanently deletes the image with id.
+	Purge(ctx context.Context, id string) error
+	// FindAllDeleted returns up to num images soft deleted before
+	// the given time.
+	FindAllDeleted(ctx context.Context, before time.Time, num int) ([]*Image, error)
 }
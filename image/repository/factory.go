@@ -3,16 +3,35 @@ package repository
 import (
 	"gophr.v2/config"
 	mysqldriver "gophr.v2/driver/mysql"
+	postgresdriver "gophr.v2/driver/postgres"
+	sqlitedriver "gophr.v2/driver/sqlite"
 	"gophr.v2/image"
 	"gophr.v2/image/repository/mysql"
+	"gophr.v2/image/repository/postgres"
+	"gophr.v2/image/repository/sqlite"
 )
 
 type RepoType int
 
 const (
 	MySQLRepo RepoType = iota
+	PostgresRepo
+	SQLiteRepo
 )
 
+// SQLRepo returns the type of the repository stored in the database
+// of conf.
+func SQLRepo(conf *config.Config) RepoType {
+	switch conf.Gophr.Database {
+	case config.PostgresDatabase:
+		return PostgresRepo
+	case config.SQLiteDatabase:
+		return SQLiteRepo
+	default:
+		return MySQLRepo
+	}
+}
+
 func Get(conf *config.Config, rt RepoType) (image.Repository, func() error) {
 	switch rt {
 	case MySQLRepo:
@@ -21,6 +40,18 @@ func Get(conf *config.Config, rt RepoType) (image.Repository, func() error) {
 			panic(err)
 		}
 		return mysql.New(db), db.Close
+	case PostgresRepo:
+		db, err := postgresdriver.Initialize(conf)
+		if err != nil {
+			panic(err)
+		}
+		return postgres.New(db), db.Close
+	case SQLiteRepo:
+		db, err := sqlitedriver.Initialize(conf)
+		if err != nil {
+			panic(err)
+		}
+		return sqlite.New(db), db.Close
 	default:
 		panic("unknown repository implementation type")
 	}
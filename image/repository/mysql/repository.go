@@ -3,12 +3,19 @@ package mysql
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
-	"github.com/prometheus/common/log"
+	driver "github.com/go-sql-driver/mysql"
+	"gophr.v2/event/outbox"
 	"gophr.v2/image"
+	"gophr.v2/image/imageutil"
+	"gophr.v2/log"
+	"gophr.v2/softdelete"
+	"strings"
+	"time"
 )
 
-const pageSize = 25
+const pageSize = image.PageSize
 
 func New(db *sql.DB) image.Repository {
 	return &repository{
@@ -44,7 +51,7 @@ func (r *repository) Save(ctx context.Context, image *image.Image) error {
 		}
 
 		image.ID = uint(id)
-		return nil
+		return outbox.Store(ctx, tx)
 	})
 }
 
@@ -61,7 +68,7 @@ func (r *repository) doSave(fn func(tx *sql.Tx) error) (err error) {
 			if e := tx.Rollback(); e != nil {
 				// TODO: I think its better to cascade the error
 				// For now just log the error.
-				log.Error("error while rolling back data in sql:", e)
+				log.WithError(e).Error("failed rolling back transaction")
 			}
 		}
 	}()
@@ -77,13 +84,14 @@ func (r *repository) doSave(fn func(tx *sql.Tx) error) (err error) {
 func (r *repository) Find(ctx context.Context, id string) (*image.Image, error) {
 	query := `SELECT id, userId, imageId, name, location, description, size, created_at, updated_at, deleted_at 
 						FROM images 
-						WHERE imageId = ?`
+						WHERE imageId = ? AND ` + softdelete.SQLFilter(ctx)
 	return r.doQuerySingleReturn(ctx, query, id)
 }
 
 func (r *repository) FindAll(ctx context.Context, offset int) ([]*image.Image, error) {
 	query := `SELECT id, userId, imageId, name, location, description, size, created_at, updated_at, deleted_at 
 						FROM images
+						WHERE ` + softdelete.SQLFilter(ctx) + `
 						ORDER BY created_at DESC
 						LIMIT ?
 						OFFSET ?`
@@ -93,13 +101,105 @@ func (r *repository) FindAll(ctx context.Context, offset int) ([]*image.Image, e
 func (r *repository) FindAllByUser(ctx context.Context, userId string, offset int) ([]*image.Image, error) {
 	query := `SELECT id, userId, imageId, name, location, description, size, created_at, updated_at, deleted_at 
 						FROM images
-						WHERE userId = ?
+						WHERE userId = ? AND ` + softdelete.SQLFilter(ctx) + `
 						ORDER BY created_at DESC
 						LIMIT ?
 						OFFSET ?`
 	return r.doQuery(ctx, query, userId, pageSize, offset)
 }
 
+// FindAllByUsers merges the images of all the users in userIds, newest
+// first. The feed is built on read with a single query using the
+// (userId, created_at) index instead of keeping a timeline per user.
+func (r *repository) FindAllByUsers(ctx context.Context, userIds []string, cursor string, num int) ([]*image.Image, string, error) {
+	if len(userIds) == 0 || num <= 0 {
+		return []*image.Image{}, "", nil
+	}
+
+	args := make([]interface{}, 0, len(userIds)+4)
+	for _, userId := range userIds {
+		args = append(args, userId)
+	}
+
+	// The first page starts from the newest image, the next ones
+	// right after the last image of the previous page
+	var after string
+	if cursor != "" {
+		createdAt, id, err := imageutil.DecodeCursor(cursor)
+		if err != nil {
+			return nil, "", err
+		}
+		after = "AND (created_at < ? OR (created_at = ? AND id < ?))"
+		args = append(args, createdAt, createdAt, id)
+	}
+	args = append(args, num)
+
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(userIds)), ",")
+	query := `SELECT id, userId, imageId, name, location, description, size, created_at, updated_at, deleted_at 
+						FROM images
+						WHERE userId IN (` + placeholders + `)
+						` + after + `
+						AND ` + softdelete.SQLFilter(ctx) + `
+						ORDER BY created_at DESC, id DESC
+						LIMIT ?`
+
+	images, err := r.doQuery(ctx, query, args...)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var nextCursor string
+	if len(images) == num {
+		nextCursor = imageutil.EncodeCursor(images[len(images)-1])
+	}
+	return images, nextCursor, nil
+}
+
+func (r *repository) Delete(ctx context.Context, id string) error {
+	query := "UPDATE images SET deleted_at = ? WHERE imageId = ? AND deleted_at IS NULL"
+	return r.doExec(ctx, query, time.Now().UTC(), id)
+}
+
+func (r *repository) Restore(ctx context.Context, id string) error {
+	query := "UPDATE images SET deleted_at = NULL WHERE imageId = ? AND deleted_at IS NOT NULL"
+	return r.doExec(ctx, query, id)
+}
+
+func (r *repository) Purge(ctx context.Context, id string) error {
+	query := "DELETE FROM images WHERE imageId = ?"
+	return r.doExec(ctx, query, id)
+}
+
+func (r *repository) FindAllDeleted(ctx context.Context, before time.Time, num int) ([]*image.Image, error) {
+	query := `SELECT id, userId, imageId, name, location, description, size, created_at, updated_at, deleted_at 
+						FROM images
+						WHERE deleted_at IS NOT NULL AND deleted_at < ?
+						ORDER BY deleted_at
+						LIMIT ?`
+	return r.doQuery(ctx, query, before, num)
+}
+
+// doExec runs the query changing a single image, returning
+// image.ErrNotFound when there's no such image. The events recorded
+// in ctx are committed along with the change.
+func (r *repository) doExec(ctx context.Context, query string, args ...interface{}) error {
+	return r.doSave(func(tx *sql.Tx) error {
+		res, err := tx.ExecContext(ctx, query, args...)
+		if err != nil {
+			return r.checkError(err)
+		}
+
+		affected, err := res.RowsAffected()
+		if err != nil {
+			return r.checkError(err)
+		}
+		if affected == 0 {
+			return image.ErrNotFound
+		}
+		return outbox.Store(ctx, tx)
+	})
+}
+
 func (r *repository) checkError(err error) error {
 	var cerr error
 	switch err {
@@ -108,7 +208,10 @@ func (r *repository) checkError(err error) error {
 	case nil:
 		cerr = nil
 	default:
-		cerr = fmt.Errorf("mysql: unexpected error %w", err)
+		cerr = duplicateError(err)
+		if cerr == nil {
+			cerr = fmt.Errorf("mysql: unexpected error %w", err)
+		}
 	}
 	return cerr
 }
@@ -116,7 +219,7 @@ func (r *repository) checkError(err error) error {
 func (r *repository) doQuery(ctx context.Context, query string, args ...interface{}) (images []*image.Image, err error) {
 	row, err := r.conn.QueryContext(ctx, query, args...)
 	if err != nil {
-		log.Debug(err)
+		log.FromContext(ctx).WithError(err).Debug("failed querying")
 		return nil, r.checkError(err)
 	}
 	defer func() {
@@ -135,7 +238,7 @@ func (r *repository) doQuery(ctx context.Context, query string, args ...interfac
 		images = append(images, &img)
 	}
 	if err = row.Err(); err != nil {
-		log.Debug(err)
+		log.FromContext(ctx).WithError(err).Debug("failed querying")
 		return nil, r.checkError(err)
 	}
 	return images, nil
@@ -152,3 +255,17 @@ func (r *repository) doQuerySingleReturn(ctx context.Context, query string, valu
 	}
 	return images[0], nil
 }
+
+// errDuplicateEntry is the number of the MySQL error raised when
+// a unique key is violated.
+const errDuplicateEntry = 1062
+
+// duplicateError returns image.ErrExists when err is about the
+// violation of the unique image ID, or nil otherwise.
+func duplicateError(err error) error {
+	var merr *driver.MySQLError
+	if !errors.As(err, &merr) || merr.Number != errDuplicateEntry {
+		return nil
+	}
+	return image.ErrExists
+}
@@ -13,6 +13,9 @@ import (
 	"gophr.v2/image"
 	"gophr.v2/image/imageutil"
 	mysqlrepo "gophr.v2/image/repository/mysql"
+	"gophr.v2/image/repository/repositorytest"
+	"gophr.v2/migration"
+	"gophr.v2/softdelete"
 	"gophr.v2/user/userutil"
 	"gophr.v2/util/valueutil"
 	"os"
@@ -36,6 +39,11 @@ func setup() {
 	if err != nil {
 		panic(err)
 	}
+
+	// The schema is bootstrapped by the migrations of the binary
+	if _, err := migration.New(db, migration.MySQLDialect{}, migration.MySQL()).Up(context.Background()); err != nil {
+		panic(err)
+	}
 }
 
 func TestMain(m *testing.M) {
@@ -49,6 +57,13 @@ func TestMain(m *testing.M) {
 	os.Exit(code)
 }
 
+func TestConformance(t *testing.T) {
+	repositorytest.Run(t, func(t *testing.T) (image.Repository, func()) {
+		deleteAllInDB()
+		return mysqlrepo.New(db), deleteAllInDB
+	})
+}
+
 func TestRepository_Save(t *testing.T) {
 	input := &image.Image{
 		CreatedAt:   valueutil.TimePointer(time.Now()),
@@ -181,6 +196,53 @@ func storeImages(t *testing.T, repo image.Repository, images []*image.Image) {
 	}
 }
 
+func TestRepository_Delete(t *testing.T) {
+	repo := mysqlrepo.New(db)
+	img := &image.Image{
+		CreatedAt:   valueutil.TimePointer(time.Now()),
+		UserID:      userutil.GenerateID(),
+		ImageID:     imageutil.GenerateID(),
+		Name:        "Luffy Monkey",
+		Location:    "East Blue",
+		Size:        1024,
+		Description: "A Pirate King from East Blue",
+	}
+	require.NoError(t, repo.Save(context.Background(), img))
+
+	err := repo.Delete(context.Background(), img.ImageID)
+	require.NoError(t, err)
+
+	_, err = repo.Find(context.Background(), img.ImageID)
+	assert.Equal(t, image.ErrNotFound, err)
+
+	t.Run("Image Not Found", func(t *testing.T) {
+		err := repo.Delete(context.Background(), img.ImageID)
+		assert.Equal(t, image.ErrNotFound, err)
+	})
+
+	t.Run("Include Deleted", func(t *testing.T) {
+		got, err := repo.Find(softdelete.Include(context.Background()), img.ImageID)
+		require.NoError(t, err)
+		assert.NotNil(t, got.DeletedAt)
+
+		deleted, err := repo.FindAllDeleted(context.Background(), time.Now().Add(time.Minute), 10)
+		require.NoError(t, err)
+		assert.NotEmpty(t, deleted)
+	})
+
+	t.Run("Restore", func(t *testing.T) {
+		require.NoError(t, repo.Restore(context.Background(), img.ImageID))
+		_, err := repo.Find(context.Background(), img.ImageID)
+		assert.NoError(t, err)
+	})
+
+	t.Run("Purge", func(t *testing.T) {
+		require.NoError(t, repo.Purge(context.Background(), img.ImageID))
+		_, err := repo.Find(softdelete.Include(context.Background()), img.ImageID)
+		assert.Equal(t, image.ErrNotFound, err)
+	})
+}
+
 func deleteAllInDB() {
 	query := "DELETE FROM images"
 	_, err := db.Exec(query)
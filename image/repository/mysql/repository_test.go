@@ -0,0 +1,85 @@
+//+build unit
+
+package mysql
+
+import (
+	"context"
+	"fmt"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gophr.v2/image"
+	"gophr.v2/image/imageutil"
+	"gophr.v2/util/valueutil"
+	"testing"
+	"time"
+)
+
+var defaultCtx = context.Background()
+
+func setup(t *testing.T) (image.Repository, sqlmock.Sqlmock, *sqlmock.Rows) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	rows := sqlmock.NewRows([]string{
+		"id", "userId", "imageId", "name", "location", "description", "size", "created_at", "updated_at", "deleted_at",
+	})
+	return New(db), mock, rows
+}
+
+func TestRepository_FindAllByUsers(t *testing.T) {
+	createdAt := time.Date(2020, 6, 1, 12, 0, 0, 0, time.UTC)
+	newImage := func(id uint) *image.Image {
+		return &image.Image{
+			ID:        id,
+			UserID:    "luffy",
+			ImageID:   fmt.Sprintf("img%d", id),
+			Name:      "straw-hat.png",
+			Location:  "straw-hat.png",
+			Size:      42,
+			CreatedAt: valueutil.TimePointer(createdAt),
+			UpdatedAt: valueutil.TimePointer(createdAt),
+		}
+	}
+	addRows := func(rows *sqlmock.Rows, images ...*image.Image) *sqlmock.Rows {
+		for _, img := range images {
+			rows.AddRow(img.ID, img.UserID, img.ImageID, img.Name, img.Location, img.Description, img.Size, img.CreatedAt, img.UpdatedAt, img.DeletedAt)
+		}
+		return rows
+	}
+
+	t.Run("First Page", func(t *testing.T) {
+		repo, mock, rows := setup(t)
+		want := []*image.Image{newImage(2), newImage(1)}
+		mock.ExpectQuery(`WHERE userId IN \(\?,\?\) AND deleted_at IS NULL ORDER BY created_at DESC, id DESC LIMIT \?`).
+			WithArgs("luffy", "zoro", 2).
+			WillReturnRows(addRows(rows, want...))
+
+		got, next, err := repo.FindAllByUsers(defaultCtx, []string{"luffy", "zoro"}, "", 2)
+		require.NoError(t, err)
+		assert.Equal(t, want, got)
+		assert.Equal(t, imageutil.EncodeCursor(want[1]), next)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Next Page", func(t *testing.T) {
+		repo, mock, rows := setup(t)
+		want := []*image.Image{newImage(1)}
+		cursor := imageutil.EncodeCursor(newImage(2))
+		mock.ExpectQuery(`WHERE userId IN \(\?,\?\) AND \(created_at < \? OR \(created_at = \? AND id < \?\)\) AND deleted_at IS NULL`).
+			WithArgs("luffy", "zoro", createdAt, createdAt, uint(2), 2).
+			WillReturnRows(addRows(rows, want...))
+
+		got, next, err := repo.FindAllByUsers(defaultCtx, []string{"luffy", "zoro"}, cursor, 2)
+		require.NoError(t, err)
+		assert.Equal(t, want, got)
+		assert.Empty(t, next)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Invalid Cursor", func(t *testing.T) {
+		repo, mock, _ := setup(t)
+		_, _, err := repo.FindAllByUsers(defaultCtx, []string{"luffy"}, "notacursor", 2)
+		assert.Equal(t, imageutil.ErrInvalidCursor, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
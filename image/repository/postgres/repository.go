@@ -0,0 +1,267 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/lib/pq"
+	"gophr.v2/event/outbox"
+	"gophr.v2/image"
+	"gophr.v2/image/imageutil"
+	"gophr.v2/log"
+	"gophr.v2/softdelete"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const pageSize = image.PageSize
+
+// columns are the columns of an image in the order they're scanned.
+const columns = "id, userId, imageId, name, location, description, size, created_at, updated_at, deleted_at"
+
+func New(db *sql.DB) image.Repository {
+	return &repository{
+		conn: db,
+	}
+}
+
+type repository struct {
+	conn *sql.DB
+}
+
+// Save inserts image and sets its ID. PostgreSQL has no last insert
+// ID so the ID is returned by the insert itself.
+func (r *repository) Save(ctx context.Context, image *image.Image) error {
+	query := "INSERT INTO images(userId, imageId, name, location, description, size, created_at, updated_at, deleted_at) VALUES($1,$2,$3,$4,$5,$6,$7,$8,$9) RETURNING id"
+	return r.doSave(func(tx *sql.Tx) error {
+		var id uint
+		err := tx.QueryRowContext(ctx, query,
+			image.UserID,
+			image.ImageID,
+			image.Name,
+			image.Location,
+			image.Description,
+			image.Size,
+			image.CreatedAt,
+			image.UpdatedAt,
+			image.DeletedAt,
+		).Scan(&id)
+		if err != nil {
+			return r.checkError(err)
+		}
+
+		image.ID = id
+		return outbox.StorePostgres(ctx, tx)
+	})
+}
+
+func (r *repository) doSave(fn func(tx *sql.Tx) error) (err error) {
+	tx, err := r.conn.Begin()
+	if err != nil {
+		return r.checkError(err)
+	}
+	defer func() {
+		switch err {
+		case nil:
+			err = tx.Commit()
+		default:
+			if e := tx.Rollback(); e != nil {
+				log.WithError(e).Error("failed rolling back transaction")
+			}
+		}
+	}()
+
+	return fn(tx)
+}
+
+func (r *repository) Find(ctx context.Context, id string) (*image.Image, error) {
+	query := `SELECT ` + columns + `
+						FROM images
+						WHERE imageId = $1 AND ` + softdelete.SQLFilter(ctx)
+	return r.doQuerySingleReturn(ctx, query, id)
+}
+
+func (r *repository) FindAll(ctx context.Context, offset int) ([]*image.Image, error) {
+	query := `SELECT ` + columns + `
+						FROM images
+						WHERE ` + softdelete.SQLFilter(ctx) + `
+						ORDER BY created_at DESC
+						LIMIT $1
+						OFFSET $2`
+	return r.doQuery(ctx, query, pageSize, offset)
+}
+
+func (r *repository) FindAllByUser(ctx context.Context, userId string, offset int) ([]*image.Image, error) {
+	query := `SELECT ` + columns + `
+						FROM images
+						WHERE userId = $1 AND ` + softdelete.SQLFilter(ctx) + `
+						ORDER BY created_at DESC
+						LIMIT $2
+						OFFSET $3`
+	return r.doQuery(ctx, query, userId, pageSize, offset)
+}
+
+// FindAllByUsers merges the images of all the users in userIds, newest
+// first, using the (userId, created_at) index.
+func (r *repository) FindAllByUsers(ctx context.Context, userIds []string, cursor string, num int) ([]*image.Image, string, error) {
+	if len(userIds) == 0 || num <= 0 {
+		return []*image.Image{}, "", nil
+	}
+
+	args := make([]interface{}, 0, len(userIds)+4)
+	placeholders := make([]string, 0, len(userIds))
+	for _, userId := range userIds {
+		args = append(args, userId)
+		placeholders = append(placeholders, "$"+strconv.Itoa(len(args)))
+	}
+
+	// The first page starts from the newest image, the next ones
+	// right after the last image of the previous page
+	var after string
+	if cursor != "" {
+		createdAt, id, err := imageutil.DecodeCursor(cursor)
+		if err != nil {
+			return nil, "", err
+		}
+		n := len(args)
+		after = `AND (created_at < $` + strconv.Itoa(n+1) + ` OR (created_at = $` + strconv.Itoa(n+2) + ` AND id < $` + strconv.Itoa(n+3) + `))`
+		args = append(args, createdAt, createdAt, id)
+	}
+	args = append(args, num)
+
+	query := `SELECT ` + columns + `
+						FROM images
+						WHERE userId IN (` + strings.Join(placeholders, ",") + `)
+						` + after + `
+						AND ` + softdelete.SQLFilter(ctx) + `
+						ORDER BY created_at DESC, id DESC
+						LIMIT $` + strconv.Itoa(len(args))
+
+	images, err := r.doQuery(ctx, query, args...)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var nextCursor string
+	if len(images) == num {
+		nextCursor = imageutil.EncodeCursor(images[len(images)-1])
+	}
+	return images, nextCursor, nil
+}
+
+func (r *repository) Delete(ctx context.Context, id string) error {
+	query := "UPDATE images SET deleted_at = $1 WHERE imageId = $2 AND deleted_at IS NULL"
+	return r.doExec(ctx, query, time.Now().UTC(), id)
+}
+
+func (r *repository) Restore(ctx context.Context, id string) error {
+	query := "UPDATE images SET deleted_at = NULL WHERE imageId = $1 AND deleted_at IS NOT NULL"
+	return r.doExec(ctx, query, id)
+}
+
+func (r *repository) Purge(ctx context.Context, id string) error {
+	query := "DELETE FROM images WHERE imageId = $1"
+	return r.doExec(ctx, query, id)
+}
+
+func (r *repository) FindAllDeleted(ctx context.Context, before time.Time, num int) ([]*image.Image, error) {
+	query := `SELECT ` + columns + `
+						FROM images
+						WHERE deleted_at IS NOT NULL AND deleted_at < $1
+						ORDER BY deleted_at
+						LIMIT $2`
+	return r.doQuery(ctx, query, before, num)
+}
+
+// doExec runs the query changing a single image, returning
+// image.ErrNotFound when there's no such image. The events recorded
+// in ctx are committed along with the change.
+func (r *repository) doExec(ctx context.Context, query string, args ...interface{}) error {
+	return r.doSave(func(tx *sql.Tx) error {
+		res, err := tx.ExecContext(ctx, query, args...)
+		if err != nil {
+			return r.checkError(err)
+		}
+
+		affected, err := res.RowsAffected()
+		if err != nil {
+			return r.checkError(err)
+		}
+		if affected == 0 {
+			return image.ErrNotFound
+		}
+		return outbox.StorePostgres(ctx, tx)
+	})
+}
+
+func (r *repository) checkError(err error) error {
+	var cerr error
+	switch err {
+	case sql.ErrNoRows:
+		cerr = image.ErrNotFound
+	case nil:
+		cerr = nil
+	default:
+		cerr = duplicateError(err)
+		if cerr == nil {
+			cerr = fmt.Errorf("postgres: unexpected error %w", err)
+		}
+	}
+	return cerr
+}
+
+func (r *repository) doQuery(ctx context.Context, query string, args ...interface{}) (images []*image.Image, err error) {
+	row, err := r.conn.QueryContext(ctx, query, args...)
+	if err != nil {
+		log.FromContext(ctx).WithError(err).Debug("failed querying")
+		return nil, r.checkError(err)
+	}
+	defer func() {
+		if e := row.Close(); err == nil && e != nil {
+			err = e
+		}
+	}()
+
+	images = make([]*image.Image, 0)
+	for row.Next() {
+		var img image.Image
+		err = row.Scan(&img.ID, &img.UserID, &img.ImageID, &img.Name, &img.Location, &img.Description, &img.Size, &img.CreatedAt, &img.UpdatedAt, &img.DeletedAt)
+		if err != nil {
+			return nil, r.checkError(err)
+		}
+		images = append(images, &img)
+	}
+	if err = row.Err(); err != nil {
+		log.FromContext(ctx).WithError(err).Debug("failed querying")
+		return nil, r.checkError(err)
+	}
+	return images, nil
+}
+
+func (r *repository) doQuerySingleReturn(ctx context.Context, query string, value interface{}) (img *image.Image, err error) {
+	images, err := r.doQuery(ctx, query, value)
+	if err != nil {
+		return nil, r.checkError(err)
+	}
+
+	if len(images) == 0 {
+		return nil, image.ErrNotFound
+	}
+	return images[0], nil
+}
+
+// errUniqueViolation is the code of the PostgreSQL error raised when
+// a unique index is violated.
+const errUniqueViolation = "23505"
+
+// duplicateError returns image.ErrExists when err is about the
+// violation of the unique image ID, or nil otherwise.
+func duplicateError(err error) error {
+	var perr *pq.Error
+	if !errors.As(err, &perr) || perr.Code != errUniqueViolation {
+		return nil
+	}
+	return image.ErrExists
+}
@@ -0,0 +1,275 @@
+//+build integration
+
+package postgres_test
+
+import (
+	"context"
+	"database/sql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gophr.v2/config"
+	"gophr.v2/config/builder/viper"
+	"gophr.v2/driver/postgres"
+	"gophr.v2/image"
+	"gophr.v2/image/imageutil"
+	postgresrepo "gophr.v2/image/repository/postgres"
+	"gophr.v2/image/repository/repositorytest"
+	"gophr.v2/migration"
+	"gophr.v2/softdelete"
+	"gophr.v2/user/userutil"
+	"gophr.v2/util/valueutil"
+	"os"
+	"testing"
+	"time"
+)
+
+var db *sql.DB
+
+func setup() {
+
+	builder := viper.NewViperBuilder(
+		viper.SetViperConfigName("config-dev.yaml"),
+		viper.SetViperConfigPath("testdata"))
+
+	conf, err := config.New(builder)
+	if err != nil {
+		panic(err)
+	}
+	db, err = postgres.Initialize(conf)
+	if err != nil {
+		panic(err)
+	}
+
+	// The schema is bootstrapped by the migrations of the binary
+	if _, err := migration.New(db, migration.PostgresDialect{}, migration.Postgres()).Up(context.Background()); err != nil {
+		panic(err)
+	}
+}
+
+func TestMain(m *testing.M) {
+	setup()
+	code := m.Run()
+	deleteAllInDB()
+	err := db.Close()
+	if err != nil {
+		panic(err)
+	}
+	os.Exit(code)
+}
+
+func TestConformance(t *testing.T) {
+	repositorytest.Run(t, func(t *testing.T) (image.Repository, func()) {
+		deleteAllInDB()
+		return postgresrepo.New(db), deleteAllInDB
+	})
+}
+
+func TestRepository_Save(t *testing.T) {
+	input := &image.Image{
+		CreatedAt:   valueutil.TimePointer(time.Now()),
+		UserID:      userutil.GenerateID(),
+		ImageID:     imageutil.GenerateID(),
+		Name:        "Luffy Monkey",
+		Location:    "East Blue",
+		Size:        1024,
+		Description: "A Pirate King from East Blue",
+	}
+
+	repo := postgresrepo.New(db)
+	err := repo.Save(context.Background(), input)
+	require.NoError(t, err)
+	assert.NotEmpty(t, input.ID)
+	assertSavedImage(t, input)
+}
+
+func TestRepository_Find(t *testing.T) {
+	repo := postgresrepo.New(db)
+
+	t.Run("Image Found", func(t *testing.T) {
+		want := &image.Image{
+			CreatedAt:   valueutil.TimePointer(time.Now()),
+			UserID:      userutil.GenerateID(),
+			ImageID:     imageutil.GenerateID(),
+			Name:        "Luffy Monkey",
+			Location:    "East Blue",
+			Size:        1024,
+			Description: "A Pirate King from East Blue",
+		}
+		err := repo.Save(context.Background(), want)
+		require.NoError(t, err)
+
+		got, err := repo.Find(context.Background(), want.ImageID)
+		require.NoError(t, err)
+		assertImage(t, want, got)
+	})
+
+	t.Run("Image Not Found", func(t *testing.T) {
+		_, err := repo.Find(context.Background(), "notfoundid")
+		assert.Error(t, err)
+		assert.Equal(t, image.ErrNotFound, err)
+	})
+}
+
+func TestRepository_FindAll(t *testing.T) {
+	// Delete the existing contents
+	deleteAllInDB()
+	images := []*image.Image{
+		{
+			CreatedAt:   valueutil.TimePointer(time.Now()),
+			UserID:      userutil.GenerateID(),
+			ImageID:     imageutil.GenerateID(),
+			Name:        "Luffy Monkey",
+			Location:    "East Blue",
+			Size:        1024,
+			Description: "A Pirate King from East Blue",
+		},
+		{
+			CreatedAt:   valueutil.TimePointer(time.Now()),
+			UserID:      userutil.GenerateID(),
+			ImageID:     imageutil.GenerateID(),
+			Name:        "Roronoa Zoro",
+			Location:    "East Blue",
+			Size:        1024,
+			Description: "A Swordsman from East Blue",
+		},
+		{
+			CreatedAt:   valueutil.TimePointer(time.Now()),
+			UserID:      userutil.GenerateID(),
+			ImageID:     imageutil.GenerateID(),
+			Name:        "Sanji Vinsmoke",
+			Location:    "West Blue",
+			Size:        1024,
+			Description: "A Cook from West Blue",
+		},
+	}
+
+	repo := postgresrepo.New(db)
+	storeImages(t, repo, images)
+
+	got, err := repo.FindAll(context.Background(), 0)
+	assert.NoError(t, err)
+	assert.Len(t, got, 3)
+}
+
+func TestRepository_FindAllByUser(t *testing.T) {
+	userId := userutil.GenerateID()
+	images := []*image.Image{
+		{
+			CreatedAt:   valueutil.TimePointer(time.Now()),
+			UserID:      userId,
+			ImageID:     imageutil.GenerateID(),
+			Name:        "Luffy Monkey",
+			Location:    "East Blue",
+			Size:        1024,
+			Description: "A Pirate King from East Blue",
+		},
+		{
+			CreatedAt:   valueutil.TimePointer(time.Now()),
+			UserID:      userId,
+			ImageID:     imageutil.GenerateID(),
+			Name:        "Roronoa Zoro",
+			Location:    "East Blue",
+			Size:        1024,
+			Description: "A Swordsman from East Blue",
+		},
+		{
+			CreatedAt:   valueutil.TimePointer(time.Now()),
+			UserID:      userutil.GenerateID(),
+			ImageID:     imageutil.GenerateID(),
+			Name:        "Sanji Vinsmoke",
+			Location:    "West Blue",
+			Size:        1024,
+			Description: "A Cook from West Blue",
+		},
+	}
+	repo := postgresrepo.New(db)
+	storeImages(t, repo, images)
+	got, err := repo.FindAllByUser(context.Background(), userId, 0)
+	assert.NoError(t, err)
+	assert.Len(t, got, 2)
+}
+
+func storeImages(t *testing.T, repo image.Repository, images []*image.Image) {
+	for _, img := range images {
+		err := repo.Save(context.Background(), img)
+		require.NoError(t, err)
+	}
+}
+
+func TestRepository_Delete(t *testing.T) {
+	repo := postgresrepo.New(db)
+	img := &image.Image{
+		CreatedAt:   valueutil.TimePointer(time.Now()),
+		UserID:      userutil.GenerateID(),
+		ImageID:     imageutil.GenerateID(),
+		Name:        "Luffy Monkey",
+		Location:    "East Blue",
+		Size:        1024,
+		Description: "A Pirate King from East Blue",
+	}
+	require.NoError(t, repo.Save(context.Background(), img))
+
+	err := repo.Delete(context.Background(), img.ImageID)
+	require.NoError(t, err)
+
+	_, err = repo.Find(context.Background(), img.ImageID)
+	assert.Equal(t, image.ErrNotFound, err)
+
+	t.Run("Image Not Found", func(t *testing.T) {
+		err := repo.Delete(context.Background(), img.ImageID)
+		assert.Equal(t, image.ErrNotFound, err)
+	})
+
+	t.Run("Include Deleted", func(t *testing.T) {
+		got, err := repo.Find(softdelete.Include(context.Background()), img.ImageID)
+		require.NoError(t, err)
+		assert.NotNil(t, got.DeletedAt)
+
+		deleted, err := repo.FindAllDeleted(context.Background(), time.Now().Add(time.Minute), 10)
+		require.NoError(t, err)
+		assert.NotEmpty(t, deleted)
+	})
+
+	t.Run("Restore", func(t *testing.T) {
+		require.NoError(t, repo.Restore(context.Background(), img.ImageID))
+		_, err := repo.Find(context.Background(), img.ImageID)
+		assert.NoError(t, err)
+	})
+
+	t.Run("Purge", func(t *testing.T) {
+		require.NoError(t, repo.Purge(context.Background(), img.ImageID))
+		_, err := repo.Find(softdelete.Include(context.Background()), img.ImageID)
+		assert.Equal(t, image.ErrNotFound, err)
+	})
+}
+
+func deleteAllInDB() {
+	query := "DELETE FROM images"
+	_, err := db.Exec(query)
+	if err != nil {
+		panic(err)
+	}
+}
+
+func assertSavedImage(t *testing.T, input *image.Image) {
+	query := "SELECT id, userId, imageId, name, location, description, size, created_at, updated_at, deleted_at FROM images WHERE id = ?"
+	row, err := db.QueryContext(context.Background(), query, input.ID)
+	require.NoError(t, err)
+	defer func() {
+		err = row.Close()
+		require.NoError(t, err)
+	}()
+	var img image.Image
+	for row.Next() {
+		err = row.Scan(&img.ID, &img.UserID, &img.ImageID, &img.Name, &img.Location, &img.Description, &img.Size, &img.CreatedAt, &img.UpdatedAt, &img.DeletedAt)
+		require.NoError(t, err)
+		break
+	}
+	assertImage(t, input, &img)
+}
+
+func assertImage(t *testing.T, want *image.Image, got *image.Image) {
+	want.CreatedAt = nil
+	got.CreatedAt = nil
+	assert.Equal(t, want, got)
+}
@@ -0,0 +1,417 @@
+// Package repositorytest provides the conformance suite of the
+// image.Repository implementations.
+//
+// Each backend runs it from its own tests:
+//
+//	func TestConformance(t *testing.T) {
+//		repositorytest.Run(t, func(t *testing.T) (image.Repository, func()) {
+//			return New(db), func() { truncate(db) }
+//		})
+//	}
+package repositorytest
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gophr.v2/image"
+	"gophr.v2/image/imageutil"
+	"gophr.v2/softdelete"
+	"sync"
+	"testing"
+	"time"
+)
+
+// Concurrency is the number of goroutines used by the concurrent cases.
+const Concurrency = 10
+
+// Factory creates an empty repository for a single case. The returned
+// func releases it when the case is done.
+type Factory func(t *testing.T) (repo image.Repository, teardown func())
+
+// Option configures the suite.
+type Option func(s *suite)
+
+// WithoutConcurrency skips the cases using a repository from several
+// goroutines at once.
+func WithoutConcurrency() Option {
+	return func(s *suite) {
+		s.concurrency = false
+	}
+}
+
+type suite struct {
+	newRepo     Factory
+	concurrency bool
+}
+
+// Run runs the suite against the repositories created by newRepo.
+func Run(t *testing.T, newRepo Factory, opts ...Option) {
+	s := &suite{newRepo: newRepo, concurrency: true}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	t.Run("Not Found", s.testNotFound)
+	t.Run("Save", s.testSave)
+	t.Run("Uniqueness", s.testUniqueness)
+	t.Run("Soft Delete", s.testSoftDelete)
+	t.Run("Pagination", s.testPagination)
+	t.Run("Concurrency", s.testConcurrency)
+	t.Run("Context Cancellation", s.testCancellation)
+}
+
+func (s *suite) testNotFound(t *testing.T) {
+	repo, teardown := s.newRepo(t)
+	defer teardown()
+	ctx := context.Background()
+
+	_, err := repo.Find(ctx, "unknown")
+	assert.Equal(t, image.ErrNotFound, err, "Find")
+	assert.Equal(t, image.ErrNotFound, repo.Delete(ctx, "unknown"), "Delete")
+	assert.Equal(t, image.ErrNotFound, repo.Restore(ctx, "unknown"), "Restore")
+	assert.Equal(t, image.ErrNotFound, repo.Purge(ctx, "unknown"), "Purge")
+
+	images, err := repo.FindAll(ctx, 0)
+	require.NoError(t, err)
+	assert.Empty(t, images, "FindAll")
+}
+
+func (s *suite) testSave(t *testing.T) {
+	repo, teardown := s.newRepo(t)
+	defer teardown()
+	ctx := context.Background()
+
+	want := NewImage("luffy", now())
+	require.NoError(t, repo.Save(ctx, want))
+
+	other := NewImage("luffy", now())
+	require.NoError(t, repo.Save(ctx, other))
+	assert.NotEqual(t, want.ID, other.ID, "the IDs are distinct")
+
+	got, err := repo.Find(ctx, want.ImageID)
+	require.NoError(t, err)
+	AssertImage(t, want, got)
+}
+
+func (s *suite) testUniqueness(t *testing.T) {
+	repo, teardown := s.newRepo(t)
+	defer teardown()
+	ctx := context.Background()
+
+	img := NewImage("luffy", now())
+	require.NoError(t, repo.Save(ctx, img))
+
+	dup := NewImage("zoro", now())
+	dup.ImageID = img.ImageID
+	assert.Equal(t, image.ErrExists, repo.Save(ctx, dup))
+
+	got, err := repo.Find(ctx, img.ImageID)
+	require.NoError(t, err)
+	assert.Equal(t, img.UserID, got.UserID, "the stored image is kept")
+}
+
+func (s *suite) testSoftDelete(t *testing.T) {
+	repo, teardown := s.newRepo(t)
+	defer teardown()
+	ctx := context.Background()
+
+	img := NewImage("luffy", now())
+	require.NoError(t, repo.Save(ctx, img))
+	require.NoError(t, repo.Delete(ctx, img.ImageID))
+
+	_, err := repo.Find(ctx, img.ImageID)
+	assert.Equal(t, image.ErrNotFound, err, "deleted images are left out")
+	assert.Equal(t, image.ErrNotFound, repo.Delete(ctx, img.ImageID), "deleting twice")
+
+	got, err := repo.Find(softdelete.Include(ctx), img.ImageID)
+	require.NoError(t, err)
+	assert.NotNil(t, got.DeletedAt)
+
+	deleted, err := repo.FindAllDeleted(ctx, time.Now().Add(time.Minute), 10)
+	require.NoError(t, err)
+	AssertImages(t, []*image.Image{img}, deleted)
+
+	deleted, err = repo.FindAllDeleted(ctx, time.Now().Add(-time.Hour), 10)
+	require.NoError(t, err)
+	assert.Empty(t, deleted, "only the images deleted before the time")
+
+	require.NoError(t, repo.Restore(ctx, img.ImageID))
+	assert.Equal(t, image.ErrNotFound, repo.Restore(ctx, img.ImageID), "restoring twice")
+	got, err = repo.Find(ctx, img.ImageID)
+	require.NoError(t, err)
+	assert.Nil(t, got.DeletedAt)
+
+	require.NoError(t, repo.Purge(ctx, img.ImageID))
+	_, err = repo.Find(softdelete.Include(ctx), img.ImageID)
+	assert.Equal(t, image.ErrNotFound, err, "purged images are gone")
+}
+
+func (s *suite) testPagination(t *testing.T) {
+	repo, teardown := s.newRepo(t)
+	defer teardown()
+	ctx := context.Background()
+
+	// The images alternate between the users and are saved out of
+	// order to check the sorting. They're listed newest first.
+	start := now().Add(-time.Hour)
+	count := image.PageSize + 5
+	images := make([]*image.Image, count)
+	for _, i := range shuffled(count) {
+		userID := "luffy"
+		if i%3 == 0 {
+			userID = "zoro"
+		}
+		images[i] = NewImage(userID, start.Add(time.Duration(count-i)*time.Second))
+		require.NoError(t, repo.Save(ctx, images[i]))
+	}
+	other := NewImage("nami", start)
+	require.NoError(t, repo.Save(ctx, other))
+
+	byUser := func(userID string) []*image.Image {
+		var res []*image.Image
+		for _, img := range images {
+			if img.UserID == userID {
+				res = append(res, img)
+			}
+		}
+		return res
+	}
+
+	t.Run("FindAll", func(t *testing.T) {
+		all := append(images[:len(images):len(images)], other)
+
+		got, err := repo.FindAll(ctx, 0)
+		require.NoError(t, err)
+		AssertImages(t, all[:image.PageSize], got)
+
+		got, err = repo.FindAll(ctx, image.PageSize)
+		require.NoError(t, err)
+		AssertImages(t, all[image.PageSize:], got)
+	})
+
+	t.Run("FindAllByUser", func(t *testing.T) {
+		got, err := repo.FindAllByUser(ctx, "zoro", 0)
+		require.NoError(t, err)
+		AssertImages(t, byUser("zoro"), got)
+
+		got, err = repo.FindAllByUser(ctx, "zoro", 2)
+		require.NoError(t, err)
+		AssertImages(t, byUser("zoro")[2:], got)
+	})
+
+	t.Run("FindAllByUsers", func(t *testing.T) {
+		var got []*image.Image
+		var cursor string
+		for page := 0; page <= count; page++ {
+			res, next, err := repo.FindAllByUsers(ctx, []string{"luffy", "zoro"}, cursor, 4)
+			require.NoError(t, err)
+			assert.True(t, len(res) <= 4)
+			got = append(got, res...)
+			if next == "" {
+				break
+			}
+			cursor = next
+		}
+		AssertImages(t, images, got)
+	})
+
+	t.Run("FindAllByUsers with the same creation time", func(t *testing.T) {
+		createdAt := start.Add(-time.Hour)
+		first, second := NewImage("sanji", createdAt), NewImage("sanji", createdAt)
+		require.NoError(t, repo.Save(ctx, first))
+		require.NoError(t, repo.Save(ctx, second))
+
+		page, cursor, err := repo.FindAllByUsers(ctx, []string{"sanji"}, "", 1)
+		require.NoError(t, err)
+		require.NotEmpty(t, cursor)
+		rest, _, err := repo.FindAllByUsers(ctx, []string{"sanji"}, cursor, 1)
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{first.ImageID, second.ImageID}, imageIDs(append(page, rest...)))
+	})
+
+	t.Run("FindAllByUsers leaves the deleted images out", func(t *testing.T) {
+		require.NoError(t, repo.Delete(ctx, images[0].ImageID))
+		defer func() {
+			require.NoError(t, repo.Restore(ctx, images[0].ImageID))
+		}()
+
+		got, _, err := repo.FindAllByUsers(ctx, []string{"luffy", "zoro"}, "", count)
+		require.NoError(t, err)
+		AssertImages(t, images[1:], got)
+	})
+
+	t.Run("FindAllByUsers without users", func(t *testing.T) {
+		got, cursor, err := repo.FindAllByUsers(ctx, nil, "", 10)
+		require.NoError(t, err)
+		assert.Empty(t, got)
+		assert.Empty(t, cursor)
+	})
+
+	t.Run("FindAllByUsers with an invalid cursor", func(t *testing.T) {
+		_, _, err := repo.FindAllByUsers(ctx, []string{"luffy"}, "invalid", 10)
+		assert.Equal(t, imageutil.ErrInvalidCursor, err)
+	})
+}
+
+func (s *suite) testConcurrency(t *testing.T) {
+	if !s.concurrency {
+		t.Skip("The repository isn't safe for concurrent use")
+	}
+	repo, teardown := s.newRepo(t)
+	defer teardown()
+	ctx := context.Background()
+
+	t.Run("Save", func(t *testing.T) {
+		images := make([]*image.Image, Concurrency)
+		errs := make([]error, Concurrency)
+		var wg sync.WaitGroup
+		for i := range images {
+			images[i] = NewImage("luffy", now().Add(-time.Duration(i)*time.Second))
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				errs[i] = repo.Save(ctx, images[i])
+			}(i)
+		}
+		wg.Wait()
+
+		ids := make(map[uint]bool)
+		for i, img := range images {
+			require.NoError(t, errs[i])
+			assert.False(t, ids[img.ID], "the IDs are distinct")
+			ids[img.ID] = true
+		}
+
+		got, err := repo.FindAllByUser(ctx, "luffy", 0)
+		require.NoError(t, err)
+		AssertImages(t, images, got)
+	})
+
+	t.Run("Save the same image ID", func(t *testing.T) {
+		imageID := imageutil.GenerateID()
+		errs := make(chan error, Concurrency)
+		for i := 0; i < Concurrency; i++ {
+			go func() {
+				img := NewImage("zoro", now())
+				img.ImageID = imageID
+				errs <- repo.Save(ctx, img)
+			}()
+		}
+
+		var saved int
+		for i := 0; i < Concurrency; i++ {
+			switch err := <-errs; err {
+			case nil:
+				saved++
+			default:
+				assert.Equal(t, image.ErrExists, err)
+			}
+		}
+		assert.Equal(t, 1, saved, "only one image gets the ID")
+	})
+}
+
+func (s *suite) testCancellation(t *testing.T) {
+	repo, teardown := s.newRepo(t)
+	defer teardown()
+
+	img := NewImage("luffy", now())
+	require.NoError(t, repo.Save(context.Background(), img))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := repo.Find(ctx, img.ImageID)
+	assertCanceled(t, err, "Find")
+	_, err = repo.FindAll(ctx, 0)
+	assertCanceled(t, err, "FindAll")
+	_, _, err = repo.FindAllByUsers(ctx, []string{"luffy"}, "", 10)
+	assertCanceled(t, err, "FindAllByUsers")
+
+	unsaved := NewImage("luffy", now())
+	assertCanceled(t, repo.Save(ctx, unsaved), "Save")
+	_, err = repo.Find(context.Background(), unsaved.ImageID)
+	assert.Equal(t, image.ErrNotFound, err, "canceled saves are not stored")
+
+	assertCanceled(t, repo.Delete(ctx, img.ImageID), "Delete")
+	_, err = repo.Find(context.Background(), img.ImageID)
+	assert.NoError(t, err, "canceled deletes are not stored")
+}
+
+func assertCanceled(t *testing.T, err error, op string) {
+	t.Helper()
+	assert.True(t, errors.Is(err, context.Canceled), "%s: want context.Canceled, got %v", op, err)
+}
+
+// NewImage creates an image of the user with userID with a unique
+// image ID created at the given time.
+func NewImage(userID string, createdAt time.Time) *image.Image {
+	imageID := imageutil.GenerateID()
+	return &image.Image{
+		UserID:      userID,
+		ImageID:     imageID,
+		Name:        "Going Merry",
+		Location:    imageID + ".png",
+		Description: "The first ship of the Straw Hats",
+		Size:        1024,
+		CreatedAt:   &createdAt,
+		UpdatedAt:   &createdAt,
+	}
+}
+
+// AssertImage asserts that got is the stored want. The times are
+// compared by instant since the location depends on the backend.
+func AssertImage(t *testing.T, want, got *image.Image) {
+	t.Helper()
+	assert.Equal(t, want.ID, got.ID)
+	assert.Equal(t, want.UserID, got.UserID)
+	assert.Equal(t, want.ImageID, got.ImageID)
+	assert.Equal(t, want.Name, got.Name)
+	assert.Equal(t, want.Location, got.Location)
+	assert.Equal(t, want.Description, got.Description)
+	assert.Equal(t, want.Size, got.Size)
+	assertTime(t, want.CreatedAt, got.CreatedAt, "CreatedAt")
+	assertTime(t, want.UpdatedAt, got.UpdatedAt, "UpdatedAt")
+}
+
+// AssertImages asserts that got are the stored want in the same order.
+func AssertImages(t *testing.T, want, got []*image.Image) {
+	t.Helper()
+	assert.Equal(t, imageIDs(want), imageIDs(got))
+}
+
+func imageIDs(images []*image.Image) []string {
+	ids := make([]string, len(images))
+	for i, img := range images {
+		ids[i] = img.ImageID
+	}
+	return ids
+}
+
+func assertTime(t *testing.T, want, got *time.Time, field string) {
+	t.Helper()
+	if want == nil || got == nil {
+		assert.Equal(t, want == nil, got == nil, field)
+		return
+	}
+	assert.True(t, want.Equal(*got), "%s: want %v, got %v", field, *want, *got)
+}
+
+// shuffled returns the indexes up to n in a fixed scrambled order.
+func shuffled(n int) []int {
+	idx := make([]int, 0, n)
+	for i := 0; i < n; i += 2 {
+		idx = append(idx, i)
+	}
+	for i := n - 1 - n%2; i > 0; i -= 2 {
+		idx = append(idx, i)
+	}
+	return idx
+}
+
+// now returns the current time at the precision of the SQL datetimes.
+func now() time.Time {
+	return time.Now().UTC().Truncate(time.Second)
+}
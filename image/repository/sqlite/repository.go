@@ -0,0 +1,270 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"gophr.v2/event/outbox"
+	"gophr.v2/image"
+	"gophr.v2/image/imageutil"
+	"gophr.v2/log"
+	"gophr.v2/softdelete"
+	"strings"
+	"time"
+)
+
+const pageSize = image.PageSize
+
+func New(db *sql.DB) image.Repository {
+	return &repository{
+		conn: db,
+	}
+}
+
+type repository struct {
+	conn *sql.DB
+}
+
+func (r *repository) Save(ctx context.Context, image *image.Image) error {
+	query := "INSERT INTO images(userId, imageId, name, location, description, size, created_at, updated_at, deleted_at) VALUES(?,?,?,?,?,?,?,?,?)"
+	return r.doSave(func(tx *sql.Tx) error {
+		res, err := tx.ExecContext(ctx, query,
+			image.UserID,
+			image.ImageID,
+			image.Name,
+			image.Location,
+			image.Description,
+			image.Size,
+			utc(image.CreatedAt),
+			utc(image.UpdatedAt),
+			utc(image.DeletedAt),
+		)
+		if err != nil {
+			return r.checkError(err)
+		}
+
+		id, err := res.LastInsertId()
+		if err != nil {
+			return r.checkError(err)
+		}
+
+		image.ID = uint(id)
+		return outbox.Store(ctx, tx)
+	})
+}
+
+func (r *repository) doSave(fn func(tx *sql.Tx) error) (err error) {
+	tx, err := r.conn.Begin()
+	if err != nil {
+		return r.checkError(err)
+	}
+	defer func() {
+		switch err {
+		case nil:
+			err = tx.Commit()
+		default:
+			if e := tx.Rollback(); e != nil {
+				log.WithError(e).Error("failed rolling back transaction")
+			}
+		}
+	}()
+
+	return fn(tx)
+}
+
+func (r *repository) Find(ctx context.Context, id string) (*image.Image, error) {
+	query := `SELECT id, userId, imageId, name, location, description, size, created_at, updated_at, deleted_at
+						FROM images
+						WHERE imageId = ? AND ` + softdelete.SQLFilter(ctx)
+	return r.doQuerySingleReturn(ctx, query, id)
+}
+
+func (r *repository) FindAll(ctx context.Context, offset int) ([]*image.Image, error) {
+	query := `SELECT id, userId, imageId, name, location, description, size, created_at, updated_at, deleted_at
+						FROM images
+						WHERE ` + softdelete.SQLFilter(ctx) + `
+						ORDER BY created_at DESC
+						LIMIT ?
+						OFFSET ?`
+	return r.doQuery(ctx, query, pageSize, offset)
+}
+
+func (r *repository) FindAllByUser(ctx context.Context, userId string, offset int) ([]*image.Image, error) {
+	query := `SELECT id, userId, imageId, name, location, description, size, created_at, updated_at, deleted_at
+						FROM images
+						WHERE userId = ? AND ` + softdelete.SQLFilter(ctx) + `
+						ORDER BY created_at DESC
+						LIMIT ?
+						OFFSET ?`
+	return r.doQuery(ctx, query, userId, pageSize, offset)
+}
+
+// FindAllByUsers merges the images of all the users in userIds, newest
+// first. The feed is built on read with a single query using the
+// (userId, created_at) index instead of keeping a timeline per user.
+func (r *repository) FindAllByUsers(ctx context.Context, userIds []string, cursor string, num int) ([]*image.Image, string, error) {
+	if len(userIds) == 0 || num <= 0 {
+		return []*image.Image{}, "", nil
+	}
+
+	args := make([]interface{}, 0, len(userIds)+4)
+	for _, userId := range userIds {
+		args = append(args, userId)
+	}
+
+	// The first page starts from the newest image, the next ones
+	// right after the last image of the previous page
+	var after string
+	if cursor != "" {
+		createdAt, id, err := imageutil.DecodeCursor(cursor)
+		if err != nil {
+			return nil, "", err
+		}
+		after = "AND (created_at < ? OR (created_at = ? AND id < ?))"
+		args = append(args, createdAt.UTC(), createdAt.UTC(), id)
+	}
+	args = append(args, num)
+
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(userIds)), ",")
+	query := `SELECT id, userId, imageId, name, location, description, size, created_at, updated_at, deleted_at
+						FROM images
+						WHERE userId IN (` + placeholders + `)
+						` + after + `
+						AND ` + softdelete.SQLFilter(ctx) + `
+						ORDER BY created_at DESC, id DESC
+						LIMIT ?`
+
+	images, err := r.doQuery(ctx, query, args...)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var nextCursor string
+	if len(images) == num {
+		nextCursor = imageutil.EncodeCursor(images[len(images)-1])
+	}
+	return images, nextCursor, nil
+}
+
+func (r *repository) Delete(ctx context.Context, id string) error {
+	query := "UPDATE images SET deleted_at = ? WHERE imageId = ? AND deleted_at IS NULL"
+	return r.doExec(ctx, query, time.Now().UTC(), id)
+}
+
+func (r *repository) Restore(ctx context.Context, id string) error {
+	query := "UPDATE images SET deleted_at = NULL WHERE imageId = ? AND deleted_at IS NOT NULL"
+	return r.doExec(ctx, query, id)
+}
+
+func (r *repository) Purge(ctx context.Context, id string) error {
+	query := "DELETE FROM images WHERE imageId = ?"
+	return r.doExec(ctx, query, id)
+}
+
+func (r *repository) FindAllDeleted(ctx context.Context, before time.Time, num int) ([]*image.Image, error) {
+	query := `SELECT id, userId, imageId, name, location, description, size, created_at, updated_at, deleted_at
+						FROM images
+						WHERE deleted_at IS NOT NULL AND deleted_at < ?
+						ORDER BY deleted_at
+						LIMIT ?`
+	return r.doQuery(ctx, query, before.UTC(), num)
+}
+
+// doExec runs the query changing a single image, returning
+// image.ErrNotFound when there's no such image. The events recorded
+// in ctx are committed along with the change.
+func (r *repository) doExec(ctx context.Context, query string, args ...interface{}) error {
+	return r.doSave(func(tx *sql.Tx) error {
+		res, err := tx.ExecContext(ctx, query, args...)
+		if err != nil {
+			return r.checkError(err)
+		}
+
+		affected, err := res.RowsAffected()
+		if err != nil {
+			return r.checkError(err)
+		}
+		if affected == 0 {
+			return image.ErrNotFound
+		}
+		return outbox.Store(ctx, tx)
+	})
+}
+
+// utc returns t in UTC so that the times stored compare correctly.
+func utc(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	u := t.UTC()
+	return &u
+}
+
+func (r *repository) checkError(err error) error {
+	var cerr error
+	switch err {
+	case sql.ErrNoRows:
+		cerr = image.ErrNotFound
+	case nil:
+		cerr = nil
+	default:
+		cerr = duplicateError(err)
+		if cerr == nil {
+			cerr = fmt.Errorf("sqlite: unexpected error %w", err)
+		}
+	}
+	return cerr
+}
+
+func (r *repository) doQuery(ctx context.Context, query string, args ...interface{}) (images []*image.Image, err error) {
+	row, err := r.conn.QueryContext(ctx, query, args...)
+	if err != nil {
+		log.FromContext(ctx).WithError(err).Debug("failed querying")
+		return nil, r.checkError(err)
+	}
+	defer func() {
+		if e := row.Close(); err == nil && e != nil {
+			err = e
+		}
+	}()
+
+	images = make([]*image.Image, 0)
+	for row.Next() {
+		var img image.Image
+		err = row.Scan(&img.ID, &img.UserID, &img.ImageID, &img.Name, &img.Location, &img.Description, &img.Size, &img.CreatedAt, &img.UpdatedAt, &img.DeletedAt)
+		if err != nil {
+			return nil, r.checkError(err)
+		}
+		images = append(images, &img)
+	}
+	if err = row.Err(); err != nil {
+		log.FromContext(ctx).WithError(err).Debug("failed querying")
+		return nil, r.checkError(err)
+	}
+	return images, nil
+}
+
+func (r *repository) doQuerySingleReturn(ctx context.Context, query string, value interface{}) (img *image.Image, err error) {
+	images, err := r.doQuery(ctx, query, value)
+	if err != nil {
+		return nil, r.checkError(err)
+	}
+
+	if len(images) == 0 {
+		return nil, image.ErrNotFound
+	}
+	return images[0], nil
+}
+
+// errUniqueViolation starts the message of the SQLite error raised
+// when a unique index is violated.
+const errUniqueViolation = "UNIQUE constraint failed: "
+
+// duplicateError returns image.ErrExists when err is about the
+// violation of the unique image ID, or nil otherwise.
+func duplicateError(err error) error {
+	if !strings.Contains(err.Error(), errUniqueViolation) {
+		return nil
+	}
+	return image.ErrExists
+}
@@ -3,6 +3,7 @@ package image
 import (
 	"context"
 	"io"
+	"time"
 )
 
 //go:generate mockery --name=Service
@@ -12,6 +13,28 @@ type Service interface {
 	Find(ctx context.Context, id string) (*Image, error)
 	FindAll(ctx context.Context, offset int) ([]*Image, error)
 	FindAllByUser(ctx context.Context, userId string, offset int) ([]*Image, error)
+	FindAllByUsers(ctx context.Context, userIds []string, cursor string, num int) (images []*Image, nextCursor string, err error)
 	CreateImageFromURL(ctx context.Context, url, userId, description string) (*Image, error)
 	CreateImageFromFile(ctx context.Context, r io.Reader, filename, description, userId string) (*Image, error)
+	// Delete soft deletes the image with id. Its file is kept until
+	// the image is purged.
+	Delete(ctx context.Context, id string) error
+	// Restore cancels the deletion of the image with id.
+	Restore(ctx context.Context, id string) error
+	// Purge permanently deletes the image with id along with its file,
+	// whether it's soft deleted or not.
+	Purge(ctx context.Context, id string) error
+	// PurgeDeleted purges a batch of the images soft deleted before
+	// the given time and returns how many were purged.
+	PurgeDeleted(ctx context.Context, before time.Time) (int, error)
+}
+
+type Decorator func(svc Service) Service
+
+func ApplyDecorators(svc Service, d ...Decorator) Service {
+	copySvc := svc
+	for _, deco := range d {
+		copySvc = deco(copySvc)
+	}
+	return copySvc
 }
@@ -0,0 +1,79 @@
+package instrumenting
+
+import (
+	"context"
+	"github.com/prometheus/client_golang/prometheus"
+	"gophr.v2/image"
+	"gophr.v2/metrics"
+	"io"
+	"time"
+)
+
+// New returns a decorator recording the count, the outcome and the
+// latency of the calls of the image service in reg.
+func New(reg prometheus.Registerer) image.Decorator {
+	calls := metrics.NewCalls(reg, "image")
+	return func(svc image.Service) image.Service {
+		return &instrumentingDecorator{svc: svc, calls: calls}
+	}
+}
+
+type instrumentingDecorator struct {
+	svc   image.Service
+	calls *metrics.Calls
+}
+
+func (i *instrumentingDecorator) Save(ctx context.Context, img *image.Image) (err error) {
+	defer i.calls.Observe("Save", time.Now(), &err)
+	return i.svc.Save(ctx, img)
+}
+
+func (i *instrumentingDecorator) Find(ctx context.Context, id string) (img *image.Image, err error) {
+	defer i.calls.Observe("Find", time.Now(), &err)
+	return i.svc.Find(ctx, id)
+}
+
+func (i *instrumentingDecorator) FindAll(ctx context.Context, offset int) (images []*image.Image, err error) {
+	defer i.calls.Observe("FindAll", time.Now(), &err)
+	return i.svc.FindAll(ctx, offset)
+}
+
+func (i *instrumentingDecorator) FindAllByUser(ctx context.Context, userId string, offset int) (images []*image.Image, err error) {
+	defer i.calls.Observe("FindAllByUser", time.Now(), &err)
+	return i.svc.FindAllByUser(ctx, userId, offset)
+}
+
+func (i *instrumentingDecorator) FindAllByUsers(ctx context.Context, userIds []string, cursor string, num int) (images []*image.Image, nextCursor string, err error) {
+	defer i.calls.Observe("FindAllByUsers", time.Now(), &err)
+	return i.svc.FindAllByUsers(ctx, userIds, cursor, num)
+}
+
+func (i *instrumentingDecorator) CreateImageFromURL(ctx context.Context, url, userId, description string) (img *image.Image, err error) {
+	defer i.calls.Observe("CreateImageFromURL", time.Now(), &err)
+	return i.svc.CreateImageFromURL(ctx, url, userId, description)
+}
+
+func (i *instrumentingDecorator) CreateImageFromFile(ctx context.Context, r io.Reader, filename, description, userId string) (img *image.Image, err error) {
+	defer i.calls.Observe("CreateImageFromFile", time.Now(), &err)
+	return i.svc.CreateImageFromFile(ctx, r, filename, description, userId)
+}
+
+func (i *instrumentingDecorator) Delete(ctx context.Context, id string) (err error) {
+	defer i.calls.Observe("Delete", time.Now(), &err)
+	return i.svc.Delete(ctx, id)
+}
+
+func (i *instrumentingDecorator) Restore(ctx context.Context, id string) (err error) {
+	defer i.calls.Observe("Restore", time.Now(), &err)
+	return i.svc.Restore(ctx, id)
+}
+
+func (i *instrumentingDecorator) Purge(ctx context.Context, id string) (err error) {
+	defer i.calls.Observe("Purge", time.Now(), &err)
+	return i.svc.Purge(ctx, id)
+}
+
+func (i *instrumentingDecorator) PurgeDeleted(ctx context.Context, before time.Time) (n int, err error) {
+	defer i.calls.Observe("PurgeDeleted", time.Now(), &err)
+	return i.svc.PurgeDeleted(ctx, before)
+}
@@ -0,0 +1,85 @@
+package tracing
+
+import (
+	"context"
+	"gophr.v2/image"
+	gophrtracing "gophr.v2/tracing"
+	"io"
+	"time"
+)
+
+// Apply decorates svc with a span for every call. It is an
+// image.Decorator.
+func Apply(svc image.Service) image.Service {
+	return &tracingDecorator{svc}
+}
+
+type tracingDecorator struct {
+	svc image.Service
+}
+
+func (t *tracingDecorator) Save(ctx context.Context, img *image.Image) (err error) {
+	ctx, span := gophrtracing.Start(ctx, "image.Save")
+	defer gophrtracing.End(span, &err)
+	return t.svc.Save(ctx, img)
+}
+
+func (t *tracingDecorator) Find(ctx context.Context, id string) (img *image.Image, err error) {
+	ctx, span := gophrtracing.Start(ctx, "image.Find")
+	defer gophrtracing.End(span, &err)
+	return t.svc.Find(ctx, id)
+}
+
+func (t *tracingDecorator) FindAll(ctx context.Context, offset int) (images []*image.Image, err error) {
+	ctx, span := gophrtracing.Start(ctx, "image.FindAll")
+	defer gophrtracing.End(span, &err)
+	return t.svc.FindAll(ctx, offset)
+}
+
+func (t *tracingDecorator) FindAllByUser(ctx context.Context, userId string, offset int) (images []*image.Image, err error) {
+	ctx, span := gophrtracing.Start(ctx, "image.FindAllByUser")
+	defer gophrtracing.End(span, &err)
+	return t.svc.FindAllByUser(ctx, userId, offset)
+}
+
+func (t *tracingDecorator) FindAllByUsers(ctx context.Context, userIds []string, cursor string, num int) (images []*image.Image, nextCursor string, err error) {
+	ctx, span := gophrtracing.Start(ctx, "image.FindAllByUsers")
+	defer gophrtracing.End(span, &err)
+	return t.svc.FindAllByUsers(ctx, userIds, cursor, num)
+}
+
+func (t *tracingDecorator) CreateImageFromURL(ctx context.Context, url, userId, description string) (img *image.Image, err error) {
+	ctx, span := gophrtracing.Start(ctx, "image.CreateImageFromURL")
+	defer gophrtracing.End(span, &err)
+	return t.svc.CreateImageFromURL(ctx, url, userId, description)
+}
+
+func (t *tracingDecorator) CreateImageFromFile(ctx context.Context, r io.Reader, filename, description, userId string) (img *image.Image, err error) {
+	ctx, span := gophrtracing.Start(ctx, "image.CreateImageFromFile")
+	defer gophrtracing.End(span, &err)
+	return t.svc.CreateImageFromFile(ctx, r, filename, description, userId)
+}
+
+func (t *tracingDecorator) Delete(ctx context.Context, id string) (err error) {
+	ctx, span := gophrtracing.Start(ctx, "image.Delete")
+	defer gophrtracing.End(span, &err)
+	return t.svc.Delete(ctx, id)
+}
+
+func (t *tracingDecorator) Restore(ctx context.Context, id string) (err error) {
+	ctx, span := gophrtracing.Start(ctx, "image.Restore")
+	defer gophrtracing.End(span, &err)
+	return t.svc.Restore(ctx, id)
+}
+
+func (t *tracingDecorator) Purge(ctx context.Context, id string) (err error) {
+	ctx, span := gophrtracing.Start(ctx, "image.Purge")
+	defer gophrtracing.End(span, &err)
+	return t.svc.Purge(ctx, id)
+}
+
+func (t *tracingDecorator) PurgeDeleted(ctx context.Context, before time.Time) (n int, err error) {
+	ctx, span := gophrtracing.Start(ctx, "image.PurgeDeleted")
+	defer gophrtracing.End(span, &err)
+	return t.svc.PurgeDeleted(ctx, before)
+}
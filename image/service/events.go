@@ -0,0 +1,41 @@
+package service
+
+import (
+	"context"
+	"gophr.v2/event"
+	"gophr.v2/image"
+	"gophr.v2/softdelete"
+	"gophr.v2/user"
+)
+
+// Subscribe purges the images of the purged users along with
+// their files.
+func Subscribe(sub event.Subscriber, svc image.Service) {
+	sub.Subscribe(user.EventPurged, func(ctx context.Context, e event.Event) error {
+		purged, ok := e.(*user.Purged)
+		if !ok {
+			return nil
+		}
+		return deleteAllForUser(ctx, svc, purged.UserID)
+	})
+}
+
+func deleteAllForUser(ctx context.Context, svc image.Service, userID string) error {
+	// The images the user deleted are purged as well
+	ctx = softdelete.Include(ctx)
+	for {
+		// Purged images leave the first page to the next ones
+		images, err := svc.FindAllByUser(ctx, userID, 0)
+		if err != nil {
+			return err
+		}
+		if len(images) == 0 {
+			return nil
+		}
+		for _, img := range images {
+			if err := svc.Purge(ctx, img.ImageID); err != nil && err != image.ErrNotFound {
+				return err
+			}
+		}
+	}
+}
@@ -3,10 +3,12 @@ package service
 import (
 	"context"
 	"fmt"
-	"github.com/jayvib/golog"
 	"github.com/spf13/afero"
+	"gophr.v2/event"
 	"gophr.v2/image"
 	"gophr.v2/image/imageutil"
+	"gophr.v2/log"
+	"gophr.v2/softdelete"
 	"gophr.v2/util/valueutil"
 	"io"
 	"mime"
@@ -19,34 +21,183 @@ import (
 
 const DefaultImagePathLocation = "./data/images"
 
-func New(repo image.Repository, fs afero.Fs, client *http.Client) image.Service {
+// DeletedImagePathLocation is where the files of the deleted images are
+// kept until they're purged, out of the images served publicly.
+const DeletedImagePathLocation = "./data/deleted-images"
+
+// purgeBatchSize is the number of deleted images purged at once.
+const purgeBatchSize = 100
+
+// Option configures the service.
+type Option func(s *service)
+
+// WithEvents publishes the image events to publisher.
+func WithEvents(publisher event.Publisher) Option {
+	return func(s *service) {
+		s.events = publisher
+	}
+}
+
+func New(repo image.Repository, fs afero.Fs, client *http.Client, opts ...Option) image.Service {
 	if client == nil {
 		client = http.DefaultClient
 	}
 
-	if _, err := os.Stat(DefaultImagePathLocation); os.IsNotExist(err) {
-		err = fs.MkdirAll(DefaultImagePathLocation, 0777)
-		if err != nil {
-			panic(err)
+	for _, dir := range []string{DefaultImagePathLocation, DeletedImagePathLocation} {
+		if _, err := os.Stat(dir); os.IsNotExist(err) {
+			err = fs.MkdirAll(dir, 0777)
+			if err != nil {
+				panic(err)
+			}
 		}
 	}
 
-	return &service{
+	s := &service{
 		repo:   repo,
 		client: client,
 		fs:     fs,
 	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
 }
 
 type service struct {
 	repo   image.Repository
 	client *http.Client
 	fs     afero.Fs
+	events event.Publisher
+}
+
+func (s *service) Save(ctx context.Context, img *image.Image) error {
+	fillNecessaryField(img)
+	ctx = event.Record(ctx, &image.Created{Image: img})
+	if err := s.repo.Save(ctx, img); err != nil {
+		return err
+	}
+	event.DispatchCommitted(ctx, s.events)
+	return nil
+}
+
+// Delete soft deletes the image with id. Its file is moved out of the
+// images served so that the image can be restored until it's purged.
+func (s *service) Delete(ctx context.Context, id string) error {
+	img, err := s.repo.Find(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := s.moveFile(img, DefaultImagePathLocation, DeletedImagePathLocation); err != nil {
+		return err
+	}
+	ctx = event.Record(ctx, &image.Deleted{Image: img})
+	if err := s.repo.Delete(ctx, id); err != nil {
+		if err := s.moveFile(img, DeletedImagePathLocation, DefaultImagePathLocation); err != nil {
+			log.FromContext(ctx).WithError(err).Error("failed restoring image file")
+		}
+		return err
+	}
+	event.DispatchCommitted(ctx, s.events)
+	return nil
+}
+
+// Restore cancels the deletion of the image with id and serves its
+// file again.
+func (s *service) Restore(ctx context.Context, id string) error {
+	img, err := s.repo.Find(softdelete.Include(ctx), id)
+	if err != nil {
+		return err
+	}
+
+	if err := s.moveFile(img, DeletedImagePathLocation, DefaultImagePathLocation); err != nil {
+		return err
+	}
+	if err := s.repo.Restore(ctx, id); err != nil {
+		if img.DeletedAt != nil {
+			if err := s.moveFile(img, DefaultImagePathLocation, DeletedImagePathLocation); err != nil {
+				log.FromContext(ctx).WithError(err).Error("failed hiding image file")
+			}
+		}
+		return err
+	}
+	return nil
 }
 
-func (s *service) Save(ctx context.Context, image *image.Image) error {
-	fillNecessaryField(image)
-	return s.repo.Save(ctx, image)
+func (s *service) Purge(ctx context.Context, id string) error {
+	img, err := s.repo.Find(softdelete.Include(ctx), id)
+	if err != nil {
+		return err
+	}
+
+	// The images purged without being deleted first are
+	// deleted as far as the other domains are concerned
+	var events []event.Event
+	if img.DeletedAt == nil {
+		events = append(events, &image.Deleted{Image: img})
+	}
+	ctx = event.Record(ctx, events...)
+	if err := s.repo.Purge(ctx, id); err != nil {
+		return err
+	}
+
+	// The image is gone even if its file lingers
+	for _, dir := range []string{DefaultImagePathLocation, DeletedImagePathLocation} {
+		err = s.fs.Remove(filepath.Join(dir, img.Location))
+		if err != nil && !os.IsNotExist(err) {
+			log.FromContext(ctx).WithError(err).Error("failed removing image file")
+		}
+	}
+
+	event.DispatchCommitted(ctx, s.events)
+	return nil
+}
+
+// moveFile moves the file of img from the from directory to the to
+// directory. A file missing from from is left as is.
+func (s *service) moveFile(img *image.Image, from, to string) error {
+	err := s.fs.Rename(filepath.Join(from, img.Location), filepath.Join(to, img.Location))
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("problem while moving: %w", err)
+	}
+	return nil
+}
+
+func (s *service) PurgeDeleted(ctx context.Context, before time.Time) (int, error) {
+	images, err := s.repo.FindAllDeleted(ctx, before, purgeBatchSize)
+	if err != nil {
+		return 0, err
+	}
+	for i, img := range images {
+		if err := s.Purge(ctx, img.ImageID); err != nil && err != image.ErrNotFound {
+			return i, err
+		}
+	}
+	return len(images), nil
+}
+
+// RunPurge purges the images deleted more than gracePeriod ago every
+// interval until ctx is done.
+func RunPurge(ctx context.Context, svc image.Service, interval, gracePeriod time.Duration) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		for {
+			n, err := svc.PurgeDeleted(ctx, time.Now().UTC().Add(-gracePeriod))
+			if err != nil {
+				log.FromContext(ctx).WithError(err).Error("failed purging deleted images")
+			}
+			if err != nil || n < purgeBatchSize {
+				break
+			}
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
 }
 
 func fillNecessaryField(img *image.Image) {
@@ -70,10 +221,14 @@ func (s *service) FindAllByUser(ctx context.Context, userId string, offset int)
 	return s.repo.FindAllByUser(ctx, userId, offset)
 }
 
+func (s *service) FindAllByUsers(ctx context.Context, userIds []string, cursor string, num int) ([]*image.Image, string, error) {
+	return s.repo.FindAllByUsers(ctx, userIds, cursor, num)
+}
+
 func (s *service) CreateImageFromURL(ctx context.Context, imageUrl string, userId string, description string) (*image.Image, error) {
 	resp, err := s.client.Get(imageUrl)
 	if err != nil {
-		golog.Debug(err)
+		log.FromContext(ctx).WithError(err).Debug("failed fetching image url")
 		return nil, image.ErrInvalidImageURL
 	}
 
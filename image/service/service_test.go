@@ -6,15 +6,19 @@ import (
 	"bufio"
 	"bytes"
 	"context"
-	"github.com/jayvib/golog"
+	"errors"
 	"github.com/spf13/afero"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
+	"gophr.v2/event/bus"
+	eventmocks "gophr.v2/event/mocks"
 	"gophr.v2/http/httputil"
 	"gophr.v2/image"
 	"gophr.v2/image/imageutil"
 	"gophr.v2/image/mocks"
+	"gophr.v2/log"
+	"gophr.v2/user"
 	"gophr.v2/user/userutil"
 	"gophr.v2/util/valueutil"
 	"io"
@@ -73,12 +77,141 @@ func TestService_Save(t *testing.T) {
 	}
 	repo := new(mocks.Repository)
 	repo.On("Save", mock.Anything, mock.AnythingOfType("*image.Image")).Return(nil).Once()
-	svc := New(repo, dummyFileSystem, nil)
+	publisher := new(eventmocks.Publisher)
+	publisher.On("Publish", mock.Anything, &image.Created{Image: want}).Return(nil).Once()
+	svc := New(repo, dummyFileSystem, nil, WithEvents(publisher))
 	err := svc.Save(dummyContext, want)
 	assert.NoError(t, err)
 	assert.NotEmpty(t, want.ImageID)
 	assert.NotEmpty(t, want.CreatedAt)
 	repo.AssertExpectations(t)
+	publisher.AssertExpectations(t)
+}
+
+func TestService_Delete(t *testing.T) {
+	img := &image.Image{
+		UserID:   userutil.GenerateID(),
+		ImageID:  imageutil.GenerateID(),
+		Location: "luffy.png",
+	}
+	fs := afero.NewMemMapFs()
+	path := filepath.Join(DefaultImagePathLocation, img.Location)
+	require.NoError(t, afero.WriteFile(fs, path, []byte("png"), 0644))
+
+	repo := new(mocks.Repository)
+	repo.On("Find", mock.Anything, img.ImageID).Return(img, nil).Once()
+	repo.On("Delete", mock.Anything, img.ImageID).Return(nil).Once()
+	publisher := new(eventmocks.Publisher)
+	publisher.On("Publish", mock.Anything, &image.Deleted{Image: img}).Return(nil).Once()
+
+	svc := New(repo, fs, nil, WithEvents(publisher))
+	err := svc.Delete(dummyContext, img.ImageID)
+	assert.NoError(t, err)
+
+	// The file is kept out of the images served until the image is purged
+	exists, err := afero.Exists(fs, path)
+	require.NoError(t, err)
+	assert.False(t, exists)
+	exists, err = afero.Exists(fs, filepath.Join(DeletedImagePathLocation, img.Location))
+	require.NoError(t, err)
+	assert.True(t, exists)
+	repo.AssertExpectations(t)
+	publisher.AssertExpectations(t)
+
+	t.Run("Restore", func(t *testing.T) {
+		deleted := *img
+		deleted.DeletedAt = valueutil.TimePointer(time.Now())
+		repo := new(mocks.Repository)
+		repo.On("Find", mock.Anything, img.ImageID).Return(&deleted, nil).Once()
+		repo.On("Restore", mock.Anything, img.ImageID).Return(nil).Once()
+		svc := New(repo, fs, nil)
+		require.NoError(t, svc.Restore(dummyContext, img.ImageID))
+
+		exists, err := afero.Exists(fs, path)
+		require.NoError(t, err)
+		assert.True(t, exists)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("Failed", func(t *testing.T) {
+		repo := new(mocks.Repository)
+		repo.On("Find", mock.Anything, img.ImageID).Return(img, nil).Once()
+		repo.On("Delete", mock.Anything, img.ImageID).Return(errors.New("failed")).Once()
+		svc := New(repo, fs, nil)
+		assert.Error(t, svc.Delete(dummyContext, img.ImageID))
+
+		// The image is still served and so is its file
+		exists, err := afero.Exists(fs, path)
+		require.NoError(t, err)
+		assert.True(t, exists)
+	})
+
+	t.Run("Not Found", func(t *testing.T) {
+		repo := new(mocks.Repository)
+		repo.On("Find", mock.Anything, "notexists").Return(nil, image.ErrNotFound).Once()
+		svc := New(repo, fs, nil, WithEvents(publisher))
+		assert.Equal(t, image.ErrNotFound, svc.Delete(dummyContext, "notexists"))
+		repo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+	})
+}
+
+func TestService_Purge(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	deletedAt := time.Now()
+	deleted := &image.Image{ImageID: "img1", Location: "img1.png", DeletedAt: &deletedAt}
+	active := &image.Image{ImageID: "img2", Location: "img2.png"}
+	require.NoError(t, afero.WriteFile(fs, filepath.Join(DeletedImagePathLocation, deleted.Location), []byte("png"), 0644))
+	require.NoError(t, afero.WriteFile(fs, filepath.Join(DefaultImagePathLocation, active.Location), []byte("png"), 0644))
+
+	repo := new(mocks.Repository)
+	repo.On("FindAllDeleted", mock.Anything, deletedAt, purgeBatchSize).Return([]*image.Image{deleted}, nil).Once()
+	repo.On("Find", mock.Anything, "img1").Return(deleted, nil).Once()
+	repo.On("Find", mock.Anything, "img2").Return(active, nil).Once()
+	repo.On("Purge", mock.Anything, "img1").Return(nil).Once()
+	repo.On("Purge", mock.Anything, "img2").Return(nil).Once()
+	publisher := new(eventmocks.Publisher)
+	publisher.On("Publish", mock.Anything, &image.Deleted{Image: active}).Return(nil).Once()
+
+	svc := New(repo, fs, nil, WithEvents(publisher))
+	n, err := svc.PurgeDeleted(dummyContext, deletedAt)
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+
+	// Purging an image that isn't deleted yet deletes it as well
+	require.NoError(t, svc.Purge(dummyContext, "img2"))
+
+	for _, img := range []*image.Image{deleted, active} {
+		for _, dir := range []string{DefaultImagePathLocation, DeletedImagePathLocation} {
+			exists, err := afero.Exists(fs, filepath.Join(dir, img.Location))
+			require.NoError(t, err)
+			assert.False(t, exists)
+		}
+	}
+	repo.AssertExpectations(t)
+	publisher.AssertExpectations(t)
+}
+
+func TestSubscribe(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	img := &image.Image{ImageID: "img1", UserID: "luffy", Location: "img1.png"}
+	path := filepath.Join(DefaultImagePathLocation, img.Location)
+	require.NoError(t, afero.WriteFile(fs, path, []byte("png"), 0644))
+
+	repo := new(mocks.Repository)
+	repo.On("FindAllByUser", mock.Anything, "luffy", 0).Return([]*image.Image{img}, nil).Once()
+	repo.On("FindAllByUser", mock.Anything, "luffy", 0).Return([]*image.Image{}, nil).Once()
+	repo.On("Find", mock.Anything, "img1").Return(img, nil).Once()
+	repo.On("Purge", mock.Anything, "img1").Return(nil).Once()
+
+	events := bus.New()
+	defer events.Close()
+	Subscribe(events, New(repo, fs, nil))
+
+	require.NoError(t, events.Publish(context.Background(), &user.Purged{UserID: "luffy"}))
+	exists, err := afero.Exists(fs, path)
+	require.NoError(t, err)
+	assert.False(t, exists)
+	repo.AssertExpectations(t)
 }
 
 func TestService_FindAll(t *testing.T) {
@@ -161,7 +294,7 @@ func TestService_FindAllByUser(t *testing.T) {
 }
 
 func TestService_CreateImageFromURL(t *testing.T) {
-	golog.SetLevel(golog.DebugLevel)
+	log.SetLevel(log.DebugLevel)
 
 	// Learned remove server stubbing here:
 	// https://itnext.io/how-to-stub-requests-to-remote-hosts-with-go-6c2c1db32bf2
@@ -0,0 +1,71 @@
+package log
+
+import (
+	"github.com/gin-gonic/gin"
+	"gophr.v2/util/randutil"
+	"net/http"
+	"time"
+)
+
+// RequestIDHeader carries the ID of a request. The ID set by a proxy
+// in front of gophr is kept, and one is generated otherwise.
+const RequestIDHeader = "X-Request-ID"
+
+// maxRequestIDLength bounds the length of the IDs given by the
+// clients.
+const maxRequestIDLength = 64
+
+// Middleware carries a logger adding the request ID and route to its
+// entries in the context of the requests, and writes an entry once a
+// request is served. The ID is sent back in the RequestIDHeader.
+func Middleware(l *Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+
+		id := c.GetHeader(RequestIDHeader)
+		if !validRequestID(id) {
+			id = randutil.GenerateToken(12)
+		}
+		c.Header(RequestIDHeader, id)
+
+		ctx := NewContext(c.Request.Context(), l.With(Fields{
+			RequestIDKey: id,
+			RouteKey:     c.FullPath(),
+			"method":     c.Request.Method,
+		}))
+		c.Request = c.Request.WithContext(ctx)
+
+		c.Next()
+
+		// The handlers may have added fields, like the user ID
+		entry := FromContext(c.Request.Context()).With(Fields{
+			"status":   c.Writer.Status(),
+			"latency":  time.Since(start).String(),
+			"clientIp": c.ClientIP(),
+			"size":     c.Writer.Size(),
+		})
+		if len(c.Errors) > 0 {
+			entry = entry.WithField("errors", c.Errors.String())
+		}
+		if c.Writer.Status() >= http.StatusInternalServerError {
+			entry.Error("request served")
+			return
+		}
+		entry.Info("request served")
+	}
+}
+
+// validRequestID tells whether id can be logged as is.
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for _, r := range id {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_', r == '.':
+		default:
+			return false
+		}
+	}
+	return true
+}
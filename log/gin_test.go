@@ -0,0 +1,98 @@
+//+build unit
+
+package log
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func setupRouter(buf *bytes.Buffer) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(Middleware(New(buf, WithJSON())), gin.Recovery())
+	r.GET("/users/:id", func(c *gin.Context) {
+		ctx := WithFields(c.Request.Context(), Fields{UserIDKey: c.Param("id")})
+		c.Request = c.Request.WithContext(ctx)
+		FromContext(ctx).Info("getting user")
+		c.Status(http.StatusOK)
+	})
+	r.GET("/panic", func(c *gin.Context) {
+		panic("boom")
+	})
+	return r
+}
+
+// entries decodes the entries written in buf.
+func entries(t *testing.T, buf *bytes.Buffer) []map[string]interface{} {
+	t.Helper()
+	var got []map[string]interface{}
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		entry := make(map[string]interface{})
+		require.NoError(t, json.Unmarshal([]byte(line), &entry))
+		got = append(got, entry)
+	}
+	return got
+}
+
+func TestMiddleware(t *testing.T) {
+	t.Run("Generated Request ID", func(t *testing.T) {
+		var buf bytes.Buffer
+		w := httptest.NewRecorder()
+		setupRouter(&buf).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users/luffy-id", nil))
+
+		id := w.Header().Get(RequestIDHeader)
+		require.NotEmpty(t, id)
+
+		got := entries(t, &buf)
+		require.Len(t, got, 2)
+		for _, entry := range got {
+			assert.Equal(t, id, entry[RequestIDKey])
+			assert.Equal(t, "/users/:id", entry[RouteKey])
+			assert.Equal(t, "luffy-id", entry[UserIDKey])
+		}
+		assert.Equal(t, "request served", got[1]["msg"])
+		assert.Equal(t, float64(http.StatusOK), got[1]["status"])
+	})
+
+	t.Run("Given Request ID", func(t *testing.T) {
+		var buf bytes.Buffer
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/users/luffy-id", nil)
+		req.Header.Set(RequestIDHeader, "proxy-id.1")
+		setupRouter(&buf).ServeHTTP(w, req)
+
+		assert.Equal(t, "proxy-id.1", w.Header().Get(RequestIDHeader))
+		assert.Equal(t, "proxy-id.1", entries(t, &buf)[0][RequestIDKey])
+	})
+
+	t.Run("Invalid Request ID", func(t *testing.T) {
+		var buf bytes.Buffer
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/users/luffy-id", nil)
+		req.Header.Set(RequestIDHeader, "bad\nid")
+		setupRouter(&buf).ServeHTTP(w, req)
+
+		id := w.Header().Get(RequestIDHeader)
+		assert.NotEqual(t, "bad\nid", id)
+		assert.NotEmpty(t, id)
+	})
+
+	t.Run("Panic", func(t *testing.T) {
+		var buf bytes.Buffer
+		w := httptest.NewRecorder()
+		setupRouter(&buf).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/panic", nil))
+
+		got := entries(t, &buf)
+		require.Len(t, got, 1)
+		assert.Equal(t, "error", got[0]["level"])
+		assert.Equal(t, float64(http.StatusInternalServerError), got[0]["status"])
+	})
+}
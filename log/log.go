@@ -0,0 +1,170 @@
+// Package log writes structured log entries. The logger of a request
+// is carried in its context along with the fields identifying it, so
+// that every entry written while serving it can be correlated:
+//
+//	log.FromContext(ctx).WithError(err).Error("failed saving image")
+//
+// The values of the sensitive fields, such as passwords and tokens,
+// are redacted before they're written.
+package log
+
+import (
+	"github.com/sirupsen/logrus"
+	"io"
+	"os"
+)
+
+// Level is the severity of an entry.
+type Level = logrus.Level
+
+// The levels from the least to the most verbose.
+const (
+	FatalLevel = logrus.FatalLevel
+	ErrorLevel = logrus.ErrorLevel
+	WarnLevel  = logrus.WarnLevel
+	InfoLevel  = logrus.InfoLevel
+	DebugLevel = logrus.DebugLevel
+)
+
+// The fields identifying a request.
+const (
+	RequestIDKey = "requestId"
+	UserIDKey    = "userId"
+	RouteKey     = "route"
+	TraceIDKey   = "traceId"
+)
+
+// ErrorKey is the field holding the error of an entry.
+const ErrorKey = "error"
+
+// Fields are the key-value pairs attached to an entry.
+type Fields map[string]interface{}
+
+type Option func(l *logrus.Logger)
+
+// WithJSON writes the entries as JSON objects, one per line, rather
+// than as text.
+func WithJSON() Option {
+	return func(l *logrus.Logger) {
+		l.SetFormatter(&redactingFormatter{&logrus.JSONFormatter{}})
+	}
+}
+
+// WithLevel sets the least severe level written. InfoLevel is the
+// default.
+func WithLevel(level Level) Option {
+	return func(l *logrus.Logger) {
+		l.SetLevel(level)
+	}
+}
+
+// New creates a logger writing to w.
+func New(w io.Writer, opts ...Option) *Logger {
+	l := logrus.New()
+	l.SetOutput(w)
+	l.SetFormatter(&redactingFormatter{&logrus.TextFormatter{FullTimestamp: true}})
+	for _, opt := range opts {
+		opt(l)
+	}
+	return &Logger{entry: logrus.NewEntry(l)}
+}
+
+// Logger writes the entries along with its fields. It is safe for
+// concurrent use.
+type Logger struct {
+	entry *logrus.Entry
+}
+
+// With returns a logger adding fields to its entries.
+func (l *Logger) With(fields Fields) *Logger {
+	return &Logger{entry: l.entry.WithFields(logrus.Fields(fields))}
+}
+
+// WithField returns a logger adding the field to its entries.
+func (l *Logger) WithField(key string, value interface{}) *Logger {
+	return &Logger{entry: l.entry.WithField(key, value)}
+}
+
+// WithError returns a logger adding err to its entries.
+func (l *Logger) WithError(err error) *Logger {
+	return &Logger{entry: l.entry.WithError(err)}
+}
+
+// Enabled tells whether the entries of level are written.
+func (l *Logger) Enabled(level Level) bool {
+	return l.entry.Logger.IsLevelEnabled(level)
+}
+
+func (l *Logger) Debug(args ...interface{}) { l.entry.Debug(args...) }
+
+func (l *Logger) Debugf(format string, args ...interface{}) { l.entry.Debugf(format, args...) }
+
+func (l *Logger) Info(args ...interface{}) { l.entry.Info(args...) }
+
+func (l *Logger) Infof(format string, args ...interface{}) { l.entry.Infof(format, args...) }
+
+func (l *Logger) Warn(args ...interface{}) { l.entry.Warn(args...) }
+
+func (l *Logger) Warnf(format string, args ...interface{}) { l.entry.Warnf(format, args...) }
+
+func (l *Logger) Error(args ...interface{}) { l.entry.Error(args...) }
+
+func (l *Logger) Errorf(format string, args ...interface{}) { l.entry.Errorf(format, args...) }
+
+// Fatal writes the entry and exits.
+func (l *Logger) Fatal(args ...interface{}) { l.entry.Fatal(args...) }
+
+// Fatalf writes the entry and exits.
+func (l *Logger) Fatalf(format string, args ...interface{}) { l.entry.Fatalf(format, args...) }
+
+var std = New(os.Stderr)
+
+// Default returns the logger used without a logger in the context.
+func Default() *Logger {
+	return std
+}
+
+// SetDefault replaces the default logger. It is meant to be called
+// once at startup.
+func SetDefault(l *Logger) {
+	std = l
+}
+
+// SetLevel sets the least severe level written by the default
+// logger.
+func SetLevel(level Level) {
+	std.entry.Logger.SetLevel(level)
+}
+
+// The package level functions write with the default logger. They're
+// meant for the code running outside of any request.
+
+// With returns the default logger adding fields to its entries.
+func With(fields Fields) *Logger { return std.With(fields) }
+
+// WithField returns the default logger adding the field to its
+// entries.
+func WithField(key string, value interface{}) *Logger { return std.WithField(key, value) }
+
+// WithError returns the default logger adding err to its entries.
+func WithError(err error) *Logger { return std.WithError(err) }
+
+func Debug(args ...interface{}) { std.Debug(args...) }
+
+func Debugf(format string, args ...interface{}) { std.Debugf(format, args...) }
+
+func Info(args ...interface{}) { std.Info(args...) }
+
+func Infof(format string, args ...interface{}) { std.Infof(format, args...) }
+
+func Warn(args ...interface{}) { std.Warn(args...) }
+
+func Warnf(format string, args ...interface{}) { std.Warnf(format, args...) }
+
+func Error(args ...interface{}) { std.Error(args...) }
+
+func Errorf(format string, args ...interface{}) { std.Errorf(format, args...) }
+
+func Fatal(args ...interface{}) { std.Fatal(args...) }
+
+func Fatalf(format string, args ...interface{}) { std.Fatalf(format, args...) }
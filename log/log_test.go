@@ -0,0 +1,127 @@
+//+build unit
+
+package log
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+)
+
+type account struct {
+	Username string
+	Password string `json:"password"`
+	Profile  *profile
+}
+
+type profile struct {
+	Website  string
+	APIToken string
+}
+
+func decodeEntry(t *testing.T, buf *bytes.Buffer) map[string]interface{} {
+	t.Helper()
+	entry := make(map[string]interface{})
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+	return entry
+}
+
+func TestLogger_JSON(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(&buf, WithJSON())
+
+	l.With(Fields{RequestIDKey: "req-1", UserIDKey: "luffy-id"}).
+		WithError(errors.New("disk is full")).
+		Error("failed saving image")
+
+	entry := decodeEntry(t, &buf)
+	assert.Equal(t, "failed saving image", entry["msg"])
+	assert.Equal(t, "error", entry["level"])
+	assert.Equal(t, "req-1", entry[RequestIDKey])
+	assert.Equal(t, "luffy-id", entry[UserIDKey])
+	assert.Equal(t, "disk is full", entry[ErrorKey])
+}
+
+func TestLogger_Level(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(&buf)
+
+	l.Debug("hidden")
+	assert.Empty(t, buf.String())
+	assert.False(t, l.Enabled(DebugLevel))
+
+	l = New(&buf, WithLevel(DebugLevel))
+	l.Debug("shown")
+	assert.Contains(t, buf.String(), "shown")
+}
+
+func TestLogger_Redaction(t *testing.T) {
+	t.Run("Sensitive Keys", func(t *testing.T) {
+		var buf bytes.Buffer
+		New(&buf, WithJSON()).With(Fields{
+			"password":      "luffy123",
+			"newPassword":   "zoro123",
+			"Authorization": "Bearer abc",
+			"csrfToken":     "xyz",
+			"username":      "luffy",
+		}).Info("registering")
+
+		entry := decodeEntry(t, &buf)
+		assert.Equal(t, Redacted, entry["password"])
+		assert.Equal(t, Redacted, entry["newPassword"])
+		assert.Equal(t, Redacted, entry["Authorization"])
+		assert.Equal(t, Redacted, entry["csrfToken"])
+		assert.Equal(t, "luffy", entry["username"])
+	})
+
+	t.Run("Nested Fields", func(t *testing.T) {
+		var buf bytes.Buffer
+		acc := &account{
+			Username: "luffy",
+			Password: "luffy123",
+			Profile:  &profile{Website: "https://gophr.com", APIToken: "secret-token"},
+		}
+		New(&buf, WithJSON()).WithField("account", acc).Info("updating")
+
+		entry := decodeEntry(t, &buf)
+		got := entry["account"].(map[string]interface{})
+		assert.Equal(t, "luffy", got["Username"])
+		assert.Equal(t, Redacted, got["password"])
+		nested := got["Profile"].(map[string]interface{})
+		assert.Equal(t, "https://gophr.com", nested["Website"])
+		assert.Equal(t, Redacted, nested["APIToken"])
+
+		// The logged value is left as is
+		assert.Equal(t, "luffy123", acc.Password)
+		assert.NotContains(t, buf.String(), "secret-token")
+	})
+
+	t.Run("Maps", func(t *testing.T) {
+		var buf bytes.Buffer
+		New(&buf).WithField("form", map[string]string{"email": "luffy@gophr.com", "password": "luffy123"}).Info("submitted")
+
+		assert.NotContains(t, buf.String(), "luffy123")
+		assert.Contains(t, buf.String(), "luffy@gophr.com")
+	})
+}
+
+func TestContext(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(&buf, WithJSON())
+
+	assert.Equal(t, Default(), FromContext(context.Background()))
+
+	ctx := NewContext(context.Background(), l.WithField(RequestIDKey, "req-1"))
+	ctx = WithFields(ctx, Fields{UserIDKey: "luffy-id"})
+	FromContext(ctx).Info("served")
+
+	entry := decodeEntry(t, &buf)
+	assert.Equal(t, "req-1", entry[RequestIDKey])
+	assert.Equal(t, "luffy-id", entry[UserIDKey])
+	assert.Equal(t, 1, strings.Count(buf.String(), "\n"))
+}
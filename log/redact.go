@@ -0,0 +1,118 @@
+package log
+
+import (
+	"encoding/json"
+	"github.com/sirupsen/logrus"
+	"reflect"
+	"strings"
+)
+
+// Redacted replaces the values of the sensitive fields.
+const Redacted = "[REDACTED]"
+
+// sensitive are the lowercase parts of the keys whose values are
+// never written.
+var sensitive = []string{"password", "secret", "token", "authorization", "cookie"}
+
+func isSensitive(key string) bool {
+	key = strings.ToLower(key)
+	for _, s := range sensitive {
+		if strings.Contains(key, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// redactingFormatter redacts the fields of the entries before they
+// are formatted.
+type redactingFormatter struct {
+	logrus.Formatter
+}
+
+func (f *redactingFormatter) Format(e *logrus.Entry) ([]byte, error) {
+	data := make(logrus.Fields, len(e.Data))
+	for k, v := range e.Data {
+		data[k] = redact(k, v)
+	}
+
+	// The entry is shared with the hooks so a copy is formatted
+	cpy := *e
+	cpy.Data = data
+	return f.Formatter.Format(&cpy)
+}
+
+// redact returns the value to write for the field key. The values
+// holding sensitive fields, like a user with its password, are
+// written as their JSON object without them.
+func redact(key string, v interface{}) interface{} {
+	if isSensitive(key) {
+		return Redacted
+	}
+	if _, ok := v.(error); ok {
+		return v
+	}
+	if !hasSensitive(reflect.TypeOf(v), 0) {
+		return v
+	}
+
+	payload, err := json.Marshal(v)
+	if err != nil {
+		return Redacted
+	}
+	var decoded interface{}
+	if err := json.Unmarshal(payload, &decoded); err != nil {
+		return Redacted
+	}
+	return redactDecoded(decoded)
+}
+
+// maxDepth bounds the search of sensitive fields in recursive types.
+const maxDepth = 8
+
+// hasSensitive tells whether the values of t may hold sensitive
+// fields. The maps are keyed by anything so they always may.
+func hasSensitive(t reflect.Type, depth int) bool {
+	if t == nil || depth > maxDepth {
+		return false
+	}
+	switch t.Kind() {
+	case reflect.Ptr, reflect.Slice, reflect.Array:
+		return hasSensitive(t.Elem(), depth+1)
+	case reflect.Map:
+		return t.Key().Kind() == reflect.String || hasSensitive(t.Elem(), depth+1)
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			// The unexported fields aren't encoded
+			if f.PkgPath != "" {
+				continue
+			}
+			if isSensitive(f.Name) || hasSensitive(f.Type, depth+1) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func redactDecoded(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, value := range v {
+			if isSensitive(k) {
+				v[k] = Redacted
+				continue
+			}
+			v[k] = redactDecoded(value)
+		}
+		return v
+	case []interface{}:
+		for i, value := range v {
+			v[i] = redactDecoded(value)
+		}
+		return v
+	default:
+		return v
+	}
+}
@@ -0,0 +1,34 @@
+package metrics
+
+import "github.com/prometheus/client_golang/prometheus"
+
+// CacheStats collects the hits and misses of the cache of name, as
+// counted by stats.
+func CacheStats(name string, stats func() (hits, misses uint64)) prometheus.Collector {
+	desc := func(metric, help string) *prometheus.Desc {
+		return prometheus.NewDesc(prometheus.BuildFQName(Namespace, name, metric), help, nil, nil)
+	}
+	return &cacheCollector{
+		stats:  stats,
+		hits:   desc("cache_hits_total", "Count of the lookups served by the cache."),
+		misses: desc("cache_misses_total", "Count of the lookups missing the cache."),
+	}
+}
+
+type cacheCollector struct {
+	stats func() (hits, misses uint64)
+
+	hits   *prometheus.Desc
+	misses *prometheus.Desc
+}
+
+func (c *cacheCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.hits
+	ch <- c.misses
+}
+
+func (c *cacheCollector) Collect(ch chan<- prometheus.Metric) {
+	hits, misses := c.stats()
+	ch <- prometheus.MustNewConstMetric(c.hits, prometheus.CounterValue, float64(hits))
+	ch <- prometheus.MustNewConstMetric(c.misses, prometheus.CounterValue, float64(misses))
+}
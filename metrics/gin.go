@@ -0,0 +1,44 @@
+package metrics
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"strconv"
+	"time"
+)
+
+// unmatchedRoute is the route of the requests matching none, so
+// that the paths requested at random don't add series.
+const unmatchedRoute = "unmatched"
+
+// Middleware records the count and the latency of the requests by
+// method and route in reg. The routes are the patterns registered,
+// like /users/:id, rather than the paths requested.
+func Middleware(reg prometheus.Registerer) gin.HandlerFunc {
+	requests := register(reg, prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: Namespace,
+		Subsystem: "http",
+		Name:      "requests_total",
+		Help:      "Count of the requests served by method, route and status.",
+	}, []string{"method", "route", "status"})).(*prometheus.CounterVec)
+	durations := register(reg, prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: Namespace,
+		Subsystem: "http",
+		Name:      "request_duration_seconds",
+		Help:      "Latency of the requests served by method and route.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route"})).(*prometheus.HistogramVec)
+
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = unmatchedRoute
+		}
+		status := strconv.Itoa(c.Writer.Status())
+		requests.WithLabelValues(c.Request.Method, route, status).Inc()
+		durations.WithLabelValues(c.Request.Method, route).Observe(time.Since(start).Seconds())
+	}
+}
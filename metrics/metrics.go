@@ -0,0 +1,52 @@
+// Package metrics exposes the Prometheus metrics of gophr: the
+// requests served, the calls of the services, the stats of the
+// database and Redis connection pools and the ones of the caches.
+//
+// The metrics are registered in the registry given to their
+// constructors so that every binary, and every test, has its own.
+package metrics
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Namespace prefixes the names of the metrics.
+const Namespace = "gophr"
+
+// Path is where the metrics are served.
+const Path = "/metrics"
+
+// NewRegistry creates a registry along with the metrics of the Go
+// runtime and of the process.
+func NewRegistry() *prometheus.Registry {
+	reg := prometheus.NewRegistry()
+	reg.MustRegister(
+		prometheus.NewGoCollector(),
+		prometheus.NewProcessCollector(prometheus.ProcessCollectorOpts{}),
+	)
+	return reg
+}
+
+// Handler serves the metrics gathered by g in the Prometheus text
+// format.
+func Handler(g prometheus.Gatherer) gin.HandlerFunc {
+	h := promhttp.HandlerFor(g, promhttp.HandlerOpts{})
+	return func(c *gin.Context) {
+		h.ServeHTTP(c.Writer, c.Request)
+	}
+}
+
+// register registers c in reg, or returns the collector already
+// registered in its place. The collectors shared by several
+// services are created once per registry this way.
+func register(reg prometheus.Registerer, c prometheus.Collector) prometheus.Collector {
+	if err := reg.Register(c); err != nil {
+		if are, ok := err.(prometheus.AlreadyRegisteredError); ok {
+			return are.ExistingCollector
+		}
+		panic(err)
+	}
+	return c
+}
@@ -0,0 +1,114 @@
+//+build unit
+
+package metrics
+
+import (
+	"database/sql"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	_ "modernc.org/sqlite"
+)
+
+func TestMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	reg := prometheus.NewRegistry()
+	r := gin.New()
+	r.Use(Middleware(reg))
+	r.GET("/users/:id", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+	r.GET(Path, Handler(reg))
+
+	for _, path := range []string{"/users/luffy-id", "/users/zoro-id", "/unknown"} {
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	expected := `
+# HELP gophr_http_requests_total Count of the requests served by method, route and status.
+# TYPE gophr_http_requests_total counter
+gophr_http_requests_total{method="GET",route="/users/:id",status="200"} 2
+gophr_http_requests_total{method="GET",route="unmatched",status="404"} 1
+`
+	require.NoError(t, testutil.GatherAndCompare(reg, strings.NewReader(expected), "gophr_http_requests_total"))
+	families, err := reg.Gather()
+	require.NoError(t, err)
+	for _, f := range families {
+		if f.GetName() == "gophr_http_request_duration_seconds" {
+			assert.Len(t, f.GetMetric(), 2)
+		}
+	}
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, Path, nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `gophr_http_requests_total{method="GET",route="/users/:id",status="200"} 2`)
+}
+
+func TestCalls(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	users := NewCalls(reg, "user")
+	images := NewCalls(reg, "image")
+
+	observe := func(c *Calls, method string, err error) {
+		defer c.Observe(method, time.Now(), &err)
+	}
+	observe(users, "GetByUserID", nil)
+	observe(users, "GetByUserID", errors.New("not found"))
+	observe(images, "Find", nil)
+
+	expected := `
+# HELP gophr_service_calls_total Count of the calls of the services by service, method and outcome.
+# TYPE gophr_service_calls_total counter
+gophr_service_calls_total{method="Find",outcome="success",service="image"} 1
+gophr_service_calls_total{method="GetByUserID",outcome="error",service="user"} 1
+gophr_service_calls_total{method="GetByUserID",outcome="success",service="user"} 1
+`
+	require.NoError(t, testutil.GatherAndCompare(reg, strings.NewReader(expected), "gophr_service_calls_total"))
+}
+
+func TestDBStats(t *testing.T) {
+	db, err := sql.Open("sqlite", "file::memory:")
+	require.NoError(t, err)
+	defer db.Close()
+	db.SetMaxOpenConns(3)
+	require.NoError(t, db.Ping())
+
+	reg := prometheus.NewRegistry()
+	reg.MustRegister(DBStats("sqlite", db))
+
+	expected := `
+# HELP gophr_db_max_open_connections Maximum number of open connections to the database.
+# TYPE gophr_db_max_open_connections gauge
+gophr_db_max_open_connections{db="sqlite"} 3
+# HELP gophr_db_open_connections Number of established connections, in use or idle.
+# TYPE gophr_db_open_connections gauge
+gophr_db_open_connections{db="sqlite"} 1
+`
+	require.NoError(t, testutil.GatherAndCompare(reg, strings.NewReader(expected),
+		"gophr_db_max_open_connections", "gophr_db_open_connections"))
+}
+
+func TestCacheStats(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	reg.MustRegister(CacheStats("user", func() (uint64, uint64) { return 3, 1 }))
+
+	expected := `
+# HELP gophr_user_cache_hits_total Count of the lookups served by the cache.
+# TYPE gophr_user_cache_hits_total counter
+gophr_user_cache_hits_total 3
+# HELP gophr_user_cache_misses_total Count of the lookups missing the cache.
+# TYPE gophr_user_cache_misses_total counter
+gophr_user_cache_misses_total 1
+`
+	require.NoError(t, testutil.GatherAndCompare(reg, strings.NewReader(expected)))
+}
@@ -0,0 +1,114 @@
+package metrics
+
+import (
+	"database/sql"
+	"github.com/go-redis/redis/v8"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// DBStats collects the stats of the connection pool of db. The
+// metrics are labeled by name so that several databases can be
+// registered in the same registry.
+func DBStats(name string, db *sql.DB) prometheus.Collector {
+	desc := func(metric, help string) *prometheus.Desc {
+		return prometheus.NewDesc(
+			prometheus.BuildFQName(Namespace, "db", metric), help,
+			nil, prometheus.Labels{"db": name})
+	}
+	return &dbCollector{
+		db:            db,
+		maxOpen:       desc("max_open_connections", "Maximum number of open connections to the database."),
+		open:          desc("open_connections", "Number of established connections, in use or idle."),
+		inUse:         desc("in_use_connections", "Number of connections in use."),
+		idle:          desc("idle_connections", "Number of idle connections."),
+		waitCount:     desc("wait_count_total", "Count of the connections waited for."),
+		waitDuration:  desc("wait_duration_seconds_total", "Total time blocked waiting for a new connection."),
+		idleClosed:    desc("max_idle_closed_total", "Count of the connections closed due to the maximum of idle connections."),
+		lifetimeClose: desc("max_lifetime_closed_total", "Count of the connections closed due to their maximum lifetime."),
+	}
+}
+
+type dbCollector struct {
+	db *sql.DB
+
+	maxOpen       *prometheus.Desc
+	open          *prometheus.Desc
+	inUse         *prometheus.Desc
+	idle          *prometheus.Desc
+	waitCount     *prometheus.Desc
+	waitDuration  *prometheus.Desc
+	idleClosed    *prometheus.Desc
+	lifetimeClose *prometheus.Desc
+}
+
+func (c *dbCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.maxOpen
+	ch <- c.open
+	ch <- c.inUse
+	ch <- c.idle
+	ch <- c.waitCount
+	ch <- c.waitDuration
+	ch <- c.idleClosed
+	ch <- c.lifetimeClose
+}
+
+func (c *dbCollector) Collect(ch chan<- prometheus.Metric) {
+	s := c.db.Stats()
+	ch <- prometheus.MustNewConstMetric(c.maxOpen, prometheus.GaugeValue, float64(s.MaxOpenConnections))
+	ch <- prometheus.MustNewConstMetric(c.open, prometheus.GaugeValue, float64(s.OpenConnections))
+	ch <- prometheus.MustNewConstMetric(c.inUse, prometheus.GaugeValue, float64(s.InUse))
+	ch <- prometheus.MustNewConstMetric(c.idle, prometheus.GaugeValue, float64(s.Idle))
+	ch <- prometheus.MustNewConstMetric(c.waitCount, prometheus.CounterValue, float64(s.WaitCount))
+	ch <- prometheus.MustNewConstMetric(c.waitDuration, prometheus.CounterValue, s.WaitDuration.Seconds())
+	ch <- prometheus.MustNewConstMetric(c.idleClosed, prometheus.CounterValue, float64(s.MaxIdleClosed))
+	ch <- prometheus.MustNewConstMetric(c.lifetimeClose, prometheus.CounterValue, float64(s.MaxLifetimeClosed))
+}
+
+// RedisStats collects the stats of the connection pool of client.
+// The metrics are labeled by name like the ones of DBStats.
+func RedisStats(name string, client *redis.Client) prometheus.Collector {
+	desc := func(metric, help string) *prometheus.Desc {
+		return prometheus.NewDesc(
+			prometheus.BuildFQName(Namespace, "redis", metric), help,
+			nil, prometheus.Labels{"client": name})
+	}
+	return &redisCollector{
+		client:   client,
+		hits:     desc("pool_hits_total", "Count of the free connections found in the pool."),
+		misses:   desc("pool_misses_total", "Count of the free connections not found in the pool."),
+		timeouts: desc("pool_timeouts_total", "Count of the timeouts waiting for a connection."),
+		total:    desc("pool_total_connections", "Number of connections in the pool."),
+		idle:     desc("pool_idle_connections", "Number of idle connections in the pool."),
+		stale:    desc("pool_stale_connections_total", "Count of the stale connections removed from the pool."),
+	}
+}
+
+type redisCollector struct {
+	client *redis.Client
+
+	hits     *prometheus.Desc
+	misses   *prometheus.Desc
+	timeouts *prometheus.Desc
+	total    *prometheus.Desc
+	idle     *prometheus.Desc
+	stale    *prometheus.Desc
+}
+
+func (c *redisCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.hits
+	ch <- c.misses
+	ch <- c.timeouts
+	ch <- c.total
+	ch <- c.idle
+	ch <- c.stale
+}
+
+func (c *redisCollector) Collect(ch chan<- prometheus.Metric) {
+	s := c.client.PoolStats()
+	ch <- prometheus.MustNewConstMetric(c.hits, prometheus.CounterValue, float64(s.Hits))
+	ch <- prometheus.MustNewConstMetric(c.misses, prometheus.CounterValue, float64(s.Misses))
+	ch <- prometheus.MustNewConstMetric(c.timeouts, prometheus.CounterValue, float64(s.Timeouts))
+	ch <- prometheus.MustNewConstMetric(c.total, prometheus.GaugeValue, float64(s.TotalConns))
+	ch <- prometheus.MustNewConstMetric(c.idle, prometheus.GaugeValue, float64(s.IdleConns))
+	ch <- prometheus.MustNewConstMetric(c.stale, prometheus.CounterValue, float64(s.StaleConns))
+}
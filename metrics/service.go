@@ -0,0 +1,50 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"time"
+)
+
+// Calls records the calls of the methods of a service. It is what
+// the instrumenting decorators of the services are built on.
+type Calls struct {
+	calls     *prometheus.CounterVec
+	durations prometheus.ObserverVec
+}
+
+// NewCalls creates the recorder of the calls of service in reg. The
+// services share the same metrics, labeled by service.
+func NewCalls(reg prometheus.Registerer, service string) *Calls {
+	calls := register(reg, prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: Namespace,
+		Subsystem: "service",
+		Name:      "calls_total",
+		Help:      "Count of the calls of the services by service, method and outcome.",
+	}, []string{"service", "method", "outcome"})).(*prometheus.CounterVec)
+	durations := register(reg, prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: Namespace,
+		Subsystem: "service",
+		Name:      "call_duration_seconds",
+		Help:      "Latency of the calls of the services by service and method.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"service", "method"})).(*prometheus.HistogramVec)
+
+	labels := prometheus.Labels{"service": service}
+	return &Calls{
+		calls:     calls.MustCurryWith(labels),
+		durations: durations.MustCurryWith(labels),
+	}
+}
+
+// Observe records a call of method which started at start and
+// returned err. It is meant to be deferred:
+//
+//	defer c.Observe("GetByUserID", time.Now(), &err)
+func (c *Calls) Observe(method string, start time.Time, err *error) {
+	outcome := "success"
+	if err != nil && *err != nil {
+		outcome = "error"
+	}
+	c.calls.WithLabelValues(method, outcome).Inc()
+	c.durations.WithLabelValues(method).Observe(time.Since(start).Seconds())
+}
@@ -0,0 +1,75 @@
+//+build integration
+
+package migration_test
+
+import (
+	"context"
+	"database/sql"
+	"github.com/stretchr/testify/require"
+	"gophr.v2/config"
+	"gophr.v2/config/builder/viper"
+	mysqldriver "gophr.v2/driver/mysql"
+	"gophr.v2/image"
+	imagemysql "gophr.v2/image/repository/mysql"
+	imagetest "gophr.v2/image/repository/repositorytest"
+	"gophr.v2/migration"
+	"gophr.v2/user"
+	usermysql "gophr.v2/user/repository/mysql"
+	usertest "gophr.v2/user/repository/repositorytest"
+	"io/ioutil"
+	"testing"
+)
+
+// baselineDB creates a database of its own with the schema of the
+// former gophr.sql.
+func baselineDB(t *testing.T) *sql.DB {
+	builder := viper.NewViperBuilder(
+		viper.SetViperConfigName("config-dev.yaml"),
+		viper.SetViperConfigPath("testdata"))
+	conf, err := config.New(builder)
+	require.NoError(t, err)
+
+	server, err := conf.Clone()
+	require.NoError(t, err)
+	server.MySQL.Database = ""
+	admin, err := mysqldriver.Initialize(server)
+	require.NoError(t, err)
+	for _, query := range []string{
+		"DROP DATABASE IF EXISTS " + conf.MySQL.Database,
+		"CREATE DATABASE " + conf.MySQL.Database,
+	} {
+		_, err := admin.Exec(query)
+		require.NoError(t, err)
+	}
+
+	db, err := mysqldriver.Initialize(conf)
+	require.NoError(t, err)
+	schema, err := ioutil.ReadFile("testdata/gophr.sql")
+	require.NoError(t, err)
+	for _, stmt := range migration.Statements(string(schema)) {
+		_, err := db.Exec(stmt)
+		require.NoError(t, err)
+	}
+	return db
+}
+
+func TestMySQL_Baseline(t *testing.T) {
+	db := baselineDB(t)
+	_, err := migration.New(db, migration.MySQLDialect{}, migration.MySQL()).Up(context.Background())
+	require.NoError(t, err)
+
+	truncate := func(table string) func() {
+		return func() {
+			_, err := db.Exec("DELETE FROM " + table)
+			require.NoError(t, err)
+		}
+	}
+	usertest.Run(t, func(t *testing.T) (user.Repository, func()) {
+		truncate("`user`")()
+		return usermysql.New(db), truncate("`user`")
+	})
+	imagetest.Run(t, func(t *testing.T) (image.Repository, func()) {
+		truncate("images")()
+		return imagemysql.New(db), truncate("images")
+	})
+}
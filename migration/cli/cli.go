@@ -0,0 +1,62 @@
+package cli
+
+import (
+	"github.com/spf13/cobra"
+	"gophr.v2/config"
+	"gophr.v2/config/configutil"
+	mysqldriver "gophr.v2/driver/mysql"
+	postgresdriver "gophr.v2/driver/postgres"
+	sqlitedriver "gophr.v2/driver/sqlite"
+	"gophr.v2/migration"
+	"log"
+)
+
+var (
+	steps int
+	dir   string
+)
+
+func init() {
+	MigrateCmd.AddCommand(upCmd, downCmd, statusCmd, createCmd)
+
+	downCmd.Flags().IntVar(&steps, "steps", 1, "Number of migrations to revert")
+	createCmd.Flags().StringVar(&dir, "dir", migration.DefaultDirectory, "Directory of the migration files, migration/postgres or migration/sqlite for the other databases")
+}
+
+var MigrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "A subcommand for migrating the schema of the gophr database",
+	Long: `
+DESCRIPTION:
+  migrate applies and reverts the migrations embedded in the binary
+  to the configured MySQL, PostgreSQL or SQLite database. Only one
+  migration runs at a time across all the instances sharing the
+  database.
+`,
+}
+
+// newMigrator creates the migrator of the configured database along
+// with the function closing it.
+func newMigrator() (*migration.Migrator, func() error) {
+	conf := configutil.Initialize()
+	switch conf.Gophr.Database {
+	case config.PostgresDatabase:
+		db, err := postgresdriver.Initialize(conf)
+		if err != nil {
+			log.Fatal(err)
+		}
+		return migration.New(db, migration.PostgresDialect{}, migration.Postgres()), db.Close
+	case config.SQLiteDatabase:
+		db, err := sqlitedriver.Initialize(conf)
+		if err != nil {
+			log.Fatal(err)
+		}
+		return migration.New(db, migration.SQLiteDialect{}, migration.SQLite()), db.Close
+	}
+
+	db, err := mysqldriver.Initialize(conf)
+	if err != nil {
+		log.Fatal(err)
+	}
+	return migration.New(db, migration.MySQLDialect{}, migration.MySQL()), db.Close
+}
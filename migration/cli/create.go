@@ -0,0 +1,31 @@
+package cli
+
+import (
+	"fmt"
+	"github.com/spf13/cobra"
+	"gophr.v2/migration"
+	"log"
+)
+
+var createCmd = &cobra.Command{
+	Use:   "create <name>",
+	Short: "create writes the files of a new migration",
+	Long: `
+DESCRIPTION:
+  create writes the empty up and down files of a new migration
+  versioned after the last one in --dir. Run go generate in the
+  migration package afterwards to embed them in the binary.
+
+EXAMPLE:
+  gophr migrate create add_user_location
+`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		up, down, err := migration.Create(dir, args[0])
+		if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Println("Created:", up)
+		fmt.Println("Created:", down)
+	},
+}
@@ -0,0 +1,33 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"github.com/spf13/cobra"
+	"log"
+)
+
+var downCmd = &cobra.Command{
+	Use:   "down",
+	Short: "down reverts the last applied migrations",
+	Long: `
+DESCRIPTION:
+  down reverts the last --steps applied migrations, newest first.
+
+EXAMPLE:
+  gophr migrate down
+  gophr migrate down --steps 3
+`,
+	Run: func(cmd *cobra.Command, args []string) {
+		migrator, closer := newMigrator()
+		defer closer()
+
+		reverted, err := migrator.Down(context.Background(), steps)
+		for _, m := range reverted {
+			fmt.Println("Reverted:", m)
+		}
+		if err != nil {
+			log.Fatal(err)
+		}
+	},
+}
@@ -0,0 +1,42 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"github.com/spf13/cobra"
+	"log"
+	"os"
+	"text/tabwriter"
+	"time"
+)
+
+var statusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "status lists the migrations and whether they are applied",
+	Run: func(cmd *cobra.Command, args []string) {
+		migrator, closer := newMigrator()
+		defer closer()
+
+		statuses, err := migrator.Status(context.Background())
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "VERSION\tNAME\tSTATUS\tAPPLIED AT")
+		for _, s := range statuses {
+			state, appliedAt := "pending", ""
+			if s.IsApplied() {
+				state, appliedAt = "applied", s.AppliedAt.Format(time.RFC3339)
+			}
+			switch {
+			case s.Migration == nil:
+				state = "unknown"
+			case s.Modified:
+				state = "modified"
+			}
+			fmt.Fprintf(w, "%04d\t%s\t%s\t%s\n", s.Version, s.Name, state, appliedAt)
+		}
+		_ = w.Flush()
+	},
+}
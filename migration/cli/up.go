@@ -0,0 +1,36 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"github.com/spf13/cobra"
+	"log"
+)
+
+var upCmd = &cobra.Command{
+	Use:   "up",
+	Short: "up applies the pending migrations",
+	Long: `
+DESCRIPTION:
+  up applies the pending migrations in order. It refuses to run when
+  an applied migration was modified since.
+
+EXAMPLE:
+  gophr migrate up
+`,
+	Run: func(cmd *cobra.Command, args []string) {
+		migrator, closer := newMigrator()
+		defer closer()
+
+		applied, err := migrator.Up(context.Background())
+		for _, m := range applied {
+			fmt.Println("Applied:", m)
+		}
+		if err != nil {
+			log.Fatal(err)
+		}
+		if len(applied) == 0 {
+			fmt.Println("The schema is up to date")
+		}
+	},
+}
@@ -0,0 +1,69 @@
+package migration
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+)
+
+// DefaultDirectory holds the MySQL migration files.
+const DefaultDirectory = "migration/mysql"
+
+var validName = regexp.MustCompile(`^\w+$`)
+
+// Create writes the empty up and down files of the migration named
+// name in dir, versioned after the last migration there. It returns
+// the paths of the files.
+func Create(dir, name string) (up, down string, err error) {
+	if !validName.MatchString(name) {
+		return "", "", fmt.Errorf("%w: %s", ErrInvalidName, name)
+	}
+
+	entries, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return "", "", err
+	}
+	var last int64
+	for _, entry := range entries {
+		match := fileName.FindStringSubmatch(entry.Name())
+		if match == nil {
+			continue
+		}
+		version, err := strconv.ParseInt(match[1], 10, 64)
+		if err != nil {
+			return "", "", fmt.Errorf("%w: %s", ErrInvalidName, entry.Name())
+		}
+		if version > last {
+			last = version
+		}
+	}
+
+	m := &Migration{Version: last + 1, Name: name}
+	up = filepath.Join(dir, m.String()+".up.sql")
+	down = filepath.Join(dir, m.String()+".down.sql")
+	if err := writeNew(up, "-- "+m.String()+"\n"); err != nil {
+		return "", "", err
+	}
+	if err := writeNew(down, "-- Reverts "+m.String()+"\n"); err != nil {
+		_ = os.Remove(up)
+		return "", "", err
+	}
+	return up, down, nil
+}
+
+// writeNew writes content to the file at path, failing when the file
+// exists.
+func writeNew(path, content string) error {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.WriteString(content); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,145 @@
+package migration
+
+import (
+	"context"
+	"database/sql"
+	"gophr.v2/driver/postgres"
+	"hash/fnv"
+	"time"
+)
+
+// lockName is the name of the lock held while migrating.
+const lockName = "gophr.schema_migrations"
+
+// Dialect holds what differs between the databases when migrating.
+type Dialect interface {
+	// CreateTable returns the statement creating the
+	// schema_migrations table when missing.
+	CreateTable() string
+	// Lock waits up to timeout for the migration lock of the database
+	// and holds it on conn. It returns ErrLocked on timeout.
+	Lock(ctx context.Context, conn *sql.Conn, timeout time.Duration) error
+	// Unlock releases the migration lock held on conn.
+	Unlock(ctx context.Context, conn *sql.Conn) error
+	// Rebind rewrites the ? placeholders of query for the database.
+	Rebind(query string) string
+}
+
+// MySQLDialect migrates MySQL databases. The lock is a named lock
+// so that it's released along with the connection of a crashed
+// migration.
+type MySQLDialect struct{}
+
+func (MySQLDialect) CreateTable() string {
+	return `CREATE TABLE IF NOT EXISTS schema_migrations(
+  version bigint(20) NOT NULL,
+  name varchar(255) NOT NULL,
+  checksum char(64) NOT NULL,
+  applied_at datetime NOT NULL,
+  PRIMARY KEY (version)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8 COLLATE=utf8_unicode_ci`
+}
+
+func (MySQLDialect) Lock(ctx context.Context, conn *sql.Conn, timeout time.Duration) error {
+	var obtained sql.NullInt64
+	err := conn.QueryRowContext(ctx, "SELECT GET_LOCK(?, ?)", lockName, int(timeout.Seconds())).Scan(&obtained)
+	if err != nil {
+		return err
+	}
+	if obtained.Int64 != 1 {
+		return ErrLocked
+	}
+	return nil
+}
+
+func (MySQLDialect) Unlock(ctx context.Context, conn *sql.Conn) error {
+	_, err := conn.ExecContext(ctx, "SELECT RELEASE_LOCK(?)", lockName)
+	return err
+}
+
+func (MySQLDialect) Rebind(query string) string {
+	return query
+}
+
+// lockKey is the key of the advisory lock of PostgreSQL, which only
+// takes numbers. It's the FNV-1a hash of the lockName.
+var lockKey = func() int64 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(lockName))
+	return int64(h.Sum64())
+}()
+
+// lockPollInterval is how often the PostgreSQL lock is tried.
+const lockPollInterval = 500 * time.Millisecond
+
+// PostgresDialect migrates PostgreSQL databases. The lock is a
+// session level advisory lock so that it's released along with the
+// connection of a crashed migration.
+type PostgresDialect struct{}
+
+func (PostgresDialect) CreateTable() string {
+	return `CREATE TABLE IF NOT EXISTS schema_migrations(
+  version bigint NOT NULL,
+  name varchar(255) NOT NULL,
+  checksum char(64) NOT NULL,
+  applied_at timestamp NOT NULL,
+  PRIMARY KEY (version)
+)`
+}
+
+// Lock polls the lock since pg_advisory_lock can't time out.
+func (PostgresDialect) Lock(ctx context.Context, conn *sql.Conn, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for {
+		var obtained bool
+		if err := conn.QueryRowContext(ctx, "SELECT pg_try_advisory_lock($1)", lockKey).Scan(&obtained); err != nil {
+			return err
+		}
+		if obtained {
+			return nil
+		}
+		if !time.Now().Before(deadline) {
+			return ErrLocked
+		}
+
+		select {
+		case <-time.After(lockPollInterval):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+func (PostgresDialect) Unlock(ctx context.Context, conn *sql.Conn) error {
+	_, err := conn.ExecContext(ctx, "SELECT pg_advisory_unlock($1)", lockKey)
+	return err
+}
+
+func (PostgresDialect) Rebind(query string) string {
+	return postgres.Rebind(query)
+}
+
+// SQLiteDialect migrates SQLite databases. There's no lock since an
+// SQLite database belongs to a single instance.
+type SQLiteDialect struct{}
+
+func (SQLiteDialect) CreateTable() string {
+	return `CREATE TABLE IF NOT EXISTS schema_migrations(
+  version bigint NOT NULL PRIMARY KEY,
+  name varchar(255) NOT NULL,
+  checksum char(64) NOT NULL,
+  applied_at datetime NOT NULL
+)`
+}
+
+func (SQLiteDialect) Lock(ctx context.Context, conn *sql.Conn, timeout time.Duration) error {
+	return nil
+}
+
+func (SQLiteDialect) Unlock(ctx context.Context, conn *sql.Conn) error {
+	return nil
+}
+
+func (SQLiteDialect) Rebind(query string) string {
+	return query
+}
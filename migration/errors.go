@@ -0,0 +1,12 @@
+package migration
+
+import "errors"
+
+var (
+	ErrInvalidName      = errors.New("migration: invalid file name")
+	ErrDuplicateVersion = errors.New("migration: version used by more than one migration")
+	ErrIncomplete       = errors.New("migration: up or down file is missing")
+	ErrChecksumMismatch = errors.New("migration: applied migration was modified")
+	ErrUnknownVersion   = errors.New("migration: applied migration is unknown")
+	ErrLocked           = errors.New("migration: timed out waiting for another migration to finish")
+)
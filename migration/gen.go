@@ -0,0 +1,61 @@
+// +build ignore
+
+// gen embeds the migration files in the binary by writing the files
+// of each directory to <dir>_files.go.
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"io/ioutil"
+	"log"
+	"path/filepath"
+	"sort"
+	"strconv"
+)
+
+// dirs are the directories of the migration files by the name of
+// the variable embedding them.
+var dirs = map[string]string{
+	"mysqlFiles":    "mysql",
+	"postgresFiles": "postgres",
+	"sqliteFiles":   "sqlite",
+}
+
+func main() {
+	for name, dir := range dirs {
+		if err := generate(name, dir); err != nil {
+			log.Fatal(err)
+		}
+	}
+}
+
+// generate writes the files in dir to <dir>_files.go as the
+// variable name.
+func generate(name, dir string) error {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.sql"))
+	if err != nil {
+		return err
+	}
+	sort.Strings(paths)
+
+	var b bytes.Buffer
+	b.WriteString("// Code generated by go generate; DO NOT EDIT.\n\n")
+	b.WriteString("package migration\n\n")
+	fmt.Fprintf(&b, "var %s = map[string]string{\n", name)
+	for _, path := range paths {
+		content, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(&b, "%s: %s,\n", strconv.Quote(filepath.Base(path)), strconv.Quote(string(content)))
+	}
+	b.WriteString("}\n")
+
+	src, err := format.Source(b.Bytes())
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(dir+"_files.go", src, 0644)
+}
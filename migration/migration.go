@@ -0,0 +1,141 @@
+// Package migration versions the SQL schema of the gophr services.
+//
+// A migration is a pair of files named <version>_<name>.up.sql and
+// <version>_<name>.down.sql, the latter reverting the former. The
+// files in the mysql, postgres and sqlite directories are embedded in
+// the binary by running go generate in this package. The applied migrations are tracked in
+// the schema_migrations table along with the checksum of their up
+// file so that editing an applied migration is caught.
+//
+// The first MySQL migrations create the tables the databases used to
+// be created with from gophr.sql, as they were then, only when they're
+// missing. Those databases are migrated from there by the later ones.
+package migration
+
+//go:generate go run gen.go
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// fileName matches the names of the migration files.
+var fileName = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+// Migration is a version of the schema.
+type Migration struct {
+	Version int64
+	Name    string
+	Up      string
+	Down    string
+}
+
+func (m *Migration) String() string {
+	return fmt.Sprintf("%04d_%s", m.Version, m.Name)
+}
+
+// Checksum is the hex encoded SHA-256 of the up file.
+func (m *Migration) Checksum() string {
+	sum := sha256.Sum256([]byte(m.Up))
+	return hex.EncodeToString(sum[:])
+}
+
+// Statements splits the SQL of a migration file into the statements
+// to run one at a time. Statements end with a semicolon at the end
+// of a line, and lines starting with -- are comments.
+func Statements(sql string) []string {
+	var statements []string
+	var b strings.Builder
+	for _, line := range strings.Split(sql, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "--") {
+			continue
+		}
+		b.WriteString(line)
+		b.WriteString("\n")
+		if strings.HasSuffix(trimmed, ";") {
+			statements = append(statements, strings.TrimSpace(b.String()))
+			b.Reset()
+		}
+	}
+	if rest := strings.TrimSpace(b.String()); rest != "" {
+		statements = append(statements, rest)
+	}
+	return statements
+}
+
+// Parse creates the migrations from their files by name, ordered
+// by version. Every up file needs its down file.
+func Parse(files map[string]string) ([]*Migration, error) {
+	byVersion := make(map[int64]*Migration)
+	for name, content := range files {
+		match := fileName.FindStringSubmatch(name)
+		if match == nil {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidName, name)
+		}
+		version, err := strconv.ParseInt(match[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidName, name)
+		}
+
+		m, ok := byVersion[version]
+		if !ok {
+			m = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = m
+		}
+		if m.Name != match[2] {
+			return nil, fmt.Errorf("%w: %d", ErrDuplicateVersion, version)
+		}
+		if match[3] == "up" {
+			m.Up = content
+		} else {
+			m.Down = content
+		}
+	}
+
+	migrations := make([]*Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.Up == "" || m.Down == "" {
+			return nil, fmt.Errorf("%w: %s", ErrIncomplete, m)
+		}
+		migrations = append(migrations, m)
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+	return migrations, nil
+}
+
+// MySQL returns the migrations of the MySQL schema.
+func MySQL() []*Migration {
+	migrations, err := Parse(mysqlFiles)
+	if err != nil {
+		panic(err)
+	}
+	return migrations
+}
+
+// Postgres returns the migrations of the PostgreSQL schema. It only
+// holds the tables of the repositories that can be stored there.
+func Postgres() []*Migration {
+	migrations, err := Parse(postgresFiles)
+	if err != nil {
+		panic(err)
+	}
+	return migrations
+}
+
+// SQLite returns the migrations of the SQLite schema. It holds the
+// tables of the repositories that can be stored there.
+func SQLite() []*Migration {
+	migrations, err := Parse(sqliteFiles)
+	if err != nil {
+		panic(err)
+	}
+	return migrations
+}
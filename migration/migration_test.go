@@ -0,0 +1,100 @@
+//+build unit
+
+package migration
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestStatements(t *testing.T) {
+	sql := `-- Creates the tables
+CREATE TABLE a(
+  id int -- the ID
+);
+
+CREATE TABLE b(id int);
+INSERT INTO b VALUES(1)`
+
+	want := []string{
+		"CREATE TABLE a(\n  id int -- the ID\n);",
+		"CREATE TABLE b(id int);",
+		"INSERT INTO b VALUES(1)",
+	}
+	assert.Equal(t, want, Statements(sql))
+}
+
+func TestParse(t *testing.T) {
+	t.Run("Ordered by version", func(t *testing.T) {
+		migrations, err := Parse(map[string]string{
+			"0002_b.up.sql":   "up b",
+			"0002_b.down.sql": "down b",
+			"0001_a.up.sql":   "up a",
+			"0001_a.down.sql": "down a",
+		})
+		require.NoError(t, err)
+		require.Len(t, migrations, 2)
+		assert.Equal(t, &Migration{Version: 1, Name: "a", Up: "up a", Down: "down a"}, migrations[0])
+		assert.Equal(t, "0002_b", migrations[1].String())
+	})
+
+	tests := map[string]struct {
+		files map[string]string
+		want  error
+	}{
+		"Invalid name":      {files: map[string]string{"a.up.sql": "up"}, want: ErrInvalidName},
+		"Duplicate version": {files: map[string]string{"1_a.up.sql": "up", "1_b.down.sql": "down"}, want: ErrDuplicateVersion},
+		"Missing down":      {files: map[string]string{"1_a.up.sql": "up"}, want: ErrIncomplete},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := Parse(tt.files)
+			assert.True(t, errors.Is(err, tt.want), err)
+		})
+	}
+}
+
+func TestEmbedded(t *testing.T) {
+	embedded := map[string]func() []*Migration{
+		"MySQL":    MySQL,
+		"Postgres": Postgres,
+		"SQLite":   SQLite,
+	}
+	for name, migrations := range embedded {
+		t.Run(name, func(t *testing.T) {
+			migrations := migrations()
+			require.NotEmpty(t, migrations)
+			for i, m := range migrations {
+				assert.Equal(t, int64(i+1), m.Version, "the versions are sequential")
+				assert.NotEmpty(t, Statements(m.Up), m.String())
+				assert.NotEmpty(t, Statements(m.Down), m.String())
+			}
+		})
+	}
+}
+
+func TestCreate(t *testing.T) {
+	dir, err := ioutil.TempDir("", "migration")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "0007_create_outbox.up.sql"), nil, 0644))
+
+	up, down, err := Create(dir, "add_location")
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "0008_add_location.up.sql"), up)
+	assert.Equal(t, filepath.Join(dir, "0008_add_location.down.sql"), down)
+	assert.FileExists(t, up)
+	assert.FileExists(t, down)
+
+	t.Run("Invalid name", func(t *testing.T) {
+		_, _, err := Create(dir, "add location")
+		assert.True(t, errors.Is(err, ErrInvalidName))
+	})
+}
+
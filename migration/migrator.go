@@ -0,0 +1,240 @@
+package migration
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// DefaultLockTimeout is how long a migration waits for the others
+// to finish.
+const DefaultLockTimeout = time.Minute
+
+// Option configures the Migrator.
+type Option func(m *Migrator)
+
+// WithLockTimeout replaces the DefaultLockTimeout.
+func WithLockTimeout(timeout time.Duration) Option {
+	return func(m *Migrator) {
+		m.lockTimeout = timeout
+	}
+}
+
+// Status tells whether a migration is applied. Migration is nil for
+// the applied migrations unknown to this binary.
+type Status struct {
+	Version   int64
+	Name      string
+	Migration *Migration
+	AppliedAt *time.Time
+	// Modified tells that the migration changed since it was applied.
+	Modified bool
+}
+
+func (s *Status) IsApplied() bool {
+	return s.AppliedAt != nil
+}
+
+// record is a row of the schema_migrations table.
+type record struct {
+	version   int64
+	name      string
+	checksum  string
+	appliedAt time.Time
+}
+
+// New creates a migrator applying migrations to db. Only one migrator
+// runs at a time across all the instances sharing db.
+func New(db *sql.DB, dialect Dialect, migrations []*Migration, opts ...Option) *Migrator {
+	m := &Migrator{
+		db:          db,
+		dialect:     dialect,
+		migrations:  migrations,
+		lockTimeout: DefaultLockTimeout,
+		now:         time.Now,
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m
+}
+
+type Migrator struct {
+	db          *sql.DB
+	dialect     Dialect
+	migrations  []*Migration
+	lockTimeout time.Duration
+	now         func() time.Time
+}
+
+// Up applies the pending migrations in order and returns them. It
+// refuses to run when an applied migration was modified.
+func (m *Migrator) Up(ctx context.Context) (applied []*Migration, err error) {
+	err = m.withLock(ctx, func(conn *sql.Conn, records map[int64]*record) error {
+		for _, mig := range m.migrations {
+			if r, ok := records[mig.Version]; ok && r.checksum != mig.Checksum() {
+				return fmt.Errorf("%w: %s", ErrChecksumMismatch, mig)
+			}
+		}
+
+		for _, mig := range m.migrations {
+			if _, ok := records[mig.Version]; ok {
+				continue
+			}
+			if err := m.apply(ctx, conn, mig); err != nil {
+				return err
+			}
+			applied = append(applied, mig)
+		}
+		return nil
+	})
+	return applied, err
+}
+
+// Down reverts the last steps applied migrations, newest first,
+// and returns them. It refuses to run when one of them is unknown
+// to this binary.
+func (m *Migrator) Down(ctx context.Context, steps int) (reverted []*Migration, err error) {
+	err = m.withLock(ctx, func(conn *sql.Conn, records map[int64]*record) error {
+		known := make(map[int64]*Migration, len(m.migrations))
+		for _, mig := range m.migrations {
+			known[mig.Version] = mig
+		}
+
+		versions := make([]int64, 0, len(records))
+		for version := range records {
+			versions = append(versions, version)
+		}
+		sort.Slice(versions, func(i, j int) bool {
+			return versions[i] > versions[j]
+		})
+		if len(versions) > steps {
+			versions = versions[:steps]
+		}
+
+		var pending []*Migration
+		for _, version := range versions {
+			mig, ok := known[version]
+			if !ok {
+				return fmt.Errorf("%w: %04d_%s", ErrUnknownVersion, version, records[version].name)
+			}
+			pending = append(pending, mig)
+		}
+
+		for _, mig := range pending {
+			if err := m.revert(ctx, conn, mig); err != nil {
+				return err
+			}
+			reverted = append(reverted, mig)
+		}
+		return nil
+	})
+	return reverted, err
+}
+
+// Status returns the status of every migration, known or applied,
+// ordered by version.
+func (m *Migrator) Status(ctx context.Context) (statuses []*Status, err error) {
+	err = m.withLock(ctx, func(conn *sql.Conn, records map[int64]*record) error {
+		for _, mig := range m.migrations {
+			s := &Status{Version: mig.Version, Name: mig.Name, Migration: mig}
+			if r, ok := records[mig.Version]; ok {
+				appliedAt := r.appliedAt
+				s.AppliedAt = &appliedAt
+				s.Modified = r.checksum != mig.Checksum()
+				delete(records, mig.Version)
+			}
+			statuses = append(statuses, s)
+		}
+		for _, r := range records {
+			appliedAt := r.appliedAt
+			statuses = append(statuses, &Status{Version: r.version, Name: r.name, AppliedAt: &appliedAt})
+		}
+		return nil
+	})
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Version < statuses[j].Version
+	})
+	return statuses, err
+}
+
+// withLock runs fn holding the migration lock on a connection of its
+// own, along with the records of the applied migrations.
+func (m *Migrator) withLock(ctx context.Context, fn func(conn *sql.Conn, records map[int64]*record) error) (err error) {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if err := m.dialect.Lock(ctx, conn, m.lockTimeout); err != nil {
+		return err
+	}
+	defer func() {
+		if e := m.dialect.Unlock(ctx, conn); err == nil && e != nil {
+			err = e
+		}
+	}()
+
+	if _, err := conn.ExecContext(ctx, m.dialect.CreateTable()); err != nil {
+		return fmt.Errorf("migration: creating schema_migrations: %w", err)
+	}
+	records, err := m.records(ctx, conn)
+	if err != nil {
+		return err
+	}
+	return fn(conn, records)
+}
+
+func (m *Migrator) records(ctx context.Context, conn *sql.Conn) (records map[int64]*record, err error) {
+	rows, err := conn.QueryContext(ctx, "SELECT version, name, checksum, applied_at FROM schema_migrations")
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if e := rows.Close(); err == nil && e != nil {
+			err = e
+		}
+	}()
+
+	records = make(map[int64]*record)
+	for rows.Next() {
+		r := new(record)
+		if err := rows.Scan(&r.version, &r.name, &r.checksum, &r.appliedAt); err != nil {
+			return nil, err
+		}
+		records[r.version] = r
+	}
+	return records, rows.Err()
+}
+
+// apply runs the up statements of mig and records it. The statements
+// are run one by one since most databases commit schema changes
+// right away anyway.
+func (m *Migrator) apply(ctx context.Context, conn *sql.Conn, mig *Migration) error {
+	for _, stmt := range Statements(mig.Up) {
+		if _, err := conn.ExecContext(ctx, stmt); err != nil {
+			return fmt.Errorf("migration: applying %s: %w", mig, err)
+		}
+	}
+	query := "INSERT INTO schema_migrations(version, name, checksum, applied_at) VALUES(?,?,?,?)"
+	if _, err := conn.ExecContext(ctx, m.dialect.Rebind(query), mig.Version, mig.Name, mig.Checksum(), m.now().UTC()); err != nil {
+		return fmt.Errorf("migration: recording %s: %w", mig, err)
+	}
+	return nil
+}
+
+func (m *Migrator) revert(ctx context.Context, conn *sql.Conn, mig *Migration) error {
+	for _, stmt := range Statements(mig.Down) {
+		if _, err := conn.ExecContext(ctx, stmt); err != nil {
+			return fmt.Errorf("migration: reverting %s: %w", mig, err)
+		}
+	}
+	query := "DELETE FROM schema_migrations WHERE version = ?"
+	if _, err := conn.ExecContext(ctx, m.dialect.Rebind(query), mig.Version); err != nil {
+		return fmt.Errorf("migration: recording the revert of %s: %w", mig, err)
+	}
+	return nil
+}
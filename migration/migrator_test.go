@@ -0,0 +1,156 @@
+//+build unit
+
+package migration
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+var testMigrations = []*Migration{
+	{Version: 1, Name: "create_a", Up: "CREATE TABLE a(id int);", Down: "DROP TABLE a;"},
+	{Version: 2, Name: "create_b", Up: "CREATE TABLE b(id int);\nCREATE INDEX b_id ON b(id);", Down: "DROP TABLE b;"},
+}
+
+func setup(t *testing.T) (*Migrator, sqlmock.Sqlmock, func() error) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	m := New(db, MySQLDialect{}, testMigrations, WithLockTimeout(5*time.Second))
+	return m, mock, db.Close
+}
+
+// expectLock expects the lock to be taken and the applied migrations
+// to be read.
+func expectLock(mock sqlmock.Sqlmock, applied ...*Migration) {
+	mock.ExpectQuery("SELECT GET_LOCK").
+		WithArgs(lockName, 5).
+		WillReturnRows(sqlmock.NewRows([]string{"lock"}).AddRow(1))
+	mock.ExpectExec("CREATE TABLE IF NOT EXISTS schema_migrations").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	rows := sqlmock.NewRows([]string{"version", "name", "checksum", "applied_at"})
+	for _, m := range applied {
+		rows.AddRow(m.Version, m.Name, m.Checksum(), time.Now())
+	}
+	mock.ExpectQuery("SELECT version, name, checksum, applied_at FROM schema_migrations").
+		WillReturnRows(rows)
+}
+
+func expectUnlock(mock sqlmock.Sqlmock) {
+	mock.ExpectExec("SELECT RELEASE_LOCK").
+		WithArgs(lockName).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+}
+
+func TestMigrator_Up(t *testing.T) {
+	t.Run("Applies the pending migrations", func(t *testing.T) {
+		m, mock, closer := setup(t)
+		defer closer()
+
+		expectLock(mock, testMigrations[0])
+		mock.ExpectExec("CREATE TABLE b").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("CREATE INDEX b_id").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("INSERT INTO schema_migrations").
+			WithArgs(int64(2), "create_b", testMigrations[1].Checksum(), sqlmock.AnyArg()).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		expectUnlock(mock)
+
+		applied, err := m.Up(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, testMigrations[1:], applied)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Modified migration", func(t *testing.T) {
+		m, mock, closer := setup(t)
+		defer closer()
+
+		modified := *testMigrations[0]
+		modified.Up = "CREATE TABLE a(id bigint);"
+		expectLock(mock, &modified)
+		expectUnlock(mock)
+
+		applied, err := m.Up(context.Background())
+		assert.True(t, errors.Is(err, ErrChecksumMismatch))
+		assert.Empty(t, applied)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Failing migration", func(t *testing.T) {
+		m, mock, closer := setup(t)
+		defer closer()
+
+		expectLock(mock)
+		mock.ExpectExec("CREATE TABLE a").WillReturnError(sql.ErrConnDone)
+		expectUnlock(mock)
+
+		applied, err := m.Up(context.Background())
+		assert.True(t, errors.Is(err, sql.ErrConnDone))
+		assert.Empty(t, applied)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("Locked", func(t *testing.T) {
+		m, mock, closer := setup(t)
+		defer closer()
+
+		mock.ExpectQuery("SELECT GET_LOCK").
+			WithArgs(lockName, 5).
+			WillReturnRows(sqlmock.NewRows([]string{"lock"}).AddRow(0))
+
+		_, err := m.Up(context.Background())
+		assert.Equal(t, ErrLocked, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestMigrator_Down(t *testing.T) {
+	m, mock, closer := setup(t)
+	defer closer()
+
+	expectLock(mock, testMigrations...)
+	mock.ExpectExec("DROP TABLE b").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("DELETE FROM schema_migrations WHERE version = ?").
+		WithArgs(int64(2)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	expectUnlock(mock)
+
+	reverted, err := m.Down(context.Background(), 1)
+	require.NoError(t, err)
+	assert.Equal(t, testMigrations[1:], reverted)
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	t.Run("Unknown migration", func(t *testing.T) {
+		m, mock, closer := setup(t)
+		defer closer()
+
+		expectLock(mock, append(testMigrations, &Migration{Version: 3, Name: "create_c"})...)
+		expectUnlock(mock)
+
+		_, err := m.Down(context.Background(), 3)
+		assert.True(t, errors.Is(err, ErrUnknownVersion))
+	})
+}
+
+func TestMigrator_Status(t *testing.T) {
+	m, mock, closer := setup(t)
+	defer closer()
+
+	expectLock(mock, testMigrations[0])
+	expectUnlock(mock)
+
+	statuses, err := m.Status(context.Background())
+	require.NoError(t, err)
+	require.Len(t, statuses, 2)
+	assert.True(t, statuses[0].IsApplied())
+	assert.False(t, statuses[0].Modified)
+	assert.False(t, statuses[1].IsApplied())
+	assert.Equal(t, testMigrations[1], statuses[1].Migration)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,7 @@
+CREATE TABLE IF NOT EXISTS follows(
+  `followerId` varchar(45) COLLATE utf8_unicode_ci NOT NULL,
+  `followeeId` varchar(45) COLLATE utf8_unicode_ci NOT NULL,
+  `created_at` datetime DEFAULT NULL,
+  PRIMARY KEY (`followerId`, `followeeId`),
+  KEY `follows_followee` (`followeeId`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8 COLLATE=utf8_unicode_ci;
@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS notifications(
+  `notificationId` varchar(45) COLLATE utf8_unicode_ci NOT NULL,
+  `userId` varchar(45) COLLATE utf8_unicode_ci NOT NULL,
+  `actorId` varchar(45) COLLATE utf8_unicode_ci NOT NULL,
+  `type` varchar(20) COLLATE utf8_unicode_ci NOT NULL,
+  `imageId` varchar(45) COLLATE utf8_unicode_ci NOT NULL DEFAULT '',
+  `text` varchar(255) COLLATE utf8_unicode_ci NOT NULL DEFAULT '',
+  `created_at` datetime DEFAULT NULL,
+  `read_at` datetime DEFAULT NULL,
+  PRIMARY KEY (`notificationId`),
+  KEY `notifications_user_created` (`userId`, `created_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8 COLLATE=utf8_unicode_ci;
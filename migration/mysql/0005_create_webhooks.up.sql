@@ -0,0 +1,29 @@
+CREATE TABLE IF NOT EXISTS webhooks(
+  `hookId` varchar(45) COLLATE utf8_unicode_ci NOT NULL,
+  `userId` varchar(45) COLLATE utf8_unicode_ci NOT NULL,
+  `url` varchar(2048) COLLATE utf8_unicode_ci NOT NULL,
+  `secret` varchar(64) COLLATE utf8_unicode_ci NOT NULL,
+  `events` varchar(255) COLLATE utf8_unicode_ci NOT NULL,
+  `global` tinyint(1) NOT NULL DEFAULT 0,
+  `active` tinyint(1) NOT NULL DEFAULT 1,
+  `created_at` datetime DEFAULT NULL,
+  PRIMARY KEY (`hookId`),
+  KEY `webhooks_user` (`userId`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8 COLLATE=utf8_unicode_ci;
+
+CREATE TABLE IF NOT EXISTS webhook_deliveries(
+  `deliveryId` varchar(45) COLLATE utf8_unicode_ci NOT NULL,
+  `hookId` varchar(45) COLLATE utf8_unicode_ci NOT NULL,
+  `event` varchar(45) COLLATE utf8_unicode_ci NOT NULL,
+  `payload` mediumtext COLLATE utf8_unicode_ci NOT NULL,
+  `status` varchar(20) COLLATE utf8_unicode_ci NOT NULL,
+  `attempts` int(11) NOT NULL DEFAULT 0,
+  `status_code` int(11) NOT NULL DEFAULT 0,
+  `error` varchar(255) COLLATE utf8_unicode_ci NOT NULL DEFAULT '',
+  `next_attempt_at` datetime DEFAULT NULL,
+  `created_at` datetime DEFAULT NULL,
+  `updated_at` datetime DEFAULT NULL,
+  PRIMARY KEY (`deliveryId`),
+  KEY `webhook_deliveries_due` (`status`, `next_attempt_at`),
+  KEY `webhook_deliveries_hook` (`hookId`, `created_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8 COLLATE=utf8_unicode_ci;
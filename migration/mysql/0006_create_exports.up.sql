@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS exports(
+  `exportId` varchar(45) COLLATE utf8_unicode_ci NOT NULL,
+  `userId` varchar(45) COLLATE utf8_unicode_ci NOT NULL,
+  `status` varchar(20) COLLATE utf8_unicode_ci NOT NULL,
+  `error` varchar(255) COLLATE utf8_unicode_ci NOT NULL DEFAULT '',
+  `size` bigint(20) NOT NULL DEFAULT 0,
+  `created_at` datetime DEFAULT NULL,
+  `completed_at` datetime DEFAULT NULL,
+  `expires_at` datetime DEFAULT NULL,
+  PRIMARY KEY (`exportId`),
+  KEY `exports_user` (`userId`, `created_at`),
+  KEY `exports_expires` (`expires_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8 COLLATE=utf8_unicode_ci;
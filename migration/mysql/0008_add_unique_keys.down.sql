@@ -0,0 +1,6 @@
+ALTER TABLE images
+  DROP KEY `images_imageId`;
+ALTER TABLE `user`
+  DROP KEY `user_email`,
+  DROP KEY `user_username`,
+  DROP KEY `user_userId`;
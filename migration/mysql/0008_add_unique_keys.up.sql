@@ -0,0 +1,6 @@
+ALTER TABLE `user`
+  ADD UNIQUE KEY `user_userId` (`userId`),
+  ADD UNIQUE KEY `user_username` (`username`),
+  ADD UNIQUE KEY `user_email` (`email`);
+ALTER TABLE images
+  ADD UNIQUE KEY `images_imageId` (`imageId`);
@@ -0,0 +1,7 @@
+ALTER TABLE images
+  DROP KEY `images_user_created`;
+ALTER TABLE `user`
+  DROP KEY `user_deleted`,
+  DROP COLUMN `website`,
+  DROP COLUMN `bio`,
+  DROP COLUMN `display_name`;
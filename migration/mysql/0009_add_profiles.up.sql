@@ -0,0 +1,7 @@
+ALTER TABLE `user`
+  ADD COLUMN `display_name` varchar(50) COLLATE utf8_unicode_ci NOT NULL DEFAULT '' AFTER `password`,
+  ADD COLUMN `bio` varchar(160) COLLATE utf8_unicode_ci NOT NULL DEFAULT '' AFTER `display_name`,
+  ADD COLUMN `website` varchar(255) COLLATE utf8_unicode_ci NOT NULL DEFAULT '' AFTER `bio`,
+  ADD KEY `user_deleted` (`deleted_at`);
+ALTER TABLE images
+  ADD KEY `images_user_created` (`userId`, `created_at`);
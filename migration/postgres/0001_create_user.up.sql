@@ -0,0 +1,15 @@
+CREATE TABLE "user"(
+  id serial NOT NULL,
+  userId varchar(45) NOT NULL,
+  username varchar(45) NOT NULL,
+  email varchar(45) NOT NULL,
+  password varchar(128) NOT NULL,
+  display_name varchar(50) NOT NULL DEFAULT '',
+  bio varchar(160) NOT NULL DEFAULT '',
+  website varchar(255) NOT NULL DEFAULT '',
+  updated_at timestamp DEFAULT NULL,
+  created_at timestamp DEFAULT NULL,
+  deleted_at timestamp DEFAULT NULL,
+  PRIMARY KEY (id)
+);
+CREATE INDEX user_deleted ON "user"(deleted_at);
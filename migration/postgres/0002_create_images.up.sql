@@ -0,0 +1,14 @@
+CREATE TABLE images(
+  id serial NOT NULL,
+  updated_at timestamp DEFAULT NULL,
+  created_at timestamp DEFAULT NULL,
+  deleted_at timestamp DEFAULT NULL,
+  userId varchar(45) NOT NULL,
+  imageId varchar(45) NOT NULL,
+  name varchar(45) NOT NULL,
+  location varchar(45) NOT NULL,
+  description varchar(100) NOT NULL,
+  size integer DEFAULT NULL,
+  PRIMARY KEY (id)
+);
+CREATE INDEX images_user_created ON images(userId, created_at);
@@ -0,0 +1,9 @@
+CREATE TABLE outbox(
+  id bigserial NOT NULL,
+  name varchar(100) NOT NULL,
+  payload text NOT NULL,
+  created_at timestamp DEFAULT NULL,
+  published_at timestamp DEFAULT NULL,
+  PRIMARY KEY (id)
+);
+CREATE INDEX outbox_pending ON outbox(published_at, id);
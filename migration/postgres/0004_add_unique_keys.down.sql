@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS images_imageid;
+DROP INDEX IF EXISTS user_email;
+DROP INDEX IF EXISTS user_username;
+DROP INDEX IF EXISTS user_userid;
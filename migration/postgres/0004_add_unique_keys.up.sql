@@ -0,0 +1,4 @@
+CREATE UNIQUE INDEX user_userid ON "user"(userId);
+CREATE UNIQUE INDEX user_username ON "user"(username);
+CREATE UNIQUE INDEX user_email ON "user"(email);
+CREATE UNIQUE INDEX images_imageid ON images(imageId);
@@ -0,0 +1,14 @@
+CREATE TABLE user(
+  id integer NOT NULL PRIMARY KEY AUTOINCREMENT,
+  userId varchar(45) NOT NULL,
+  username varchar(45) NOT NULL,
+  email varchar(45) NOT NULL,
+  password varchar(128) NOT NULL,
+  display_name varchar(50) NOT NULL DEFAULT '',
+  bio varchar(160) NOT NULL DEFAULT '',
+  website varchar(255) NOT NULL DEFAULT '',
+  updated_at datetime DEFAULT NULL,
+  created_at datetime DEFAULT NULL,
+  deleted_at datetime DEFAULT NULL
+);
+CREATE INDEX user_deleted ON user(deleted_at);
@@ -0,0 +1,13 @@
+CREATE TABLE images(
+  id integer NOT NULL PRIMARY KEY AUTOINCREMENT,
+  updated_at datetime DEFAULT NULL,
+  created_at datetime DEFAULT NULL,
+  deleted_at datetime DEFAULT NULL,
+  userId varchar(45) NOT NULL,
+  imageId varchar(45) NOT NULL,
+  name varchar(45) NOT NULL,
+  location varchar(45) NOT NULL,
+  description varchar(100) NOT NULL,
+  size integer DEFAULT NULL
+);
+CREATE INDEX images_user_created ON images(userId, created_at);
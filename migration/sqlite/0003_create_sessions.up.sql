@@ -0,0 +1,8 @@
+CREATE TABLE sessions(
+  id varchar(64) NOT NULL PRIMARY KEY,
+  userId varchar(45) NOT NULL DEFAULT '',
+  payload text NOT NULL,
+  expiry datetime NOT NULL
+);
+CREATE INDEX sessions_user ON sessions(userId);
+CREATE INDEX sessions_expiry ON sessions(expiry);
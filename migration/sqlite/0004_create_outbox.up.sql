@@ -0,0 +1,8 @@
+CREATE TABLE outbox(
+  id integer NOT NULL PRIMARY KEY AUTOINCREMENT,
+  name varchar(100) NOT NULL,
+  payload text NOT NULL,
+  created_at datetime DEFAULT NULL,
+  published_at datetime DEFAULT NULL
+);
+CREATE INDEX outbox_pending ON outbox(published_at, id);
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS images_imageId;
+DROP INDEX IF EXISTS user_email;
+DROP INDEX IF EXISTS user_username;
+DROP INDEX IF EXISTS user_userId;
@@ -1,5 +1,5 @@
-CREATE DATABASE IF NOT EXISTS `gophr`;
-USE `gophr`;
+-- The schema of gophr.sql before the migrations, without selecting
+-- the database so that it's loaded in the one of the tests.
 
 DROP TABLE IF EXISTS `user`;
 CREATE TABLE `user`(
@@ -0,0 +1,9 @@
+package notification
+
+import "errors"
+
+var (
+	ErrNotFound      = errors.New("notification: item not found")
+	ErrMissingUser   = errors.New("notification: recipient is missing")
+	ErrSelfNotifying = errors.New("notification: users aren't notified of their own actions")
+)
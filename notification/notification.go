@@ -0,0 +1,39 @@
+package notification
+
+import (
+	"gophr.v2/util/randutil"
+	"time"
+)
+
+// Type is the kind of event a notification tells about.
+type Type string
+
+const (
+	TypeFollow  Type = "follow"
+	TypeLike    Type = "like"
+	TypeComment Type = "comment"
+	// TypeExport tells that a requested data export is ready. It
+	// has no actor.
+	TypeExport Type = "export"
+)
+
+// Notification tells the user identified by UserID that the user
+// identified by ActorID did something involving them.
+type Notification struct {
+	ID        string     `json:"id,omitempty"`
+	UserID    string     `json:"userId,omitempty"`
+	ActorID   string     `json:"actorId,omitempty"`
+	Type      Type       `json:"type,omitempty"`
+	ImageID   string     `json:"imageId,omitempty"`
+	Text      string     `json:"text,omitempty"`
+	CreatedAt *time.Time `json:"createdAt,omitempty"`
+	ReadAt    *time.Time `json:"readAt,omitempty"`
+}
+
+func (n *Notification) IsRead() bool {
+	return n.ReadAt != nil
+}
+
+func GenerateID() string {
+	return randutil.GenerateID("notification")
+}
@@ -0,0 +1,18 @@
+package notification
+
+import "context"
+
+//go:generate mockery --name=Repository
+
+type Repository interface {
+	Save(ctx context.Context, n *Notification) error
+	// FindByUser returns the notifications of userID, newest first.
+	FindByUser(ctx context.Context, userID string, offset, num int) ([]*Notification, error)
+	CountUnread(ctx context.Context, userID string) (int, error)
+	// MarkRead marks the notifications of userID with the given IDs
+	// as read, or all of them when no ID is given.
+	MarkRead(ctx context.Context, userID string, ids ...string) error
+	// DeleteAllForUser removes the notifications received by userID
+	// and the ones about the actions of userID.
+	DeleteAllForUser(ctx context.Context, userID string) error
+}
@@ -0,0 +1,35 @@
+package repository
+
+import (
+	"gophr.v2/config"
+	mysqldriver "gophr.v2/driver/mysql"
+	"gophr.v2/notification"
+	"gophr.v2/notification/repository/memory"
+	"gophr.v2/notification/repository/mysql"
+)
+
+type RepoType int
+
+const (
+	MemoryRepo RepoType = iota
+	MySQLRepo
+)
+
+func Get(conf *config.Config, rt RepoType) (notification.Repository, func() error) {
+	switch rt {
+	case MemoryRepo:
+		return memory.New(), noOpClose
+	case MySQLRepo:
+		db, err := mysqldriver.Initialize(conf)
+		if err != nil {
+			panic(err)
+		}
+		return mysql.New(db), db.Close
+	default:
+		panic("unknown repository implementation type")
+	}
+}
+
+func noOpClose() error {
+	return nil
+}
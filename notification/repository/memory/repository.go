@@ -0,0 +1,97 @@
+package memory
+
+import (
+	"context"
+	"gophr.v2/notification"
+	"gophr.v2/util/valueutil"
+	"sort"
+	"sync"
+	"time"
+)
+
+var _ notification.Repository = (*Repository)(nil)
+
+// New creates a notification repository kept in memory. It's
+// meant for tests and single instance deployments.
+func New() *Repository {
+	return &Repository{byUser: make(map[string][]*notification.Notification)}
+}
+
+type Repository struct {
+	mu     sync.RWMutex
+	byUser map[string][]*notification.Notification
+}
+
+func (r *Repository) Save(ctx context.Context, n *notification.Notification) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	cpy := *n
+	notifications := append(r.byUser[n.UserID], &cpy)
+	sort.SliceStable(notifications, func(i, j int) bool {
+		return notifications[i].CreatedAt.After(*notifications[j].CreatedAt)
+	})
+	r.byUser[n.UserID] = notifications
+	return nil
+}
+
+func (r *Repository) FindByUser(ctx context.Context, userID string, offset, num int) ([]*notification.Notification, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	notifications := r.byUser[userID]
+	result := make([]*notification.Notification, 0)
+	for i := offset; i < len(notifications) && len(result) < num; i++ {
+		cpy := *notifications[i]
+		result = append(result, &cpy)
+	}
+	return result, nil
+}
+
+func (r *Repository) CountUnread(ctx context.Context, userID string) (int, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var n int
+	for _, notif := range r.byUser[userID] {
+		if !notif.IsRead() {
+			n++
+		}
+	}
+	return n, nil
+}
+
+func (r *Repository) MarkRead(ctx context.Context, userID string, ids ...string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	marked := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		marked[id] = true
+	}
+
+	now := valueutil.TimePointer(time.Now().UTC())
+	for _, notif := range r.byUser[userID] {
+		if !notif.IsRead() && (len(ids) == 0 || marked[notif.ID]) {
+			notif.ReadAt = now
+		}
+	}
+	return nil
+}
+
+func (r *Repository) DeleteAllForUser(ctx context.Context, userID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.byUser, userID)
+	for id, notifications := range r.byUser {
+		kept := notifications[:0]
+		for _, notif := range notifications {
+			if notif.ActorID != userID {
+				kept = append(kept, notif)
+			}
+		}
+		r.byUser[id] = kept
+	}
+	return nil
+}
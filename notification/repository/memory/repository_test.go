@@ -0,0 +1,79 @@
+//+build unit
+
+package memory
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gophr.v2/notification"
+	"gophr.v2/util/valueutil"
+	"testing"
+	"time"
+)
+
+func TestRepository(t *testing.T) {
+	ctx := context.Background()
+	repo := New()
+	now := time.Now()
+
+	for i, id := range []string{"first", "second", "third"} {
+		require.NoError(t, repo.Save(ctx, &notification.Notification{
+			ID:        id,
+			UserID:    "luffy",
+			ActorID:   "zoro",
+			Type:      notification.TypeFollow,
+			CreatedAt: valueutil.TimePointer(now.Add(time.Duration(i) * time.Minute)),
+		}))
+	}
+
+	t.Run("Newest First", func(t *testing.T) {
+		notifications, err := repo.FindByUser(ctx, "luffy", 0, 2)
+		require.NoError(t, err)
+		require.Len(t, notifications, 2)
+		assert.Equal(t, "third", notifications[0].ID)
+		assert.Equal(t, "second", notifications[1].ID)
+
+		notifications, err = repo.FindByUser(ctx, "luffy", 2, 2)
+		require.NoError(t, err)
+		require.Len(t, notifications, 1)
+		assert.Equal(t, "first", notifications[0].ID)
+	})
+
+	t.Run("Mark One As Read", func(t *testing.T) {
+		require.NoError(t, repo.MarkRead(ctx, "luffy", "second"))
+		unread, err := repo.CountUnread(ctx, "luffy")
+		require.NoError(t, err)
+		assert.Equal(t, 2, unread)
+	})
+
+	t.Run("Other Users Are Untouched", func(t *testing.T) {
+		require.NoError(t, repo.MarkRead(ctx, "zoro", "first"))
+		unread, err := repo.CountUnread(ctx, "luffy")
+		require.NoError(t, err)
+		assert.Equal(t, 2, unread)
+	})
+
+	t.Run("Mark All As Read", func(t *testing.T) {
+		require.NoError(t, repo.MarkRead(ctx, "luffy"))
+		unread, err := repo.CountUnread(ctx, "luffy")
+		require.NoError(t, err)
+		assert.Equal(t, 0, unread)
+	})
+	t.Run("Delete All For User", func(t *testing.T) {
+		require.NoError(t, repo.Save(ctx, &notification.Notification{
+			ID:        "fourth",
+			UserID:    "zoro",
+			ActorID:   "luffy",
+			Type:      notification.TypeFollow,
+			CreatedAt: valueutil.TimePointer(now),
+		}))
+		require.NoError(t, repo.DeleteAllForUser(ctx, "luffy"))
+
+		for _, id := range []string{"luffy", "zoro"} {
+			notifications, err := repo.FindByUser(ctx, id, 0, 10)
+			require.NoError(t, err)
+			assert.Empty(t, notifications, id)
+		}
+	})
+}
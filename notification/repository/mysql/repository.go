@@ -0,0 +1,106 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"gophr.v2/notification"
+	"strings"
+	"time"
+)
+
+var _ notification.Repository = (*Repository)(nil)
+
+func New(conn *sql.DB) *Repository {
+	return &Repository{conn: conn}
+}
+
+type Repository struct {
+	conn *sql.DB
+}
+
+func (r *Repository) Save(ctx context.Context, n *notification.Notification) error {
+	query := "INSERT INTO notifications(notificationId, userId, actorId, type, imageId, text, created_at, read_at) VALUES(?,?,?,?,?,?,?,?)"
+	_, err := r.conn.ExecContext(ctx, query,
+		n.ID,
+		n.UserID,
+		n.ActorID,
+		n.Type,
+		n.ImageID,
+		n.Text,
+		n.CreatedAt,
+		n.ReadAt,
+	)
+	return r.checkError(err)
+}
+
+func (r *Repository) FindByUser(ctx context.Context, userID string, offset, num int) (notifications []*notification.Notification, err error) {
+	query := `SELECT notificationId, userId, actorId, type, imageId, text, created_at, read_at
+						FROM notifications
+						WHERE userId = ?
+						ORDER BY created_at DESC
+						LIMIT ?
+						OFFSET ?`
+	rows, err := r.conn.QueryContext(ctx, query, userID, num, offset)
+	if err != nil {
+		return nil, r.checkError(err)
+	}
+	defer func() {
+		if e := rows.Close(); err == nil && e != nil {
+			err = e
+		}
+	}()
+
+	notifications = make([]*notification.Notification, 0)
+	for rows.Next() {
+		var n notification.Notification
+		err = rows.Scan(&n.ID, &n.UserID, &n.ActorID, &n.Type, &n.ImageID, &n.Text, &n.CreatedAt, &n.ReadAt)
+		if err != nil {
+			return nil, r.checkError(err)
+		}
+		notifications = append(notifications, &n)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, r.checkError(err)
+	}
+	return notifications, nil
+}
+
+func (r *Repository) CountUnread(ctx context.Context, userID string) (int, error) {
+	query := "SELECT COUNT(*) FROM notifications WHERE userId = ? AND read_at IS NULL"
+	var n int
+	if err := r.conn.QueryRowContext(ctx, query, userID).Scan(&n); err != nil {
+		return 0, r.checkError(err)
+	}
+	return n, nil
+}
+
+func (r *Repository) MarkRead(ctx context.Context, userID string, ids ...string) error {
+	query := "UPDATE notifications SET read_at = ? WHERE userId = ? AND read_at IS NULL"
+	args := []interface{}{time.Now().UTC(), userID}
+	if len(ids) > 0 {
+		query += " AND notificationId IN (" + strings.TrimSuffix(strings.Repeat("?,", len(ids)), ",") + ")"
+		for _, id := range ids {
+			args = append(args, id)
+		}
+	}
+	_, err := r.conn.ExecContext(ctx, query, args...)
+	return r.checkError(err)
+}
+
+func (r *Repository) DeleteAllForUser(ctx context.Context, userID string) error {
+	query := "DELETE FROM notifications WHERE userId = ? OR actorId = ?"
+	_, err := r.conn.ExecContext(ctx, query, userID, userID)
+	return r.checkError(err)
+}
+
+func (r *Repository) checkError(err error) error {
+	switch err {
+	case nil:
+		return nil
+	case sql.ErrNoRows:
+		return notification.ErrNotFound
+	default:
+		return fmt.Errorf("mysql: unexpected error %w", err)
+	}
+}
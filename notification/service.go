@@ -0,0 +1,12 @@
+package notification
+
+import "context"
+
+//go:generate mockery --name=Service
+
+type Service interface {
+	Notify(ctx context.Context, n *Notification) error
+	List(ctx context.Context, userID string, offset, num int) ([]*Notification, error)
+	CountUnread(ctx context.Context, userID string) (int, error)
+	MarkRead(ctx context.Context, userID string, ids ...string) error
+}
@@ -0,0 +1,87 @@
+package service
+
+import (
+	"context"
+	"gophr.v2/log"
+	"gophr.v2/notification"
+	"gophr.v2/realtime"
+	"gophr.v2/util/valueutil"
+	"time"
+)
+
+// DefaultPageSize is the number of notifications listed when none is given.
+const DefaultPageSize = 20
+
+var _ notification.Service = (*Service)(nil)
+
+// Option configures the Service.
+type Option func(s *Service)
+
+// WithPublisher streams the new notifications to the browsers of
+// their recipients.
+func WithPublisher(publisher realtime.Publisher) Option {
+	return func(s *Service) {
+		s.publisher = publisher
+	}
+}
+
+func New(repo notification.Repository, opts ...Option) *Service {
+	s := &Service{repo: repo}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+type Service struct {
+	repo      notification.Repository
+	publisher realtime.Publisher
+}
+
+func (s *Service) Notify(ctx context.Context, n *notification.Notification) error {
+	if n.UserID == "" {
+		return notification.ErrMissingUser
+	}
+	if n.UserID == n.ActorID {
+		return notification.ErrSelfNotifying
+	}
+	if n.ID == "" {
+		n.ID = notification.GenerateID()
+	}
+	if n.CreatedAt == nil {
+		n.CreatedAt = valueutil.TimePointer(time.Now().UTC())
+	}
+	if err := s.repo.Save(ctx, n); err != nil {
+		return err
+	}
+
+	if s.publisher != nil {
+		if err := s.publish(ctx, n); err != nil {
+			log.FromContext(ctx).WithError(err).Error("failed publishing notification")
+		}
+	}
+	return nil
+}
+
+func (s *Service) publish(ctx context.Context, n *notification.Notification) error {
+	e, err := realtime.NewEvent(realtime.TypeNotification, n.UserID, n)
+	if err != nil {
+		return err
+	}
+	return s.publisher.Publish(ctx, e)
+}
+
+func (s *Service) List(ctx context.Context, userID string, offset, num int) ([]*notification.Notification, error) {
+	if num <= 0 {
+		num = DefaultPageSize
+	}
+	return s.repo.FindByUser(ctx, userID, offset, num)
+}
+
+func (s *Service) CountUnread(ctx context.Context, userID string) (int, error) {
+	return s.repo.CountUnread(ctx, userID)
+}
+
+func (s *Service) MarkRead(ctx context.Context, userID string, ids ...string) error {
+	return s.repo.MarkRead(ctx, userID, ids...)
+}
@@ -0,0 +1,67 @@
+//+build unit
+
+package service
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gophr.v2/notification"
+	"gophr.v2/notification/mocks"
+	"gophr.v2/realtime"
+	realtimemocks "gophr.v2/realtime/mocks"
+	"testing"
+)
+
+func TestService_Notify(t *testing.T) {
+	t.Run("Success", func(t *testing.T) {
+		repo := new(mocks.Repository)
+		repo.On("Save", mock.Anything, mock.AnythingOfType("*notification.Notification")).Return(nil).Once()
+
+		n := &notification.Notification{UserID: "luffy", ActorID: "zoro", Type: notification.TypeFollow}
+		err := New(repo).Notify(context.Background(), n)
+		assert.NoError(t, err)
+		assert.NotEmpty(t, n.ID)
+		assert.NotNil(t, n.CreatedAt)
+		assert.False(t, n.IsRead())
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("Publishes To The Recipient", func(t *testing.T) {
+		repo := new(mocks.Repository)
+		repo.On("Save", mock.Anything, mock.AnythingOfType("*notification.Notification")).Return(nil).Once()
+		publisher := new(realtimemocks.Publisher)
+		publisher.On("Publish", mock.Anything, mock.MatchedBy(func(e *realtime.Event) bool {
+			return e.Type == realtime.TypeNotification && e.UserID == "luffy"
+		})).Return(nil).Once()
+
+		n := &notification.Notification{UserID: "luffy", ActorID: "zoro", Type: notification.TypeFollow}
+		err := New(repo, WithPublisher(publisher)).Notify(context.Background(), n)
+		assert.NoError(t, err)
+		publisher.AssertExpectations(t)
+	})
+
+	t.Run("Missing User", func(t *testing.T) {
+		repo := new(mocks.Repository)
+		err := New(repo).Notify(context.Background(), &notification.Notification{ActorID: "zoro"})
+		assert.Equal(t, notification.ErrMissingUser, err)
+		repo.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Self Notifying", func(t *testing.T) {
+		repo := new(mocks.Repository)
+		err := New(repo).Notify(context.Background(), &notification.Notification{UserID: "luffy", ActorID: "luffy"})
+		assert.Equal(t, notification.ErrSelfNotifying, err)
+		repo.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+	})
+}
+
+func TestService_List(t *testing.T) {
+	repo := new(mocks.Repository)
+	repo.On("FindByUser", mock.Anything, "luffy", 0, DefaultPageSize).Return([]*notification.Notification{}, nil).Once()
+
+	notifications, err := New(repo).List(context.Background(), "luffy", 0, 0)
+	assert.NoError(t, err)
+	assert.Empty(t, notifications)
+	repo.AssertExpectations(t)
+}
@@ -0,0 +1,24 @@
+package realtime
+
+import "context"
+
+//go:generate mockery --name=Publisher
+
+// Publisher sends events to every subscriber interested in them.
+type Publisher interface {
+	Publish(ctx context.Context, e *Event) error
+}
+
+// Subscriber streams the events of a user.
+type Subscriber interface {
+	// Subscribe returns the events for userID, an empty userID
+	// receiving only the broadcast ones. When lastEventID is given
+	// the events published after it are replayed first. The channel
+	// is closed once ctx is done.
+	Subscribe(ctx context.Context, userID, lastEventID string) (<-chan *Event, error)
+}
+
+type Broker interface {
+	Publisher
+	Subscriber
+}
@@ -0,0 +1,48 @@
+package realtime
+
+import (
+	"encoding/json"
+	"gophr.v2/util/randutil"
+)
+
+// Type is the kind of an event streamed to the browsers.
+type Type string
+
+const (
+	TypeImage        Type = "image"
+	TypeNotification Type = "notification"
+)
+
+// Event is a message streamed to the connected browsers. Events
+// without a UserID are broadcast to everyone, while the others
+// only reach the sessions of that user.
+type Event struct {
+	ID     string          `json:"id"`
+	Type   Type            `json:"type"`
+	UserID string          `json:"userId,omitempty"`
+	Data   json.RawMessage `json:"data"`
+}
+
+// NewEvent creates an event of typ for userID holding v encoded
+// as JSON.
+func NewEvent(typ Type, userID string, v interface{}) (*Event, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return &Event{
+		ID:     GenerateID(),
+		Type:   typ,
+		UserID: userID,
+		Data:   data,
+	}, nil
+}
+
+// IsFor tells whether the event must reach the sessions of userID.
+func (e *Event) IsFor(userID string) bool {
+	return e.UserID == "" || e.UserID == userID
+}
+
+func GenerateID() string {
+	return randutil.GenerateID("event")
+}
@@ -0,0 +1,23 @@
+package realtime
+
+import (
+	"context"
+	"gophr.v2/event"
+	"gophr.v2/image"
+)
+
+// Subscribe streams the domain events the browsers care about
+// through publisher.
+func Subscribe(sub event.Subscriber, publisher Publisher) {
+	sub.SubscribeAsync(image.EventCreated, func(ctx context.Context, e event.Event) error {
+		created, ok := e.(*image.Created)
+		if !ok {
+			return nil
+		}
+		re, err := NewEvent(TypeImage, "", created.Image)
+		if err != nil {
+			return err
+		}
+		return publisher.Publish(ctx, re)
+	})
+}
@@ -0,0 +1,142 @@
+package hub
+
+import (
+	"context"
+	"gophr.v2/log"
+	"gophr.v2/realtime"
+	"sync"
+)
+
+// DefaultHistorySize is the number of recent events kept for
+// replaying them to reconnecting subscribers.
+const DefaultHistorySize = 100
+
+// subscriberBuffer is the number of events a subscriber can fall
+// behind before missing some.
+const subscriberBuffer = 16
+
+var _ realtime.Broker = (*Hub)(nil)
+
+// New creates an in-process hub keeping the last historySize events.
+func New(historySize int) *Hub {
+	if historySize <= 0 {
+		historySize = DefaultHistorySize
+	}
+	return &Hub{
+		subs:        make(map[*subscription]struct{}),
+		historySize: historySize,
+	}
+}
+
+// Hub fans out the events to the subscribers of the current process.
+type Hub struct {
+	mu          sync.RWMutex
+	subs        map[*subscription]struct{}
+	history     []*realtime.Event
+	historySize int
+}
+
+type subscription struct {
+	userID string
+	events chan *realtime.Event
+}
+
+func (h *Hub) Publish(ctx context.Context, e *realtime.Event) error {
+	if e.ID == "" {
+		e.ID = realtime.GenerateID()
+	}
+	h.Deliver(e)
+	return nil
+}
+
+// Deliver hands e to the subscribers interested in it. Subscribers
+// too slow to keep up miss the event rather than blocking the others.
+func (h *Hub) Deliver(e *realtime.Event) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.history = append(h.history, e)
+	if len(h.history) > h.historySize {
+		h.history = h.history[len(h.history)-h.historySize:]
+	}
+
+	for sub := range h.subs {
+		if !e.IsFor(sub.userID) {
+			continue
+		}
+		select {
+		case sub.events <- e:
+		default:
+			log.WithField("eventId", e.ID).Debug("realtime: dropping event for a slow subscriber")
+		}
+	}
+}
+
+func (h *Hub) Subscribe(ctx context.Context, userID, lastEventID string) (<-chan *realtime.Event, error) {
+	sub := &subscription{
+		userID: userID,
+		events: make(chan *realtime.Event, subscriberBuffer),
+	}
+
+	// Registering and taking the missed events at once so that
+	// none is lost or sent twice.
+	h.mu.Lock()
+	missed := h.since(lastEventID, userID)
+	h.subs[sub] = struct{}{}
+	h.mu.Unlock()
+
+	out := make(chan *realtime.Event)
+	go func() {
+		defer close(out)
+		defer h.unsubscribe(sub)
+
+		for _, e := range missed {
+			select {
+			case out <- e:
+			case <-ctx.Done():
+				return
+			}
+		}
+
+		for {
+			select {
+			case e := <-sub.events:
+				select {
+				case out <- e:
+				case <-ctx.Done():
+					return
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out, nil
+}
+
+// since returns the events for userID published after the event
+// with lastEventID. Nothing is returned when that event is unknown.
+func (h *Hub) since(lastEventID, userID string) []*realtime.Event {
+	if lastEventID == "" {
+		return nil
+	}
+	for i := len(h.history) - 1; i >= 0; i-- {
+		if h.history[i].ID != lastEventID {
+			continue
+		}
+		var missed []*realtime.Event
+		for _, e := range h.history[i+1:] {
+			if e.IsFor(userID) {
+				missed = append(missed, e)
+			}
+		}
+		return missed
+	}
+	return nil
+}
+
+func (h *Hub) unsubscribe(sub *subscription) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	delete(h.subs, sub)
+}
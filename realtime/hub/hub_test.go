@@ -0,0 +1,111 @@
+//+build unit
+
+package hub
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gophr.v2/realtime"
+	"testing"
+	"time"
+)
+
+func receive(t *testing.T, events <-chan *realtime.Event) *realtime.Event {
+	t.Helper()
+	select {
+	case e := <-events:
+		return e
+	case <-time.After(time.Second):
+		t.Fatal("no event received")
+		return nil
+	}
+}
+
+func assertNothingReceived(t *testing.T, events <-chan *realtime.Event) {
+	t.Helper()
+	select {
+	case e := <-events:
+		t.Fatalf("unexpected event %s", e.ID)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestHub(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	h := New(DefaultHistorySize)
+
+	luffy, err := h.Subscribe(ctx, "luffy", "")
+	require.NoError(t, err)
+	anonymous, err := h.Subscribe(ctx, "", "")
+	require.NoError(t, err)
+
+	t.Run("Broadcast", func(t *testing.T) {
+		e := &realtime.Event{Type: realtime.TypeImage}
+		require.NoError(t, h.Publish(ctx, e))
+		assert.NotEmpty(t, e.ID)
+		assert.Equal(t, e, receive(t, luffy))
+		assert.Equal(t, e, receive(t, anonymous))
+	})
+
+	t.Run("Only The Recipient", func(t *testing.T) {
+		e := &realtime.Event{Type: realtime.TypeNotification, UserID: "luffy"}
+		require.NoError(t, h.Publish(ctx, e))
+		assert.Equal(t, e, receive(t, luffy))
+		assertNothingReceived(t, anonymous)
+	})
+}
+
+func TestHub_Replay(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	h := New(2)
+
+	first := &realtime.Event{ID: "1", Type: realtime.TypeImage}
+	second := &realtime.Event{ID: "2", Type: realtime.TypeNotification, UserID: "zoro"}
+	third := &realtime.Event{ID: "3", Type: realtime.TypeImage}
+	for _, e := range []*realtime.Event{first, second, third} {
+		h.Deliver(e)
+	}
+
+	t.Run("Missed Events", func(t *testing.T) {
+		events, err := h.Subscribe(ctx, "luffy", "2")
+		require.NoError(t, err)
+		assert.Equal(t, third, receive(t, events))
+		assertNothingReceived(t, events)
+	})
+
+	t.Run("Other Users Events Are Skipped", func(t *testing.T) {
+		events, err := h.Subscribe(ctx, "zoro", "2")
+		require.NoError(t, err)
+		assert.Equal(t, third, receive(t, events))
+	})
+
+	t.Run("Unknown Event", func(t *testing.T) {
+		// The first event fell out of the history
+		events, err := h.Subscribe(ctx, "luffy", "1")
+		require.NoError(t, err)
+		assertNothingReceived(t, events)
+	})
+}
+
+func TestHub_Unsubscribe(t *testing.T) {
+	h := New(DefaultHistorySize)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	events, err := h.Subscribe(ctx, "luffy", "")
+	require.NoError(t, err)
+	cancel()
+
+	select {
+	case _, ok := <-events:
+		assert.False(t, ok)
+	case <-time.After(time.Second):
+		t.Fatal("channel not closed")
+	}
+
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	assert.Empty(t, h.subs)
+}
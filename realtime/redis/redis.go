@@ -0,0 +1,75 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/go-redis/redis/v8"
+	"gophr.v2/log"
+	"gophr.v2/realtime"
+	"gophr.v2/realtime/hub"
+)
+
+// DefaultChannel is the Redis channel the events are published on.
+const DefaultChannel = "gophr:events"
+
+var _ realtime.Broker = (*Broker)(nil)
+
+// New creates a broker sharing the events between every gophr
+// instance through Redis pub/sub. The events received are handed
+// to the local subscribers through h once Listen is running.
+func New(client *redis.Client, h *hub.Hub) *Broker {
+	return &Broker{client: client, hub: h, channel: DefaultChannel}
+}
+
+type Broker struct {
+	client  *redis.Client
+	hub     *hub.Hub
+	channel string
+}
+
+func (b *Broker) Publish(ctx context.Context, e *realtime.Event) error {
+	if e.ID == "" {
+		e.ID = realtime.GenerateID()
+	}
+	payload, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	return b.client.Publish(ctx, b.channel, payload).Err()
+}
+
+func (b *Broker) Subscribe(ctx context.Context, userID, lastEventID string) (<-chan *realtime.Event, error) {
+	return b.hub.Subscribe(ctx, userID, lastEventID)
+}
+
+// Listen delivers the events published by every instance, including
+// this one, to the local subscribers until ctx is done.
+func (b *Broker) Listen(ctx context.Context) error {
+	pubsub := b.client.Subscribe(ctx, b.channel)
+	defer func() {
+		_ = pubsub.Close()
+	}()
+
+	// Wait for the subscription to be confirmed
+	if _, err := pubsub.Receive(ctx); err != nil {
+		return err
+	}
+
+	messages := pubsub.Channel()
+	for {
+		select {
+		case msg, ok := <-messages:
+			if !ok {
+				return nil
+			}
+			e := new(realtime.Event)
+			if err := json.Unmarshal([]byte(msg.Payload), e); err != nil {
+				log.FromContext(ctx).WithError(err).Error("realtime: invalid event")
+				continue
+			}
+			b.hub.Deliver(e)
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
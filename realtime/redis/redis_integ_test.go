@@ -0,0 +1,57 @@
+// +build integration
+
+package redis_test
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gophr.v2/config"
+	"gophr.v2/driver/redis"
+	"gophr.v2/realtime"
+	"gophr.v2/realtime/hub"
+	realtimeredis "gophr.v2/realtime/redis"
+	"testing"
+	"time"
+)
+
+var conf = &config.Config{
+	Redis: config.Redis{
+		Address:  "localhost:6379",
+		Username: "", Password: "",
+		Database: 0,
+	},
+}
+
+func TestBroker(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Two brokers standing for two gophr instances
+	publisher := realtimeredis.New(redis.New(conf), hub.New(hub.DefaultHistorySize))
+	subscriber := realtimeredis.New(redis.New(conf), hub.New(hub.DefaultHistorySize))
+	listening := make(chan error, 1)
+	go func() { listening <- subscriber.Listen(ctx) }()
+
+	events, err := subscriber.Subscribe(ctx, "luffy", "")
+	require.NoError(t, err)
+
+	// Give the listener time to subscribe to the channel
+	time.Sleep(100 * time.Millisecond)
+
+	e, err := realtime.NewEvent(realtime.TypeNotification, "luffy", map[string]string{"id": "n1"})
+	require.NoError(t, err)
+	require.NoError(t, publisher.Publish(ctx, e))
+
+	select {
+	case got := <-events:
+		assert.Equal(t, e.ID, got.ID)
+		assert.Equal(t, e.Type, got.Type)
+		assert.JSONEq(t, `{"id":"n1"}`, string(got.Data))
+	case <-time.After(time.Second):
+		t.Fatal("event not received")
+	}
+
+	cancel()
+	assert.Equal(t, context.Canceled, <-listening)
+}
@@ -8,6 +8,7 @@ import (
 var (
 	ErrNotFound   = errors.New("session: session not exists")
 	ErrItemExists = errors.New("session: session is already exists")
+	ErrExpired    = errors.New("session: session is expired")
 )
 
 func NewError(orig error, msg string) error {
@@ -0,0 +1,37 @@
+package session
+
+import (
+	"gophr.v2/event"
+	"time"
+)
+
+const (
+	EventStarted = "session.started"
+	EventEnded   = "session.ended"
+)
+
+func init() {
+	event.Register(EventStarted, func() event.Event { return new(Started) })
+	event.Register(EventEnded, func() event.Event { return new(Ended) })
+}
+
+// Started is emitted once a user signed in. The session ID is a
+// credential so it's never part of the session events.
+type Started struct {
+	UserID    string    `json:"userId"`
+	IP        string    `json:"ip,omitempty"`
+	UserAgent string    `json:"userAgent,omitempty"`
+	At        time.Time `json:"at"`
+}
+
+func (*Started) EventName() string { return EventStarted }
+
+// Ended is emitted once a session was signed out or revoked. All is
+// set when every session of the user ended at once.
+type Ended struct {
+	UserID string    `json:"userId"`
+	All    bool      `json:"all,omitempty"`
+	At     time.Time `json:"at"`
+}
+
+func (*Ended) EventName() string { return EventEnded }
@@ -0,0 +1,32 @@
+package session
+
+// FlashLevel is the severity of a flash message.
+type FlashLevel string
+
+const (
+	FlashSuccess FlashLevel = "success"
+	FlashInfo    FlashLevel = "info"
+	FlashWarning FlashLevel = "warning"
+	FlashError   FlashLevel = "error"
+)
+
+// Flash is a one-shot message shown on the next page rendered
+// within the session.
+type Flash struct {
+	Level   FlashLevel `json:"level"`
+	Message string     `json:"message"`
+}
+
+// AddFlash queues a flash message. The session must be saved
+// for the message to survive the request.
+func (s *Session) AddFlash(level FlashLevel, message string) {
+	s.Flashes = append(s.Flashes, Flash{Level: level, Message: message})
+}
+
+// PopFlashes returns the queued flash messages and removes
+// them from the session.
+func (s *Session) PopFlashes() []Flash {
+	flashes := s.Flashes
+	s.Flashes = nil
+	return flashes
+}
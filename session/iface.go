@@ -12,6 +12,22 @@ type Saver interface {
 	Save(ctx context.Context, s *Session) error
 }
 
+type Updater interface {
+	Update(ctx context.Context, s *Session) error
+}
+
 type Deleter interface {
 	Delete(ctx context.Context, id string) error
 }
+
+type UserFinder interface {
+	FindByUser(ctx context.Context, userID string) ([]*Session, error)
+}
+
+type UserDeleter interface {
+	DeleteAllForUser(ctx context.Context, userID string) error
+}
+
+type Renewer interface {
+	Renew(ctx context.Context, s *Session) (renewed bool, err error)
+}
@@ -25,6 +25,20 @@ func (_m *Repository) Delete(ctx context.Context, id string) error {
 	return r0
 }
 
+// DeleteAllForUser provides a mock function with given fields: ctx, userID
+func (_m *Repository) DeleteAllForUser(ctx context.Context, userID string) error {
+	ret := _m.Called(ctx, userID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Find provides a mock function with given fields: ctx, id
 func (_m *Repository) Find(ctx context.Context, id string) (*session.Session, error) {
 	ret := _m.Called(ctx, id)
@@ -48,6 +62,29 @@ func (_m *Repository) Find(ctx context.Context, id string) (*session.Session, er
 	return r0, r1
 }
 
+// FindByUser provides a mock function with given fields: ctx, userID
+func (_m *Repository) FindByUser(ctx context.Context, userID string) ([]*session.Session, error) {
+	ret := _m.Called(ctx, userID)
+
+	var r0 []*session.Session
+	if rf, ok := ret.Get(0).(func(context.Context, string) []*session.Session); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*session.Session)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Save provides a mock function with given fields: ctx, s
 func (_m *Repository) Save(ctx context.Context, s *session.Session) error {
 	ret := _m.Called(ctx, s)
@@ -61,3 +98,17 @@ func (_m *Repository) Save(ctx context.Context, s *session.Session) error {
 
 	return r0
 }
+
+// Update provides a mock function with given fields: ctx, s
+func (_m *Repository) Update(ctx context.Context, s *session.Session) error {
+	ret := _m.Called(ctx, s)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *session.Session) error); ok {
+		r0 = rf(ctx, s)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
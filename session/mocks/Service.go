@@ -25,6 +25,20 @@ func (_m *Service) Delete(ctx context.Context, id string) error {
 	return r0
 }
 
+// DeleteAllForUser provides a mock function with given fields: ctx, userID
+func (_m *Service) DeleteAllForUser(ctx context.Context, userID string) error {
+	ret := _m.Called(ctx, userID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Find provides a mock function with given fields: ctx, id
 func (_m *Service) Find(ctx context.Context, id string) (*session.Session, error) {
 	ret := _m.Called(ctx, id)
@@ -48,6 +62,50 @@ func (_m *Service) Find(ctx context.Context, id string) (*session.Session, error
 	return r0, r1
 }
 
+// FindByUser provides a mock function with given fields: ctx, userID
+func (_m *Service) FindByUser(ctx context.Context, userID string) ([]*session.Session, error) {
+	ret := _m.Called(ctx, userID)
+
+	var r0 []*session.Session
+	if rf, ok := ret.Get(0).(func(context.Context, string) []*session.Session); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*session.Session)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Renew provides a mock function with given fields: ctx, s
+func (_m *Service) Renew(ctx context.Context, s *session.Session) (bool, error) {
+	ret := _m.Called(ctx, s)
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(context.Context, *session.Session) bool); ok {
+		r0 = rf(ctx, s)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *session.Session) error); ok {
+		r1 = rf(ctx, s)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Save provides a mock function with given fields: ctx, s
 func (_m *Service) Save(ctx context.Context, s *session.Session) error {
 	ret := _m.Called(ctx, s)
@@ -61,3 +119,17 @@ func (_m *Service) Save(ctx context.Context, s *session.Session) error {
 
 	return r0
 }
+
+// Update provides a mock function with given fields: ctx, s
+func (_m *Service) Update(ctx context.Context, s *session.Session) error {
+	ret := _m.Called(ctx, s)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *session.Session) error); ok {
+		r0 = rf(ctx, s)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
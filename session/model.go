@@ -1,20 +1,81 @@
 package session
 
 import (
+	"strings"
 	"time"
 )
 
 const (
+	// Duration is the default absolute lifetime of a session.
 	Duration   = 24 * 3 * time.Hour
 	CookieName = "GophrSession"
+
+	// LastSeenInterval is how often the last-seen time of an active
+	// session is written back to the repository.
+	LastSeenInterval = time.Minute
 )
 
 type Session struct {
-	ID     string    `json:"id,omitempty"`
-	UserID string    `json:"userId,omitempty"`
-	Expiry time.Time `json:"expiry,omitempty"`
+	ID        string    `json:"id,omitempty"`
+	UserID    string    `json:"userId,omitempty"`
+	Expiry    time.Time `json:"expiry,omitempty"`
+	CreatedAt time.Time `json:"createdAt,omitempty"`
+	LastSeen  time.Time `json:"lastSeen,omitempty"`
+	IP        string    `json:"ip,omitempty"`
+	UserAgent string    `json:"userAgent,omitempty"`
+
+	// CSRFToken is the synchronizer token the forms
+	// posted within the session must carry.
+	CSRFToken string `json:"csrfToken,omitempty"`
+
+	// Flashes are the messages waiting to be shown.
+	Flashes []Flash `json:"flashes,omitempty"`
+}
+
+// Clone returns a copy of s sharing nothing with it.
+func (s *Session) Clone() *Session {
+	cpy := *s
+	cpy.Flashes = append([]Flash(nil), s.Flashes...)
+	return &cpy
 }
 
 func (s *Session) IsExpired() bool {
 	return s.Expiry.Before(time.Now())
 }
+
+// TTL returns how long a repository should keep the session.
+func (s *Session) TTL() time.Duration {
+	return time.Until(s.Expiry)
+}
+
+// Device returns a short description of the browser and the operating
+// system found in the user agent of the session.
+func (s *Session) Device() string {
+	browser := match(s.UserAgent, "Unknown browser",
+		"Edg", "Edge",
+		"OPR", "Opera",
+		"Firefox", "Firefox",
+		"Chrome", "Chrome",
+		"Safari", "Safari",
+		"curl", "curl",
+	)
+	os := match(s.UserAgent, "Unknown OS",
+		"Android", "Android",
+		"iPhone", "iOS",
+		"iPad", "iOS",
+		"Windows", "Windows",
+		"Mac OS", "macOS",
+		"Linux", "Linux",
+	)
+	return browser + " on " + os
+}
+
+// match returns the name following the first token found in ua.
+func match(ua, fallback string, tokenNames ...string) string {
+	for i := 0; i+1 < len(tokenNames); i += 2 {
+		if strings.Contains(ua, tokenNames[i]) {
+			return tokenNames[i+1]
+		}
+	}
+	return fallback
+}
@@ -5,5 +5,8 @@ package session
 type Repository interface {
 	Finder
 	Saver
+	Updater
 	Deleter
+	UserFinder
+	UserDeleter
 }
@@ -4,10 +4,12 @@ import (
 	"github.com/patrickmn/go-cache"
 	"gophr.v2/config"
 	"gophr.v2/driver/redis"
+	sqlitedriver "gophr.v2/driver/sqlite"
 	"gophr.v2/session"
 	"gophr.v2/session/repository/file"
 	"gophr.v2/session/repository/gocache"
 	redisrepo "gophr.v2/session/repository/redis"
+	"gophr.v2/session/repository/sqlite"
 )
 
 // factory design pattern. Its purpose is to abstract the user from the knowledge
@@ -23,6 +25,8 @@ const (
 	GoCacheRepo
 	// RedisRepo describes the redis implementation of the repository.
 	RedisRepo
+	// SQLiteRepo describes the SQLite implementation of the repository.
+	SQLiteRepo
 )
 
 // Get is a factory function that accepts rt repository type
@@ -37,6 +41,12 @@ func Get(conf *config.Config, rt RepoType) session.Repository {
 	case RedisRepo:
 		conn := redis.New(conf)
 		return redisrepo.New(conn)
+	case SQLiteRepo:
+		db, err := sqlitedriver.Initialize(conf)
+		if err != nil {
+			panic(err)
+		}
+		return sqlite.New(db)
 	default:
 		panic("unknown repository type implementation")
 	}
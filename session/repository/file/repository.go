@@ -4,75 +4,169 @@ import (
 	"context"
 	"encoding/json"
 	"gophr.v2/session"
-	"io/ioutil"
-	"os"
+	"gophr.v2/util/fileutil"
+	"sync"
 )
 
 const DefaultFilename = "sessions.db"
 
+// New opens the repository persisting the sessions in filename. It
+// panics when the file can't be read.
 func New(filename string) session.Repository {
+	log, err := fileutil.OpenLog(filename)
+	if err != nil {
+		panic(err)
+	}
+
 	r := &repository{
-		filename: filename,
+		log:      log,
 		sessions: make(map[string]*session.Session),
 	}
-	r.init()
+	for id, value := range log.Values() {
+		sess := new(session.Session)
+		if err := json.Unmarshal(value, sess); err != nil {
+			panic(session.NewError(err, "failed to unmarshal sessions"))
+		}
+		r.sessions[id] = sess
+	}
 	return r
 }
 
+// repository keeps the sessions in memory and persists every change
+// to an append-only log before applying it.
 type repository struct {
-	filename string
+	mu       sync.RWMutex
+	log      *fileutil.Log
 	sessions map[string]*session.Session
 }
 
-func (r *repository) init() {
-	errFunc := func(err error) {
-		if err != nil && !os.IsNotExist(err) {
-			panic(err)
-		}
+func (r *repository) Find(ctx context.Context, id string) (*session.Session, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
 	}
-	file, err := os.Open(r.filename)
-	errFunc(err)
-	if err == nil {
-		defer func() {
-			_ = file.Close()
-		}()
 
-		err = json.NewDecoder(file).Decode(&r.sessions)
-		errFunc(err)
-	}
-}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
 
-func (r *repository) Find(ctx context.Context, id string) (*session.Session, error) {
-	var sess *session.Session
-	var ok bool
-	if sess, ok = r.sessions[id]; !ok {
+	sess, ok := r.sessions[id]
+	if !ok || sess.TTL() <= 0 {
 		return nil, session.ErrNotFound
 	}
-	return sess, nil
+	return sess.Clone(), nil
 }
 
+// Save fails with session.ErrItemExists when a live session has the
+// same ID. The expired sessions are deleted on the way.
 func (r *repository) Save(ctx context.Context, s *session.Session) error {
-	r.sessions[s.ID] = s
-	payload, err := r.marshalToJSON()
-	if err != nil {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if s.TTL() <= 0 {
+		return session.ErrExpired
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err := r.prune(); err != nil {
 		return err
 	}
-	return ioutil.WriteFile(r.filename, payload, 0755)
+	if _, ok := r.sessions[s.ID]; ok {
+		return session.ErrItemExists
+	}
+	return r.put(s)
 }
 
-func (r *repository) marshalToJSON() ([]byte, error) {
-	payload, err := json.MarshalIndent(r.sessions, "", "  ")
-	if err != nil {
-		return nil, session.NewError(err, "failed to unmarshal sessions")
+func (r *repository) Update(ctx context.Context, s *session.Session) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if s.TTL() <= 0 {
+		return session.ErrExpired
 	}
-	return payload, nil
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if sess, ok := r.sessions[s.ID]; !ok || sess.TTL() <= 0 {
+		return session.ErrNotFound
+	}
+	return r.put(s)
 }
 
 func (r *repository) Delete(ctx context.Context, id string) error {
-	delete(r.sessions, id)
-	payload, err := r.marshalToJSON()
-	if err != nil {
+	if err := ctx.Err(); err != nil {
 		return err
 	}
-	return ioutil.WriteFile(r.filename, payload, 0755)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.delete(id)
+}
+
+func (r *repository) FindByUser(ctx context.Context, userID string) ([]*session.Session, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	sessions := make([]*session.Session, 0)
+	for _, sess := range r.sessions {
+		if sess.UserID == userID && sess.TTL() > 0 {
+			sessions = append(sessions, sess.Clone())
+		}
+	}
+	return sessions, nil
+}
+
+func (r *repository) DeleteAllForUser(ctx context.Context, userID string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var ids []string
+	for id, sess := range r.sessions {
+		if sess.UserID == userID {
+			ids = append(ids, id)
+		}
+	}
+	return r.delete(ids...)
+}
+
+// prune deletes the expired sessions. r.mu must be held.
+func (r *repository) prune() error {
+	var ids []string
+	for id, sess := range r.sessions {
+		if sess.TTL() <= 0 {
+			ids = append(ids, id)
+		}
+	}
+	return r.delete(ids...)
+}
+
+// put persists a copy of s and then keeps it in memory. r.mu must
+// be held.
+func (r *repository) put(s *session.Session) error {
+	if err := r.log.Put(s.ID, s); err != nil {
+		return session.NewError(err, "failed to write sessions")
+	}
+	r.sessions[s.ID] = s.Clone()
+	return nil
+}
+
+// delete persists the deletion of the sessions with ids and then
+// forgets them. r.mu must be held.
+func (r *repository) delete(ids ...string) error {
+	if err := r.log.Delete(ids...); err != nil {
+		return session.NewError(err, "failed to write sessions")
+	}
+	for _, id := range ids {
+		delete(r.sessions, id)
+	}
+	return nil
 }
@@ -4,9 +4,7 @@ package file_test
 
 import (
 	"context"
-	"encoding/json"
 	"github.com/stretchr/testify/assert"
-	"github.com/stretchr/testify/require"
 	"gophr.v2/session"
 	"gophr.v2/session/repository/file"
 	"os"
@@ -18,7 +16,7 @@ func TestRepository_Save(t *testing.T) {
 	sess := &session.Session{
 		ID:     "sesstest",
 		UserID: "userid",
-		Expiry: time.Now(),
+		Expiry: time.Now().Add(session.Duration),
 	}
 
 	filename := "./session_test.db"
@@ -32,7 +30,7 @@ func TestRepository_Delete(t *testing.T) {
 	sess := &session.Session{
 		ID:     "sesstest",
 		UserID: "userid",
-		Expiry: time.Now(),
+		Expiry: time.Now().Add(session.Duration),
 	}
 
 	filename := "./session_test.db"
@@ -47,7 +45,7 @@ func TestRepository_Find(t *testing.T) {
 	sess := &session.Session{
 		ID:     "sesstest",
 		UserID: "userid",
-		Expiry: time.Now(),
+		Expiry: time.Now().Add(session.Duration),
 	}
 
 	filename := "./session_test.db"
@@ -68,16 +66,11 @@ func saveToSession(t *testing.T, repo session.Repository, sess *session.Session)
 
 func assertSessionFound(err error, t *testing.T, filename string, sess *session.Session) {
 	t.Helper()
-	file, err := os.Open(filename)
+	// The session is read back from the file
+	got, err := file.New(filename).Find(context.Background(), sess.ID)
 	assert.NoError(t, err)
-	var sessions map[string]*session.Session
-	err = json.NewDecoder(file).Decode(&sessions)
-	assert.NoError(t, err)
-	_, ok := sessions[sess.ID]
-	assert.True(t, ok)
-	err = file.Close()
-	require.NoError(t, err)
-	teardownFile(t, file.Name())
+	assert.Equal(t, sess.ID, got.ID)
+	teardownFile(t, filename)
 }
 
 func teardownFile(t *testing.T, filename string) {
@@ -87,14 +80,8 @@ func teardownFile(t *testing.T, filename string) {
 
 func assertSessionNotFound(err error, t *testing.T, filename string, sess *session.Session) {
 	t.Helper()
-	file, err := os.Open(filename)
-	assert.NoError(t, err)
-	var sessions map[string]*session.Session
-	err = json.NewDecoder(file).Decode(&sessions)
-	assert.NoError(t, err)
-	_, ok := sessions[sess.ID]
-	assert.False(t, ok)
-	err = file.Close()
-	require.NoError(t, err)
-	teardownFile(t, file.Name())
+	// The deletion is read back from the file
+	_, err = file.New(filename).Find(context.Background(), sess.ID)
+	assert.Equal(t, session.ErrNotFound, err)
+	teardownFile(t, filename)
 }
@@ -0,0 +1,22 @@
+//+build unit
+
+package file_test
+
+import (
+	"github.com/stretchr/testify/require"
+	"gophr.v2/session"
+	"gophr.v2/session/repository/file"
+	"gophr.v2/session/repository/repositorytest"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestConformance(t *testing.T) {
+	repositorytest.Run(t, func(t *testing.T) (session.Repository, func()) {
+		dir, err := ioutil.TempDir("", "gophr")
+		require.NoError(t, err)
+		return file.New(filepath.Join(dir, file.DefaultFilename)), func() { _ = os.RemoveAll(dir) }
+	})
+}
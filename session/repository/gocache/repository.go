@@ -4,25 +4,37 @@ import (
 	"context"
 	"fmt"
 	"gophr.v2/session"
+	"sync"
 	"time"
 )
 
+// DefaultExpirationTime is the default expiration of the cache. Sessions
+// are always kept until their own expiry.
 const DefaultExpirationTime = 24 * time.Hour
 
 type CacheIFace interface {
 	Get(id string) (data interface{}, ok bool)
 	Add(id string, data interface{}, d time.Duration) error
+	Replace(id string, data interface{}, d time.Duration) error
 	Delete(id string)
 }
 
 func New(c CacheIFace) *Repository {
 	return &Repository{
-		c: c,
+		c:      c,
+		byUser: make(map[string]map[string]struct{}),
 	}
 }
 
+// Repository keeps copies of the sessions in the cache so that the
+// requests changing theirs don't share them.
 type Repository struct {
 	c CacheIFace
+
+	// byUser indexes the session IDs by user ID because the
+	// cache can't be queried by value.
+	mu     sync.Mutex
+	byUser map[string]map[string]struct{}
 }
 
 type result struct {
@@ -57,11 +69,17 @@ func (r *Repository) Find(ctx context.Context, id string) (*session.Session, err
 		if res.err != nil {
 			return nil, res.err
 		}
-		return res.sess.(*session.Session), nil
+		return res.sess.(*session.Session).Clone(), nil
 	}
 }
 
 func (r *Repository) Save(ctx context.Context, s *session.Session) error {
+	// A non-positive duration would make the cache keep
+	// the session with its default expiration.
+	if s.TTL() <= 0 {
+		return session.ErrExpired
+	}
+
 	res := make(chan error, 1)
 	go func() {
 		defer close(res)
@@ -71,9 +89,11 @@ func (r *Repository) Save(ctx context.Context, s *session.Session) error {
 		case <-ctx.Done():
 			err = ctx.Err()
 		default:
-			e := r.c.Add(s.ID, s, DefaultExpirationTime)
+			e := r.c.Add(s.ID, s.Clone(), s.TTL())
 			if e != nil {
 				err = fmt.Errorf("%w:%s", session.ErrItemExists, e.Error())
+			} else {
+				r.index(s)
 			}
 		}
 		res <- err
@@ -87,6 +107,79 @@ func (r *Repository) Save(ctx context.Context, s *session.Session) error {
 	}
 }
 
+func (r *Repository) Update(ctx context.Context, s *session.Session) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if s.TTL() <= 0 {
+		return session.ErrExpired
+	}
+	if err := r.c.Replace(s.ID, s.Clone(), s.TTL()); err != nil {
+		return session.ErrNotFound
+	}
+	return nil
+}
+
 func (r *Repository) Delete(ctx context.Context, id string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if v, ok := r.c.Get(id); ok {
+		r.unindex(v.(*session.Session))
+	}
+	r.c.Delete(id)
 	return nil
 }
+
+func (r *Repository) FindByUser(ctx context.Context, userID string) ([]*session.Session, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	sessions := make([]*session.Session, 0)
+	for id := range r.byUser[userID] {
+		v, ok := r.c.Get(id)
+		if !ok {
+			// Expired by the cache
+			delete(r.byUser[userID], id)
+			continue
+		}
+		sessions = append(sessions, v.(*session.Session).Clone())
+	}
+	return sessions, nil
+}
+
+func (r *Repository) DeleteAllForUser(ctx context.Context, userID string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for id := range r.byUser[userID] {
+		r.c.Delete(id)
+	}
+	delete(r.byUser, userID)
+	return nil
+}
+
+func (r *Repository) index(s *session.Session) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	ids, ok := r.byUser[s.UserID]
+	if !ok {
+		ids = make(map[string]struct{})
+		r.byUser[s.UserID] = ids
+	}
+	ids[s.ID] = struct{}{}
+}
+
+func (r *Repository) unindex(s *session.Session) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.byUser[s.UserID], s.ID)
+}
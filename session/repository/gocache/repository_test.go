@@ -6,6 +6,7 @@ import (
 	"github.com/patrickmn/go-cache"
 	"github.com/stretchr/testify/assert"
 	"gophr.v2/session"
+	"gophr.v2/session/repository/repositorytest"
 	"gophr.v2/session/sessionutil"
 	"gophr.v2/util/randutil"
 	"testing"
@@ -28,13 +29,19 @@ func (s *stubCache) Add(id string, data interface{}, d time.Duration) error {
 	return nil
 }
 
+func TestConformance(t *testing.T) {
+	repositorytest.Run(t, func(t *testing.T) (session.Repository, func()) {
+		return New(cache.New(DefaultExpirationTime, 10*time.Minute)), func() {}
+	})
+}
+
 func TestRepository_Find(t *testing.T) {
 	c := cache.New(DefaultExpirationTime, 10*time.Minute)
 	t.Run("Found", func(t *testing.T) {
 		want := &session.Session{
 			ID:     sessionutil.GenerateID(),
 			UserID: randutil.GenerateID("user"),
-			Expiry: time.Now(),
+			Expiry: time.Now().Add(session.Duration),
 		}
 
 		r := New(c)
@@ -75,7 +82,7 @@ func TestRepository_Save(t *testing.T) {
 		want := &session.Session{
 			ID:     sessionutil.GenerateID(),
 			UserID: randutil.GenerateID("user"),
-			Expiry: time.Now(),
+			Expiry: time.Now().Add(session.Duration),
 		}
 
 		r := New(c)
@@ -90,7 +97,7 @@ func TestRepository_Save(t *testing.T) {
 		want := &session.Session{
 			ID:     sessionutil.GenerateID(),
 			UserID: randutil.GenerateID("user"),
-			Expiry: time.Now(),
+			Expiry: time.Now().Add(session.Duration),
 		}
 
 		r := New(c)
@@ -110,7 +117,7 @@ func TestRepository_Save(t *testing.T) {
 		want := &session.Session{
 			ID:     sessionutil.GenerateID(),
 			UserID: randutil.GenerateID("user"),
-			Expiry: time.Now(),
+			Expiry: time.Now().Add(session.Duration),
 		}
 
 		err := r.Save(ctx, want)
@@ -129,7 +136,7 @@ func TestRepository_Delete(t *testing.T) {
 	want := &session.Session{
 		ID:     sessionutil.GenerateID(),
 		UserID: randutil.GenerateID("user"),
-		Expiry: time.Now(),
+		Expiry: time.Now().Add(session.Duration),
 	}
 
 	c := cache.New(DefaultExpirationTime, 10*time.Minute)
@@ -138,3 +145,68 @@ func TestRepository_Delete(t *testing.T) {
 	err := r.Delete(defaultCtx, want.ID)
 	assert.NoError(t, err)
 }
+
+func TestRepository_FindByUser(t *testing.T) {
+	c := cache.New(DefaultExpirationTime, 10*time.Minute)
+	r := New(c)
+	userID := randutil.GenerateID("user")
+
+	first := &session.Session{ID: sessionutil.GenerateID(), UserID: userID, Expiry: time.Now().Add(session.Duration)}
+	second := &session.Session{ID: sessionutil.GenerateID(), UserID: userID, Expiry: time.Now().Add(session.Duration)}
+	other := &session.Session{ID: sessionutil.GenerateID(), UserID: randutil.GenerateID("user"), Expiry: time.Now().Add(session.Duration)}
+	saveData(t, first, r)
+	saveData(t, second, r)
+	saveData(t, other, r)
+
+	got, err := r.FindByUser(defaultCtx, userID)
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []*session.Session{first, second}, got)
+
+	t.Run("Deleted session is not listed", func(t *testing.T) {
+		assert.NoError(t, r.Delete(defaultCtx, first.ID))
+		got, err := r.FindByUser(defaultCtx, userID)
+		assert.NoError(t, err)
+		assert.Equal(t, []*session.Session{second}, got)
+	})
+}
+
+func TestRepository_DeleteAllForUser(t *testing.T) {
+	c := cache.New(DefaultExpirationTime, 10*time.Minute)
+	r := New(c)
+	userID := randutil.GenerateID("user")
+
+	sess := &session.Session{ID: sessionutil.GenerateID(), UserID: userID, Expiry: time.Now().Add(session.Duration)}
+	other := &session.Session{ID: sessionutil.GenerateID(), UserID: randutil.GenerateID("user"), Expiry: time.Now().Add(session.Duration)}
+	saveData(t, sess, r)
+	saveData(t, other, r)
+
+	err := r.DeleteAllForUser(defaultCtx, userID)
+	assert.NoError(t, err)
+
+	_, err = r.Find(defaultCtx, sess.ID)
+	assert.Equal(t, session.ErrNotFound, err)
+	assertSavedSession(t, r, other)
+}
+
+func TestRepository_SaveExpired(t *testing.T) {
+	c := cache.New(DefaultExpirationTime, 10*time.Minute)
+	r := New(c)
+
+	sess := &session.Session{ID: sessionutil.GenerateID(), Expiry: time.Now().Add(-time.Minute)}
+	err := r.Save(defaultCtx, sess)
+	assert.Equal(t, session.ErrExpired, err)
+}
+
+func TestRepository_Update(t *testing.T) {
+	c := cache.New(DefaultExpirationTime, 10*time.Minute)
+	r := New(c)
+
+	sess := &session.Session{ID: sessionutil.GenerateID(), UserID: randutil.GenerateID("user"), Expiry: time.Now().Add(session.Duration)}
+	err := r.Update(defaultCtx, sess)
+	assert.Equal(t, session.ErrNotFound, err)
+
+	saveData(t, sess, r)
+	updated := &session.Session{ID: sess.ID, UserID: sess.UserID, LastSeen: time.Now(), Expiry: sess.Expiry}
+	assert.NoError(t, r.Update(defaultCtx, updated))
+	assertSavedSession(t, r, updated)
+}
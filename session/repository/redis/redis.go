@@ -6,6 +6,7 @@ import (
 	"github.com/go-redis/redis/v8"
 	"gophr.v2/session"
 	"strings"
+	"time"
 )
 
 func New(client *redis.Client) session.Repository {
@@ -16,6 +17,11 @@ type repository struct {
 	client *redis.Client
 }
 
+// userKey is the key of the set holding the session IDs of a user.
+func userKey(userID string) string {
+	return "session:user:" + userID
+}
+
 func (r *repository) Find(ctx context.Context, id string) (*session.Session, error) {
 	val, err := r.client.Get(ctx, id).Result()
 	if err != nil {
@@ -36,20 +42,119 @@ func (r *repository) Find(ctx context.Context, id string) (*session.Session, err
 	return sess, nil
 }
 
+// Save fails with session.ErrItemExists when a live session has the
+// same ID.
 func (r *repository) Save(ctx context.Context, s *session.Session) error {
+	ttl := s.TTL()
+	if ttl <= 0 {
+		return session.ErrExpired
+	}
+
 	payload, err := json.Marshal(s)
 	if err != nil {
 		return err
 	}
-	_, err = r.client.Set(ctx, s.ID, payload, session.DefaultExpiry).Result()
+
+	// The session is indexed only once its ID is known to be free
+	ok, err := r.client.SetNX(ctx, s.ID, payload, ttl).Result()
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return session.ErrItemExists
+	}
+
+	if s.UserID != "" {
+		if err := r.client.SAdd(ctx, userKey(s.UserID), s.ID).Err(); err != nil {
+			return err
+		}
+	}
+	return r.extendIndex(ctx, s.UserID, ttl)
+}
+
+func (r *repository) Update(ctx context.Context, s *session.Session) error {
+	ttl := s.TTL()
+	if ttl <= 0 {
+		return session.ErrExpired
+	}
+
+	payload, err := json.Marshal(s)
 	if err != nil {
 		return err
 	}
 
-	return nil
+	ok, err := r.client.SetXX(ctx, s.ID, payload, ttl).Result()
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return session.ErrNotFound
+	}
+	return r.extendIndex(ctx, s.UserID, ttl)
+}
+
+// extendIndex makes the session index of the user live at least
+// as long as ttl. It is never shortened since other sessions of
+// the user may live longer.
+func (r *repository) extendIndex(ctx context.Context, userID string, ttl time.Duration) error {
+	if userID == "" {
+		return nil
+	}
+	current, err := r.client.TTL(ctx, userKey(userID)).Result()
+	if err != nil {
+		return err
+	}
+	if current >= ttl {
+		return nil
+	}
+	return r.client.Expire(ctx, userKey(userID), ttl).Err()
 }
 
 func (r *repository) Delete(ctx context.Context, id string) error {
-	r.client.Del(ctx, id)
-	return nil
+	sess, err := r.Find(ctx, id)
+	if err != nil {
+		if err == session.ErrNotFound {
+			return nil
+		}
+		return err
+	}
+
+	_, err = r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Del(ctx, id)
+		pipe.SRem(ctx, userKey(sess.UserID), id)
+		return nil
+	})
+	return err
+}
+
+func (r *repository) FindByUser(ctx context.Context, userID string) ([]*session.Session, error) {
+	ids, err := r.client.SMembers(ctx, userKey(userID)).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	sessions := make([]*session.Session, 0, len(ids))
+	for _, id := range ids {
+		sess, err := r.Find(ctx, id)
+		if err != nil {
+			if err == session.ErrNotFound {
+				// The session already expired
+				r.client.SRem(ctx, userKey(userID), id)
+				continue
+			}
+			return nil, err
+		}
+		sessions = append(sessions, sess)
+	}
+	return sessions, nil
+}
+
+func (r *repository) DeleteAllForUser(ctx context.Context, userID string) error {
+	ids, err := r.client.SMembers(ctx, userKey(userID)).Result()
+	if err != nil {
+		return err
+	}
+
+	keys := append(ids, userKey(userID))
+	return r.client.Del(ctx, keys...).Err()
 }
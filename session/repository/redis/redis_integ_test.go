@@ -6,16 +6,18 @@ import (
 	"context"
 	"encoding/json"
 	redis2 "github.com/go-redis/redis/v8"
-	"github.com/jayvib/golog"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"gophr.v2/config"
 	"gophr.v2/driver/redis"
+	"gophr.v2/log"
 	"gophr.v2/session"
 	sessionrepo "gophr.v2/session/repository/redis"
+	"gophr.v2/session/repository/repositorytest"
 	"gophr.v2/session/sessionutil"
 	"gophr.v2/user/userutil"
 	"testing"
+	"time"
 )
 
 var conf = &config.Config{
@@ -28,8 +30,19 @@ var conf = &config.Config{
 
 var dummyCtx = context.Background()
 
+func TestConformance(t *testing.T) {
+	client := redis.New(conf)
+	repositorytest.Run(t, func(t *testing.T) (session.Repository, func()) {
+		flush := func() {
+			require.NoError(t, client.FlushDB(dummyCtx).Err())
+		}
+		flush()
+		return sessionrepo.New(client), flush
+	})
+}
+
 func TestRepository_Find(t *testing.T) {
-	golog.Debug(golog.DebugLevel)
+	log.Debug(log.DebugLevel)
 	client := redis.New(conf)
 
 	repo := sessionrepo.New(client)
@@ -38,6 +51,7 @@ func TestRepository_Find(t *testing.T) {
 		want := &session.Session{
 			ID:     sessionutil.GenerateID(),
 			UserID: userutil.GenerateID(),
+			Expiry: time.Now().Add(session.Duration).Round(0),
 		}
 		preSaveToRedis(t, client, want)
 		got, err := repo.Find(dummyCtx, want.ID)
@@ -58,6 +72,7 @@ func TestRepository_Save(t *testing.T) {
 	want := &session.Session{
 		ID:     sessionutil.GenerateID(),
 		UserID: userutil.GenerateID(),
+		Expiry: time.Now().Add(session.Duration).Round(0),
 	}
 
 	repo := sessionrepo.New(client)
@@ -0,0 +1,379 @@
+// Package repositorytest provides the conformance suite of the
+// session.Repository implementations.
+//
+// Each backend runs it from its own tests:
+//
+//	func TestConformance(t *testing.T) {
+//		repositorytest.Run(t, func(t *testing.T) (session.Repository, func()) {
+//			return New(client), func() { flush(client) }
+//		})
+//	}
+package repositorytest
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gophr.v2/session"
+	"gophr.v2/session/sessionutil"
+	"gophr.v2/util/randutil"
+	"sync"
+	"testing"
+	"time"
+)
+
+// Concurrency is the number of goroutines used by the concurrent cases.
+const Concurrency = 10
+
+// shortTTL is the time to live of the sessions expiring during a case.
+const shortTTL = 50 * time.Millisecond
+
+// Factory creates an empty repository for a single case. The returned
+// func releases it when the case is done.
+type Factory func(t *testing.T) (repo session.Repository, teardown func())
+
+// Option configures the suite.
+type Option func(s *suite)
+
+// WithoutConcurrency skips the cases using a repository from several
+// goroutines at once.
+func WithoutConcurrency() Option {
+	return func(s *suite) {
+		s.concurrency = false
+	}
+}
+
+type suite struct {
+	newRepo     Factory
+	concurrency bool
+}
+
+// Run runs the suite against the repositories created by newRepo.
+func Run(t *testing.T, newRepo Factory, opts ...Option) {
+	s := &suite{newRepo: newRepo, concurrency: true}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	t.Run("Not Found", s.testNotFound)
+	t.Run("Save", s.testSave)
+	t.Run("Update", s.testUpdate)
+	t.Run("Uniqueness", s.testUniqueness)
+	t.Run("Expiry", s.testExpiry)
+	t.Run("By User", s.testByUser)
+	t.Run("Concurrency", s.testConcurrency)
+	t.Run("Context Cancellation", s.testCancellation)
+}
+
+func (s *suite) testNotFound(t *testing.T) {
+	repo, teardown := s.newRepo(t)
+	defer teardown()
+	ctx := context.Background()
+
+	_, err := repo.Find(ctx, "unknown")
+	assert.Equal(t, session.ErrNotFound, err, "Find")
+	assert.Equal(t, session.ErrNotFound, repo.Update(ctx, NewSession("luffy", time.Hour)), "Update")
+	assert.NoError(t, repo.Delete(ctx, "unknown"), "deleting is idempotent")
+	assert.NoError(t, repo.DeleteAllForUser(ctx, "unknown"), "DeleteAllForUser")
+
+	sessions, err := repo.FindByUser(ctx, "unknown")
+	require.NoError(t, err)
+	assert.Empty(t, sessions, "FindByUser")
+}
+
+func (s *suite) testSave(t *testing.T) {
+	repo, teardown := s.newRepo(t)
+	defer teardown()
+	ctx := context.Background()
+
+	want := NewSession("luffy", time.Hour)
+	want.IP = "127.0.0.1"
+	want.UserAgent = "Mozilla/5.0"
+	require.NoError(t, repo.Save(ctx, want))
+
+	got, err := repo.Find(ctx, want.ID)
+	require.NoError(t, err)
+	AssertSession(t, want, got)
+
+	t.Run("Returns a copy", func(t *testing.T) {
+		got, err := repo.Find(ctx, want.ID)
+		require.NoError(t, err)
+		got.CSRFToken = "changed"
+		got.Flashes = append(got.Flashes, session.Flash{Message: "changed"})
+
+		got, err = repo.Find(ctx, want.ID)
+		require.NoError(t, err)
+		AssertSession(t, want, got)
+		assert.Empty(t, got.Flashes)
+
+		// Nor does the session saved change along with the one given
+		want.UserAgent = "changed"
+		got, err = repo.Find(ctx, want.ID)
+		require.NoError(t, err)
+		assert.NotEqual(t, want.UserAgent, got.UserAgent)
+	})
+
+	t.Run("Expired", func(t *testing.T) {
+		sess := NewSession("luffy", -time.Second)
+		assert.Equal(t, session.ErrExpired, repo.Save(ctx, sess))
+		_, err := repo.Find(ctx, sess.ID)
+		assert.Equal(t, session.ErrNotFound, err)
+	})
+}
+
+func (s *suite) testUpdate(t *testing.T) {
+	repo, teardown := s.newRepo(t)
+	defer teardown()
+	ctx := context.Background()
+
+	sess := NewSession("luffy", time.Hour)
+	require.NoError(t, repo.Save(ctx, sess))
+
+	updated := *sess
+	updated.LastSeen = time.Now()
+	updated.Expiry = updated.LastSeen.Add(2 * time.Hour)
+	updated.CSRFToken = "renewed"
+	require.NoError(t, repo.Update(ctx, &updated))
+
+	got, err := repo.Find(ctx, sess.ID)
+	require.NoError(t, err)
+	AssertSession(t, &updated, got)
+
+	t.Run("Expired", func(t *testing.T) {
+		expired := updated
+		expired.Expiry = time.Now().Add(-time.Second)
+		assert.Equal(t, session.ErrExpired, repo.Update(ctx, &expired))
+	})
+}
+
+func (s *suite) testUniqueness(t *testing.T) {
+	repo, teardown := s.newRepo(t)
+	defer teardown()
+	ctx := context.Background()
+
+	sess := NewSession("luffy", time.Hour)
+	require.NoError(t, repo.Save(ctx, sess))
+
+	dup := NewSession("zoro", time.Hour)
+	dup.ID = sess.ID
+	assert.True(t, errors.Is(repo.Save(ctx, dup), session.ErrItemExists))
+
+	got, err := repo.Find(ctx, sess.ID)
+	require.NoError(t, err)
+	assert.Equal(t, sess.UserID, got.UserID, "the stored session is kept")
+
+	sessions, err := repo.FindByUser(ctx, "zoro")
+	require.NoError(t, err)
+	assert.Empty(t, sessions)
+
+	t.Run("Expired sessions free their ID", func(t *testing.T) {
+		sess := NewSession("luffy", shortTTL)
+		require.NoError(t, repo.Save(ctx, sess))
+		time.Sleep(2 * shortTTL)
+
+		reuse := NewSession("luffy", time.Hour)
+		reuse.ID = sess.ID
+		assert.NoError(t, repo.Save(ctx, reuse))
+	})
+}
+
+func (s *suite) testExpiry(t *testing.T) {
+	repo, teardown := s.newRepo(t)
+	defer teardown()
+	ctx := context.Background()
+
+	sess := NewSession("luffy", shortTTL)
+	require.NoError(t, repo.Save(ctx, sess))
+	live := NewSession("luffy", time.Hour)
+	require.NoError(t, repo.Save(ctx, live))
+	time.Sleep(2 * shortTTL)
+
+	_, err := repo.Find(ctx, sess.ID)
+	assert.Equal(t, session.ErrNotFound, err, "Find")
+
+	sessions, err := repo.FindByUser(ctx, "luffy")
+	require.NoError(t, err)
+	AssertSessionIDs(t, []*session.Session{live}, sessions, "FindByUser")
+
+	renewed := *sess
+	renewed.Expiry = time.Now().Add(time.Hour)
+	assert.Equal(t, session.ErrNotFound, repo.Update(ctx, &renewed), "expired sessions can't be renewed")
+}
+
+func (s *suite) testByUser(t *testing.T) {
+	repo, teardown := s.newRepo(t)
+	defer teardown()
+	ctx := context.Background()
+
+	first, second, other := NewSession("luffy", time.Hour), NewSession("luffy", time.Hour), NewSession("zoro", time.Hour)
+	for _, sess := range []*session.Session{first, second, other} {
+		require.NoError(t, repo.Save(ctx, sess))
+	}
+
+	sessions, err := repo.FindByUser(ctx, "luffy")
+	require.NoError(t, err)
+	AssertSessionIDs(t, []*session.Session{first, second}, sessions)
+
+	require.NoError(t, repo.Delete(ctx, first.ID))
+	require.NoError(t, repo.Delete(ctx, first.ID), "deleting is idempotent")
+	_, err = repo.Find(ctx, first.ID)
+	assert.Equal(t, session.ErrNotFound, err)
+
+	sessions, err = repo.FindByUser(ctx, "luffy")
+	require.NoError(t, err)
+	AssertSessionIDs(t, []*session.Session{second}, sessions)
+
+	require.NoError(t, repo.DeleteAllForUser(ctx, "luffy"))
+	sessions, err = repo.FindByUser(ctx, "luffy")
+	require.NoError(t, err)
+	assert.Empty(t, sessions)
+	_, err = repo.Find(ctx, second.ID)
+	assert.Equal(t, session.ErrNotFound, err)
+
+	_, err = repo.Find(ctx, other.ID)
+	assert.NoError(t, err, "the sessions of the other users are kept")
+}
+
+func (s *suite) testConcurrency(t *testing.T) {
+	if !s.concurrency {
+		t.Skip("The repository isn't safe for concurrent use")
+	}
+	repo, teardown := s.newRepo(t)
+	defer teardown()
+	ctx := context.Background()
+
+	t.Run("Save", func(t *testing.T) {
+		sessions := make([]*session.Session, Concurrency)
+		var wg sync.WaitGroup
+		for i := range sessions {
+			sessions[i] = NewSession("luffy", time.Hour)
+			wg.Add(1)
+			go func(sess *session.Session) {
+				defer wg.Done()
+				assert.NoError(t, repo.Save(ctx, sess))
+			}(sessions[i])
+		}
+		wg.Wait()
+
+		got, err := repo.FindByUser(ctx, "luffy")
+		require.NoError(t, err)
+		AssertSessionIDs(t, sessions, got)
+	})
+
+	t.Run("Save the same ID", func(t *testing.T) {
+		id := sessionutil.GenerateID()
+		errs := make(chan error, Concurrency)
+		for i := 0; i < Concurrency; i++ {
+			go func() {
+				sess := NewSession("zoro", time.Hour)
+				sess.ID = id
+				errs <- repo.Save(ctx, sess)
+			}()
+		}
+
+		var saved int
+		for i := 0; i < Concurrency; i++ {
+			switch err := <-errs; {
+			case err == nil:
+				saved++
+			default:
+				assert.True(t, errors.Is(err, session.ErrItemExists), "got %v", err)
+			}
+		}
+		assert.Equal(t, 1, saved, "only one session gets the ID")
+	})
+
+	t.Run("Update while deleting", func(t *testing.T) {
+		sess := NewSession("nami", time.Hour)
+		require.NoError(t, repo.Save(ctx, sess))
+
+		var wg sync.WaitGroup
+		for i := 0; i < Concurrency; i++ {
+			wg.Add(2)
+			go func() {
+				defer wg.Done()
+				renewed := *sess
+				renewed.LastSeen = time.Now()
+				if err := repo.Update(ctx, &renewed); err != nil {
+					assert.Equal(t, session.ErrNotFound, err)
+				}
+			}()
+			go func() {
+				defer wg.Done()
+				assert.NoError(t, repo.Delete(ctx, sess.ID))
+			}()
+		}
+		wg.Wait()
+	})
+}
+
+func (s *suite) testCancellation(t *testing.T) {
+	repo, teardown := s.newRepo(t)
+	defer teardown()
+
+	sess := NewSession("luffy", time.Hour)
+	require.NoError(t, repo.Save(context.Background(), sess))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := repo.Find(ctx, sess.ID)
+	assertCanceled(t, err, "Find")
+	_, err = repo.FindByUser(ctx, sess.UserID)
+	assertCanceled(t, err, "FindByUser")
+	assertCanceled(t, repo.Update(ctx, sess), "Update")
+
+	unsaved := NewSession("luffy", time.Hour)
+	assertCanceled(t, repo.Save(ctx, unsaved), "Save")
+	_, err = repo.Find(context.Background(), unsaved.ID)
+	assert.Equal(t, session.ErrNotFound, err, "canceled saves are not stored")
+
+	assertCanceled(t, repo.Delete(ctx, sess.ID), "Delete")
+	assertCanceled(t, repo.DeleteAllForUser(ctx, sess.UserID), "DeleteAllForUser")
+	_, err = repo.Find(context.Background(), sess.ID)
+	assert.NoError(t, err, "canceled deletes are not stored")
+}
+
+func assertCanceled(t *testing.T, err error, op string) {
+	t.Helper()
+	assert.True(t, errors.Is(err, context.Canceled), "%s: want context.Canceled, got %v", op, err)
+}
+
+// NewSession creates a session of the user with userID living
+// for ttl.
+func NewSession(userID string, ttl time.Duration) *session.Session {
+	sess := sessionutil.New(userID)
+	sess.LastSeen = sess.CreatedAt
+	sess.Expiry = sess.CreatedAt.Add(ttl)
+	sess.CSRFToken = randutil.GenerateToken(16)
+	return sess
+}
+
+// AssertSession asserts that got is the stored want. The times are
+// compared by instant since the backends may serialize them.
+func AssertSession(t *testing.T, want, got *session.Session) {
+	t.Helper()
+	assert.Equal(t, want.ID, got.ID)
+	assert.Equal(t, want.UserID, got.UserID)
+	assert.Equal(t, want.IP, got.IP)
+	assert.Equal(t, want.UserAgent, got.UserAgent)
+	assert.Equal(t, want.CSRFToken, got.CSRFToken)
+	assert.True(t, want.Expiry.Equal(got.Expiry), "Expiry: want %v, got %v", want.Expiry, got.Expiry)
+	assert.True(t, want.CreatedAt.Equal(got.CreatedAt), "CreatedAt: want %v, got %v", want.CreatedAt, got.CreatedAt)
+	assert.True(t, want.LastSeen.Equal(got.LastSeen), "LastSeen: want %v, got %v", want.LastSeen, got.LastSeen)
+}
+
+// AssertSessionIDs asserts that got are the stored want in any order.
+func AssertSessionIDs(t *testing.T, want, got []*session.Session, msgAndArgs ...interface{}) {
+	t.Helper()
+	assert.ElementsMatch(t, sessionIDs(want), sessionIDs(got), msgAndArgs...)
+}
+
+func sessionIDs(sessions []*session.Session) []string {
+	ids := make([]string, len(sessions))
+	for i, sess := range sessions {
+		ids[i] = sess.ID
+	}
+	return ids
+}
@@ -0,0 +1,158 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"gophr.v2/session"
+	"strings"
+	"time"
+)
+
+var _ session.Repository = (*Repository)(nil)
+
+// errUniqueViolation starts the message of the SQLite error raised
+// when a unique index is violated.
+const errUniqueViolation = "UNIQUE constraint failed: "
+
+// New creates a repository storing the sessions in db. The sessions
+// are stored as JSON like in Redis.
+func New(db *sql.DB) *Repository {
+	return &Repository{conn: db}
+}
+
+type Repository struct {
+	conn *sql.DB
+}
+
+func (r *Repository) Find(ctx context.Context, id string) (*session.Session, error) {
+	var payload string
+	var expiry time.Time
+	query := "SELECT payload, expiry FROM sessions WHERE id = ?"
+	err := r.conn.QueryRowContext(ctx, query, id).Scan(&payload, &expiry)
+	if err != nil {
+		return nil, r.checkError(err)
+	}
+
+	if !time.Now().Before(expiry) {
+		_ = r.Delete(ctx, id)
+		return nil, session.ErrNotFound
+	}
+	return r.decode(payload)
+}
+
+// Save fails with session.ErrItemExists when a live session has the
+// same ID. The expired sessions nobody looked for since are deleted on
+// the way, freeing their IDs.
+func (r *Repository) Save(ctx context.Context, s *session.Session) error {
+	payload, err := r.encode(s)
+	if err != nil {
+		return err
+	}
+
+	if _, err := r.conn.ExecContext(ctx, "DELETE FROM sessions WHERE expiry <= ?", time.Now().UTC()); err != nil {
+		return r.checkError(err)
+	}
+
+	query := "INSERT INTO sessions(id, userId, payload, expiry) VALUES(?,?,?,?)"
+	_, err = r.conn.ExecContext(ctx, query, s.ID, s.UserID, payload, s.Expiry.UTC())
+	if err != nil && strings.Contains(err.Error(), errUniqueViolation) {
+		return session.ErrItemExists
+	}
+	return r.checkError(err)
+}
+
+func (r *Repository) Update(ctx context.Context, s *session.Session) error {
+	payload, err := r.encode(s)
+	if err != nil {
+		return err
+	}
+
+	query := "UPDATE sessions SET userId = ?, payload = ?, expiry = ? WHERE id = ? AND expiry > ?"
+	res, err := r.conn.ExecContext(ctx, query, s.UserID, payload, s.Expiry.UTC(), s.ID, time.Now().UTC())
+	if err != nil {
+		return r.checkError(err)
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return r.checkError(err)
+	}
+	if affected == 0 {
+		return session.ErrNotFound
+	}
+	return nil
+}
+
+// Delete is idempotent like the other repositories.
+func (r *Repository) Delete(ctx context.Context, id string) error {
+	_, err := r.conn.ExecContext(ctx, "DELETE FROM sessions WHERE id = ?", id)
+	return r.checkError(err)
+}
+
+func (r *Repository) FindByUser(ctx context.Context, userID string) (sessions []*session.Session, err error) {
+	query := "SELECT payload FROM sessions WHERE userId = ? AND expiry > ? ORDER BY expiry"
+	rows, err := r.conn.QueryContext(ctx, query, userID, time.Now().UTC())
+	if err != nil {
+		return nil, r.checkError(err)
+	}
+	defer func() {
+		if e := rows.Close(); err == nil && e != nil {
+			err = e
+		}
+	}()
+
+	sessions = make([]*session.Session, 0)
+	for rows.Next() {
+		var payload string
+		if err := rows.Scan(&payload); err != nil {
+			return nil, r.checkError(err)
+		}
+		sess, err := r.decode(payload)
+		if err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, sess)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, r.checkError(err)
+	}
+	return sessions, nil
+}
+
+func (r *Repository) DeleteAllForUser(ctx context.Context, userID string) error {
+	_, err := r.conn.ExecContext(ctx, "DELETE FROM sessions WHERE userId = ?", userID)
+	return r.checkError(err)
+}
+
+func (r *Repository) encode(s *session.Session) (string, error) {
+	if s.TTL() <= 0 {
+		return "", session.ErrExpired
+	}
+	payload, err := json.Marshal(s)
+	if err != nil {
+		return "", err
+	}
+	return string(payload), nil
+}
+
+func (r *Repository) decode(payload string) (*session.Session, error) {
+	sess := new(session.Session)
+	if err := json.Unmarshal([]byte(payload), sess); err != nil {
+		return nil, err
+	}
+	return sess, nil
+}
+
+func (r *Repository) checkError(err error) error {
+	var cerr error
+	switch err {
+	case sql.ErrNoRows:
+		cerr = session.ErrNotFound
+	case nil:
+		cerr = nil
+	default:
+		cerr = fmt.Errorf("sqlite: unexpected error %w", err)
+	}
+	return cerr
+}
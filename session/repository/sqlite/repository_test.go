@@ -0,0 +1,132 @@
+//+build unit
+
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	sqlitedriver "gophr.v2/driver/sqlite"
+	"gophr.v2/migration"
+	"gophr.v2/session"
+	"gophr.v2/session/repository/repositorytest"
+	"gophr.v2/session/sessionutil"
+	"testing"
+	"time"
+)
+
+var defaultCtx = context.Background()
+
+func setup(t *testing.T) (*Repository, *sql.DB) {
+	db, err := sqlitedriver.Open(sqlitedriver.Memory)
+	require.NoError(t, err)
+	_, err = migration.New(db, migration.SQLiteDialect{}, migration.SQLite()).Up(defaultCtx)
+	require.NoError(t, err)
+	return New(db), db
+}
+
+func newSession(userID string) *session.Session {
+	sess := sessionutil.New(userID)
+	sess.Expiry = time.Now().Add(time.Hour)
+	sess.LastSeen = sess.CreatedAt
+	sess.CSRFToken = "token"
+	return sess
+}
+
+func TestConformance(t *testing.T) {
+	repositorytest.Run(t, func(t *testing.T) (session.Repository, func()) {
+		repo, db := setup(t)
+		return repo, func() { _ = db.Close() }
+	})
+}
+
+func TestRepository_Save(t *testing.T) {
+	repo, db := setup(t)
+	defer db.Close()
+
+	want := newSession("luffy")
+	require.NoError(t, repo.Save(defaultCtx, want))
+
+	got, err := repo.Find(defaultCtx, want.ID)
+	require.NoError(t, err)
+	assert.Equal(t, want.ID, got.ID)
+	assert.Equal(t, want.UserID, got.UserID)
+	assert.Equal(t, want.CSRFToken, got.CSRFToken)
+	assert.True(t, want.Expiry.Equal(got.Expiry))
+
+	t.Run("Expired", func(t *testing.T) {
+		sess := newSession("luffy")
+		sess.Expiry = time.Now().Add(-time.Second)
+		assert.Equal(t, session.ErrExpired, repo.Save(defaultCtx, sess))
+	})
+}
+
+func TestRepository_Find(t *testing.T) {
+	repo, db := setup(t)
+	defer db.Close()
+
+	t.Run("Not Found", func(t *testing.T) {
+		_, err := repo.Find(defaultCtx, "unknown")
+		assert.Equal(t, session.ErrNotFound, err)
+	})
+
+	t.Run("Expired", func(t *testing.T) {
+		sess := newSession("luffy")
+		sess.Expiry = time.Now().Add(50 * time.Millisecond)
+		require.NoError(t, repo.Save(defaultCtx, sess))
+		time.Sleep(100 * time.Millisecond)
+
+		_, err := repo.Find(defaultCtx, sess.ID)
+		assert.Equal(t, session.ErrNotFound, err)
+	})
+}
+
+func TestRepository_Update(t *testing.T) {
+	repo, db := setup(t)
+	defer db.Close()
+
+	sess := newSession("luffy")
+	require.NoError(t, repo.Save(defaultCtx, sess))
+
+	sess.Expiry = time.Now().Add(2 * time.Hour)
+	sess.CSRFToken = "renewed"
+	require.NoError(t, repo.Update(defaultCtx, sess))
+
+	got, err := repo.Find(defaultCtx, sess.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "renewed", got.CSRFToken)
+
+	t.Run("Not Found", func(t *testing.T) {
+		assert.Equal(t, session.ErrNotFound, repo.Update(defaultCtx, newSession("luffy")))
+	})
+}
+
+func TestRepository_ByUser(t *testing.T) {
+	repo, db := setup(t)
+	defer db.Close()
+
+	first, second, other := newSession("luffy"), newSession("luffy"), newSession("zoro")
+	for _, sess := range []*session.Session{first, second, other} {
+		require.NoError(t, repo.Save(defaultCtx, sess))
+	}
+
+	sessions, err := repo.FindByUser(defaultCtx, "luffy")
+	require.NoError(t, err)
+	assert.Len(t, sessions, 2)
+
+	require.NoError(t, repo.Delete(defaultCtx, first.ID))
+	require.NoError(t, repo.Delete(defaultCtx, first.ID), "deleting is idempotent")
+	sessions, err = repo.FindByUser(defaultCtx, "luffy")
+	require.NoError(t, err)
+	require.Len(t, sessions, 1)
+	assert.Equal(t, second.ID, sessions[0].ID)
+
+	require.NoError(t, repo.DeleteAllForUser(defaultCtx, "luffy"))
+	sessions, err = repo.FindByUser(defaultCtx, "luffy")
+	require.NoError(t, err)
+	assert.Empty(t, sessions)
+
+	_, err = repo.Find(defaultCtx, other.ID)
+	assert.NoError(t, err)
+}
@@ -4,10 +4,25 @@ import "time"
 
 //go:generate mockery --name=Service
 
+// DefaultExpiry is the default idle timeout of a session.
 const DefaultExpiry = 12 * time.Hour
 
 type Service interface {
 	Finder
 	Saver
+	Updater
 	Deleter
+	UserFinder
+	UserDeleter
+	Renewer
+}
+
+type Decorator func(svc Service) Service
+
+func ApplyDecorators(svc Service, d ...Decorator) Service {
+	copySvc := svc
+	for _, deco := range d {
+		copySvc = deco(copySvc)
+	}
+	return copySvc
 }
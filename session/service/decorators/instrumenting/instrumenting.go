@@ -0,0 +1,58 @@
+package instrumenting
+
+import (
+	"context"
+	"github.com/prometheus/client_golang/prometheus"
+	"gophr.v2/metrics"
+	"gophr.v2/session"
+	"time"
+)
+
+// New returns a decorator recording the count, the outcome and the
+// latency of the calls of the session service in reg.
+func New(reg prometheus.Registerer) session.Decorator {
+	calls := metrics.NewCalls(reg, "session")
+	return func(svc session.Service) session.Service {
+		return &instrumentingDecorator{svc: svc, calls: calls}
+	}
+}
+
+type instrumentingDecorator struct {
+	svc   session.Service
+	calls *metrics.Calls
+}
+
+func (i *instrumentingDecorator) Find(ctx context.Context, id string) (sess *session.Session, err error) {
+	defer i.calls.Observe("Find", time.Now(), &err)
+	return i.svc.Find(ctx, id)
+}
+
+func (i *instrumentingDecorator) Save(ctx context.Context, s *session.Session) (err error) {
+	defer i.calls.Observe("Save", time.Now(), &err)
+	return i.svc.Save(ctx, s)
+}
+
+func (i *instrumentingDecorator) Update(ctx context.Context, s *session.Session) (err error) {
+	defer i.calls.Observe("Update", time.Now(), &err)
+	return i.svc.Update(ctx, s)
+}
+
+func (i *instrumentingDecorator) Delete(ctx context.Context, id string) (err error) {
+	defer i.calls.Observe("Delete", time.Now(), &err)
+	return i.svc.Delete(ctx, id)
+}
+
+func (i *instrumentingDecorator) FindByUser(ctx context.Context, userID string) (sessions []*session.Session, err error) {
+	defer i.calls.Observe("FindByUser", time.Now(), &err)
+	return i.svc.FindByUser(ctx, userID)
+}
+
+func (i *instrumentingDecorator) DeleteAllForUser(ctx context.Context, userID string) (err error) {
+	defer i.calls.Observe("DeleteAllForUser", time.Now(), &err)
+	return i.svc.DeleteAllForUser(ctx, userID)
+}
+
+func (i *instrumentingDecorator) Renew(ctx context.Context, s *session.Session) (renewed bool, err error) {
+	defer i.calls.Observe("Renew", time.Now(), &err)
+	return i.svc.Renew(ctx, s)
+}
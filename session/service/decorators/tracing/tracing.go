@@ -0,0 +1,59 @@
+package tracing
+
+import (
+	"context"
+	"gophr.v2/session"
+	gophrtracing "gophr.v2/tracing"
+)
+
+// Apply decorates svc with a span for every call. It is a
+// session.Decorator.
+func Apply(svc session.Service) session.Service {
+	return &tracingDecorator{svc}
+}
+
+type tracingDecorator struct {
+	svc session.Service
+}
+
+func (t *tracingDecorator) Find(ctx context.Context, id string) (sess *session.Session, err error) {
+	ctx, span := gophrtracing.Start(ctx, "session.Find")
+	defer gophrtracing.End(span, &err)
+	return t.svc.Find(ctx, id)
+}
+
+func (t *tracingDecorator) Save(ctx context.Context, s *session.Session) (err error) {
+	ctx, span := gophrtracing.Start(ctx, "session.Save")
+	defer gophrtracing.End(span, &err)
+	return t.svc.Save(ctx, s)
+}
+
+func (t *tracingDecorator) Update(ctx context.Context, s *session.Session) (err error) {
+	ctx, span := gophrtracing.Start(ctx, "session.Update")
+	defer gophrtracing.End(span, &err)
+	return t.svc.Update(ctx, s)
+}
+
+func (t *tracingDecorator) Delete(ctx context.Context, id string) (err error) {
+	ctx, span := gophrtracing.Start(ctx, "session.Delete")
+	defer gophrtracing.End(span, &err)
+	return t.svc.Delete(ctx, id)
+}
+
+func (t *tracingDecorator) FindByUser(ctx context.Context, userID string) (sessions []*session.Session, err error) {
+	ctx, span := gophrtracing.Start(ctx, "session.FindByUser")
+	defer gophrtracing.End(span, &err)
+	return t.svc.FindByUser(ctx, userID)
+}
+
+func (t *tracingDecorator) DeleteAllForUser(ctx context.Context, userID string) (err error) {
+	ctx, span := gophrtracing.Start(ctx, "session.DeleteAllForUser")
+	defer gophrtracing.End(span, &err)
+	return t.svc.DeleteAllForUser(ctx, userID)
+}
+
+func (t *tracingDecorator) Renew(ctx context.Context, s *session.Session) (renewed bool, err error) {
+	ctx, span := gophrtracing.Start(ctx, "session.Renew")
+	defer gophrtracing.End(span, &err)
+	return t.svc.Renew(ctx, s)
+}
@@ -3,15 +3,94 @@ package service
 import (
 	"context"
 	"fmt"
+	"gophr.v2/config"
+	"gophr.v2/event"
 	"gophr.v2/session"
+	"time"
 )
 
-func New(repo session.Repository) session.Service {
-	return &Service{repo: repo}
+// DefaultPolicy is the session lifecycle used when none is configured.
+var DefaultPolicy = Policy{
+	IdleTimeout:      session.DefaultExpiry,
+	AbsoluteLifetime: session.Duration,
+	RenewInterval:    session.LastSeenInterval,
+}
+
+// Policy describes the lifecycle of a session.
+//
+// A session expires after IdleTimeout without any request and never
+// lives longer than AbsoluteLifetime after its creation. Active sessions
+// are renewed at most once per RenewInterval.
+type Policy struct {
+	IdleTimeout      time.Duration
+	AbsoluteLifetime time.Duration
+	RenewInterval    time.Duration
+}
+
+// PolicyFromConfig creates a policy from conf. Zero values fall
+// back to the DefaultPolicy.
+func PolicyFromConfig(conf config.Session) Policy {
+	p := DefaultPolicy
+	if conf.IdleTimeout > 0 {
+		p.IdleTimeout = conf.IdleTimeout
+	}
+	if conf.AbsoluteLifetime > 0 {
+		p.AbsoluteLifetime = conf.AbsoluteLifetime
+	}
+	return p
+}
+
+// expiry returns the expiry of sess when it is used at now.
+func (p Policy) expiry(sess *session.Session, now time.Time) time.Time {
+	expiry := now.Add(p.IdleTimeout)
+	if deadline := sess.CreatedAt.Add(p.AbsoluteLifetime); expiry.After(deadline) {
+		expiry = deadline
+	}
+	return expiry
+}
+
+func (p Policy) isExpired(sess *session.Session, now time.Time) bool {
+	if !now.Before(sess.Expiry) {
+		return true
+	}
+	if !sess.CreatedAt.IsZero() && !now.Before(sess.CreatedAt.Add(p.AbsoluteLifetime)) {
+		return true
+	}
+	if !sess.LastSeen.IsZero() && !now.Before(sess.LastSeen.Add(p.IdleTimeout)) {
+		return true
+	}
+	return false
+}
+
+// Option configures the Service.
+type Option func(s *Service)
+
+// WithPolicy replaces the DefaultPolicy.
+func WithPolicy(policy Policy) Option {
+	return func(s *Service) {
+		s.policy = policy
+	}
+}
+
+// WithEvents publishes the session events to publisher.
+func WithEvents(publisher event.Publisher) Option {
+	return func(s *Service) {
+		s.events = publisher
+	}
+}
+
+func New(repo session.Repository, opts ...Option) session.Service {
+	s := &Service{repo: repo, policy: DefaultPolicy}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
 }
 
 type Service struct {
-	repo session.Repository
+	repo   session.Repository
+	policy Policy
+	events event.Publisher
 }
 
 func (s *Service) Find(ctx context.Context, id string) (*session.Session, error) {
@@ -29,10 +108,86 @@ func (s *Service) wrapError(err error, msg string) error {
 	return err
 }
 
+// Save starts the lifecycle of sess by setting its creation,
+// last seen and expiry time.
 func (s *Service) Save(ctx context.Context, sess *session.Session) error {
-	return s.repo.Save(ctx, sess)
+	now := time.Now()
+	if sess.CreatedAt.IsZero() {
+		sess.CreatedAt = now
+	}
+	sess.LastSeen = now
+	sess.Expiry = s.policy.expiry(sess, now)
+
+	ctx = event.Record(ctx, &session.Started{
+		UserID:    sess.UserID,
+		IP:        sess.IP,
+		UserAgent: sess.UserAgent,
+		At:        now,
+	})
+	if err := s.repo.Save(ctx, sess); err != nil {
+		return err
+	}
+	event.DispatchCommitted(ctx, s.events)
+	return nil
 }
 
 func (s *Service) Delete(ctx context.Context, id string) error {
-	return s.repo.Delete(ctx, id)
+	sess, err := s.repo.Find(ctx, id)
+	if err != nil {
+		// Deleting is idempotent
+		if err == session.ErrNotFound {
+			return nil
+		}
+		return err
+	}
+
+	ctx = event.Record(ctx, &session.Ended{UserID: sess.UserID, At: time.Now()})
+	if err := s.repo.Delete(ctx, id); err != nil {
+		return err
+	}
+	event.DispatchCommitted(ctx, s.events)
+	return nil
+}
+
+func (s *Service) Update(ctx context.Context, sess *session.Session) error {
+	return s.repo.Update(ctx, sess)
+}
+
+// Renew slides the expiry of an active sess. It returns session.ErrExpired
+// when sess is past its idle timeout or absolute lifetime, and false when
+// it was renewed less than the renew interval ago.
+//
+// The renewed sess isn't saved, it's meant to be saved with Update along
+// with the other changes of the request.
+func (s *Service) Renew(ctx context.Context, sess *session.Session) (bool, error) {
+	now := time.Now()
+	if s.policy.isExpired(sess, now) {
+		_ = s.repo.Delete(ctx, sess.ID)
+		return false, session.ErrExpired
+	}
+
+	if now.Sub(sess.LastSeen) < s.policy.RenewInterval {
+		return false, nil
+	}
+
+	sess.LastSeen = now
+	sess.Expiry = s.policy.expiry(sess, now)
+	return true, nil
+}
+
+func (s *Service) FindByUser(ctx context.Context, userID string) ([]*session.Session, error) {
+	sessions, err := s.repo.FindByUser(ctx, userID)
+	if err != nil {
+		return nil, s.wrapError(err, fmt.Sprintf("Failed finding sessions of user: %s", userID))
+	}
+	return sessions, nil
+}
+
+func (s *Service) DeleteAllForUser(ctx context.Context, userID string) error {
+	ctx = event.Record(ctx, &session.Ended{UserID: userID, All: true, At: time.Now()})
+	if err := s.repo.DeleteAllForUser(ctx, userID); err != nil {
+		return err
+	}
+	event.DispatchCommitted(ctx, s.events)
+	return nil
 }
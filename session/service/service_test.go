@@ -6,6 +6,7 @@ import (
 	"context"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	eventmocks "gophr.v2/event/mocks"
 	"gophr.v2/session"
 	"gophr.v2/session/mocks"
 	"testing"
@@ -14,12 +15,28 @@ import (
 
 func TestService_Delete(t *testing.T) {
 	repo := new(mocks.Repository)
+	repo.On("Find", mock.Anything, "1234").Return(&session.Session{ID: "1234", UserID: "user123"}, nil).Once()
 	repo.On("Delete", mock.Anything, mock.AnythingOfType("string")).Return(nil).Once()
-	svc := New(repo)
+	publisher := new(eventmocks.Publisher)
+	publisher.On("Publish", mock.Anything, mock.MatchedBy(func(e *session.Ended) bool {
+		return e.UserID == "user123" && !e.All
+	})).Return(nil).Once()
+	svc := New(repo, WithEvents(publisher))
 
 	err := svc.Delete(context.Background(), "1234")
 	assert.NoError(t, err)
 	repo.AssertExpectations(t)
+	publisher.AssertExpectations(t)
+
+	t.Run("Already Deleted", func(t *testing.T) {
+		repo := new(mocks.Repository)
+		repo.On("Find", mock.Anything, "1234").Return(nil, session.ErrNotFound).Once()
+		svc := New(repo, WithEvents(publisher))
+
+		err := svc.Delete(context.Background(), "1234")
+		assert.NoError(t, err)
+		repo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+	})
 }
 
 func TestService_Find(t *testing.T) {
@@ -63,9 +80,111 @@ func TestService_Save(t *testing.T) {
 	}
 	repo := new(mocks.Repository)
 	repo.On("Save", mock.Anything, mock.AnythingOfType("*session.Session")).Return(nil).Once()
+	publisher := new(eventmocks.Publisher)
+	publisher.On("Publish", mock.Anything, mock.MatchedBy(func(e *session.Started) bool {
+		return e.UserID == sess.UserID
+	})).Return(nil).Once()
+	svc := New(repo, WithEvents(publisher))
+
+	err := svc.Save(context.Background(), sess)
+	assert.NoError(t, err)
+	repo.AssertExpectations(t)
+	publisher.AssertExpectations(t)
+}
+
+func TestService_FindByUser(t *testing.T) {
+	want := []*session.Session{{ID: "test123", UserID: "userid123"}}
+	repo := new(mocks.Repository)
+	repo.On("FindByUser", mock.Anything, "userid123").Return(want, nil).Once()
 	svc := New(repo)
 
+	got, err := svc.FindByUser(context.Background(), "userid123")
+	assert.NoError(t, err)
+	assert.Equal(t, want, got)
+	repo.AssertExpectations(t)
+}
+
+func TestService_SaveSetsExpiry(t *testing.T) {
+	policy := Policy{IdleTimeout: time.Hour, AbsoluteLifetime: 2 * time.Hour, RenewInterval: time.Minute}
+	sess := &session.Session{ID: "test123", UserID: "userid123"}
+	repo := new(mocks.Repository)
+	repo.On("Save", mock.Anything, sess).Return(nil).Once()
+	svc := New(repo, WithPolicy(policy))
+
 	err := svc.Save(context.Background(), sess)
 	assert.NoError(t, err)
+	assert.WithinDuration(t, time.Now(), sess.CreatedAt, time.Second)
+	assert.WithinDuration(t, time.Now().Add(time.Hour), sess.Expiry, time.Second)
 	repo.AssertExpectations(t)
 }
+
+func TestService_Renew(t *testing.T) {
+	policy := Policy{IdleTimeout: time.Hour, AbsoluteLifetime: 2 * time.Hour, RenewInterval: time.Minute}
+
+	t.Run("Renewed", func(t *testing.T) {
+		sess := &session.Session{
+			ID:        "test123",
+			CreatedAt: time.Now().Add(-30 * time.Minute),
+			LastSeen:  time.Now().Add(-10 * time.Minute),
+			Expiry:    time.Now().Add(50 * time.Minute),
+		}
+		repo := new(mocks.Repository)
+		svc := New(repo, WithPolicy(policy))
+
+		renewed, err := svc.Renew(context.Background(), sess)
+		assert.NoError(t, err)
+		assert.True(t, renewed)
+		assert.WithinDuration(t, time.Now().Add(time.Hour), sess.Expiry, time.Second)
+		// The renewal is saved with the other changes of the request
+		repo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Recently Renewed", func(t *testing.T) {
+		sess := &session.Session{
+			ID:        "test123",
+			CreatedAt: time.Now().Add(-30 * time.Minute),
+			LastSeen:  time.Now(),
+			Expiry:    time.Now().Add(time.Hour),
+		}
+		repo := new(mocks.Repository)
+		svc := New(repo, WithPolicy(policy))
+
+		renewed, err := svc.Renew(context.Background(), sess)
+		assert.NoError(t, err)
+		assert.False(t, renewed)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("Capped By Absolute Lifetime", func(t *testing.T) {
+		createdAt := time.Now().Add(-90 * time.Minute)
+		sess := &session.Session{
+			ID:        "test123",
+			CreatedAt: createdAt,
+			LastSeen:  time.Now().Add(-10 * time.Minute),
+			Expiry:    time.Now().Add(20 * time.Minute),
+		}
+		repo := new(mocks.Repository)
+		svc := New(repo, WithPolicy(policy))
+
+		renewed, err := svc.Renew(context.Background(), sess)
+		assert.NoError(t, err)
+		assert.True(t, renewed)
+		assert.Equal(t, createdAt.Add(2*time.Hour), sess.Expiry)
+	})
+
+	t.Run("Idle", func(t *testing.T) {
+		sess := &session.Session{
+			ID:        "test123",
+			CreatedAt: time.Now().Add(-90 * time.Minute),
+			LastSeen:  time.Now().Add(-61 * time.Minute),
+			Expiry:    time.Now().Add(time.Hour),
+		}
+		repo := new(mocks.Repository)
+		repo.On("Delete", mock.Anything, sess.ID).Return(nil).Once()
+		svc := New(repo, WithPolicy(policy))
+
+		_, err := svc.Renew(context.Background(), sess)
+		assert.Equal(t, session.ErrExpired, err)
+		repo.AssertExpectations(t)
+	})
+}
@@ -1,31 +1,89 @@
 package sessionutil
 
 import (
+	"gophr.v2/config"
 	"gophr.v2/session"
 	"gophr.v2/util/randutil"
 	"net/http"
+	"strings"
 	"time"
 )
 
+// DefaultCookie holds the cookie attributes used when none are configured.
+var DefaultCookie = Cookie{
+	Path:     "/",
+	SameSite: http.SameSiteLaxMode,
+}
+
+// Cookie describes the attributes of the session cookie. The
+// cookie is always HttpOnly.
+type Cookie struct {
+	Path     string
+	Domain   string
+	Secure   bool
+	SameSite http.SameSite
+}
+
+// CookieFromConfig creates the cookie attributes from conf. Zero
+// values fall back to the DefaultCookie.
+func CookieFromConfig(conf config.Cookie) Cookie {
+	c := DefaultCookie
+	if conf.Path != "" {
+		c.Path = conf.Path
+	}
+	c.Domain = conf.Domain
+	c.Secure = conf.Secure
+
+	switch strings.ToLower(conf.SameSite) {
+	case "strict":
+		c.SameSite = http.SameSiteStrictMode
+	case "none":
+		c.SameSite = http.SameSiteNoneMode
+	case "lax":
+		c.SameSite = http.SameSiteLaxMode
+	}
+	return c
+}
+
 // GenerateID generates a random tokens based on UUID Version 5
 func GenerateID() string {
 	return randutil.GenerateID("session")
 }
 
-func WriteSessionTo(w http.ResponseWriter) *session.Session {
-	expiry := time.Now().Add(session.Duration)
-	sess := &session.Session{
-		ID:     GenerateID(),
-		Expiry: expiry,
-	}
-
-	cookie := http.Cookie{
-		Name:    session.CookieName,
-		Value:   sess.ID,
-		Expires: sess.Expiry,
+// New creates a session for the user with userID. Its expiry is
+// set by the session service when saved.
+func New(userID string) *session.Session {
+	return &session.Session{
+		ID:        GenerateID(),
+		UserID:    userID,
+		CreatedAt: time.Now(),
 	}
+}
 
-	http.SetCookie(w, &cookie)
+// WriteCookie writes the cookie of sess expiring along with it.
+func WriteCookie(w http.ResponseWriter, sess *session.Session, c Cookie) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     session.CookieName,
+		Value:    sess.ID,
+		Expires:  sess.Expiry,
+		Path:     c.Path,
+		Domain:   c.Domain,
+		Secure:   c.Secure,
+		HttpOnly: true,
+		SameSite: c.SameSite,
+	})
+}
 
-	return sess
+// ClearCookie tells the browser to remove the session cookie.
+func ClearCookie(w http.ResponseWriter, c Cookie) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     session.CookieName,
+		Value:    "",
+		MaxAge:   -1,
+		Path:     c.Path,
+		Domain:   c.Domain,
+		Secure:   c.Secure,
+		HttpOnly: true,
+		SameSite: c.SameSite,
+	})
 }
@@ -0,0 +1,35 @@
+// Package softdelete lets the reads of the repositories see the soft
+// deleted records.
+//
+// Deleting a user or an image only marks it as deleted. The
+// repositories leave the marked records out of every read unless
+// the context says otherwise, so that only the code restoring or
+// purging them has to care.
+package softdelete
+
+import "context"
+
+type includeKey struct{}
+
+// Include returns a copy of ctx whose reads include the soft
+// deleted records.
+func Include(ctx context.Context) context.Context {
+	return context.WithValue(ctx, includeKey{}, true)
+}
+
+// Included tells whether the reads done with ctx include the soft
+// deleted records.
+func Included(ctx context.Context) bool {
+	included, _ := ctx.Value(includeKey{}).(bool)
+	return included
+}
+
+// SQLFilter is the SQL condition leaving the soft deleted records out
+// of a query unless ctx includes them. The records are expected to be
+// marked by a deleted_at column.
+func SQLFilter(ctx context.Context) string {
+	if Included(ctx) {
+		return "TRUE"
+	}
+	return "deleted_at IS NULL"
+}
@@ -0,0 +1,66 @@
+//+build unit
+
+package tracing
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"gophr.v2/log"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func setupRouter(buf *bytes.Buffer) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(log.Middleware(log.New(buf, log.WithJSON())), Middleware("gophr-test"), gin.Recovery())
+	r.GET("/users/:id", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+	return r
+}
+
+func TestMiddleware(t *testing.T) {
+	t.Run("Propagated Trace", func(t *testing.T) {
+		rec, restore := record(t)
+		defer restore()
+
+		var buf bytes.Buffer
+		req := httptest.NewRequest(http.MethodGet, "/users/luffy-id", nil)
+		req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+		setupRouter(&buf).ServeHTTP(httptest.NewRecorder(), req)
+
+		spans := rec.Ended()
+		require.Len(t, spans, 1)
+		assert.Equal(t, "GET /users/:id", spans[0].Name())
+		assert.Equal(t, trace.SpanKindServer, spans[0].SpanKind())
+		assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", spans[0].SpanContext().TraceID().String())
+		assert.Equal(t, "00f067aa0ba902b7", spans[0].Parent().SpanID().String())
+
+		entry := make(map[string]interface{})
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+		assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", entry[log.TraceIDKey])
+	})
+
+	t.Run("Unmatched", func(t *testing.T) {
+		rec, restore := record(t)
+		defer restore()
+
+		var buf bytes.Buffer
+		setupRouter(&buf).ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/nowhere?token=secret", nil))
+
+		spans := rec.Ended()
+		require.Len(t, spans, 1)
+		assert.Equal(t, "GET unmatched", spans[0].Name())
+		assert.Equal(t, codes.Unset, spans[0].Status().Code)
+		for _, attr := range spans[0].Attributes() {
+			assert.NotContains(t, attr.Value.Emit(), "secret")
+		}
+	})
+}
@@ -0,0 +1,49 @@
+package tracing
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"github.com/XSAM/otelsql"
+)
+
+// OpenDB opens the database like sql.Open, starting a span for every
+// query and transaction. system is the database system recorded in
+// the spans, like "mysql".
+//
+// The statements are recorded with their placeholders, never with
+// their arguments.
+func OpenDB(driverName, dataSourceName, system string) (*sql.DB, error) {
+	// sql.Open only looks the driver up, nothing is connected yet
+	db, err := sql.Open(driverName, dataSourceName)
+	if err != nil {
+		return nil, err
+	}
+	drv := db.Driver()
+	_ = db.Close()
+
+	traced := otelsql.WrapDriver(drv, system)
+	if _, ok := drv.(driver.DriverContext); !ok {
+		return sql.OpenDB(dsnConnector{dsn: dataSourceName, driver: traced}), nil
+	}
+	connector, err := traced.(driver.DriverContext).OpenConnector(dataSourceName)
+	if err != nil {
+		return nil, err
+	}
+	return sql.OpenDB(connector), nil
+}
+
+// dsnConnector connects through the drivers having no connector of
+// their own, like sql.Open does.
+type dsnConnector struct {
+	dsn    string
+	driver driver.Driver
+}
+
+func (c dsnConnector) Connect(context.Context) (driver.Conn, error) {
+	return c.driver.Open(c.dsn)
+}
+
+func (c dsnConnector) Driver() driver.Driver {
+	return c.driver
+}
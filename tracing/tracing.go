@@ -0,0 +1,127 @@
+// Package tracing traces the requests across gophr with OpenTelemetry.
+// The spans of a request are carried in its context from the Gin
+// handlers down to the services, the SQL queries and the Redis
+// commands, and the trace continues in the services called over
+// HTTP through the W3C trace context headers.
+//
+// Nothing is exported until Setup installs an exporter, so that the
+// spans cost next to nothing when tracing is disabled.
+package tracing
+
+import (
+	"context"
+	"errors"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.4.0"
+	"go.opentelemetry.io/otel/trace"
+	"gophr.v2/config"
+	"io"
+	"os"
+)
+
+// TracerName is the instrumentation name of the spans of gophr.
+const TracerName = "gophr.v2"
+
+// The exporters of the spans.
+const (
+	// OTLPExporter sends the spans to an OpenTelemetry collector
+	// over HTTP.
+	OTLPExporter = "otlp"
+	// StdoutExporter writes the spans as JSON, which needs no
+	// collector at all.
+	StdoutExporter = "stdout"
+)
+
+var ErrUnknownExporter = errors.New("tracing: unknown exporter")
+
+type Option func(s *setup)
+
+type setup struct {
+	w io.Writer
+}
+
+// WithWriter sets where the StdoutExporter writes the spans. They're
+// written to os.Stdout by default.
+func WithWriter(w io.Writer) Option {
+	return func(s *setup) {
+		s.w = w
+	}
+}
+
+// Setup installs the tracer provider exporting the spans of service
+// as configured by conf, along with the propagator of the W3C trace
+// context. The returned func flushes the spans left and is meant to
+// be called on exit.
+func Setup(ctx context.Context, service string, conf config.Tracing, opts ...Option) (shutdown func(context.Context) error, err error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{}, propagation.Baggage{}))
+
+	s := &setup{w: os.Stdout}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	var exporter sdktrace.SpanExporter
+	switch conf.Exporter {
+	case "":
+		return func(context.Context) error { return nil }, nil
+	case StdoutExporter:
+		exporter, err = stdouttrace.New(stdouttrace.WithWriter(s.w))
+	case OTLPExporter:
+		exporter, err = otlptracehttp.New(ctx, otlpOptions(conf)...)
+	default:
+		return nil, ErrUnknownExporter
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	sampler := sdktrace.AlwaysSample()
+	if conf.SampleRatio > 0 && conf.SampleRatio < 1 {
+		sampler = sdktrace.TraceIDRatioBased(conf.SampleRatio)
+	}
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		// The traces sampled by the caller are sampled all along
+		sdktrace.WithSampler(sdktrace.ParentBased(sampler)),
+		sdktrace.WithResource(resource.NewWithAttributes(semconv.SchemaURL,
+			semconv.ServiceNameKey.String(service))),
+	)
+	otel.SetTracerProvider(provider)
+	return provider.Shutdown, nil
+}
+
+func otlpOptions(conf config.Tracing) []otlptracehttp.Option {
+	var opts []otlptracehttp.Option
+	if conf.Endpoint != "" {
+		opts = append(opts, otlptracehttp.WithEndpoint(conf.Endpoint))
+	}
+	if conf.Insecure {
+		opts = append(opts, otlptracehttp.WithInsecure())
+	}
+	return opts
+}
+
+// Start starts a span called name within the span of ctx.
+func Start(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	return otel.Tracer(TracerName).Start(ctx, name, opts...)
+}
+
+// End ends span, recording *err as its error when set. It is meant
+// to be deferred:
+//
+//	ctx, span := tracing.Start(ctx, "user.GetByUserID")
+//	defer tracing.End(span, &err)
+func End(span trace.Span, err *error) {
+	if err != nil && *err != nil {
+		span.RecordError(*err)
+		span.SetStatus(codes.Error, (*err).Error())
+	}
+	span.End()
+}
@@ -0,0 +1,80 @@
+//+build unit
+
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"gophr.v2/config"
+	"testing"
+)
+
+// record installs a tracer provider recording the spans ended, along
+// with the W3C trace context propagator. The returned func restores
+// the provider installed before.
+func record(t *testing.T) (*tracetest.SpanRecorder, func()) {
+	t.Helper()
+	provider, propagator := otel.GetTracerProvider(), otel.GetTextMapPropagator()
+	rec := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(rec)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	return rec, func() {
+		otel.SetTracerProvider(provider)
+		otel.SetTextMapPropagator(propagator)
+	}
+}
+
+func TestSetup(t *testing.T) {
+	provider := otel.GetTracerProvider()
+	defer otel.SetTracerProvider(provider)
+
+	t.Run("Stdout", func(t *testing.T) {
+		var buf bytes.Buffer
+		shutdown, err := Setup(context.Background(), "gophr-test", config.Tracing{Exporter: StdoutExporter}, WithWriter(&buf))
+		require.NoError(t, err)
+
+		_, span := Start(context.Background(), "user.GetByUserID")
+		span.End()
+		require.NoError(t, shutdown(context.Background()))
+
+		assert.Contains(t, buf.String(), `"Name":"user.GetByUserID"`)
+		assert.Contains(t, buf.String(), "gophr-test")
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		shutdown, err := Setup(context.Background(), "gophr-test", config.Tracing{})
+		require.NoError(t, err)
+		assert.NoError(t, shutdown(context.Background()))
+	})
+
+	t.Run("Unknown Exporter", func(t *testing.T) {
+		_, err := Setup(context.Background(), "gophr-test", config.Tracing{Exporter: "jaeger"})
+		assert.Equal(t, ErrUnknownExporter, err)
+	})
+}
+
+func TestEnd(t *testing.T) {
+	rec, restore := record(t)
+	defer restore()
+
+	_, span := Start(context.Background(), "success")
+	End(span, new(error))
+	errNotFound := errors.New("not found")
+	_, span = Start(context.Background(), "failure")
+	End(span, &errNotFound)
+
+	spans := rec.Ended()
+	require.Len(t, spans, 2)
+	assert.Equal(t, codes.Unset, spans[0].Status().Code)
+	assert.Equal(t, codes.Error, spans[1].Status().Code)
+	assert.Equal(t, "not found", spans[1].Status().Description)
+	require.Len(t, spans[1].Events(), 1)
+}
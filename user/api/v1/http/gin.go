@@ -7,7 +7,7 @@ import (
 	"fmt"
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
-	"github.com/jayvib/golog"
+	"gophr.v2/log"
 	"gophr.v2/user"
 	"net/http"
 	"strconv"
@@ -27,6 +27,7 @@ func RegisterHandlers(r gin.IRouter, svc user.Service) {
 	handler := New(svc)
 	r.GET("/users/:id", handler.GetByUserID)
 	r.GET("/users", handler.GetAll)
+	r.POST("/users/lookup", handler.GetByUserIDs)
 	r.PUT("/users", handler.Register)
 	r.POST("/users", handler.Update)
 	r.DELETE("/users/:id", handler.Delete)
@@ -47,9 +48,51 @@ func (g *GinHandler) GetByUserID(c *gin.Context) {
 	g.get(c, id, g.svc.GetByUserID)
 }
 
+// MaxLookupUserIDs is the most users looked up at once.
+const MaxLookupUserIDs = 100
+
+// LookupRequest is the body of a lookup of several users.
+type LookupRequest struct {
+	UserIDs []string `json:"userIds"`
+}
+
+// LookupResult is the data of the response to a lookup. Missing
+// holds the IDs of the users which weren't found.
+type LookupResult struct {
+	Users   []*user.User `json:"users"`
+	Missing []string     `json:"missing"`
+}
+
+// GetByUserIDs looks up the users of the IDs in the body at once, up
+// to MaxLookupUserIDs. The users come without their password.
+func (g *GinHandler) GetByUserIDs(c *gin.Context) {
+	var req LookupRequest
+	if err := json.NewDecoder(c.Request.Body).Decode(&req); err != nil {
+		g.renderError(c, err)
+		return
+	}
+	if len(req.UserIDs) == 0 || len(req.UserIDs) > MaxLookupUserIDs {
+		c.JSON(http.StatusBadRequest, &Response{
+			Success: false,
+			Message: fmt.Sprintf("Between 1 and %d user IDs are looked up at once", MaxLookupUserIDs),
+		})
+		return
+	}
+
+	usrs, missing, err := g.svc.GetByUserIDs(c.Request.Context(), req.UserIDs)
+	if err != nil {
+		g.renderError(c, err)
+		return
+	}
+	for i, usr := range usrs {
+		usrs[i] = usr.Clone()
+		usrs[i].Password = ""
+	}
+	g.renderData(c, http.StatusOK, &LookupResult{Users: usrs, Missing: missing})
+}
+
 func (g *GinHandler) GetByEmail(c *gin.Context) {
 	email := c.Param("email")
-	golog.Debug("Email:", email)
 	g.get(c, email, g.svc.GetByEmail)
 }
 
@@ -70,14 +113,12 @@ func (g *GinHandler) Delete(c *gin.Context) {
 func (g *GinHandler) Update(c *gin.Context) {
 	usr, err := g.decodeUserFromBody(c)
 	if err != nil {
-		golog.Debug("error:", err)
 		g.renderError(c, err)
 		return
 	}
 
 	err = g.svc.Update(c.Request.Context(), usr)
 	if err != nil {
-		golog.Debug("error:", err)
 		g.renderError(c, err)
 		return
 	}
@@ -94,14 +135,12 @@ func (g *GinHandler) Register(c *gin.Context) {
 
 	err = validater.Struct(usr)
 	if err != nil {
-		golog.Debugf("%T\n", err)
 		g.renderError(c, err)
 		return
 	}
 
 	err = g.svc.Register(c.Request.Context(), usr)
 	if err != nil {
-		golog.Debug(err.Error())
 		g.renderError(c, err)
 		return
 	}
@@ -112,13 +151,11 @@ func (g *GinHandler) GetAll(c *gin.Context) {
 	numString := c.Query("num")
 	num, _ := strconv.Atoi(numString)
 	cursor := c.Query("cursor")
-	golog.Debug("num", num, "cursor", cursor)
 	usrs, nextCursor, err := g.svc.GetAll(c.Request.Context(), cursor, num)
 	if err != nil {
 		g.renderError(c, err)
 		return
 	}
-	golog.Debug(usrs)
 	c.Header(`X-Cursor`, nextCursor)
 	g.renderData(c, http.StatusOK, usrs)
 }
@@ -135,15 +172,21 @@ func (g *GinHandler) decodeUserFromBody(c *gin.Context) (*user.User, error) {
 }
 
 func getStatusFromError(err error) int {
-	golog.Debug(err)
 	var status int
 	switch errors.Unwrap(err) {
 	case user.ErrEmptyUsername, user.ErrEmptyEmail, user.ErrEmptyPassword, user.ErrUserExists:
 		status = http.StatusBadRequest
 	case user.ErrUserNotExists:
 		status = http.StatusBadRequest
+	case user.ErrPasswordTooShort, user.ErrPasswordTooLong, user.ErrPasswordTooSimple,
+		user.ErrPasswordContainsUserInfo, user.ErrPasswordBreached:
+		status = http.StatusBadRequest
+	case user.ErrDisplayNameTooLong, user.ErrBioTooLong, user.ErrInvalidWebsite:
+		status = http.StatusBadRequest
 	case user.ErrNotFound:
 		status = http.StatusNotFound
+	case user.ErrAccountLocked:
+		status = http.StatusTooManyRequests
 	default:
 		switch err.(type) {
 		case validator.ValidationErrors, *json.SyntaxError:
@@ -178,8 +221,11 @@ func generateMessageFromError(err error) string {
 }
 
 func (g *GinHandler) renderError(c *gin.Context, err error) {
-	if uerr, ok := err.(*user.Error); ok {
-		golog.Error(uerr)
+	logger := log.FromContext(c.Request.Context()).WithError(err)
+	if _, ok := err.(*user.Error); ok {
+		logger.Error("request failed")
+	} else {
+		logger.Debug("request failed")
 	}
 
 	c.JSON(getStatusFromError(err), &Response{
@@ -7,11 +7,11 @@ import (
 	"encoding/json"
 	"flag"
 	"github.com/gin-gonic/gin"
-	"github.com/jayvib/golog"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 	"gophr.v2/http/httputil"
+	"gophr.v2/log"
 	"gophr.v2/user"
 	"gophr.v2/user/mocks"
 	"gophr.v2/user/service"
@@ -28,8 +28,8 @@ var debug = flag.Bool("debug", false, "Debugging")
 func TestMain(m *testing.M) {
 	flag.Parse()
 	if *debug {
-		golog.Info("Debugging Mode!")
-		golog.SetLevel(golog.DebugLevel)
+		log.Info("Debugging Mode!")
+		log.SetLevel(log.DebugLevel)
 	}
 	gin.SetMode(gin.TestMode)
 	os.Exit(m.Run())
@@ -82,6 +82,55 @@ func TestGetByID(t *testing.T) {
 	})
 }
 
+func TestGetByUserIDs(t *testing.T) {
+	usr := &user.User{
+		ID:       1,
+		UserID:   userutil.GenerateID(),
+		Username: "luffy.monkey",
+		Email:    "luffy.monkey@gmail.com",
+		Password: "$2a$10$hash",
+	}
+	ids := []string{usr.UserID, "unknown"}
+
+	e := gin.Default()
+	repo := new(mocks.Repository)
+	repo.On("GetByUserIDs", mock.Anything, ids).Return([]*user.User{usr}, nil)
+	RegisterHandlers(e, service.New(repo))
+
+	payload, err := json.Marshal(&LookupRequest{UserIDs: ids})
+	require.NoError(t, err)
+	response := httputil.PerformRequest(e, http.MethodPost, "/users/lookup", bytes.NewReader(payload))
+	require.Equal(t, http.StatusOK, response.Code)
+
+	var got struct {
+		Data LookupResult `json:"data"`
+	}
+	require.NoError(t, json.NewDecoder(response.Body).Decode(&got))
+	require.Len(t, got.Data.Users, 1)
+	assert.Equal(t, usr.UserID, got.Data.Users[0].UserID)
+	assert.Empty(t, got.Data.Users[0].Password, "the passwords aren't returned")
+	assert.Equal(t, []string{"unknown"}, got.Data.Missing)
+	repo.AssertExpectations(t)
+
+	t.Run("Invalid Sizes", func(t *testing.T) {
+		tooMany := make([]string, MaxLookupUserIDs+1)
+		for i := range tooMany {
+			tooMany[i] = userutil.GenerateID()
+		}
+		for _, ids := range [][]string{nil, tooMany} {
+			e := gin.Default()
+			repo := new(mocks.Repository)
+			RegisterHandlers(e, service.New(repo))
+
+			payload, err := json.Marshal(&LookupRequest{UserIDs: ids})
+			require.NoError(t, err)
+			response := httputil.PerformRequest(e, http.MethodPost, "/users/lookup", bytes.NewReader(payload))
+			assert.Equal(t, http.StatusBadRequest, response.Code)
+			repo.AssertNotCalled(t, "GetByUserIDs", mock.Anything, mock.Anything)
+		}
+	})
+}
+
 func TestRegister(t *testing.T) {
 	t.Run("StatusCreated", func(t *testing.T) {
 		usr := &user.User{
@@ -236,3 +285,20 @@ func extractUserFromData(got Response) (*user.User, error) {
 	err = json.Unmarshal(usrPayload, &gotUser)
 	return &gotUser, err
 }
+
+func TestGetStatusFromError(t *testing.T) {
+	tests := map[string]struct {
+		err  error
+		want int
+	}{
+		"Not Found":       {err: user.NewError(user.ErrNotFound), want: http.StatusNotFound},
+		"Account Locked":  {err: user.NewError(user.ErrAccountLocked), want: http.StatusTooManyRequests},
+		"Invalid Profile": {err: user.NewError(user.ErrInvalidWebsite), want: http.StatusBadRequest},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tt.want, getStatusFromError(tt.err))
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package user
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+const (
+	sha1HexLen    = sha1.Size * 2
+	hashPrefixLen = 5
+)
+
+var _ BreachChecker = (*HashList)(nil)
+
+// LoadHashList reads the breached password hash list from filename.
+func LoadHashList(filename string) (*HashList, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+	return NewHashList(file)
+}
+
+// NewHashList reads a breached password list from r.
+//
+// Every line contains the uppercase or lowercase hex encoded SHA-1 of a
+// breached password optionally followed by ":<count>", the format used by
+// the Pwned Passwords downloads. Empty lines and lines starting with '#'
+// are ignored.
+//
+// Like the k-anonymity range API, hashes are bucketed by their first
+// five characters and only the suffixes are kept and compared.
+func NewHashList(r io.Reader) (*HashList, error) {
+	l := &HashList{buckets: make(map[string]map[string]struct{})}
+
+	scanner := bufio.NewScanner(r)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		if i := strings.IndexByte(text, ':'); i >= 0 {
+			text = text[:i]
+		}
+
+		if len(text) != sha1HexLen {
+			return nil, fmt.Errorf("user: invalid hash on line %d of the breached password list", line)
+		}
+		if _, err := hex.DecodeString(text); err != nil {
+			return nil, fmt.Errorf("user: invalid hash on line %d of the breached password list: %w", line, err)
+		}
+		l.add(strings.ToUpper(text))
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+// HashList is an offline BreachChecker.
+type HashList struct {
+	buckets map[string]map[string]struct{}
+}
+
+func (l *HashList) add(hash string) {
+	prefix, suffix := hash[:hashPrefixLen], hash[hashPrefixLen:]
+	bucket, ok := l.buckets[prefix]
+	if !ok {
+		bucket = make(map[string]struct{})
+		l.buckets[prefix] = bucket
+	}
+	bucket[suffix] = struct{}{}
+}
+
+func (l *HashList) IsBreached(ctx context.Context, password string) (bool, error) {
+	sum := sha1.Sum([]byte(password))
+	hash := strings.ToUpper(hex.EncodeToString(sum[:]))
+	_, ok := l.buckets[hash[:hashPrefixLen]][hash[hashPrefixLen:]]
+	return ok, nil
+}
@@ -0,0 +1,19 @@
+package user
+
+import (
+	"context"
+	"time"
+)
+
+//go:generate mockery --name=Cache
+
+// Cache keeps the recently looked up users as encoded entries so
+// that the lookups don't reach the repository.
+type Cache interface {
+	// Get returns ErrCacheMiss when there is no entry under key.
+	Get(ctx context.Context, key string) ([]byte, error)
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	// Delete removes the entries under keys. The missing keys are
+	// ignored.
+	Delete(ctx context.Context, keys ...string) error
+}
@@ -0,0 +1,110 @@
+package lru
+
+import (
+	"container/list"
+	"context"
+	"gophr.v2/user"
+	"sync"
+	"time"
+)
+
+// DefaultSize is how many entries a cache keeps when no size is
+// given.
+const DefaultSize = 10000
+
+var _ user.Cache = (*Cache)(nil)
+
+// New creates an in-process cache keeping up to size entries. The
+// least recently used entry is evicted to make room for a new one.
+func New(size int) *Cache {
+	if size <= 0 {
+		size = DefaultSize
+	}
+	return &Cache{
+		size:    size,
+		ll:      list.New(),
+		entries: make(map[string]*list.Element),
+		now:     time.Now,
+	}
+}
+
+// Cache is a least recently used cache of users. It is safe for
+// concurrent use.
+type Cache struct {
+	mu      sync.Mutex
+	size    int
+	ll      *list.List
+	entries map[string]*list.Element
+	now     func() time.Time
+}
+
+type entry struct {
+	key       string
+	value     []byte
+	expiresAt time.Time
+}
+
+func (c *Cache) Get(ctx context.Context, key string) ([]byte, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.entries[key]
+	if !ok {
+		return nil, user.ErrCacheMiss
+	}
+	e := elem.Value.(*entry)
+	if !c.now().Before(e.expiresAt) {
+		c.remove(elem)
+		return nil, user.ErrCacheMiss
+	}
+	c.ll.MoveToFront(elem)
+	return append([]byte(nil), e.value...), nil
+}
+
+func (c *Cache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e := &entry{
+		key:       key,
+		value:     append([]byte(nil), value...),
+		expiresAt: c.now().Add(ttl),
+	}
+	if elem, ok := c.entries[key]; ok {
+		elem.Value = e
+		c.ll.MoveToFront(elem)
+		return nil
+	}
+
+	c.entries[key] = c.ll.PushFront(e)
+	for c.ll.Len() > c.size {
+		c.remove(c.ll.Back())
+	}
+	return nil
+}
+
+func (c *Cache) Delete(ctx context.Context, keys ...string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, key := range keys {
+		if elem, ok := c.entries[key]; ok {
+			c.remove(elem)
+		}
+	}
+	return nil
+}
+
+// Len returns the number of entries, including the expired ones
+// which weren't evicted yet.
+func (c *Cache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.ll.Len()
+}
+
+// remove evicts elem. c.mu must be held.
+func (c *Cache) remove(elem *list.Element) {
+	c.ll.Remove(elem)
+	delete(c.entries, elem.Value.(*entry).key)
+}
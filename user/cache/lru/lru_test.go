@@ -0,0 +1,82 @@
+//+build unit
+
+package lru
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gophr.v2/user"
+	"testing"
+	"time"
+)
+
+func TestCache(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Get", func(t *testing.T) {
+		c := New(2)
+		_, err := c.Get(ctx, "missing")
+		assert.Equal(t, user.ErrCacheMiss, err)
+
+		value := []byte("luffy")
+		require.NoError(t, c.Set(ctx, "key", value, time.Minute))
+		value[0] = 'L'
+
+		got, err := c.Get(ctx, "key")
+		require.NoError(t, err)
+		assert.Equal(t, []byte("luffy"), got)
+	})
+
+	t.Run("Expiry", func(t *testing.T) {
+		now := time.Now()
+		c := New(2)
+		c.now = func() time.Time { return now }
+
+		require.NoError(t, c.Set(ctx, "key", []byte("luffy"), time.Minute))
+		now = now.Add(time.Minute)
+
+		_, err := c.Get(ctx, "key")
+		assert.Equal(t, user.ErrCacheMiss, err)
+		assert.Equal(t, 0, c.Len())
+	})
+
+	t.Run("Eviction", func(t *testing.T) {
+		c := New(2)
+		require.NoError(t, c.Set(ctx, "a", []byte("a"), time.Minute))
+		require.NoError(t, c.Set(ctx, "b", []byte("b"), time.Minute))
+
+		// a is now the most recently used
+		_, err := c.Get(ctx, "a")
+		require.NoError(t, err)
+		require.NoError(t, c.Set(ctx, "c", []byte("c"), time.Minute))
+
+		assert.Equal(t, 2, c.Len())
+		_, err = c.Get(ctx, "b")
+		assert.Equal(t, user.ErrCacheMiss, err)
+		_, err = c.Get(ctx, "a")
+		assert.NoError(t, err)
+		_, err = c.Get(ctx, "c")
+		assert.NoError(t, err)
+	})
+
+	t.Run("Overwrite", func(t *testing.T) {
+		c := New(2)
+		require.NoError(t, c.Set(ctx, "key", []byte("luffy"), time.Minute))
+		require.NoError(t, c.Set(ctx, "key", []byte("zoro"), time.Minute))
+
+		got, err := c.Get(ctx, "key")
+		require.NoError(t, err)
+		assert.Equal(t, []byte("zoro"), got)
+		assert.Equal(t, 1, c.Len())
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		c := New(2)
+		require.NoError(t, c.Set(ctx, "key", []byte("luffy"), time.Minute))
+		require.NoError(t, c.Delete(ctx, "key", "missing"))
+
+		_, err := c.Get(ctx, "key")
+		assert.Equal(t, user.ErrCacheMiss, err)
+	})
+}
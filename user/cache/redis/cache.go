@@ -0,0 +1,42 @@
+package redis
+
+import (
+	"context"
+	"github.com/go-redis/redis/v8"
+	"gophr.v2/user"
+	"time"
+)
+
+var _ user.Cache = (*Cache)(nil)
+
+// New creates a user cache stored in Redis, shared by every
+// instance of gophr.
+func New(client *redis.Client) *Cache {
+	return &Cache{client: client}
+}
+
+type Cache struct {
+	client *redis.Client
+}
+
+func (c *Cache) Get(ctx context.Context, key string) ([]byte, error) {
+	value, err := c.client.Get(ctx, key).Bytes()
+	if err != nil {
+		if err == redis.Nil {
+			return nil, user.ErrCacheMiss
+		}
+		return nil, err
+	}
+	return value, nil
+}
+
+func (c *Cache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	return c.client.Set(ctx, key, value, ttl).Err()
+}
+
+func (c *Cache) Delete(ctx context.Context, keys ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	return c.client.Del(ctx, keys...).Err()
+}
@@ -1,8 +1,8 @@
 package cli
 
 import (
-	"github.com/jayvib/golog"
 	"github.com/spf13/cobra"
+	"gophr.v2/log"
 	"gophr.v2/user"
 	"gophr.v2/user/service/proxy/remote"
 )
@@ -18,10 +18,10 @@ type getResult struct {
 }
 
 func init() {
-	UserCmd.AddCommand(getCmd, registerCmd, getAllCmd, deleteCmd)
+	UserCmd.AddCommand(getCmd, registerCmd, getAllCmd, deleteCmd, exportCmd)
 	client, err := remote.NewClient()
 	if err != nil {
-		golog.Fatal(err)
+		log.Fatal(err)
 	}
 	userService = remote.New(client)
 
@@ -0,0 +1,120 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+	"gophr.v2/config/configutil"
+	exportrepo "gophr.v2/export/repository"
+	exportservice "gophr.v2/export/service"
+	followrepo "gophr.v2/follow/repository"
+	followservice "gophr.v2/follow/service"
+	imagerepo "gophr.v2/image/repository"
+	imageservice "gophr.v2/image/service"
+	"gophr.v2/log"
+	notificationrepo "gophr.v2/notification/repository"
+	notificationservice "gophr.v2/notification/service"
+	sessionrepo "gophr.v2/session/repository"
+	userrepo "gophr.v2/user/repository"
+	webhookrepo "gophr.v2/webhook/repository"
+	webhookservice "gophr.v2/webhook/service"
+	"io"
+	"os"
+)
+
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "export is a command for exporting the personal data of users",
+	Long: `
+DESCRIPTION:
+  export generates an archive of the personal data held about each
+  user id right away, the same as the users requesting it from their
+  account. The archive is written to --to-file when there's a single
+  user id, and its download link is printed otherwise.
+
+EXAMPLE:
+  gophr user export id1
+  gophr user export id1 --to-file export.zip
+`,
+	Args: cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		svc, secret, closers := newExportService()
+		defer func() {
+			for _, closer := range closers {
+				_ = closer()
+			}
+		}()
+
+		if secret == "" {
+			log.Warn("export: no secret is configured, the printed links won't be accepted by the web app")
+		}
+
+		ctx := context.Background()
+		for _, id := range args {
+			e, err := svc.Export(ctx, id)
+			if err != nil {
+				fmt.Println(id, err)
+				continue
+			}
+			if !e.IsReady() {
+				fmt.Println(id, "export failed:", e.Error)
+				continue
+			}
+
+			if writeToFilePath != "" && len(args) == 1 {
+				if err := copyArchive(ctx, svc, e.ID); err != nil {
+					log.Fatal(err)
+				}
+				continue
+			}
+			fmt.Println(id, svc.Link(e))
+		}
+	},
+}
+
+// newExportService creates the export service from the configuration
+// of the web app so that the archives are the ones it serves.
+func newExportService() (svc *exportservice.Service, secret string, closers []func() error) {
+	conf := configutil.Initialize()
+
+	userRepo, closeUsers := userrepo.Get(conf, userrepo.SQLRepo(conf))
+	imageRepo, closeImages := imagerepo.Get(conf, imagerepo.SQLRepo(conf))
+	followRepo, closeFollows := followrepo.Get(conf, followrepo.MySQLRepo)
+	notificationRepo, closeNotifications := notificationrepo.Get(conf, notificationrepo.MySQLRepo)
+	webhookRepo, closeWebhooks := webhookrepo.Get(conf, webhookrepo.MySQLRepo)
+	exportRepo, closeExports := exportrepo.Get(conf, exportrepo.MySQLRepo)
+	closers = []func() error{closeUsers, closeImages, closeFollows, closeNotifications, closeWebhooks, closeExports}
+
+	fs := afero.NewOsFs()
+	store, err := exportservice.NewStore(fs, conf.Export)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	opts := append(exportservice.OptionsFromConfig(conf.Export),
+		exportservice.WithImages(imageservice.New(imageRepo, fs, nil), afero.NewBasePathFs(fs, imageservice.DefaultImagePathLocation)),
+		exportservice.WithSessions(sessionrepo.Get(conf, sessionrepo.RedisRepo)),
+		exportservice.WithFollows(followservice.New(followRepo, userRepo)),
+		exportservice.WithNotifications(notificationservice.New(notificationRepo)),
+		exportservice.WithWebhooks(webhookservice.New(webhookRepo)))
+	return exportservice.New(exportRepo, userRepo, store, conf.Export.Secret, opts...), conf.Export.Secret, closers
+}
+
+// copyArchive writes the archive of the export with id to the
+// --to-file path.
+func copyArchive(ctx context.Context, svc *exportservice.Service, id string) error {
+	_, archive, err := svc.Archive(ctx, id)
+	if err != nil {
+		return err
+	}
+	defer archive.Close()
+
+	f, err := os.Create(writeToFilePath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = io.Copy(f, archive)
+	return err
+}
@@ -4,10 +4,8 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"github.com/jayvib/golog"
 	"github.com/spf13/cobra"
-	usersvc "gophr.v2/user/service"
-	"log"
+	"gophr.v2/log"
 	"os"
 )
 
@@ -24,9 +22,12 @@ EXAMPLE:
 
 	Run: func(cmd *cobra.Command, args []string) {
 
-		usrs, err := usersvc.GetByUserIDs(context.Background(), userService, args...)
+		usrs, missing, err := userService.GetByUserIDs(context.Background(), args)
 		if err != nil {
-			fmt.Println(err)
+			log.Fatal(err)
+		}
+		for _, id := range missing {
+			fmt.Fprintf(os.Stderr, "user with id '%s' not exist\n", id)
 		}
 
 		if len(usrs) == 0 {
@@ -38,7 +39,7 @@ EXAMPLE:
 			log.Fatal(err)
 		}
 
-		golog.Debug("filepath", writeToFilePath)
+		log.WithField("filepath", writeToFilePath).Debug("writing users")
 		switch {
 		case writeToFilePath != "":
 			f, err := os.Create(writeToFilePath)
@@ -4,9 +4,8 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"github.com/jayvib/golog"
 	"github.com/spf13/cobra"
-	"log"
+	"gophr.v2/log"
 )
 
 var cursor string
@@ -17,7 +16,7 @@ var getAllCmd = &cobra.Command{
 	Short: "A command for getting all the users",
 	Run: func(cmd *cobra.Command, args []string) {
 
-		golog.Debugf("Cursor: %s Num: %d\n", cursor, num)
+		log.With(log.Fields{"cursor": cursor, "num": num}).Debug("listing users")
 
 		usrs, next, err := userService.GetAll(context.Background(), cursor, num)
 		if err != nil {
@@ -29,7 +28,7 @@ var getAllCmd = &cobra.Command{
 			log.Fatal(err)
 		}
 
-		golog.Debug("next cursor:", next)
+		log.WithField("nextCursor", next).Debug("listed users")
 		if next != "" {
 			fmt.Println("Next Cursor:", next)
 		}
@@ -4,11 +4,10 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"github.com/jayvib/golog"
 	"github.com/spf13/cobra"
 	"golang.org/x/crypto/ssh/terminal"
+	"gophr.v2/log"
 	"gophr.v2/user"
-	"log"
 	"syscall"
 )
 
@@ -38,9 +37,7 @@ var registerCmd = &cobra.Command{
 			log.Fatal(err)
 		}
 		password = string(bytePassword)
-		golog.Debug("Username:", username)
-		golog.Debug("Email:", email)
-		golog.Debug("Password:", password)
+		log.With(log.Fields{"username": username, "email": email}).Debug("registering user")
 
 		usr := &user.User{
 			Username: username,
@@ -17,6 +17,21 @@ var (
 	ErrNotFound           = errors.New("user: item not found")
 	ErrUserNotExists      = errors.New("user: cannot do operation because user is not exists")
 	ErrInvalidCredentials = errors.New("user: invalid credentials")
+	ErrAccountLocked      = errors.New("user: account is temporarily locked")
+	ErrAccountDeleted     = errors.New("user: account is scheduled for deletion")
+	ErrAccountNotDeleted  = errors.New("user: account is not scheduled for deletion")
+
+	ErrPasswordTooShort         = errors.New("user: password is too short")
+	ErrPasswordTooLong          = errors.New("user: password is too long")
+	ErrPasswordTooSimple        = errors.New("user: password doesn't have enough character classes")
+	ErrPasswordContainsUserInfo = errors.New("user: password contains the username or email")
+	ErrPasswordBreached         = errors.New("user: password is found in a data breach")
+
+	ErrDisplayNameTooLong = errors.New("user: display name is too long")
+	ErrBioTooLong         = errors.New("user: bio is too long")
+	ErrInvalidWebsite     = errors.New("user: website is not a valid http or https url")
+
+	ErrCacheMiss = errors.New("user: cache miss")
 )
 
 func NewError(origErr error) *Error {
@@ -72,6 +87,28 @@ func (s *Error) getMessage() string {
 		return "Failed because user exists"
 	case ErrInvalidCredentials:
 		return "Invalid Username/Password"
+	case ErrAccountLocked:
+		return "Too many failed login attempts. Please try again later"
+	case ErrAccountDeleted:
+		return "This account is scheduled for deletion. Restore it to sign in again"
+	case ErrAccountNotDeleted:
+		return "This account is not scheduled for deletion"
+	case ErrPasswordTooShort:
+		return "Password is too short"
+	case ErrPasswordTooLong:
+		return "Password is too long"
+	case ErrPasswordTooSimple:
+		return "Password should mix lowercase, uppercase, digits and symbols"
+	case ErrPasswordContainsUserInfo:
+		return "Password should not contain your username or email"
+	case ErrPasswordBreached:
+		return "Password has appeared in a data breach. Please choose another one"
+	case ErrDisplayNameTooLong:
+		return fmt.Sprintf("Display name should be at most %d characters", MaxDisplayNameLength)
+	case ErrBioTooLong:
+		return fmt.Sprintf("Bio should be at most %d characters", MaxBioLength)
+	case ErrInvalidWebsite:
+		return "Website should be a valid http or https address"
 	default:
 		return "Unexpected error"
 	}
@@ -0,0 +1,29 @@
+package user
+
+import "gophr.v2/event"
+
+const (
+	EventRegistered = "user.registered"
+	EventPurged     = "user.purged"
+)
+
+func init() {
+	event.Register(EventRegistered, func() event.Event { return new(Registered) })
+	event.Register(EventPurged, func() event.Event { return new(Purged) })
+}
+
+// Registered is emitted once a user signed up. The password of
+// the user is never part of it.
+type Registered struct {
+	User *User `json:"user"`
+}
+
+func (*Registered) EventName() string { return EventRegistered }
+
+// Purged is emitted once a deleted account is gone for good. The
+// domains holding records of the user remove them when handling it.
+type Purged struct {
+	UserID string `json:"userId"`
+}
+
+func (*Purged) EventName() string { return EventPurged }
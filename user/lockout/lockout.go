@@ -0,0 +1,190 @@
+package lockout
+
+import (
+	"context"
+	"errors"
+	"gophr.v2/user"
+	"time"
+)
+
+// ErrNotFound is returned by a Store when there is no failed
+// attempt recorded for a key.
+var ErrNotFound = errors.New("lockout: attempt not found")
+
+var (
+	// DefaultAccountPolicy is applied to failed logins of a single account.
+	DefaultAccountPolicy = Policy{
+		FreeAttempts: 3,
+		MaxAttempts:  10,
+		BaseDelay:    time.Second,
+		MaxDelay:     5 * time.Minute,
+		Lockout:      30 * time.Minute,
+		Window:       24 * time.Hour,
+	}
+
+	// DefaultIPPolicy is applied to failed logins coming from a single
+	// client IP. It is more lenient than the account policy because many
+	// users may share an address.
+	DefaultIPPolicy = Policy{
+		FreeAttempts: 10,
+		MaxAttempts:  50,
+		BaseDelay:    time.Second,
+		MaxDelay:     5 * time.Minute,
+		Lockout:      time.Hour,
+		Window:       24 * time.Hour,
+	}
+)
+
+// Attempt holds the failed login attempts recorded for a key.
+type Attempt struct {
+	Key         string    `json:"key,omitempty"`
+	Failures    int       `json:"failures,omitempty"`
+	LastFailure time.Time `json:"lastFailure,omitempty"`
+}
+
+// Store persists the failed login attempts.
+type Store interface {
+	// Find returns the attempt recorded for key or ErrNotFound.
+	Find(ctx context.Context, key string) (*Attempt, error)
+	// Increment atomically records one more failure for key and
+	// keeps it for at least ttl after the last failure.
+	Increment(ctx context.Context, key string, ttl time.Duration) (*Attempt, error)
+	// Reset forgets every failure recorded for key.
+	Reset(ctx context.Context, key string) error
+}
+
+// Policy describes how failed attempts are throttled.
+//
+// The first FreeAttempts failures are not throttled. Every failure after
+// that blocks the key for BaseDelay doubled on each failure, capped to
+// MaxDelay. Once MaxAttempts is reached the key is locked for Lockout.
+// Failures are forgotten after Window of inactivity.
+type Policy struct {
+	FreeAttempts int
+	MaxAttempts  int
+	BaseDelay    time.Duration
+	MaxDelay     time.Duration
+	Lockout      time.Duration
+	Window       time.Duration
+}
+
+// BlockedUntil returns the time until the key with the attempt a
+// is not allowed to try again.
+func (p Policy) BlockedUntil(a *Attempt) time.Time {
+	if a == nil || a.Failures <= p.FreeAttempts {
+		return time.Time{}
+	}
+
+	if p.MaxAttempts > 0 && a.Failures >= p.MaxAttempts {
+		return a.LastFailure.Add(p.Lockout)
+	}
+
+	delay := p.BaseDelay
+	for i := p.FreeAttempts + 1; i < a.Failures; i++ {
+		delay *= 2
+		if delay >= p.MaxDelay {
+			delay = p.MaxDelay
+			break
+		}
+	}
+	return a.LastFailure.Add(delay)
+}
+
+func (p Policy) ttl() time.Duration {
+	if p.Lockout > p.Window {
+		return p.Lockout
+	}
+	return p.Window
+}
+
+// New creates a guard that tracks the failed attempts per account
+// and per client IP in store.
+func New(store Store, account, ip Policy) *Guard {
+	return &Guard{
+		store:   store,
+		account: account,
+		ip:      ip,
+		now:     time.Now,
+	}
+}
+
+// Guard decides whether a login is allowed based on the
+// failed attempts of the account and of the client IP.
+type Guard struct {
+	store   Store
+	account Policy
+	ip      Policy
+	now     func() time.Time
+}
+
+// Check returns user.ErrAccountLocked when either the username or
+// the ip is still blocked.
+func (g *Guard) Check(ctx context.Context, username, ip string) error {
+	for _, k := range g.keys(username, ip) {
+		a, err := g.store.Find(ctx, k.key)
+		if err != nil {
+			if err == ErrNotFound {
+				continue
+			}
+			return err
+		}
+
+		if g.now().Before(k.policy.BlockedUntil(a)) {
+			return user.ErrAccountLocked
+		}
+	}
+	return nil
+}
+
+// Fail records a failed login for both the username and the ip.
+func (g *Guard) Fail(ctx context.Context, username, ip string) error {
+	for _, k := range g.keys(username, ip) {
+		_, err := g.store.Increment(ctx, k.key, k.policy.ttl())
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Succeed clears the failed attempts of the username. The failures
+// of the client IP are kept so that a valid login of one account
+// can't be used to keep guessing the others.
+func (g *Guard) Succeed(ctx context.Context, username string) error {
+	return g.store.Reset(ctx, accountKey(username))
+}
+
+type policyKey struct {
+	key    string
+	policy Policy
+}
+
+func (g *Guard) keys(username, ip string) []policyKey {
+	keys := []policyKey{{key: accountKey(username), policy: g.account}}
+	if ip != "" {
+		keys = append(keys, policyKey{key: ipKey(ip), policy: g.ip})
+	}
+	return keys
+}
+
+func accountKey(username string) string {
+	return "lockout:account:" + username
+}
+
+func ipKey(ip string) string {
+	return "lockout:ip:" + ip
+}
+
+type clientIPKey struct{}
+
+// WithClientIP returns a copy of ctx carrying the ip of the client
+// that initiated the request.
+func WithClientIP(ctx context.Context, ip string) context.Context {
+	return context.WithValue(ctx, clientIPKey{}, ip)
+}
+
+// ClientIPFromContext returns the client ip stored in ctx, if any.
+func ClientIPFromContext(ctx context.Context) string {
+	ip, _ := ctx.Value(clientIPKey{}).(string)
+	return ip
+}
@@ -0,0 +1,142 @@
+//+build unit
+
+package lockout
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gophr.v2/user"
+	"testing"
+	"time"
+)
+
+var defaultCtx = context.Background()
+
+type stubStore struct {
+	attempts map[string]*Attempt
+	now      func() time.Time
+}
+
+func newStubStore(now func() time.Time) *stubStore {
+	return &stubStore{attempts: make(map[string]*Attempt), now: now}
+}
+
+func (s *stubStore) Find(ctx context.Context, key string) (*Attempt, error) {
+	a, ok := s.attempts[key]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return a, nil
+}
+
+func (s *stubStore) Increment(ctx context.Context, key string, ttl time.Duration) (*Attempt, error) {
+	a, ok := s.attempts[key]
+	if !ok {
+		a = &Attempt{Key: key}
+		s.attempts[key] = a
+	}
+	a.Failures++
+	a.LastFailure = s.now()
+	return a, nil
+}
+
+func (s *stubStore) Reset(ctx context.Context, key string) error {
+	delete(s.attempts, key)
+	return nil
+}
+
+var testPolicy = Policy{
+	FreeAttempts: 2,
+	MaxAttempts:  5,
+	BaseDelay:    time.Second,
+	MaxDelay:     3 * time.Second,
+	Lockout:      time.Hour,
+	Window:       24 * time.Hour,
+}
+
+func TestPolicy_BlockedUntil(t *testing.T) {
+	last := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		failures int
+		want     time.Duration
+	}{
+		{failures: 1, want: 0},
+		{failures: 2, want: 0},
+		{failures: 3, want: time.Second},
+		{failures: 4, want: 2 * time.Second},
+		{failures: 5, want: time.Hour},
+	}
+
+	for _, tt := range tests {
+		got := testPolicy.BlockedUntil(&Attempt{Failures: tt.failures, LastFailure: last})
+		if tt.want == 0 {
+			assert.True(t, got.IsZero(), "failures: %d", tt.failures)
+			continue
+		}
+		assert.Equal(t, last.Add(tt.want), got, "failures: %d", tt.failures)
+	}
+
+	t.Run("Delay is capped", func(t *testing.T) {
+		p := testPolicy
+		p.MaxAttempts = 0
+		got := p.BlockedUntil(&Attempt{Failures: 20, LastFailure: last})
+		assert.Equal(t, last.Add(p.MaxDelay), got)
+	})
+}
+
+func TestGuard(t *testing.T) {
+	now := time.Now()
+	clock := func() time.Time { return now }
+
+	newGuard := func() *Guard {
+		g := New(newStubStore(clock), testPolicy, testPolicy)
+		g.now = clock
+		return g
+	}
+
+	fail := func(t *testing.T, g *Guard, username, ip string, n int) {
+		t.Helper()
+		for i := 0; i < n; i++ {
+			require.NoError(t, g.Fail(defaultCtx, username, ip))
+		}
+	}
+
+	t.Run("Free attempts are not blocked", func(t *testing.T) {
+		g := newGuard()
+		fail(t, g, "luffy", "10.0.0.1", testPolicy.FreeAttempts)
+		assert.NoError(t, g.Check(defaultCtx, "luffy", "10.0.0.1"))
+	})
+
+	t.Run("Account is locked", func(t *testing.T) {
+		g := newGuard()
+		fail(t, g, "luffy", "", testPolicy.MaxAttempts)
+		assert.Equal(t, user.ErrAccountLocked, g.Check(defaultCtx, "luffy", "10.0.0.2"))
+
+		now = now.Add(testPolicy.Lockout)
+		defer func() { now = now.Add(-testPolicy.Lockout) }()
+		assert.NoError(t, g.Check(defaultCtx, "luffy", "10.0.0.2"))
+	})
+
+	t.Run("IP is blocked across accounts", func(t *testing.T) {
+		g := newGuard()
+		fail(t, g, "luffy", "10.0.0.1", 2)
+		fail(t, g, "zoro", "10.0.0.1", 2)
+		assert.Equal(t, user.ErrAccountLocked, g.Check(defaultCtx, "sanji", "10.0.0.1"))
+		assert.NoError(t, g.Check(defaultCtx, "sanji", "10.0.0.2"))
+	})
+
+	t.Run("Success resets only the account", func(t *testing.T) {
+		g := newGuard()
+		fail(t, g, "luffy", "10.0.0.1", testPolicy.MaxAttempts)
+		require.NoError(t, g.Succeed(defaultCtx, "luffy"))
+		assert.NoError(t, g.Check(defaultCtx, "luffy", ""))
+		assert.Equal(t, user.ErrAccountLocked, g.Check(defaultCtx, "luffy", "10.0.0.1"))
+	})
+}
+
+func TestClientIPFromContext(t *testing.T) {
+	assert.Equal(t, "", ClientIPFromContext(defaultCtx))
+	ctx := WithClientIP(defaultCtx, "127.0.0.1")
+	assert.Equal(t, "127.0.0.1", ClientIPFromContext(ctx))
+}
@@ -0,0 +1,82 @@
+package memory
+
+import (
+	"context"
+	"gophr.v2/user/lockout"
+	"sync"
+	"time"
+)
+
+var _ lockout.Store = (*Store)(nil)
+
+// New creates an in-memory attempt store. It is meant for tests
+// and single instance deployments.
+func New() *Store {
+	return &Store{
+		attempts: make(map[string]*entry),
+		now:      time.Now,
+	}
+}
+
+type entry struct {
+	attempt lockout.Attempt
+	expiry  time.Time
+}
+
+type Store struct {
+	mu       sync.Mutex
+	attempts map[string]*entry
+	now      func() time.Time
+}
+
+func (s *Store) Find(ctx context.Context, key string) (*lockout.Attempt, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, ok := s.get(key)
+	if !ok {
+		return nil, lockout.ErrNotFound
+	}
+	a := e.attempt
+	return &a, nil
+}
+
+func (s *Store) Increment(ctx context.Context, key string, ttl time.Duration) (*lockout.Attempt, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, ok := s.get(key)
+	if !ok {
+		e = &entry{attempt: lockout.Attempt{Key: key}}
+		s.attempts[key] = e
+	}
+
+	now := s.now()
+	e.attempt.Failures++
+	e.attempt.LastFailure = now
+	e.expiry = now.Add(ttl)
+
+	a := e.attempt
+	return &a, nil
+}
+
+func (s *Store) Reset(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.attempts, key)
+	return nil
+}
+
+// get returns the entry of key. Expired entries are removed.
+// The caller must hold the lock.
+func (s *Store) get(key string) (*entry, bool) {
+	e, ok := s.attempts[key]
+	if !ok {
+		return nil, false
+	}
+	if !e.expiry.After(s.now()) {
+		delete(s.attempts, key)
+		return nil, false
+	}
+	return e, true
+}
@@ -0,0 +1,80 @@
+package redis
+
+import (
+	"context"
+	"github.com/go-redis/redis/v8"
+	"gophr.v2/user/lockout"
+	"strconv"
+	"time"
+)
+
+const (
+	failuresField    = "failures"
+	lastFailureField = "lastFailure"
+)
+
+var _ lockout.Store = (*Store)(nil)
+
+// New creates an attempt store that keeps every key as a redis
+// hash so that failures can be incremented atomically.
+func New(client *redis.Client) *Store {
+	return &Store{client: client}
+}
+
+type Store struct {
+	client *redis.Client
+}
+
+func (s *Store) Find(ctx context.Context, key string) (*lockout.Attempt, error) {
+	fields, err := s.client.HGetAll(ctx, key).Result()
+	if err != nil {
+		return nil, err
+	}
+	if len(fields) == 0 {
+		return nil, lockout.ErrNotFound
+	}
+	return decodeAttempt(key, fields)
+}
+
+func (s *Store) Increment(ctx context.Context, key string, ttl time.Duration) (*lockout.Attempt, error) {
+	now := time.Now()
+
+	var incr *redis.IntCmd
+	_, err := s.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		incr = pipe.HIncrBy(ctx, key, failuresField, 1)
+		pipe.HSet(ctx, key, lastFailureField, now.UnixNano())
+		pipe.Expire(ctx, key, ttl)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &lockout.Attempt{
+		Key:         key,
+		Failures:    int(incr.Val()),
+		LastFailure: now,
+	}, nil
+}
+
+func (s *Store) Reset(ctx context.Context, key string) error {
+	return s.client.Del(ctx, key).Err()
+}
+
+func decodeAttempt(key string, fields map[string]string) (*lockout.Attempt, error) {
+	failures, err := strconv.Atoi(fields[failuresField])
+	if err != nil {
+		return nil, err
+	}
+
+	lastFailure, err := strconv.ParseInt(fields[lastFailureField], 10, 64)
+	if err != nil {
+		return nil, err
+	}
+
+	return &lockout.Attempt{
+		Key:         key,
+		Failures:    failures,
+		LastFailure: time.Unix(0, lastFailure),
+	}, nil
+}
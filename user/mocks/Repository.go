@@ -5,6 +5,7 @@ package mocks
 import context "context"
 import mock "github.com/stretchr/testify/mock"
 import user "gophr.v2/user"
+import time "time"
 
 // Repository is an autogenerated mock type for the Repository type
 type Repository struct {
@@ -55,6 +56,29 @@ func (_m *Repository) GetAll(ctx context.Context, cursor string, num int) ([]*us
 	return r0, r1, r2
 }
 
+// GetAllDeleted provides a mock function with given fields: ctx, before, num
+func (_m *Repository) GetAllDeleted(ctx context.Context, before time.Time, num int) ([]*user.User, error) {
+	ret := _m.Called(ctx, before, num)
+
+	var r0 []*user.User
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, int) []*user.User); ok {
+		r0 = rf(ctx, before, num)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*user.User)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time, int) error); ok {
+		r1 = rf(ctx, before, num)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetByEmail provides a mock function with given fields: ctx, email
 func (_m *Repository) GetByEmail(ctx context.Context, email string) (*user.User, error) {
 	ret := _m.Called(ctx, email)
@@ -124,6 +148,29 @@ func (_m *Repository) GetByUserID(ctx context.Context, userId string) (*user.Use
 	return r0, r1
 }
 
+// GetByUserIDs provides a mock function with given fields: ctx, userIDs
+func (_m *Repository) GetByUserIDs(ctx context.Context, userIDs []string) ([]*user.User, error) {
+	ret := _m.Called(ctx, userIDs)
+
+	var r0 []*user.User
+	if rf, ok := ret.Get(0).(func(context.Context, []string) []*user.User); ok {
+		r0 = rf(ctx, userIDs)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*user.User)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, []string) error); ok {
+		r1 = rf(ctx, userIDs)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetByUsername provides a mock function with given fields: ctx, uname
 func (_m *Repository) GetByUsername(ctx context.Context, uname string) (*user.User, error) {
 	ret := _m.Called(ctx, uname)
@@ -147,6 +194,34 @@ func (_m *Repository) GetByUsername(ctx context.Context, uname string) (*user.Us
 	return r0, r1
 }
 
+// Purge provides a mock function with given fields: ctx, id
+func (_m *Repository) Purge(ctx context.Context, id interface{}) error {
+	ret := _m.Called(ctx, id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, interface{}) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Restore provides a mock function with given fields: ctx, userID
+func (_m *Repository) Restore(ctx context.Context, userID string) error {
+	ret := _m.Called(ctx, userID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Save provides a mock function with given fields: ctx, _a1
 func (_m *Repository) Save(ctx context.Context, _a1 *user.User) error {
 	ret := _m.Called(ctx, _a1)
@@ -161,6 +236,20 @@ func (_m *Repository) Save(ctx context.Context, _a1 *user.User) error {
 	return r0
 }
 
+// SoftDelete provides a mock function with given fields: ctx, userID, at
+func (_m *Repository) SoftDelete(ctx context.Context, userID string, at time.Time) error {
+	ret := _m.Called(ctx, userID, at)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time) error); ok {
+		r0 = rf(ctx, userID, at)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Update provides a mock function with given fields: ctx, _a1
 func (_m *Repository) Update(ctx context.Context, _a1 *user.User) error {
 	ret := _m.Called(ctx, _a1)
@@ -14,6 +14,20 @@ type Service struct {
 	mock.Mock
 }
 
+// ChangePassword provides a mock function with given fields: ctx, userID, currentPassword, newPassword
+func (_m *Service) ChangePassword(ctx context.Context, userID string, currentPassword string, newPassword string) error {
+	ret := _m.Called(ctx, userID, currentPassword, newPassword)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) error); ok {
+		r0 = rf(ctx, userID, currentPassword, newPassword)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Delete provides a mock function with given fields: ctx, id
 func (_m *Service) Delete(ctx context.Context, id interface{}) error {
 	ret := _m.Called(ctx, id)
@@ -28,6 +42,20 @@ func (_m *Service) Delete(ctx context.Context, id interface{}) error {
 	return r0
 }
 
+// DeleteAccount provides a mock function with given fields: ctx, userID, password
+func (_m *Service) DeleteAccount(ctx context.Context, userID string, password string) error {
+	ret := _m.Called(ctx, userID, password)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, userID, password)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // GetAll provides a mock function with given fields: ctx, cursor, num
 func (_m *Service) GetAll(ctx context.Context, cursor string, num int) ([]*user.User, string, error) {
 	ret := _m.Called(ctx, cursor, num)
@@ -127,6 +155,38 @@ func (_m *Service) GetByUserID(ctx context.Context, userID string) (*user.User,
 	return r0, r1
 }
 
+// GetByUserIDs provides a mock function with given fields: ctx, userIDs
+func (_m *Service) GetByUserIDs(ctx context.Context, userIDs []string) ([]*user.User, []string, error) {
+	ret := _m.Called(ctx, userIDs)
+
+	var r0 []*user.User
+	if rf, ok := ret.Get(0).(func(context.Context, []string) []*user.User); ok {
+		r0 = rf(ctx, userIDs)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*user.User)
+		}
+	}
+
+	var r1 []string
+	if rf, ok := ret.Get(1).(func(context.Context, []string) []string); ok {
+		r1 = rf(ctx, userIDs)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).([]string)
+		}
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(context.Context, []string) error); ok {
+		r2 = rf(ctx, userIDs)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
 // GetByUsername provides a mock function with given fields: ctx, uname
 func (_m *Service) GetByUsername(ctx context.Context, uname string) (*user.User, error) {
 	ret := _m.Called(ctx, uname)
@@ -178,6 +238,20 @@ func (_m *Service) Register(ctx context.Context, _a1 *user.User) error {
 	return r0
 }
 
+// RestoreAccount provides a mock function with given fields: ctx, _a1
+func (_m *Service) RestoreAccount(ctx context.Context, _a1 *user.User) error {
+	ret := _m.Called(ctx, _a1)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *user.User) error); ok {
+		r0 = rf(ctx, _a1)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Save provides a mock function with given fields: ctx, _a1
 func (_m *Service) Save(ctx context.Context, _a1 *user.User) error {
 	ret := _m.Called(ctx, _a1)
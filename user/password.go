@@ -0,0 +1,161 @@
+package user
+
+import (
+	"context"
+	"fmt"
+	"golang.org/x/crypto/bcrypt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+var (
+	// DefaultPasswordPolicy is the policy applied when none is configured.
+	DefaultPasswordPolicy = PasswordPolicy{
+		MinLength:        8,
+		MaxLength:        72,
+		MinClasses:       1,
+		DisallowUserInfo: true,
+	}
+
+	// DefaultPasswordHasher is the hasher used when none is configured.
+	DefaultPasswordHasher PasswordHasher = NewBcryptHasher(bcrypt.DefaultCost)
+)
+
+// PasswordPolicy describes what a password should look like.
+type PasswordPolicy struct {
+	// MinLength is the minimum number of characters.
+	MinLength int
+	// MaxLength is the maximum number of bytes. bcrypt ignores
+	// everything after the 72nd byte.
+	MaxLength int
+	// MinClasses is the minimum number of character classes (lowercase,
+	// uppercase, digits and symbols) that must be present.
+	MinClasses int
+	// DisallowUserInfo rejects passwords containing the username
+	// or the local part of the email.
+	DisallowUserInfo bool
+}
+
+// Validate checks password against the policy. usr is used to
+// check that the password doesn't contain the user's information
+// and can be nil.
+func (p PasswordPolicy) Validate(password string, usr *User) error {
+	if password == "" {
+		return ErrEmptyPassword
+	}
+
+	if utf8.RuneCountInString(password) < p.MinLength {
+		return ErrPasswordTooShort
+	}
+
+	if p.MaxLength > 0 && len(password) > p.MaxLength {
+		return ErrPasswordTooLong
+	}
+
+	if countClasses(password) < p.MinClasses {
+		return ErrPasswordTooSimple
+	}
+
+	if p.DisallowUserInfo && usr != nil && containsUserInfo(password, usr) {
+		return ErrPasswordContainsUserInfo
+	}
+
+	return nil
+}
+
+func countClasses(password string) int {
+	var lower, upper, digit, symbol int
+	for _, r := range password {
+		switch {
+		case unicode.IsLower(r):
+			lower = 1
+		case unicode.IsUpper(r):
+			upper = 1
+		case unicode.IsDigit(r):
+			digit = 1
+		default:
+			symbol = 1
+		}
+	}
+	return lower + upper + digit + symbol
+}
+
+func containsUserInfo(password string, usr *User) bool {
+	password = strings.ToLower(password)
+	infos := []string{usr.Username}
+	if i := strings.Index(usr.Email, "@"); i > 0 {
+		infos = append(infos, usr.Email[:i])
+	}
+
+	for _, info := range infos {
+		// Short values like initials are too common to be rejected.
+		if len(info) < 3 {
+			continue
+		}
+		if strings.Contains(password, strings.ToLower(info)) {
+			return true
+		}
+	}
+	return false
+}
+
+// PasswordHasher hashes and verifies passwords.
+type PasswordHasher interface {
+	// Hash returns the encoded hash of password.
+	Hash(password string) (string, error)
+	// Compare returns ErrInvalidCredentials when password doesn't
+	// match hash.
+	Compare(hash, password string) error
+	// NeedsRehash reports whether hash was created with a different
+	// algorithm or cost than the hasher's and should be replaced.
+	NeedsRehash(hash string) bool
+}
+
+// NewBcryptHasher creates a hasher using bcrypt with cost. The version
+// and cost are encoded in every hash so hashes created with an older
+// setting can be detected and upgraded.
+func NewBcryptHasher(cost int) *BcryptHasher {
+	if cost == 0 {
+		cost = bcrypt.DefaultCost
+	}
+	return &BcryptHasher{cost: cost}
+}
+
+type BcryptHasher struct {
+	cost int
+}
+
+func (h *BcryptHasher) Hash(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), h.cost)
+	if err != nil {
+		return "", fmt.Errorf("user: unable to hash password: %w", err)
+	}
+	return string(hash), nil
+}
+
+func (h *BcryptHasher) Compare(hash, password string) error {
+	err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
+	switch err {
+	case nil:
+		return nil
+	case bcrypt.ErrMismatchedHashAndPassword:
+		return ErrInvalidCredentials
+	default:
+		return fmt.Errorf("user: unable to compare password: %w", err)
+	}
+}
+
+func (h *BcryptHasher) NeedsRehash(hash string) bool {
+	cost, err := bcrypt.Cost([]byte(hash))
+	if err != nil {
+		return true
+	}
+	return cost != h.cost
+}
+
+// BreachChecker reports whether a password is known to be
+// part of a data breach.
+type BreachChecker interface {
+	IsBreached(ctx context.Context, password string) (bool, error)
+}
@@ -0,0 +1,82 @@
+//+build unit
+
+package user
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+	"strings"
+	"testing"
+)
+
+func TestPasswordPolicy_Validate(t *testing.T) {
+	policy := PasswordPolicy{
+		MinLength:        8,
+		MaxLength:        72,
+		MinClasses:       3,
+		DisallowUserInfo: true,
+	}
+	usr := &User{Username: "luffy", Email: "monkey.d@gophr.com"}
+
+	tests := map[string]struct {
+		password string
+		want     error
+	}{
+		"Valid":             {password: "Pirate-King1", want: nil},
+		"Empty":             {password: "", want: ErrEmptyPassword},
+		"Too Short":         {password: "Pk-1", want: ErrPasswordTooShort},
+		"Too Long":          {password: "Pk-1" + strings.Repeat("a", 80), want: ErrPasswordTooLong},
+		"Too Simple":        {password: "pirateking", want: ErrPasswordTooSimple},
+		"Contains Username": {password: "Im-LUFFY-123", want: ErrPasswordContainsUserInfo},
+		"Contains Email":    {password: "Monkey.D-123", want: ErrPasswordContainsUserInfo},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tt.want, policy.Validate(tt.password, usr))
+		})
+	}
+}
+
+func TestBcryptHasher(t *testing.T) {
+	hasher := NewBcryptHasher(bcrypt.MinCost)
+	hash, err := hasher.Hash("iampirateking")
+	require.NoError(t, err)
+
+	assert.NoError(t, hasher.Compare(hash, "iampirateking"))
+	assert.Equal(t, ErrInvalidCredentials, hasher.Compare(hash, "iamnotpirateking"))
+	assert.False(t, hasher.NeedsRehash(hash))
+
+	t.Run("Different cost needs rehash", func(t *testing.T) {
+		assert.True(t, NewBcryptHasher(bcrypt.MinCost+1).NeedsRehash(hash))
+	})
+
+	t.Run("Unknown algorithm needs rehash", func(t *testing.T) {
+		assert.True(t, hasher.NeedsRehash("iampirateking"))
+	})
+}
+
+func TestHashList(t *testing.T) {
+	sum := sha1.Sum([]byte("password123"))
+	list := "# breached passwords\n\n" + strings.ToLower(hex.EncodeToString(sum[:])) + ":42\n"
+
+	l, err := NewHashList(strings.NewReader(list))
+	require.NoError(t, err)
+
+	breached, err := l.IsBreached(context.Background(), "password123")
+	require.NoError(t, err)
+	assert.True(t, breached)
+
+	breached, err = l.IsBreached(context.Background(), "Pirate-King1")
+	require.NoError(t, err)
+	assert.False(t, breached)
+
+	t.Run("Invalid hash", func(t *testing.T) {
+		_, err := NewHashList(strings.NewReader("notahash\n"))
+		assert.Error(t, err)
+	})
+}
@@ -2,17 +2,33 @@ package user
 
 import (
 	"context"
+	"time"
 )
 
 //go:generate mockery --name=Repository
 
+// Repository stores the users. The reads leave the soft deleted users
+// out unless the context includes them with softdelete.Include.
 type Repository interface {
 	GetByID(ctx context.Context, id interface{}) (*User, error)
 	GetByUserID(ctx context.Context, userId string) (*User, error)
+	// GetByUserIDs returns the users with userIDs in the same order.
+	// The missing users are left out and every user is returned once.
+	GetByUserIDs(ctx context.Context, userIDs []string) ([]*User, error)
 	GetByEmail(ctx context.Context, email string) (*User, error)
 	GetByUsername(ctx context.Context, uname string) (*User, error)
 	Save(ctx context.Context, user *User) error
 	GetAll(ctx context.Context, cursor string, num int) (users []*User, nextCursor string, err error)
+	// Delete soft deletes the user with id. It's kept until purged.
 	Delete(ctx context.Context, id interface{}) error
+	// Purge permanently deletes the user with id.
+	Purge(ctx context.Context, id interface{}) error
 	Update(ctx context.Context, user *User) error
+	// SoftDelete marks the user with userID as deleted at the given time.
+	SoftDelete(ctx context.Context, userID string, at time.Time) error
+	// Restore removes the deletion mark of the user with userID.
+	Restore(ctx context.Context, userID string) error
+	// GetAllDeleted returns up to num users marked as deleted before
+	// the given time.
+	GetAllDeleted(ctx context.Context, before time.Time, num int) ([]*User, error)
 }
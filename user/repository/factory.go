@@ -3,9 +3,13 @@ package repository
 import (
 	"gophr.v2/config"
 	mysqldriver "gophr.v2/driver/mysql"
+	postgresdriver "gophr.v2/driver/postgres"
+	sqlitedriver "gophr.v2/driver/sqlite"
 	"gophr.v2/user"
 	"gophr.v2/user/repository/file"
 	"gophr.v2/user/repository/mysql"
+	"gophr.v2/user/repository/postgres"
+	"gophr.v2/user/repository/sqlite"
 )
 
 type RepoType int
@@ -13,23 +17,47 @@ type RepoType int
 const (
 	FileRepo RepoType = iota
 	MySQLRepo
+	PostgresRepo
+	SQLiteRepo
 )
 
+// SQLRepo returns the type of the repository stored in the database
+// of conf.
+func SQLRepo(conf *config.Config) RepoType {
+	switch conf.Gophr.Database {
+	case config.PostgresDatabase:
+		return PostgresRepo
+	case config.SQLiteDatabase:
+		return SQLiteRepo
+	default:
+		return MySQLRepo
+	}
+}
+
 func Get(conf *config.Config, rt RepoType) (user.Repository, func() error) {
 	switch rt {
 	case FileRepo:
-		return file.New(file.DefaultFileName), noOpClose
+		store := file.New(file.DefaultFileName)
+		return store, store.Close
 	case MySQLRepo:
 		db, err := mysqldriver.Initialize(conf)
 		if err != nil {
 			panic(err)
 		}
 		return mysql.New(db), db.Close
+	case PostgresRepo:
+		db, err := postgresdriver.Initialize(conf)
+		if err != nil {
+			panic(err)
+		}
+		return postgres.New(db), db.Close
+	case SQLiteRepo:
+		db, err := sqlitedriver.Initialize(conf)
+		if err != nil {
+			panic(err)
+		}
+		return sqlite.New(db), db.Close
 	default:
 		panic("unknown repository implementation type")
 	}
 }
-
-func noOpClose() error {
-	return nil
-}
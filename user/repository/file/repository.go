@@ -4,133 +4,332 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"github.com/jayvib/golog"
+	"gophr.v2/softdelete"
 	"gophr.v2/user"
-	"io"
-	"io/ioutil"
-	"math/rand"
-	"os"
+	"gophr.v2/user/userutil"
+	"gophr.v2/util/fileutil"
+	"sort"
+	"sync"
 	"time"
 )
 
 const DefaultFileName = "users.db"
 
-func init() {
-	rand.Seed(time.Now().UnixNano())
-}
+var _ user.Repository = (*FileUserStore)(nil)
 
+// New opens the store persisting the users in filename. It panics
+// when the file can't be read.
 func New(filename string) *FileUserStore {
-	file, err := os.Open(filename)
+	log, err := fileutil.OpenLog(filename)
 	if err != nil {
-		if _, ok := err.(*os.PathError); !ok {
-			panic(err)
-		}
+		panic(err)
 	}
 
 	s := &FileUserStore{
-		filename:  filename,
-		users:     make(map[string]*user.User),
-		idCounter: rand.Intn(16),
-	}
-
-	// meaning this is a path error not exists
-	if err != nil {
-		return s
+		log:      log,
+		users:    make(map[string]*user.User),
+		byUserID: make(map[string]string),
 	}
-
-	err = json.NewDecoder(file).Decode(&s.users)
-	if err != nil && err != io.EOF {
-		panic(err)
+	for key, value := range log.Values() {
+		usr := new(user.User)
+		if err := json.Unmarshal(value, usr); err != nil {
+			panic(fmt.Errorf("FileUserStore: error while unmarshalling user %s: %w", key, err))
+		}
+		s.users[key] = usr
+		s.byUserID[usr.UserID] = key
+		if usr.ID > s.lastID {
+			s.lastID = usr.ID
+		}
 	}
 	return s
 }
 
+// FileUserStore keeps the users in memory and persists every change
+// to an append-only log before applying it.
 type FileUserStore struct {
-	filename string
-	users    map[string]*user.User
-	user.Repository
-	idCounter int
+	mu    sync.RWMutex
+	log   *fileutil.Log
+	users map[string]*user.User
+	// byUserID maps the user IDs to the keys of the users
+	byUserID map[string]string
+	lastID   uint
+}
+
+// key returns the key of the user with id in the log.
+func key(id interface{}) string {
+	return fmt.Sprintf("%v", id)
 }
 
 func (s *FileUserStore) GetByID(ctx context.Context, id interface{}) (*user.User, error) {
-	for _, usr := range s.users {
-		if usr.UserID == id {
-			return usr.Clone(), nil
+	k := key(id)
+	return s.find(ctx, func(usr *user.User) bool {
+		return key(usr.ID) == k
+	})
+}
+
+func (s *FileUserStore) GetByUserID(ctx context.Context, userID string) (*user.User, error) {
+	return s.find(ctx, func(usr *user.User) bool {
+		return usr.UserID == userID
+	})
+}
+
+// GetByUserIDs looks the users up by their user ID.
+func (s *FileUserStore) GetByUserIDs(ctx context.Context, userIDs []string) ([]*user.User, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	included := softdelete.Included(ctx)
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	users := make([]*user.User, 0, len(userIDs))
+	for _, id := range userIDs {
+		usr, ok := s.users[s.byUserID[id]]
+		if !ok || (usr.IsDeleted() && !included) {
+			continue
 		}
+		users = append(users, usr.Clone())
 	}
-	return nil, user.ErrNotFound
+	return userutil.OrderByUserIDs(users, userIDs), nil
 }
+
 func (s *FileUserStore) GetByEmail(ctx context.Context, email string) (*user.User, error) {
-	for _, usr := range s.users {
-		if usr.Email == email {
-			return usr.Clone(), nil
-		}
-	}
-	return nil, user.ErrNotFound
+	return s.find(ctx, func(usr *user.User) bool {
+		return usr.Email == email
+	})
 }
 
 func (s *FileUserStore) GetByUsername(ctx context.Context, uname string) (*user.User, error) {
+	return s.find(ctx, func(usr *user.User) bool {
+		return usr.Username == uname
+	})
+}
+
+// find returns a copy of the first user matching fn. The soft deleted
+// users are skipped unless ctx includes them.
+func (s *FileUserStore) find(ctx context.Context, fn func(usr *user.User) bool) (*user.User, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	usr, ok := s.lookup(softdelete.Included(ctx), fn)
+	if !ok {
+		return nil, user.ErrNotFound
+	}
+	return usr.Clone(), nil
+}
+
+// lookup returns the stored user matching fn.
+func (s *FileUserStore) lookup(includeDeleted bool, fn func(usr *user.User) bool) (*user.User, bool) {
 	for _, usr := range s.users {
-		if usr.Username == uname {
-			return usr.Clone(), nil
+		if usr.IsDeleted() && !includeDeleted {
+			continue
+		}
+		if fn(usr) {
+			return usr, true
 		}
 	}
-	return nil, user.ErrNotFound
+	return nil, false
 }
+
+// Save assigns the next ID to usr and stores it. The username and
+// email of the soft deleted users stay taken until they're purged.
 func (s *FileUserStore) Save(ctx context.Context, usr *user.User) error {
 	const op = "FileUserStore.Save"
-	// check first if the username is already exists
-	res, err := s.GetByUsername(ctx, usr.Username)
-	if err == nil {
-		golog.Debug(err)
-		golog.Debugf("%#v\n", res)
-		return user.ErrUserNameExists
+	if err := ctx.Err(); err != nil {
+		return err
 	}
 
-	_, err = s.GetByEmail(ctx, usr.Email)
-	if err == nil {
-		golog.Debug(err)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.lookup(true, func(u *user.User) bool { return u.Username == usr.Username }); ok {
+		return user.ErrUserNameExists
+	}
+	if _, ok := s.lookup(true, func(u *user.User) bool { return u.Email == usr.Email }); ok {
 		return user.ErrEmailExists
 	}
+	if _, ok := s.lookup(true, func(u *user.User) bool { return u.UserID == usr.UserID }); ok {
+		return user.ErrUserExists
+	}
 
-	usr.ID = uint(s.idCounter)
-	s.idCounter++
-	s.users[fmt.Sprintf("%d", usr.ID)] = usr
+	saved := usr.Clone()
+	saved.ID = s.lastID + 1
+	if err := s.put(op, saved); err != nil {
+		return err
+	}
+	s.lastID = saved.ID
+	usr.ID = saved.ID
+	return nil
+}
 
-	content, err := json.MarshalIndent(s.users, "", "	")
-	if err != nil {
-		return fmt.Errorf("%s: error while marsalling user: %w", op, err)
+func (s *FileUserStore) Update(ctx context.Context, usr *user.User) error {
+	const op = "FileUserStore.Update"
+	if err := ctx.Err(); err != nil {
+		return err
 	}
 
-	err = ioutil.WriteFile(s.filename, content, 0666)
-	if err != nil {
-		return fmt.Errorf("%s: error while writing to file: %w", op, err)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored, ok := s.users[key(usr.ID)]
+	if !ok {
+		return user.ErrNotFound
 	}
-	return nil
+	updated := usr.Clone()
+	updated.DeletedAt = stored.DeletedAt
+	return s.put(op, updated)
 }
 
 func (s *FileUserStore) Delete(ctx context.Context, id interface{}) error {
-	delete(s.users, id.(string))
-	return nil
+	return s.update(ctx, "FileUserStore.Delete", func(usr *user.User) bool {
+		return key(usr.ID) == key(id) && !usr.IsDeleted()
+	}, func(usr *user.User) {
+		now := time.Now().UTC()
+		usr.DeletedAt = &now
+	})
 }
 
-func (s *FileUserStore) Update(ctx context.Context, usr *user.User) error {
-	const op = "Update"
-	id := fmt.Sprintf("%d", usr.ID)
-	golog.Debug("id:", id)
-	if _, ok := s.users[id]; !ok {
+func (s *FileUserStore) SoftDelete(ctx context.Context, userID string, at time.Time) error {
+	return s.update(ctx, "FileUserStore.SoftDelete", func(usr *user.User) bool {
+		return usr.UserID == userID
+	}, func(usr *user.User) {
+		at = at.UTC()
+		usr.DeletedAt = &at
+	})
+}
+
+func (s *FileUserStore) Restore(ctx context.Context, userID string) error {
+	return s.update(ctx, "FileUserStore.Restore", func(usr *user.User) bool {
+		return usr.UserID == userID
+	}, func(usr *user.User) {
+		usr.DeletedAt = nil
+	})
+}
+
+// update applies change to a copy of the user matching fn and
+// stores it, returning user.ErrNotFound when there's no such user.
+func (s *FileUserStore) update(ctx context.Context, op string, fn func(usr *user.User) bool, change func(usr *user.User)) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	usr, ok := s.lookup(true, fn)
+	if !ok {
 		return user.ErrNotFound
 	}
-	s.users[id] = usr
+	updated := usr.Clone()
+	change(updated)
+	return s.put(op, updated)
+}
 
-	content, err := json.MarshalIndent(s.users, "", "	")
-	if err != nil {
-		return fmt.Errorf("%s: error while marsalling user: %w", op, err)
+func (s *FileUserStore) Purge(ctx context.Context, id interface{}) error {
+	if err := ctx.Err(); err != nil {
+		return err
 	}
 
-	err = ioutil.WriteFile(s.filename, content, 0666)
-	if err != nil {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	k := key(id)
+	usr, ok := s.users[k]
+	if !ok {
+		return user.ErrNotFound
+	}
+	if err := s.log.Delete(k); err != nil {
+		return fmt.Errorf("FileUserStore.Purge: error while writing to file: %w", err)
+	}
+	delete(s.users, k)
+	delete(s.byUserID, usr.UserID)
+	return nil
+}
+
+// put persists usr and then keeps it in memory. s.mu must be held.
+func (s *FileUserStore) put(op string, usr *user.User) error {
+	k := key(usr.ID)
+	if err := s.log.Put(k, usr); err != nil {
 		return fmt.Errorf("%s: error while writing to file: %w", op, err)
 	}
+	if old, ok := s.users[k]; ok && old.UserID != usr.UserID {
+		delete(s.byUserID, old.UserID)
+	}
+	s.users[k] = usr
+	s.byUserID[usr.UserID] = k
 	return nil
 }
+
+func (s *FileUserStore) GetAllDeleted(ctx context.Context, before time.Time, num int) ([]*user.User, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	users := make([]*user.User, 0)
+	for _, usr := range s.users {
+		if usr.DeletedAt != nil && usr.DeletedAt.Before(before) {
+			users = append(users, usr.Clone())
+		}
+	}
+	s.mu.RUnlock()
+
+	sort.Slice(users, func(i, j int) bool {
+		return users[i].DeletedAt.Before(*users[j].DeletedAt)
+	})
+	if len(users) > num {
+		users = users[:num]
+	}
+	return users, nil
+}
+
+// GetAll returns up to num users created after the cursor, oldest
+// first, like the SQL repositories.
+func (s *FileUserStore) GetAll(ctx context.Context, cursor string, num int) (users []*user.User, nextCursor string, err error) {
+	if err := ctx.Err(); err != nil {
+		return nil, "", err
+	}
+
+	var after time.Time
+	if cursor != "" {
+		after, err = userutil.DecodeCursor(cursor)
+		if err != nil {
+			return nil, "", err
+		}
+	}
+
+	included := softdelete.Included(ctx)
+	s.mu.RLock()
+	users = make([]*user.User, 0)
+	for _, usr := range s.users {
+		if usr.IsDeleted() && !included {
+			continue
+		}
+		if usr.CreatedAt != nil && usr.CreatedAt.After(after) {
+			users = append(users, usr.Clone())
+		}
+	}
+	s.mu.RUnlock()
+
+	sort.Slice(users, func(i, j int) bool {
+		return users[i].CreatedAt.Before(*users[j].CreatedAt)
+	})
+	if len(users) > num {
+		users = users[:num]
+	}
+
+	if len(users) == num && num > 0 {
+		nextCursor = userutil.EncodeCursor(*users[len(users)-1].CreatedAt)
+	}
+	return users, nextCursor, nil
+}
+
+// Close closes the file of the store.
+func (s *FileUserStore) Close() error {
+	return s.log.Close()
+}
@@ -0,0 +1,84 @@
+//+build unit
+
+package file
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gophr.v2/softdelete"
+	"gophr.v2/user"
+	"gophr.v2/user/repository/repositorytest"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFileUserStore_SoftDelete(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gophr")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	ctx := context.Background()
+	store := New(filepath.Join(dir, DefaultFileName))
+	usr := &user.User{UserID: "luffy", Username: "luffy", Email: "luffy@gophr.com"}
+	require.NoError(t, store.Save(ctx, usr))
+
+	require.NoError(t, store.Delete(ctx, usr.ID))
+	_, err = store.GetByUsername(ctx, "luffy")
+	assert.Equal(t, user.ErrNotFound, err)
+	assert.Equal(t, user.ErrNotFound, store.Delete(ctx, usr.ID))
+
+	got, err := store.GetByUsername(softdelete.Include(ctx), "luffy")
+	require.NoError(t, err)
+	assert.True(t, got.IsDeleted())
+
+	t.Run("Deleted users keep their username", func(t *testing.T) {
+		err := store.Save(ctx, &user.User{UserID: "other", Username: "luffy", Email: "other@gophr.com"})
+		assert.Equal(t, user.ErrUserNameExists, err)
+	})
+
+	t.Run("Restore", func(t *testing.T) {
+		require.NoError(t, store.Restore(ctx, "luffy"))
+		_, err := store.GetByUserID(ctx, "luffy")
+		assert.NoError(t, err)
+	})
+
+	t.Run("Purge", func(t *testing.T) {
+		require.NoError(t, store.Purge(ctx, usr.ID))
+		_, err := New(filepath.Join(dir, DefaultFileName)).GetByUserID(softdelete.Include(ctx), "luffy")
+		assert.Equal(t, user.ErrNotFound, err)
+	})
+}
+
+func TestConformance(t *testing.T) {
+	repositorytest.Run(t, func(t *testing.T) (user.Repository, func()) {
+		dir, err := ioutil.TempDir("", "gophr")
+		require.NoError(t, err)
+		return New(filepath.Join(dir, DefaultFileName)), func() { _ = os.RemoveAll(dir) }
+	})
+}
+
+func TestFileUserStore_Reopen(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gophr")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	ctx := context.Background()
+	filename := filepath.Join(dir, DefaultFileName)
+	store := New(filename)
+	luffy := &user.User{UserID: "luffy", Username: "luffy", Email: "luffy@gophr.com"}
+	require.NoError(t, store.Save(ctx, luffy))
+	require.NoError(t, store.Delete(ctx, luffy.ID))
+	require.NoError(t, store.Close())
+
+	store = New(filename)
+	defer store.Close()
+	_, err = store.GetByUserID(ctx, "luffy")
+	assert.Equal(t, user.ErrNotFound, err, "the deletion is persisted")
+
+	zoro := &user.User{UserID: "zoro", Username: "zoro", Email: "zoro@gophr.com"}
+	require.NoError(t, store.Save(ctx, zoro))
+	assert.NotEqual(t, luffy.ID, zoro.ID, "the IDs aren't reused")
+}
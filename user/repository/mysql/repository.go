@@ -5,10 +5,14 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
-	log "github.com/jayvib/golog"
+	driver "github.com/go-sql-driver/mysql"
 	_ "github.com/jinzhu/gorm/dialects/mysql"
+	"gophr.v2/event/outbox"
+	"gophr.v2/log"
+	"gophr.v2/softdelete"
 	"gophr.v2/user"
 	"gophr.v2/user/userutil"
+	"strings"
 	"time"
 )
 
@@ -23,30 +27,53 @@ type Repository struct {
 }
 
 func (r *Repository) GetByUserID(ctx context.Context, userID string) (u *user.User, err error) {
-	query := "SELECT id,userId,username,email,password,created_at,updated_at,deleted_at FROM user WHERE userId = ?"
+	query := "SELECT id,userId,username,email,password,display_name,bio,website,created_at,updated_at,deleted_at FROM user WHERE userId = ? AND " + softdelete.SQLFilter(ctx)
 	return r.doQuerySingleReturn(ctx, query, userID)
 }
 
+// GetByUserIDs finds the users with a single query.
+func (r *Repository) GetByUserIDs(ctx context.Context, userIDs []string) ([]*user.User, error) {
+	if len(userIDs) == 0 {
+		return []*user.User{}, nil
+	}
+
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(userIDs)), ",")
+	query := "SELECT id,userId,username,email,password,display_name,bio,website,created_at,updated_at,deleted_at FROM user WHERE userId IN (" + placeholders + ") AND " + softdelete.SQLFilter(ctx)
+
+	args := make([]interface{}, 0, len(userIDs))
+	for _, userID := range userIDs {
+		args = append(args, userID)
+	}
+	users, err := r.doQuery(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	return userutil.OrderByUserIDs(users, userIDs), nil
+}
+
 func (r *Repository) GetByID(ctx context.Context, id interface{}) (u *user.User, err error) {
-	query := "SELECT id,userId,username,email,password,created_at,updated_at,deleted_at FROM user WHERE id = ?"
+	query := "SELECT id,userId,username,email,password,display_name,bio,website,created_at,updated_at,deleted_at FROM user WHERE id = ? AND " + softdelete.SQLFilter(ctx)
 	return r.doQuerySingleReturn(ctx, query, id)
 }
 func (r *Repository) GetByEmail(ctx context.Context, email string) (u *user.User, err error) {
-	query := "SELECT id,userId,username,email,password,created_at,updated_at,deleted_at FROM user WHERE email = ?"
+	query := "SELECT id,userId,username,email,password,display_name,bio,website,created_at,updated_at,deleted_at FROM user WHERE email = ? AND " + softdelete.SQLFilter(ctx)
 	return r.doQuerySingleReturn(ctx, query, email)
 }
 func (r *Repository) GetByUsername(ctx context.Context, uname string) (*user.User, error) {
-	query := "SELECT id,userId,username,email,password,created_at,updated_at,deleted_at FROM user WHERE username = ?"
+	query := "SELECT id,userId,username,email,password,display_name,bio,website,created_at,updated_at,deleted_at FROM user WHERE username = ? AND " + softdelete.SQLFilter(ctx)
 	return r.doQuerySingleReturn(ctx, query, uname)
 }
 func (r *Repository) Save(ctx context.Context, usr *user.User) (err error) {
-	query := "INSERT INTO user(userId, username, email, password, created_at, updated_at) VALUES(?,?,?,?,?,?)"
+	query := "INSERT INTO user(userId, username, email, password, display_name, bio, website, created_at, updated_at) VALUES(?,?,?,?,?,?,?,?,?)"
 	return r.doSave(func(tx *sql.Tx) error {
 		res, err := tx.ExecContext(ctx, query,
 			usr.UserID,
 			usr.Username,
 			usr.Email,
 			usr.Password,
+			usr.DisplayName,
+			usr.Bio,
+			usr.Website,
 			usr.CreatedAt,
 			usr.UpdatedAt,
 		)
@@ -61,24 +88,47 @@ func (r *Repository) Save(ctx context.Context, usr *user.User) (err error) {
 
 		usr.ID = uint(id)
 
-		return nil
+		// The events of the registration are committed along with it
+		return outbox.Store(ctx, tx)
 	})
 }
 func (r *Repository) Update(ctx context.Context, usr *user.User) error {
-	query := "UPDATE user SET userId=?, username=?, email=?, password=?, updated_at=? WHERE id=?"
+	query := "UPDATE user SET userId=?, username=?, email=?, password=?, display_name=?, bio=?, website=?, updated_at=? WHERE id=?"
 	return r.doSave(func(tx *sql.Tx) error {
 		_, err := tx.ExecContext(ctx, query,
 			usr.UserID,
 			usr.Username,
 			usr.Email,
 			usr.Password, // TODO: Remove
+			usr.DisplayName,
+			usr.Bio,
+			usr.Website,
 			usr.UpdatedAt,
 			usr.ID,
 		)
-		return err
+		return r.checkError(err)
 	})
 }
 func (r *Repository) Delete(ctx context.Context, id interface{}) error {
+	query := "UPDATE user SET deleted_at = ? WHERE id = ? AND deleted_at IS NULL"
+	return r.doSave(func(tx *sql.Tx) error {
+		res, err := tx.ExecContext(ctx, query, time.Now().UTC(), id)
+		if err != nil {
+			return r.checkError(err)
+		}
+
+		affected, err := res.RowsAffected()
+		if err != nil {
+			return r.checkError(err)
+		}
+		if affected == 0 {
+			return user.ErrNotFound
+		}
+		return outbox.Store(ctx, tx)
+	})
+}
+
+func (r *Repository) Purge(ctx context.Context, id interface{}) error {
 	query := "DELETE FROM user WHERE id = ?"
 	return r.doSave(func(tx *sql.Tx) error {
 		res, err := tx.ExecContext(ctx, query, id)
@@ -90,8 +140,55 @@ func (r *Repository) Delete(ctx context.Context, id interface{}) error {
 		if err != nil {
 			return err
 		}
-		if affected != 1 {
-			return errors.New("repository/mysql: number of rows affected is more than 1")
+		if affected == 0 {
+			return user.ErrNotFound
+		}
+
+		// The records of the user are cleaned up by the handlers
+		// of the events committed along with the deletion
+		return outbox.Store(ctx, tx)
+	})
+}
+
+func (r *Repository) SoftDelete(ctx context.Context, userID string, at time.Time) error {
+	query := "UPDATE user SET deleted_at = ? WHERE userId = ?"
+	return r.doUpdateByUserID(ctx, query, at, userID)
+}
+
+func (r *Repository) Restore(ctx context.Context, userID string) error {
+	query := "UPDATE user SET deleted_at = NULL WHERE userId = ?"
+	return r.doUpdateByUserID(ctx, query, userID)
+}
+
+func (r *Repository) GetAllDeleted(ctx context.Context, before time.Time, num int) ([]*user.User, error) {
+	query := `
+		SELECT
+			id, userId, username, email, password, display_name, bio, website, created_at, updated_at, deleted_at
+		FROM
+			user
+		WHERE
+			deleted_at IS NOT NULL AND deleted_at < ?
+		ORDER BY
+			deleted_at
+		LIMIT ?`
+	return r.doQuery(ctx, query, before, num)
+}
+
+// doUpdateByUserID runs the update query whose last argument is the
+// user ID, returning user.ErrNotFound when there's no such user.
+func (r *Repository) doUpdateByUserID(ctx context.Context, query string, args ...interface{}) error {
+	return r.doSave(func(tx *sql.Tx) error {
+		res, err := tx.ExecContext(ctx, query, args...)
+		if err != nil {
+			return r.checkError(err)
+		}
+
+		affected, err := res.RowsAffected()
+		if err != nil {
+			return r.checkError(err)
+		}
+		if affected == 0 {
+			return user.ErrNotFound
 		}
 		return nil
 	})
@@ -99,11 +196,11 @@ func (r *Repository) Delete(ctx context.Context, id interface{}) error {
 func (r *Repository) GetAll(ctx context.Context, cursor string, num int) (users []*user.User, nextCursor string, err error) {
 	query := `
 		SELECT 
-			id, userId, username, email, password, created_at, updated_at, deleted_at 
+			id, userId, username, email, password, display_name, bio, website, created_at, updated_at, deleted_at 
 		FROM 
 			user 
 		WHERE 
-			created_at > ? 
+			created_at > ? AND ` + softdelete.SQLFilter(ctx) + `
 		ORDER BY 
 			created_at 
 		LIMIT ?`
@@ -117,7 +214,6 @@ func (r *Repository) GetAll(ctx context.Context, cursor string, num int) (users
 	} else {
 		decodedCursor = time.Now().AddDate(-100, 0, 0)
 	}
-	log.Debug("cursor:", decodedCursor)
 
 	res, err := r.doQuery(ctx, query, decodedCursor, num)
 	if err != nil {
@@ -129,8 +225,7 @@ func (r *Repository) GetAll(ctx context.Context, cursor string, num int) (users
 		nextCursor = userutil.EncodeCursor(*res[len(res)-1].CreatedAt)
 	}
 
-	log.Debug("Result:", res)
-	log.Debug("Next cursor:", nextCursor)
+	log.FromContext(ctx).With(log.Fields{"cursor": decodedCursor, "nextCursor": nextCursor, "count": len(res)}).Debug("listed users")
 	return res, nextCursor, nil
 }
 
@@ -148,7 +243,7 @@ func (r *Repository) doSave(fn func(tx *sql.Tx) error) (err error) {
 			if e := tx.Rollback(); e != nil {
 				// TODO: I think its better to cascade the error
 				// For now just log the error.
-				log.Error("error while rolling back data in sql:", e)
+				log.WithError(e).Error("failed rolling back transaction")
 			}
 		}
 	}()
@@ -164,7 +259,7 @@ func (r *Repository) doSave(fn func(tx *sql.Tx) error) (err error) {
 func (r *Repository) doQuery(ctx context.Context, query string, args ...interface{}) (users []*user.User, err error) {
 	row, err := r.conn.QueryContext(ctx, query, args...)
 	if err != nil {
-		log.Debug(err)
+		log.FromContext(ctx).WithError(err).Debug("failed querying")
 		return nil, r.checkError(err)
 	}
 	defer func() {
@@ -176,14 +271,14 @@ func (r *Repository) doQuery(ctx context.Context, query string, args ...interfac
 	users = make([]*user.User, 0)
 	for row.Next() {
 		var u user.User
-		err = row.Scan(&u.ID, &u.UserID, &u.Username, &u.Email, &u.Password, &u.CreatedAt, &u.UpdatedAt, &u.DeletedAt)
+		err = row.Scan(&u.ID, &u.UserID, &u.Username, &u.Email, &u.Password, &u.DisplayName, &u.Bio, &u.Website, &u.CreatedAt, &u.UpdatedAt, &u.DeletedAt)
 		if err != nil {
 			return nil, r.checkError(err)
 		}
 		users = append(users, &u)
 	}
 	if err = row.Err(); err != nil {
-		log.Debug(err)
+		log.FromContext(ctx).WithError(err).Debug("failed querying")
 		return nil, r.checkError(err)
 	}
 	return users, nil
@@ -208,7 +303,31 @@ func (r *Repository) checkError(err error) error {
 	case nil:
 		cerr = nil
 	default:
-		cerr = fmt.Errorf("mysql: unexpected error %w", err)
+		cerr = duplicateError(err)
+		if cerr == nil {
+			cerr = fmt.Errorf("mysql: unexpected error %w", err)
+		}
 	}
 	return cerr
 }
+
+// errDuplicateEntry is the number of the MySQL error raised when
+// a unique key is violated.
+const errDuplicateEntry = 1062
+
+// duplicateError returns the error of the unique key of the user
+// table violated by err, or nil when err is about something else.
+func duplicateError(err error) error {
+	var merr *driver.MySQLError
+	if !errors.As(err, &merr) || merr.Number != errDuplicateEntry {
+		return nil
+	}
+	switch {
+	case strings.Contains(merr.Message, "user_username"):
+		return user.ErrUserNameExists
+	case strings.Contains(merr.Message, "user_email"):
+		return user.ErrEmailExists
+	default:
+		return user.ErrUserExists
+	}
+}
@@ -6,17 +6,19 @@ import (
 	"context"
 	"database/sql"
 	"flag"
-	"github.com/jayvib/golog"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"gophr.v2/config"
 	"gophr.v2/config/builder/viper"
 	mysqldriver "gophr.v2/driver/mysql"
+	"gophr.v2/log"
+	"gophr.v2/migration"
+	"gophr.v2/softdelete"
 	"gophr.v2/user"
 	"gophr.v2/user/repository/mysql"
+	"gophr.v2/user/repository/repositorytest"
 	"gophr.v2/user/userutil"
 	"gophr.v2/util/valueutil"
-	"log"
 	"os"
 	"testing"
 	"time"
@@ -42,6 +44,11 @@ func setup() error {
 		return err
 	}
 
+	// The schema is bootstrapped by the migrations of the binary
+	if _, err := migration.New(db, migration.MySQLDialect{}, migration.MySQL()).Up(context.Background()); err != nil {
+		return err
+	}
+
 	repo = mysql.New(db)
 	return nil
 }
@@ -57,7 +64,7 @@ func teardown() {
 func TestMain(t *testing.M) {
 	flag.Parse()
 	if *debug {
-		golog.SetLevel(golog.DebugLevel)
+		log.SetLevel(log.DebugLevel)
 	}
 	if err := setup(); err != nil {
 		log.Fatal(err)
@@ -70,6 +77,13 @@ func TestMain(t *testing.M) {
 	os.Exit(code)
 }
 
+func TestConformance(t *testing.T) {
+	repositorytest.Run(t, func(t *testing.T) (user.Repository, func()) {
+		teardown()
+		return repo, teardown
+	})
+}
+
 func TestRepository_GetByEmail(t *testing.T) {
 	defer teardown()
 	t.Run("found", func(t *testing.T) {
@@ -193,6 +207,25 @@ func TestRepository_Delete(t *testing.T) {
 
 	// check
 	assertDelete(t, want.ID)
+
+	t.Run("Include Deleted", func(t *testing.T) {
+		got, err := repo.GetByID(softdelete.Include(context.Background()), want.ID)
+		require.NoError(t, err)
+		assert.True(t, got.IsDeleted())
+	})
+
+	t.Run("Restore", func(t *testing.T) {
+		require.NoError(t, repo.Restore(context.Background(), want.UserID))
+		got, err := repo.GetByID(context.Background(), want.ID)
+		require.NoError(t, err)
+		assert.False(t, got.IsDeleted())
+	})
+
+	t.Run("Purge", func(t *testing.T) {
+		require.NoError(t, repo.Purge(context.Background(), want.ID))
+		_, err := repo.GetByID(softdelete.Include(context.Background()), want.ID)
+		assert.Equal(t, user.ErrNotFound, err)
+	})
 }
 
 func TestRepository_Save(t *testing.T) {
@@ -246,7 +279,7 @@ func TestRepository_GetAll(t *testing.T) {
 
 	assert.Len(t, got, 3)
 
-	golog.Debug(got)
+	log.Debug(got)
 
 	// Compare the result from the input
 	assertGetAll(t, input, got)
@@ -254,14 +287,14 @@ func TestRepository_GetAll(t *testing.T) {
 
 func deleteSaved(t *testing.T, id interface{}) {
 	t.Helper()
-	err := repo.Delete(context.Background(), id)
+	err := repo.Purge(context.Background(), id)
 	assert.NoError(t, err)
 }
 
 func getTimeCursor(t time.Time) string {
 	subTime := t.Add(-time.Second)
 	cursor := userutil.EncodeCursor(subTime)
-	golog.Debugf("Cursor: %s\n", cursor)
+	log.Debugf("Cursor: %s\n", cursor)
 
 	// Get all
 	return cursor
@@ -291,7 +324,7 @@ func setupGetAll(t *testing.T, input []*user.User) (teardown func()) {
 	}
 	return func() {
 		for _, in := range input {
-			err := repo.Delete(context.Background(), in.ID)
+			err := repo.Purge(context.Background(), in.ID)
 			assert.NoError(t, err)
 		}
 	}
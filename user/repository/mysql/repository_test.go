@@ -8,9 +8,10 @@ import (
 	"errors"
 	"flag"
 	"github.com/DATA-DOG/go-sqlmock"
-	"github.com/jayvib/golog"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"gophr.v2/log"
+	"gophr.v2/softdelete"
 	"gophr.v2/user"
 	"gophr.v2/user/userutil"
 	"gophr.v2/util/valueutil"
@@ -26,8 +27,8 @@ var defaultCtx = context.Background()
 func TestMain(t *testing.M) {
 	flag.Parse()
 	if *debug {
-		golog.Info("Debug Level")
-		golog.SetLevel(golog.DebugLevel)
+		log.Info("Debug Level")
+		log.SetLevel(log.DebugLevel)
 	}
 	os.Exit(t.Run())
 }
@@ -38,7 +39,7 @@ func setup(t *testing.T) (*sql.DB, sqlmock.Sqlmock, *sqlmock.Rows) {
 		t.Fatal(err)
 	}
 	rows := sqlmock.NewRows([]string{
-		"id", "userId", "username", "email", "password", "created_at", "updated_at", "deleted_at",
+		"id", "userId", "username", "email", "password", "display_name", "bio", "website", "created_at", "updated_at", "deleted_at",
 	})
 	return db, mock, rows
 }
@@ -64,12 +65,15 @@ func TestRepository_GetByEmail(t *testing.T) {
 			mockUser.Username,
 			mockUser.Email,
 			mockUser.Password,
+			mockUser.DisplayName,
+			mockUser.Bio,
+			mockUser.Website,
 			mockUser.CreatedAt,
 			mockUser.UpdatedAt,
 			mockUser.DeletedAt,
 		)
 
-		query := "SELECT id,userId,username,email,password,created_at,updated_at,deleted_at FROM user WHERE email = ?"
+		query := "SELECT id,userId,username,email,password,display_name,bio,website,created_at,updated_at,deleted_at FROM user WHERE email = ?"
 		mock.ExpectQuery(query).WillReturnRows(rows)
 		u, err := repo.GetByEmail(defaultCtx, "unit.test@golang.com")
 		checkErr(t, err)
@@ -79,7 +83,7 @@ func TestRepository_GetByEmail(t *testing.T) {
 	t.Run("Not Found", func(t *testing.T) {
 		db, mock, _ := setup(t)
 		repo := New(db)
-		query := "SELECT id,userId,username,email,password,created_at,updated_at,deleted_at FROM user WHERE email = ?"
+		query := "SELECT id,userId,username,email,password,display_name,bio,website,created_at,updated_at,deleted_at FROM user WHERE email = ?"
 		mock.ExpectQuery(query).WillReturnError(sql.ErrNoRows)
 		u, err := repo.GetByEmail(defaultCtx, "unit.test@golang.com")
 		assert.Nil(t, u)
@@ -90,7 +94,7 @@ func TestRepository_GetByEmail(t *testing.T) {
 	t.Run("Unexpected error", func(t *testing.T) {
 		db, mock, _ := setup(t)
 		repo := New(db)
-		query := "SELECT id,userId,username,email,password,created_at,updated_at,deleted_at FROM user WHERE email = ?"
+		query := "SELECT id,userId,username,email,password,display_name,bio,website,created_at,updated_at,deleted_at FROM user WHERE email = ?"
 		mock.ExpectQuery(query).WillReturnError(sql.ErrConnDone)
 		u, err := repo.GetByEmail(defaultCtx, "unit.test@golang.com")
 		assert.Nil(t, u)
@@ -120,12 +124,15 @@ func TestRepository_GetByID(t *testing.T) {
 		mockUser.Username,
 		mockUser.Email,
 		mockUser.Password,
+		mockUser.DisplayName,
+		mockUser.Bio,
+		mockUser.Website,
 		mockUser.CreatedAt,
 		mockUser.UpdatedAt,
 		mockUser.DeletedAt,
 	)
 
-	query := "SELECT id,userId,username,email,password,created_at,updated_at,deleted_at FROM user WHERE id = ?"
+	query := "SELECT id,userId,username,email,password,display_name,bio,website,created_at,updated_at,deleted_at FROM user WHERE id = ?"
 	mock.ExpectQuery(query).WillReturnRows(rows)
 	u, err := repo.GetByID(defaultCtx, mockUser.ID)
 	checkErr(t, err)
@@ -152,18 +159,40 @@ func TestRepository_GetByUserID(t *testing.T) {
 		mockUser.Username,
 		mockUser.Email,
 		mockUser.Password,
+		mockUser.DisplayName,
+		mockUser.Bio,
+		mockUser.Website,
 		mockUser.CreatedAt,
 		mockUser.UpdatedAt,
 		mockUser.DeletedAt,
 	)
 
-	query := "SELECT id,userId,username,email,password,created_at,updated_at,deleted_at FROM user WHERE userId = ?"
+	query := "SELECT id,userId,username,email,password,display_name,bio,website,created_at,updated_at,deleted_at FROM user WHERE userId = ?"
 	mock.ExpectQuery(query).WillReturnRows(rows)
 	u, err := repo.GetByUserID(defaultCtx, mockUser.UserID)
 	checkErr(t, err)
 	assert.Equal(t, mockUser, u)
 }
 
+func TestRepository_GetByUserIDs(t *testing.T) {
+	db, mock, rows := setup(t)
+	repo := New(db)
+	users := []*user.User{
+		{ID: 1, UserID: userutil.GenerateID(), Username: "unit.test", Email: "unit.test@golang.com"},
+		{ID: 2, UserID: userutil.GenerateID(), Username: "unit.test2", Email: "unit.test2@golang.com"},
+	}
+	for _, u := range users {
+		rows.AddRow(u.ID, u.UserID, u.Username, u.Email, u.Password, u.DisplayName, u.Bio, u.Website, u.CreatedAt, u.UpdatedAt, u.DeletedAt)
+	}
+
+	query := "SELECT id,userId,username,email,password,display_name,bio,website,created_at,updated_at,deleted_at FROM user WHERE userId IN \\(\\?,\\?,\\?\\)"
+	mock.ExpectQuery(query).WithArgs(users[1].UserID, "unknown", users[0].UserID).WillReturnRows(rows)
+	got, err := repo.GetByUserIDs(defaultCtx, []string{users[1].UserID, "unknown", users[0].UserID})
+	checkErr(t, err)
+	assert.Equal(t, []*user.User{users[1], users[0]}, got)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
 func TestRepository_GetByUsername(t *testing.T) {
 	db, mock, rows := setup(t)
 	repo := New(db)
@@ -184,12 +213,15 @@ func TestRepository_GetByUsername(t *testing.T) {
 		mockUser.Username,
 		mockUser.Email,
 		mockUser.Password,
+		mockUser.DisplayName,
+		mockUser.Bio,
+		mockUser.Website,
 		mockUser.CreatedAt,
 		mockUser.UpdatedAt,
 		mockUser.DeletedAt,
 	)
 
-	query := "SELECT id,userId,username,email,password,created_at,updated_at,deleted_at FROM user WHERE username = ?"
+	query := "SELECT id,userId,username,email,password,display_name,bio,website,created_at,updated_at,deleted_at FROM user WHERE username = ?"
 	mock.ExpectQuery(query).WillReturnRows(rows)
 	u, err := repo.GetByUsername(defaultCtx, mockUser.Username)
 	checkErr(t, err)
@@ -215,6 +247,9 @@ func TestRepository_Save(t *testing.T) {
 		mockUser.Username,
 		mockUser.Email,
 		mockUser.Password,
+		mockUser.DisplayName,
+		mockUser.Bio,
+		mockUser.Website,
 		mockUser.CreatedAt,
 		mockUser.UpdatedAt,
 	).WillReturnResult(sqlmock.NewResult(1, 1))
@@ -228,12 +263,15 @@ func TestRepository_Save(t *testing.T) {
 
 func TestRepository_Update(t *testing.T) {
 	mockUser := &user.User{
-		UserID:    "testid123",
-		Username:  "unit.test",
-		Email:     "unit.test@golang.com",
-		Password:  "qwerty",
-		CreatedAt: valueutil.TimePointer(time.Now()),
-		UpdatedAt: valueutil.TimePointer(time.Now()),
+		UserID:      "testid123",
+		Username:    "unit.test",
+		Email:       "unit.test@golang.com",
+		Password:    "qwerty",
+		DisplayName: "Unit Test",
+		Bio:         "Testing all the things",
+		Website:     "https://golang.org",
+		CreatedAt:   valueutil.TimePointer(time.Now()),
+		UpdatedAt:   valueutil.TimePointer(time.Now()),
 	}
 	db, mock, _ := setup(t)
 	mock.ExpectBegin()
@@ -242,6 +280,9 @@ func TestRepository_Update(t *testing.T) {
 		mockUser.Username,
 		mockUser.Email,
 		mockUser.Password,
+		mockUser.DisplayName,
+		mockUser.Bio,
+		mockUser.Website,
 		mockUser.UpdatedAt,
 		mockUser.ID,
 	).WillReturnResult(sqlmock.NewResult(1, 1))
@@ -263,7 +304,8 @@ func TestRepository_Delete(t *testing.T) {
 	}
 	db, mock, _ := setup(t)
 	mock.ExpectBegin()
-	mock.ExpectExec("DELETE FROM user").WithArgs(
+	mock.ExpectExec("UPDATE user SET deleted_at").WithArgs(
+		sqlmock.AnyArg(),
 		mockUser.ID,
 	).WillReturnResult(sqlmock.NewResult(1, 1))
 	mock.ExpectCommit()
@@ -271,6 +313,38 @@ func TestRepository_Delete(t *testing.T) {
 	err := repo.Delete(context.Background(), mockUser.ID)
 	require.NoError(t, err)
 	assert.NoError(t, mock.ExpectationsWereMet())
+
+	t.Run("Already deleted", func(t *testing.T) {
+		db, mock, _ := setup(t)
+		mock.ExpectBegin()
+		mock.ExpectExec("UPDATE user SET deleted_at").WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectRollback()
+		err := New(db).Delete(context.Background(), mockUser.ID)
+		assert.Equal(t, user.ErrNotFound, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestRepository_Purge(t *testing.T) {
+	db, mock, _ := setup(t)
+	mock.ExpectBegin()
+	mock.ExpectExec("DELETE FROM user").WithArgs(1).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+	err := New(db).Purge(context.Background(), 1)
+	require.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRepository_IncludeDeleted(t *testing.T) {
+	db, mock, rows := setup(t)
+	deletedAt := time.Now()
+	rows.AddRow(1, "testid123", "unit.test", "unit.test@golang.com", "qwerty", "", "", "", nil, nil, &deletedAt)
+
+	mock.ExpectQuery("FROM user WHERE username = \\? AND TRUE$").WillReturnRows(rows)
+	u, err := New(db).GetByUsername(softdelete.Include(defaultCtx), "unit.test")
+	require.NoError(t, err)
+	assert.True(t, u.IsDeleted())
+	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
 func TestRepository_GetAll(t *testing.T) {
@@ -310,11 +384,11 @@ func TestRepository_GetAll(t *testing.T) {
 
 		// Add the mock users to the row
 		for _, u := range mockUsers {
-			rows.AddRow(u.ID, u.UserID, u.Username, u.Email, u.Password, u.CreatedAt, u.UpdatedAt, u.DeletedAt)
+			rows.AddRow(u.ID, u.UserID, u.Username, u.Email, u.Password, u.DisplayName, u.Bio, u.Website, u.CreatedAt, u.UpdatedAt, u.DeletedAt)
 		}
 		// Need to escape the "?" character as per this issue:
 		// https://github.com/DATA-DOG/go-sqlmock/issues/70
-		query := "SELECT id, userId, username, email, password, created_at, updated_at, deleted_at FROM user WHERE created_at > \\? ORDER BY created_at LIMIT \\?"
+		query := "SELECT id, userId, username, email, password, display_name, bio, website, created_at, updated_at, deleted_at FROM user WHERE created_at > \\? AND deleted_at IS NULL ORDER BY created_at LIMIT \\?"
 		mock.ExpectQuery(query).WillReturnRows(rows)
 
 		repo := New(db)
@@ -331,11 +405,11 @@ func TestRepository_GetAll(t *testing.T) {
 
 		// Add the mock users to the row
 		for _, u := range mockUsers {
-			rows.AddRow(u.ID, u.UserID, u.Username, u.Email, u.Password, u.CreatedAt, u.UpdatedAt, u.DeletedAt)
+			rows.AddRow(u.ID, u.UserID, u.Username, u.Email, u.Password, u.DisplayName, u.Bio, u.Website, u.CreatedAt, u.UpdatedAt, u.DeletedAt)
 		}
 		// Need to escape the "?" character as per this issue:
 		// https://github.com/DATA-DOG/go-sqlmock/issues/70
-		query := "SELECT id, userId, username, email, password, created_at, updated_at, deleted_at FROM user WHERE created_at > \\? ORDER BY created_at LIMIT \\?"
+		query := "SELECT id, userId, username, email, password, display_name, bio, website, created_at, updated_at, deleted_at FROM user WHERE created_at > \\? AND deleted_at IS NULL ORDER BY created_at LIMIT \\?"
 		mock.ExpectQuery(query).WillReturnRows(rows)
 
 		repo := New(db)
@@ -0,0 +1,333 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/lib/pq"
+	"gophr.v2/event/outbox"
+	"gophr.v2/log"
+	"gophr.v2/softdelete"
+	"gophr.v2/user"
+	"gophr.v2/user/userutil"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var _ user.Repository = (*Repository)(nil)
+
+// columns are the columns of a user in the order they're scanned.
+const columns = "id, userId, username, email, password, display_name, bio, website, created_at, updated_at, deleted_at"
+
+func New(conn *sql.DB) *Repository {
+	return &Repository{conn: conn}
+}
+
+type Repository struct {
+	conn *sql.DB
+}
+
+func (r *Repository) GetByUserID(ctx context.Context, userID string) (u *user.User, err error) {
+	query := `SELECT ` + columns + ` FROM "user" WHERE userId = $1 AND ` + softdelete.SQLFilter(ctx)
+	return r.doQuerySingleReturn(ctx, query, userID)
+}
+
+// GetByUserIDs finds the users with a single query.
+func (r *Repository) GetByUserIDs(ctx context.Context, userIDs []string) ([]*user.User, error) {
+	if len(userIDs) == 0 {
+		return []*user.User{}, nil
+	}
+
+	args := make([]interface{}, 0, len(userIDs))
+	placeholders := make([]string, 0, len(userIDs))
+	for _, userID := range userIDs {
+		args = append(args, userID)
+		placeholders = append(placeholders, "$"+strconv.Itoa(len(args)))
+	}
+	query := `SELECT ` + columns + ` FROM "user" WHERE userId IN (` + strings.Join(placeholders, ",") + `) AND ` + softdelete.SQLFilter(ctx)
+
+	users, err := r.doQuery(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	return userutil.OrderByUserIDs(users, userIDs), nil
+}
+
+func (r *Repository) GetByID(ctx context.Context, id interface{}) (u *user.User, err error) {
+	query := `SELECT ` + columns + ` FROM "user" WHERE id = $1 AND ` + softdelete.SQLFilter(ctx)
+	return r.doQuerySingleReturn(ctx, query, id)
+}
+
+func (r *Repository) GetByEmail(ctx context.Context, email string) (u *user.User, err error) {
+	query := `SELECT ` + columns + ` FROM "user" WHERE email = $1 AND ` + softdelete.SQLFilter(ctx)
+	return r.doQuerySingleReturn(ctx, query, email)
+}
+
+func (r *Repository) GetByUsername(ctx context.Context, uname string) (*user.User, error) {
+	query := `SELECT ` + columns + ` FROM "user" WHERE username = $1 AND ` + softdelete.SQLFilter(ctx)
+	return r.doQuerySingleReturn(ctx, query, uname)
+}
+
+// Save inserts usr and sets its ID. PostgreSQL has no last insert ID
+// so the ID is returned by the insert itself.
+func (r *Repository) Save(ctx context.Context, usr *user.User) (err error) {
+	query := `INSERT INTO "user"(userId, username, email, password, display_name, bio, website, created_at, updated_at) VALUES($1,$2,$3,$4,$5,$6,$7,$8,$9) RETURNING id`
+	return r.doSave(func(tx *sql.Tx) error {
+		var id uint
+		err := tx.QueryRowContext(ctx, query,
+			usr.UserID,
+			usr.Username,
+			usr.Email,
+			usr.Password,
+			usr.DisplayName,
+			usr.Bio,
+			usr.Website,
+			usr.CreatedAt,
+			usr.UpdatedAt,
+		).Scan(&id)
+		if err != nil {
+			return r.checkError(err)
+		}
+
+		usr.ID = id
+
+		// The events of the registration are committed along with it
+		return outbox.StorePostgres(ctx, tx)
+	})
+}
+
+func (r *Repository) Update(ctx context.Context, usr *user.User) error {
+	query := `UPDATE "user" SET userId=$1, username=$2, email=$3, password=$4, display_name=$5, bio=$6, website=$7, updated_at=$8 WHERE id=$9`
+	return r.doSave(func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, query,
+			usr.UserID,
+			usr.Username,
+			usr.Email,
+			usr.Password,
+			usr.DisplayName,
+			usr.Bio,
+			usr.Website,
+			usr.UpdatedAt,
+			usr.ID,
+		)
+		return r.checkError(err)
+	})
+}
+
+func (r *Repository) Delete(ctx context.Context, id interface{}) error {
+	query := `UPDATE "user" SET deleted_at = $1 WHERE id = $2 AND deleted_at IS NULL`
+	return r.doSave(func(tx *sql.Tx) error {
+		res, err := tx.ExecContext(ctx, query, time.Now().UTC(), id)
+		if err != nil {
+			return r.checkError(err)
+		}
+
+		affected, err := res.RowsAffected()
+		if err != nil {
+			return r.checkError(err)
+		}
+		if affected == 0 {
+			return user.ErrNotFound
+		}
+		return outbox.StorePostgres(ctx, tx)
+	})
+}
+
+func (r *Repository) Purge(ctx context.Context, id interface{}) error {
+	query := `DELETE FROM "user" WHERE id = $1`
+	return r.doSave(func(tx *sql.Tx) error {
+		res, err := tx.ExecContext(ctx, query, id)
+		if err != nil {
+			return err
+		}
+
+		affected, err := res.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if affected == 0 {
+			return user.ErrNotFound
+		}
+
+		// The records of the user are cleaned up by the handlers
+		// of the events committed along with the deletion
+		return outbox.StorePostgres(ctx, tx)
+	})
+}
+
+func (r *Repository) SoftDelete(ctx context.Context, userID string, at time.Time) error {
+	query := `UPDATE "user" SET deleted_at = $1 WHERE userId = $2`
+	return r.doUpdateByUserID(ctx, query, at, userID)
+}
+
+func (r *Repository) Restore(ctx context.Context, userID string) error {
+	query := `UPDATE "user" SET deleted_at = NULL WHERE userId = $1`
+	return r.doUpdateByUserID(ctx, query, userID)
+}
+
+func (r *Repository) GetAllDeleted(ctx context.Context, before time.Time, num int) ([]*user.User, error) {
+	query := `
+		SELECT
+			` + columns + `
+		FROM
+			"user"
+		WHERE
+			deleted_at IS NOT NULL AND deleted_at < $1
+		ORDER BY
+			deleted_at
+		LIMIT $2`
+	return r.doQuery(ctx, query, before, num)
+}
+
+// doUpdateByUserID runs the update query whose last argument is the
+// user ID, returning user.ErrNotFound when there's no such user.
+func (r *Repository) doUpdateByUserID(ctx context.Context, query string, args ...interface{}) error {
+	return r.doSave(func(tx *sql.Tx) error {
+		res, err := tx.ExecContext(ctx, query, args...)
+		if err != nil {
+			return r.checkError(err)
+		}
+
+		affected, err := res.RowsAffected()
+		if err != nil {
+			return r.checkError(err)
+		}
+		if affected == 0 {
+			return user.ErrNotFound
+		}
+		return nil
+	})
+}
+
+func (r *Repository) GetAll(ctx context.Context, cursor string, num int) (users []*user.User, nextCursor string, err error) {
+	query := `
+		SELECT
+			` + columns + `
+		FROM
+			"user"
+		WHERE
+			created_at > $1 AND ` + softdelete.SQLFilter(ctx) + `
+		ORDER BY
+			created_at
+		LIMIT $2`
+
+	var decodedCursor time.Time
+	if cursor != "" {
+		decodedCursor, err = userutil.DecodeCursor(cursor)
+		if err != nil {
+			return nil, "", err
+		}
+	} else {
+		decodedCursor = time.Now().AddDate(-100, 0, 0)
+	}
+
+	res, err := r.doQuery(ctx, query, decodedCursor, num)
+	if err != nil {
+		return nil, "", err
+	}
+
+	// Generate next pagination cursor
+	if len(res) == num {
+		nextCursor = userutil.EncodeCursor(*res[len(res)-1].CreatedAt)
+	}
+	return res, nextCursor, nil
+}
+
+func (r *Repository) doSave(fn func(tx *sql.Tx) error) (err error) {
+	tx, err := r.conn.Begin()
+	if err != nil {
+		return r.checkError(err)
+	}
+	defer func() {
+		switch err {
+		case nil:
+			err = tx.Commit()
+		default:
+			if e := tx.Rollback(); e != nil {
+				log.WithError(e).Error("failed rolling back transaction")
+			}
+		}
+	}()
+
+	return fn(tx)
+}
+
+func (r *Repository) doQuery(ctx context.Context, query string, args ...interface{}) (users []*user.User, err error) {
+	row, err := r.conn.QueryContext(ctx, query, args...)
+	if err != nil {
+		log.FromContext(ctx).WithError(err).Debug("failed querying")
+		return nil, r.checkError(err)
+	}
+	defer func() {
+		if e := row.Close(); err == nil && e != nil {
+			err = e
+		}
+	}()
+
+	users = make([]*user.User, 0)
+	for row.Next() {
+		var u user.User
+		err = row.Scan(&u.ID, &u.UserID, &u.Username, &u.Email, &u.Password, &u.DisplayName, &u.Bio, &u.Website, &u.CreatedAt, &u.UpdatedAt, &u.DeletedAt)
+		if err != nil {
+			return nil, r.checkError(err)
+		}
+		users = append(users, &u)
+	}
+	if err = row.Err(); err != nil {
+		log.FromContext(ctx).WithError(err).Debug("failed querying")
+		return nil, r.checkError(err)
+	}
+	return users, nil
+}
+
+func (r *Repository) doQuerySingleReturn(ctx context.Context, query string, value interface{}) (u *user.User, err error) {
+	users, err := r.doQuery(ctx, query, value)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(users) == 0 {
+		return nil, user.ErrNotFound
+	}
+
+	return users[0], nil
+}
+
+func (r *Repository) checkError(err error) error {
+	var cerr error
+	switch err {
+	case sql.ErrNoRows:
+		cerr = user.ErrNotFound
+	case nil:
+		cerr = nil
+	default:
+		cerr = duplicateError(err)
+		if cerr == nil {
+			cerr = fmt.Errorf("postgres: unexpected error %w", err)
+		}
+	}
+	return cerr
+}
+
+// errUniqueViolation is the code of the PostgreSQL error raised when
+// a unique index is violated.
+const errUniqueViolation = "23505"
+
+// duplicateError returns the error of the unique index of the user
+// table violated by err, or nil when err is about something else.
+func duplicateError(err error) error {
+	var perr *pq.Error
+	if !errors.As(err, &perr) || perr.Code != errUniqueViolation {
+		return nil
+	}
+	switch perr.Constraint {
+	case "user_username":
+		return user.ErrUserNameExists
+	case "user_email":
+		return user.ErrEmailExists
+	default:
+		return user.ErrUserExists
+	}
+}
@@ -0,0 +1,92 @@
+//+build unit
+
+package postgres
+
+import (
+	"context"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gophr.v2/softdelete"
+	"gophr.v2/user"
+	"gophr.v2/util/valueutil"
+	"regexp"
+	"testing"
+	"time"
+)
+
+var defaultCtx = context.Background()
+
+func TestRepository_Save(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	repo := New(db)
+
+	usr := &user.User{
+		UserID:    "testid123",
+		Username:  "unit.test",
+		Email:     "unit.test@golang.com",
+		Password:  "qwerty",
+		CreatedAt: valueutil.TimePointer(time.Now()),
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "user"(userId, username, email, password, display_name, bio, website, created_at, updated_at) VALUES($1,$2,$3,$4,$5,$6,$7,$8,$9) RETURNING id`)).
+		WithArgs(usr.UserID, usr.Username, usr.Email, usr.Password, usr.DisplayName, usr.Bio, usr.Website, usr.CreatedAt, usr.UpdatedAt).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(42))
+	mock.ExpectCommit()
+
+	require.NoError(t, repo.Save(defaultCtx, usr))
+	assert.Equal(t, uint(42), usr.ID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRepository_GetByUsername(t *testing.T) {
+	columns := []string{"id", "userId", "username", "email", "password", "display_name", "bio", "website", "created_at", "updated_at", "deleted_at"}
+	query := `SELECT id, userId, username, email, password, display_name, bio, website, created_at, updated_at, deleted_at FROM "user" WHERE username = $1 AND `
+
+	t.Run("Found", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		repo := New(db)
+
+		want := &user.User{ID: 1, UserID: "testid123", Username: "unit.test", Email: "unit.test@golang.com", Password: "qwerty"}
+		mock.ExpectQuery(regexp.QuoteMeta(query + "deleted_at IS NULL")).
+			WithArgs("unit.test").
+			WillReturnRows(sqlmock.NewRows(columns).AddRow(want.ID, want.UserID, want.Username, want.Email, want.Password, "", "", "", nil, nil, nil))
+
+		got, err := repo.GetByUsername(defaultCtx, "unit.test")
+		require.NoError(t, err)
+		assert.Equal(t, want, got)
+	})
+
+	t.Run("Include Deleted", func(t *testing.T) {
+		db, mock, err := sqlmock.New()
+		require.NoError(t, err)
+		repo := New(db)
+
+		mock.ExpectQuery(regexp.QuoteMeta(query + "TRUE")).
+			WithArgs("unit.test").
+			WillReturnRows(sqlmock.NewRows(columns))
+
+		_, err = repo.GetByUsername(softdelete.Include(defaultCtx), "unit.test")
+		assert.Equal(t, user.ErrNotFound, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestRepository_Delete(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	repo := New(db)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "user" SET deleted_at = $1 WHERE id = $2 AND deleted_at IS NULL`)).
+		WithArgs(sqlmock.AnyArg(), 1).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+
+	err = repo.Delete(defaultCtx, 1)
+	assert.Equal(t, user.ErrNotFound, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,473 @@
+// Package repositorytest provides the conformance suite of the
+// user.Repository implementations.
+//
+// Each backend runs it from its own tests:
+//
+//	func TestConformance(t *testing.T) {
+//		repositorytest.Run(t, func(t *testing.T) (user.Repository, func()) {
+//			return New(db), func() { truncate(db) }
+//		})
+//	}
+package repositorytest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gophr.v2/softdelete"
+	"gophr.v2/user"
+	"gophr.v2/util/randutil"
+	"sync"
+	"testing"
+	"time"
+)
+
+// Concurrency is the number of goroutines used by the concurrent cases.
+const Concurrency = 10
+
+// Factory creates an empty repository for a single case. The returned
+// func releases it when the case is done.
+type Factory func(t *testing.T) (repo user.Repository, teardown func())
+
+// Option configures the suite.
+type Option func(s *suite)
+
+// WithoutConcurrency skips the cases using a repository from several
+// goroutines at once.
+func WithoutConcurrency() Option {
+	return func(s *suite) {
+		s.concurrency = false
+	}
+}
+
+type suite struct {
+	newRepo     Factory
+	concurrency bool
+}
+
+// Run runs the suite against the repositories created by newRepo.
+func Run(t *testing.T, newRepo Factory, opts ...Option) {
+	s := &suite{newRepo: newRepo, concurrency: true}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	t.Run("Not Found", s.testNotFound)
+	t.Run("Save", s.testSave)
+	t.Run("Update", s.testUpdate)
+	t.Run("Uniqueness", s.testUniqueness)
+	t.Run("Soft Delete", s.testSoftDelete)
+	t.Run("Batch", s.testBatch)
+	t.Run("Pagination", s.testPagination)
+	t.Run("Concurrency", s.testConcurrency)
+	t.Run("Context Cancellation", s.testCancellation)
+}
+
+func (s *suite) testNotFound(t *testing.T) {
+	repo, teardown := s.newRepo(t)
+	defer teardown()
+	ctx := context.Background()
+
+	_, err := repo.GetByID(ctx, uint(404))
+	assert.Equal(t, user.ErrNotFound, err, "GetByID")
+	_, err = repo.GetByUserID(ctx, "unknown")
+	assert.Equal(t, user.ErrNotFound, err, "GetByUserID")
+	_, err = repo.GetByEmail(ctx, "unknown@gophr.com")
+	assert.Equal(t, user.ErrNotFound, err, "GetByEmail")
+	_, err = repo.GetByUsername(ctx, "unknown")
+	assert.Equal(t, user.ErrNotFound, err, "GetByUsername")
+
+	assert.Equal(t, user.ErrNotFound, repo.Delete(ctx, uint(404)), "Delete")
+	assert.Equal(t, user.ErrNotFound, repo.Purge(ctx, uint(404)), "Purge")
+	assert.Equal(t, user.ErrNotFound, repo.SoftDelete(ctx, "unknown", time.Now()), "SoftDelete")
+	assert.Equal(t, user.ErrNotFound, repo.Restore(ctx, "unknown"), "Restore")
+}
+
+func (s *suite) testSave(t *testing.T) {
+	repo, teardown := s.newRepo(t)
+	defer teardown()
+	ctx := context.Background()
+
+	want := NewUser(now())
+	require.NoError(t, repo.Save(ctx, want))
+
+	other := NewUser(now())
+	require.NoError(t, repo.Save(ctx, other))
+	assert.NotEqual(t, want.ID, other.ID, "the IDs are distinct")
+
+	lookups := map[string]func() (*user.User, error){
+		"GetByID":       func() (*user.User, error) { return repo.GetByID(ctx, want.ID) },
+		"GetByUserID":   func() (*user.User, error) { return repo.GetByUserID(ctx, want.UserID) },
+		"GetByEmail":    func() (*user.User, error) { return repo.GetByEmail(ctx, want.Email) },
+		"GetByUsername": func() (*user.User, error) { return repo.GetByUsername(ctx, want.Username) },
+	}
+	for name, lookup := range lookups {
+		got, err := lookup()
+		if assert.NoError(t, err, name) {
+			AssertUser(t, want, got)
+		}
+	}
+
+	t.Run("Returns a copy", func(t *testing.T) {
+		got, err := repo.GetByID(ctx, want.ID)
+		require.NoError(t, err)
+		got.Username = "changed"
+
+		got, err = repo.GetByID(ctx, want.ID)
+		require.NoError(t, err)
+		assert.Equal(t, want.Username, got.Username)
+	})
+}
+
+func (s *suite) testUpdate(t *testing.T) {
+	repo, teardown := s.newRepo(t)
+	defer teardown()
+	ctx := context.Background()
+
+	usr := NewUser(now())
+	require.NoError(t, repo.Save(ctx, usr))
+
+	updatedAt := now().Add(time.Minute)
+	usr.DisplayName = "Straw Hat"
+	usr.Bio = "I'm gonna be the king of the pirates"
+	usr.UpdatedAt = &updatedAt
+	require.NoError(t, repo.Update(ctx, usr))
+
+	got, err := repo.GetByID(ctx, usr.ID)
+	require.NoError(t, err)
+	AssertUser(t, usr, got)
+}
+
+func (s *suite) testUniqueness(t *testing.T) {
+	repo, teardown := s.newRepo(t)
+	defer teardown()
+	ctx := context.Background()
+
+	usr := NewUser(now())
+	require.NoError(t, repo.Save(ctx, usr))
+
+	t.Run("Username", func(t *testing.T) {
+		dup := NewUser(now())
+		dup.Username = usr.Username
+		assert.Equal(t, user.ErrUserNameExists, repo.Save(ctx, dup))
+	})
+
+	t.Run("Email", func(t *testing.T) {
+		dup := NewUser(now())
+		dup.Email = usr.Email
+		assert.Equal(t, user.ErrEmailExists, repo.Save(ctx, dup))
+	})
+
+	t.Run("Soft deleted users keep their username and email", func(t *testing.T) {
+		require.NoError(t, repo.Delete(ctx, usr.ID))
+
+		dup := NewUser(now())
+		dup.Username = usr.Username
+		assert.Equal(t, user.ErrUserNameExists, repo.Save(ctx, dup))
+
+		dup = NewUser(now())
+		dup.Email = usr.Email
+		assert.Equal(t, user.ErrEmailExists, repo.Save(ctx, dup))
+	})
+
+	t.Run("Purged users release them", func(t *testing.T) {
+		require.NoError(t, repo.Purge(ctx, usr.ID))
+
+		reuse := NewUser(now())
+		reuse.Username, reuse.Email = usr.Username, usr.Email
+		assert.NoError(t, repo.Save(ctx, reuse))
+	})
+}
+
+func (s *suite) testSoftDelete(t *testing.T) {
+	repo, teardown := s.newRepo(t)
+	defer teardown()
+	ctx := context.Background()
+
+	usr := NewUser(now())
+	require.NoError(t, repo.Save(ctx, usr))
+	require.NoError(t, repo.Delete(ctx, usr.ID))
+
+	_, err := repo.GetByID(ctx, usr.ID)
+	assert.Equal(t, user.ErrNotFound, err, "deleted users are left out")
+	assert.Equal(t, user.ErrNotFound, repo.Delete(ctx, usr.ID), "deleting twice")
+
+	got, err := repo.GetByID(softdelete.Include(ctx), usr.ID)
+	require.NoError(t, err)
+	assert.True(t, got.IsDeleted())
+
+	require.NoError(t, repo.Restore(ctx, usr.UserID))
+	got, err = repo.GetByID(ctx, usr.ID)
+	require.NoError(t, err)
+	assert.False(t, got.IsDeleted())
+
+	require.NoError(t, repo.Purge(ctx, usr.ID))
+	_, err = repo.GetByID(softdelete.Include(ctx), usr.ID)
+	assert.Equal(t, user.ErrNotFound, err, "purged users are gone")
+}
+
+func (s *suite) testBatch(t *testing.T) {
+	repo, teardown := s.newRepo(t)
+	defer teardown()
+	ctx := context.Background()
+
+	users := make([]*user.User, 3)
+	for i := range users {
+		users[i] = NewUser(now())
+		require.NoError(t, repo.Save(ctx, users[i]))
+	}
+	deleted := NewUser(now())
+	require.NoError(t, repo.Save(ctx, deleted))
+	require.NoError(t, repo.Delete(ctx, deleted.ID))
+
+	got, err := repo.GetByUserIDs(ctx, []string{})
+	require.NoError(t, err)
+	assert.Empty(t, got, "no IDs")
+
+	ids := []string{users[2].UserID, "unknown", users[0].UserID, deleted.UserID, users[2].UserID}
+	got, err = repo.GetByUserIDs(ctx, ids)
+	require.NoError(t, err)
+	AssertUsers(t, []*user.User{users[2], users[0]}, got, "in order, once each, without the missing and deleted users")
+	if assert.Len(t, got, 2) {
+		AssertUser(t, users[2], got[0])
+	}
+
+	got, err = repo.GetByUserIDs(softdelete.Include(ctx), ids)
+	require.NoError(t, err)
+	AssertUsers(t, []*user.User{users[2], users[0], deleted}, got, "with the deleted users included")
+
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	_, err = repo.GetByUserIDs(canceled, ids)
+	assertCanceled(t, err, "GetByUserIDs")
+}
+
+func (s *suite) testPagination(t *testing.T) {
+	repo, teardown := s.newRepo(t)
+	defer teardown()
+	ctx := context.Background()
+
+	// The users are saved out of order to check the sorting
+	start := now().Add(-time.Hour)
+	users := make([]*user.User, 5)
+	for _, i := range []int{3, 0, 4, 1, 2} {
+		users[i] = NewUser(start.Add(time.Duration(i) * time.Second))
+		require.NoError(t, repo.Save(ctx, users[i]))
+	}
+
+	t.Run("GetAll", func(t *testing.T) {
+		var got []*user.User
+		var cursor string
+		for page := 0; page < len(users); page++ {
+			res, next, err := repo.GetAll(ctx, cursor, 2)
+			require.NoError(t, err)
+			got = append(got, res...)
+			if next == "" {
+				break
+			}
+			cursor = next
+		}
+		AssertUsers(t, users, got)
+	})
+
+	t.Run("GetAll leaves the deleted users out", func(t *testing.T) {
+		require.NoError(t, repo.Delete(ctx, users[0].ID))
+		defer func() {
+			require.NoError(t, repo.Restore(ctx, users[0].UserID))
+		}()
+
+		got, _, err := repo.GetAll(ctx, "", len(users))
+		require.NoError(t, err)
+		AssertUsers(t, users[1:], got)
+	})
+
+	t.Run("GetAllDeleted", func(t *testing.T) {
+		for i, usr := range users {
+			require.NoError(t, repo.SoftDelete(ctx, usr.UserID, start.Add(time.Duration(len(users)-i)*time.Minute)))
+		}
+
+		got, err := repo.GetAllDeleted(ctx, now(), 2)
+		require.NoError(t, err)
+		AssertUsers(t, []*user.User{users[4], users[3]}, got)
+
+		got, err = repo.GetAllDeleted(ctx, start.Add(3*time.Minute), len(users))
+		require.NoError(t, err)
+		AssertUsers(t, []*user.User{users[4], users[3]}, got, "only the users deleted before the time")
+	})
+}
+
+func (s *suite) testConcurrency(t *testing.T) {
+	if !s.concurrency {
+		t.Skip("The repository isn't safe for concurrent use")
+	}
+	repo, teardown := s.newRepo(t)
+	defer teardown()
+	ctx := context.Background()
+
+	t.Run("Save", func(t *testing.T) {
+		users := make([]*user.User, Concurrency)
+		errs := make([]error, Concurrency)
+		var wg sync.WaitGroup
+		for i := range users {
+			users[i] = NewUser(now().Add(time.Duration(i) * time.Second))
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				errs[i] = repo.Save(ctx, users[i])
+			}(i)
+		}
+		wg.Wait()
+
+		ids := make(map[uint]bool)
+		for i, usr := range users {
+			require.NoError(t, errs[i])
+			assert.False(t, ids[usr.ID], "the IDs are distinct")
+			ids[usr.ID] = true
+		}
+
+		got, _, err := repo.GetAll(ctx, "", 2*Concurrency)
+		require.NoError(t, err)
+		AssertUsers(t, users, got)
+	})
+
+	t.Run("Save the same username", func(t *testing.T) {
+		username := "sanji" + uniq()
+		errs := make(chan error, Concurrency)
+		for i := 0; i < Concurrency; i++ {
+			go func() {
+				usr := NewUser(now())
+				usr.Username = username
+				errs <- repo.Save(ctx, usr)
+			}()
+		}
+
+		var saved int
+		for i := 0; i < Concurrency; i++ {
+			switch err := <-errs; err {
+			case nil:
+				saved++
+			default:
+				assert.Equal(t, user.ErrUserNameExists, err)
+			}
+		}
+		assert.Equal(t, 1, saved, "only one user gets the username")
+	})
+
+	t.Run("Read while writing", func(t *testing.T) {
+		usr := NewUser(now())
+		require.NoError(t, repo.Save(ctx, usr))
+
+		var wg sync.WaitGroup
+		for i := 0; i < Concurrency; i++ {
+			wg.Add(2)
+			go func() {
+				defer wg.Done()
+				_, err := repo.GetByUserID(ctx, usr.UserID)
+				assert.NoError(t, err)
+			}()
+			go func() {
+				defer wg.Done()
+				assert.NoError(t, repo.Save(ctx, NewUser(now())))
+			}()
+		}
+		wg.Wait()
+	})
+}
+
+func (s *suite) testCancellation(t *testing.T) {
+	repo, teardown := s.newRepo(t)
+	defer teardown()
+
+	usr := NewUser(now())
+	require.NoError(t, repo.Save(context.Background(), usr))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := repo.GetByUserID(ctx, usr.UserID)
+	assertCanceled(t, err, "GetByUserID")
+	_, _, err = repo.GetAll(ctx, "", 10)
+	assertCanceled(t, err, "GetAll")
+	_, err = repo.GetAllDeleted(ctx, now(), 10)
+	assertCanceled(t, err, "GetAllDeleted")
+
+	unsaved := NewUser(now())
+	assertCanceled(t, repo.Save(ctx, unsaved), "Save")
+	_, err = repo.GetByUserID(context.Background(), unsaved.UserID)
+	assert.Equal(t, user.ErrNotFound, err, "canceled saves are not stored")
+
+	assertCanceled(t, repo.Delete(ctx, usr.ID), "Delete")
+	_, err = repo.GetByUserID(context.Background(), usr.UserID)
+	assert.NoError(t, err, "canceled deletes are not stored")
+}
+
+func assertCanceled(t *testing.T, err error, op string) {
+	t.Helper()
+	assert.True(t, errors.Is(err, context.Canceled), "%s: want context.Canceled, got %v", op, err)
+}
+
+// NewUser creates a user with a unique user ID, username and email
+// created at the given time.
+func NewUser(createdAt time.Time) *user.User {
+	id := uniq()
+	return &user.User{
+		UserID:      randutil.GenerateID("user"),
+		Username:    "luffy" + id,
+		Email:       fmt.Sprintf("luffy%s@gophr.com", id),
+		Password:    "Pirate-King1",
+		DisplayName: "Luffy",
+		CreatedAt:   &createdAt,
+		UpdatedAt:   &createdAt,
+	}
+}
+
+// AssertUser asserts that got is the stored want. The times are
+// compared by instant since the location depends on the backend.
+func AssertUser(t *testing.T, want, got *user.User) {
+	t.Helper()
+	assert.Equal(t, want.ID, got.ID)
+	assert.Equal(t, want.UserID, got.UserID)
+	assert.Equal(t, want.Username, got.Username)
+	assert.Equal(t, want.Email, got.Email)
+	assert.Equal(t, want.Password, got.Password)
+	assert.Equal(t, want.DisplayName, got.DisplayName)
+	assert.Equal(t, want.Bio, got.Bio)
+	assert.Equal(t, want.Website, got.Website)
+	assertTime(t, want.CreatedAt, got.CreatedAt, "CreatedAt")
+	assertTime(t, want.UpdatedAt, got.UpdatedAt, "UpdatedAt")
+}
+
+// AssertUsers asserts that got are the stored want in the same order.
+func AssertUsers(t *testing.T, want, got []*user.User, msgAndArgs ...interface{}) {
+	t.Helper()
+	wantIDs := make([]string, len(want))
+	for i, usr := range want {
+		wantIDs[i] = usr.UserID
+	}
+	gotIDs := make([]string, len(got))
+	for i, usr := range got {
+		gotIDs[i] = usr.UserID
+	}
+	assert.Equal(t, wantIDs, gotIDs, msgAndArgs...)
+}
+
+func assertTime(t *testing.T, want, got *time.Time, field string) {
+	t.Helper()
+	if want == nil || got == nil {
+		assert.Equal(t, want == nil, got == nil, field)
+		return
+	}
+	assert.True(t, want.Equal(*got), "%s: want %v, got %v", field, *want, *got)
+}
+
+// now returns the current time at the precision of the SQL datetimes.
+func now() time.Time {
+	return time.Now().UTC().Truncate(time.Second)
+}
+
+// uniq returns a short unique suffix fitting in the user columns.
+func uniq() string {
+	return randutil.GenerateID("suffix")[:12]
+}
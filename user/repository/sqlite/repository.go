@@ -0,0 +1,329 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"gophr.v2/event/outbox"
+	"gophr.v2/log"
+	"gophr.v2/softdelete"
+	"gophr.v2/user"
+	"gophr.v2/user/userutil"
+	"strings"
+	"time"
+)
+
+var _ user.Repository = (*Repository)(nil)
+
+func New(conn *sql.DB) *Repository {
+	return &Repository{conn: conn}
+}
+
+type Repository struct {
+	conn *sql.DB
+}
+
+func (r *Repository) GetByUserID(ctx context.Context, userID string) (u *user.User, err error) {
+	query := "SELECT id,userId,username,email,password,display_name,bio,website,created_at,updated_at,deleted_at FROM user WHERE userId = ? AND " + softdelete.SQLFilter(ctx)
+	return r.doQuerySingleReturn(ctx, query, userID)
+}
+
+// GetByUserIDs finds the users with a single query.
+func (r *Repository) GetByUserIDs(ctx context.Context, userIDs []string) ([]*user.User, error) {
+	if len(userIDs) == 0 {
+		return []*user.User{}, nil
+	}
+
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(userIDs)), ",")
+	query := "SELECT id,userId,username,email,password,display_name,bio,website,created_at,updated_at,deleted_at FROM user WHERE userId IN (" + placeholders + ") AND " + softdelete.SQLFilter(ctx)
+
+	args := make([]interface{}, 0, len(userIDs))
+	for _, userID := range userIDs {
+		args = append(args, userID)
+	}
+	users, err := r.doQuery(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	return userutil.OrderByUserIDs(users, userIDs), nil
+}
+
+func (r *Repository) GetByID(ctx context.Context, id interface{}) (u *user.User, err error) {
+	query := "SELECT id,userId,username,email,password,display_name,bio,website,created_at,updated_at,deleted_at FROM user WHERE id = ? AND " + softdelete.SQLFilter(ctx)
+	return r.doQuerySingleReturn(ctx, query, id)
+}
+func (r *Repository) GetByEmail(ctx context.Context, email string) (u *user.User, err error) {
+	query := "SELECT id,userId,username,email,password,display_name,bio,website,created_at,updated_at,deleted_at FROM user WHERE email = ? AND " + softdelete.SQLFilter(ctx)
+	return r.doQuerySingleReturn(ctx, query, email)
+}
+func (r *Repository) GetByUsername(ctx context.Context, uname string) (*user.User, error) {
+	query := "SELECT id,userId,username,email,password,display_name,bio,website,created_at,updated_at,deleted_at FROM user WHERE username = ? AND " + softdelete.SQLFilter(ctx)
+	return r.doQuerySingleReturn(ctx, query, uname)
+}
+func (r *Repository) Save(ctx context.Context, usr *user.User) (err error) {
+	query := "INSERT INTO user(userId, username, email, password, display_name, bio, website, created_at, updated_at) VALUES(?,?,?,?,?,?,?,?,?)"
+	return r.doSave(func(tx *sql.Tx) error {
+		res, err := tx.ExecContext(ctx, query,
+			usr.UserID,
+			usr.Username,
+			usr.Email,
+			usr.Password,
+			usr.DisplayName,
+			usr.Bio,
+			usr.Website,
+			utc(usr.CreatedAt),
+			utc(usr.UpdatedAt),
+		)
+		if err != nil {
+			return r.checkError(err)
+		}
+
+		id, err := res.LastInsertId()
+		if err != nil {
+			return r.checkError(err)
+		}
+
+		usr.ID = uint(id)
+
+		// The events of the registration are committed along with it
+		return outbox.Store(ctx, tx)
+	})
+}
+func (r *Repository) Update(ctx context.Context, usr *user.User) error {
+	query := "UPDATE user SET userId=?, username=?, email=?, password=?, display_name=?, bio=?, website=?, updated_at=? WHERE id=?"
+	return r.doSave(func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, query,
+			usr.UserID,
+			usr.Username,
+			usr.Email,
+			usr.Password,
+			usr.DisplayName,
+			usr.Bio,
+			usr.Website,
+			utc(usr.UpdatedAt),
+			usr.ID,
+		)
+		return r.checkError(err)
+	})
+}
+func (r *Repository) Delete(ctx context.Context, id interface{}) error {
+	query := "UPDATE user SET deleted_at = ? WHERE id = ? AND deleted_at IS NULL"
+	return r.doSave(func(tx *sql.Tx) error {
+		res, err := tx.ExecContext(ctx, query, time.Now().UTC(), id)
+		if err != nil {
+			return r.checkError(err)
+		}
+
+		affected, err := res.RowsAffected()
+		if err != nil {
+			return r.checkError(err)
+		}
+		if affected == 0 {
+			return user.ErrNotFound
+		}
+		return outbox.Store(ctx, tx)
+	})
+}
+
+func (r *Repository) Purge(ctx context.Context, id interface{}) error {
+	query := "DELETE FROM user WHERE id = ?"
+	return r.doSave(func(tx *sql.Tx) error {
+		res, err := tx.ExecContext(ctx, query, id)
+		if err != nil {
+			return err
+		}
+
+		affected, err := res.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if affected == 0 {
+			return user.ErrNotFound
+		}
+
+		// The records of the user are cleaned up by the handlers
+		// of the events committed along with the deletion
+		return outbox.Store(ctx, tx)
+	})
+}
+
+func (r *Repository) SoftDelete(ctx context.Context, userID string, at time.Time) error {
+	query := "UPDATE user SET deleted_at = ? WHERE userId = ?"
+	return r.doUpdateByUserID(ctx, query, at.UTC(), userID)
+}
+
+func (r *Repository) Restore(ctx context.Context, userID string) error {
+	query := "UPDATE user SET deleted_at = NULL WHERE userId = ?"
+	return r.doUpdateByUserID(ctx, query, userID)
+}
+
+func (r *Repository) GetAllDeleted(ctx context.Context, before time.Time, num int) ([]*user.User, error) {
+	query := `
+		SELECT
+			id, userId, username, email, password, display_name, bio, website, created_at, updated_at, deleted_at
+		FROM
+			user
+		WHERE
+			deleted_at IS NOT NULL AND deleted_at < ?
+		ORDER BY
+			deleted_at
+		LIMIT ?`
+	return r.doQuery(ctx, query, before.UTC(), num)
+}
+
+// doUpdateByUserID runs the update query whose last argument is the
+// user ID, returning user.ErrNotFound when there's no such user.
+func (r *Repository) doUpdateByUserID(ctx context.Context, query string, args ...interface{}) error {
+	return r.doSave(func(tx *sql.Tx) error {
+		res, err := tx.ExecContext(ctx, query, args...)
+		if err != nil {
+			return r.checkError(err)
+		}
+
+		affected, err := res.RowsAffected()
+		if err != nil {
+			return r.checkError(err)
+		}
+		if affected == 0 {
+			return user.ErrNotFound
+		}
+		return nil
+	})
+}
+func (r *Repository) GetAll(ctx context.Context, cursor string, num int) (users []*user.User, nextCursor string, err error) {
+	query := `
+		SELECT
+			id, userId, username, email, password, display_name, bio, website, created_at, updated_at, deleted_at
+		FROM
+			user
+		WHERE
+			created_at > ? AND ` + softdelete.SQLFilter(ctx) + `
+		ORDER BY
+			created_at
+		LIMIT ?`
+
+	var decodedCursor time.Time
+	if cursor != "" {
+		decodedCursor, err = userutil.DecodeCursor(cursor)
+		if err != nil {
+			return nil, "", err
+		}
+	} else {
+		decodedCursor = time.Now().AddDate(-100, 0, 0)
+	}
+
+	res, err := r.doQuery(ctx, query, decodedCursor.UTC(), num)
+	if err != nil {
+		return nil, "", err
+	}
+
+	// Generate next pagination cursor
+	if len(res) == int(num) {
+		nextCursor = userutil.EncodeCursor(*res[len(res)-1].CreatedAt)
+	}
+
+	log.FromContext(ctx).With(log.Fields{"cursor": decodedCursor, "nextCursor": nextCursor, "count": len(res)}).Debug("listed users")
+	return res, nextCursor, nil
+}
+
+// utc returns t in UTC so that the times stored compare correctly.
+func utc(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	u := t.UTC()
+	return &u
+}
+
+func (r *Repository) doSave(fn func(tx *sql.Tx) error) (err error) {
+	tx, err := r.conn.Begin()
+	if err != nil {
+		return r.checkError(err)
+	}
+	defer func() {
+		switch err {
+		case nil:
+			err = tx.Commit()
+		default:
+			if e := tx.Rollback(); e != nil {
+				log.WithError(e).Error("failed rolling back transaction")
+			}
+		}
+	}()
+
+	return fn(tx)
+}
+func (r *Repository) doQuery(ctx context.Context, query string, args ...interface{}) (users []*user.User, err error) {
+	row, err := r.conn.QueryContext(ctx, query, args...)
+	if err != nil {
+		log.FromContext(ctx).WithError(err).Debug("failed querying")
+		return nil, r.checkError(err)
+	}
+	defer func() {
+		if e := row.Close(); err == nil && e != nil {
+			err = e
+		}
+	}()
+
+	users = make([]*user.User, 0)
+	for row.Next() {
+		var u user.User
+		err = row.Scan(&u.ID, &u.UserID, &u.Username, &u.Email, &u.Password, &u.DisplayName, &u.Bio, &u.Website, &u.CreatedAt, &u.UpdatedAt, &u.DeletedAt)
+		if err != nil {
+			return nil, r.checkError(err)
+		}
+		users = append(users, &u)
+	}
+	if err = row.Err(); err != nil {
+		log.FromContext(ctx).WithError(err).Debug("failed querying")
+		return nil, r.checkError(err)
+	}
+	return users, nil
+}
+func (r *Repository) doQuerySingleReturn(ctx context.Context, query string, value interface{}) (u *user.User, err error) {
+	users, err := r.doQuery(ctx, query, value)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(users) == 0 {
+		return nil, user.ErrNotFound
+	}
+
+	return users[0], nil
+}
+func (r *Repository) checkError(err error) error {
+	var cerr error
+	switch err {
+	case sql.ErrNoRows:
+		cerr = user.ErrNotFound
+	case nil:
+		cerr = nil
+	default:
+		cerr = duplicateError(err)
+		if cerr == nil {
+			cerr = fmt.Errorf("sqlite: unexpected error %w", err)
+		}
+	}
+	return cerr
+}
+
+// errUniqueViolation starts the message of the SQLite error raised
+// when a unique index is violated. It's followed by the columns.
+const errUniqueViolation = "UNIQUE constraint failed: "
+
+// duplicateError returns the error of the unique index of the user
+// table violated by err, or nil when err is about something else.
+func duplicateError(err error) error {
+	msg := err.Error()
+	switch {
+	case !strings.Contains(msg, errUniqueViolation):
+		return nil
+	case strings.Contains(msg, errUniqueViolation+"user.username"):
+		return user.ErrUserNameExists
+	case strings.Contains(msg, errUniqueViolation+"user.email"):
+		return user.ErrEmailExists
+	default:
+		return user.ErrUserExists
+	}
+}
@@ -8,6 +8,9 @@ import (
 
 type Service interface {
 	GetterByUserID
+	// GetByUserIDs returns the users with userIDs in the same order
+	// along with the IDs of the missing users.
+	GetByUserIDs(ctx context.Context, userIDs []string) (users []*User, missing []string, err error)
 	GetByID(ctx context.Context, id interface{}) (*User, error)
 	GetByEmail(ctx context.Context, email string) (*User, error)
 	GetByUsername(ctx context.Context, uname string) (*User, error)
@@ -17,6 +20,14 @@ type Service interface {
 	Update(ctx context.Context, user *User) error
 	Register(ctx context.Context, user *User) error
 	Login(ctx context.Context, user *User) error
+	ChangePassword(ctx context.Context, userID, currentPassword, newPassword string) error
+	// DeleteAccount schedules the deletion of the account with userID
+	// once password is confirmed. The account can be restored until
+	// its grace period is over.
+	DeleteAccount(ctx context.Context, userID, password string) error
+	// RestoreAccount cancels the deletion of the account identified
+	// by the credentials of user, like Login.
+	RestoreAccount(ctx context.Context, user *User) error
 }
 
 type GetterByUserID interface {
@@ -0,0 +1,362 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"golang.org/x/sync/singleflight"
+	"gophr.v2/event"
+	"gophr.v2/log"
+	"gophr.v2/softdelete"
+	"gophr.v2/user"
+	"gophr.v2/user/userutil"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultTTL is how long a looked up user is cached when no TTL
+// is given.
+const DefaultTTL = 5 * time.Minute
+
+// loadTimeout bounds the lookups shared by the concurrent misses,
+// which outlive the request of the caller that started them.
+const loadTimeout = 10 * time.Second
+
+// Keys of the entries. A user is stored under its user ID, and its
+// username and email only point to that ID so that there's a single
+// entry to invalidate.
+const (
+	userIDPrefix   = "user:id:"
+	usernamePrefix = "user:username:"
+	emailPrefix    = "user:email:"
+)
+
+type Option func(*Decorator)
+
+// WithTTL sets how long a looked up user is cached.
+func WithTTL(ttl time.Duration) Option {
+	return func(d *Decorator) {
+		d.ttl = ttl
+	}
+}
+
+// New creates a decorator caching the user lookups in c.
+func New(c user.Cache, opts ...Option) *Decorator {
+	d := &Decorator{
+		cache: c,
+		ttl:   DefaultTTL,
+	}
+	for _, opt := range opts {
+		opt(d)
+	}
+	return d
+}
+
+// Decorator reads the users through a cache. The lookups by user
+// ID, username and email are served from the cache, and the
+// concurrent misses for the same key are loaded only once. The
+// entries of a user are invalidated when it changes through the
+// decorated service or gets purged.
+//
+// The cached users never include their password, and the missing
+// or soft deleted users are never cached.
+type Decorator struct {
+	cache  user.Cache
+	ttl    time.Duration
+	group  singleflight.Group
+	hits   uint64
+	misses uint64
+}
+
+// Stats are the counts of the lookups served by the cache.
+type Stats struct {
+	Hits   uint64
+	Misses uint64
+}
+
+// Stats returns the counts of the lookups so far.
+func (d *Decorator) Stats() Stats {
+	return Stats{
+		Hits:   atomic.LoadUint64(&d.hits),
+		Misses: atomic.LoadUint64(&d.misses),
+	}
+}
+
+// Apply decorates svc. It is a user.Decorator.
+func (d *Decorator) Apply(svc user.Service) user.Service {
+	return &cachingDecorator{Service: svc, d: d}
+}
+
+// Subscribe invalidates the purged users.
+func (d *Decorator) Subscribe(sub event.Subscriber) {
+	sub.Subscribe(user.EventPurged, d.handleEvent)
+}
+
+func (d *Decorator) handleEvent(ctx context.Context, e event.Event) error {
+	purged, ok := e.(*user.Purged)
+	if !ok {
+		return nil
+	}
+	d.invalidate(ctx, purged.UserID)
+	return nil
+}
+
+// get returns the cached user with userID.
+func (d *Decorator) get(ctx context.Context, userID string) (*user.User, bool) {
+	payload, ok := d.lookup(ctx, userIDPrefix+userID)
+	if !ok {
+		return nil, false
+	}
+
+	usr := new(user.User)
+	if err := json.Unmarshal(payload, usr); err != nil {
+		log.FromContext(ctx).WithError(err).Error("user cache: failed decoding user")
+		return nil, false
+	}
+	return usr, true
+}
+
+// resolve returns the cached user which key points to, as long as
+// it still matches the key.
+func (d *Decorator) resolve(ctx context.Context, key string, matches func(usr *user.User) bool) (*user.User, bool) {
+	userID, ok := d.lookup(ctx, key)
+	if !ok {
+		return nil, false
+	}
+	// The user may have changed its username or email since
+	usr, ok := d.get(ctx, string(userID))
+	if !ok || !matches(usr) {
+		return nil, false
+	}
+	return usr, true
+}
+
+// lookup returns the entry under key. The cache failing is a miss
+// so that the lookups still work when it's down.
+func (d *Decorator) lookup(ctx context.Context, key string) ([]byte, bool) {
+	payload, err := d.cache.Get(ctx, key)
+	if err != nil {
+		if err != user.ErrCacheMiss {
+			log.FromContext(ctx).WithError(err).WithField("key", key).Error("user cache: failed getting")
+		}
+		return nil, false
+	}
+	return payload, true
+}
+
+// set caches usr along with the keys pointing to it.
+func (d *Decorator) set(ctx context.Context, usr *user.User) {
+	payload, err := json.Marshal(usr)
+	if err != nil {
+		log.FromContext(ctx).WithError(err).Error("user cache: failed encoding user")
+		return
+	}
+
+	entries := []struct {
+		key   string
+		value []byte
+	}{
+		{userIDPrefix + usr.UserID, payload},
+		{usernamePrefix + usr.Username, []byte(usr.UserID)},
+		{emailPrefix + usr.Email, []byte(usr.UserID)},
+	}
+	for _, e := range entries {
+		if err := d.cache.Set(ctx, e.key, e.value, d.ttl); err != nil {
+			log.FromContext(ctx).WithError(err).WithField("key", e.key).Error("user cache: failed setting")
+			return
+		}
+	}
+}
+
+// invalidate removes the cached user with userID. The keys still
+// pointing to it are ignored by resolve once the user is cached
+// again with another username or email.
+func (d *Decorator) invalidate(ctx context.Context, userID string) {
+	if userID == "" {
+		return
+	}
+	if err := d.cache.Delete(ctx, userIDPrefix+userID); err != nil {
+		log.FromContext(ctx).WithError(err).WithField(log.UserIDKey, userID).Error("user cache: failed invalidating")
+	}
+}
+
+// load returns the user loaded by fn on a cache miss, sharing a
+// single call among the concurrent misses of the same key. The call
+// is detached from ctx so that a caller giving up doesn't fail the
+// others, the callers giving up return right away though.
+func (d *Decorator) load(ctx context.Context, key string, fn func(ctx context.Context) (*user.User, error)) (*user.User, error) {
+	atomic.AddUint64(&d.misses, 1)
+	ch := d.group.DoChan(key, func() (interface{}, error) {
+		ctx, cancel := context.WithTimeout(detached{ctx}, loadTimeout)
+		defer cancel()
+		usr, err := fn(ctx)
+		if err != nil {
+			return nil, err
+		}
+		usr = withoutPassword(usr)
+		d.set(ctx, usr)
+		return usr, nil
+	})
+
+	select {
+	case res := <-ch:
+		if res.Err != nil {
+			return nil, res.Err
+		}
+		// Every caller gets its own copy of the shared user
+		return res.Val.(*user.User).Clone(), nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// detached is a context with the values of its parent, like the
+// logger and the span, but without its deadline and cancelation.
+type detached struct {
+	parent context.Context
+}
+
+func (detached) Deadline() (time.Time, bool)         { return time.Time{}, false }
+func (detached) Done() <-chan struct{}               { return nil }
+func (detached) Err() error                          { return nil }
+func (d detached) Value(key interface{}) interface{} { return d.parent.Value(key) }
+
+// withoutPassword returns a copy of usr without its password so that
+// the users come the same on a hit or a miss.
+func withoutPassword(usr *user.User) *user.User {
+	cpy := usr.Clone()
+	cpy.Password = ""
+	return cpy
+}
+
+func (d *Decorator) hit(usr *user.User) (*user.User, error) {
+	atomic.AddUint64(&d.hits, 1)
+	return usr, nil
+}
+
+// cachingDecorator serves the lookups of a service from the cache
+// of d. The methods it doesn't override go to the service as is.
+type cachingDecorator struct {
+	user.Service
+	d *Decorator
+}
+
+func (c *cachingDecorator) GetByUserID(ctx context.Context, userID string) (*user.User, error) {
+	// The soft deleted users aren't cached
+	if softdelete.Included(ctx) {
+		return c.Service.GetByUserID(ctx, userID)
+	}
+	if usr, ok := c.d.get(ctx, userID); ok {
+		return c.d.hit(usr)
+	}
+	return c.d.load(ctx, userIDPrefix+userID, func(ctx context.Context) (*user.User, error) {
+		return c.Service.GetByUserID(ctx, userID)
+	})
+}
+
+// GetByUserIDs serves the cached users and looks all the others up
+// at once.
+func (c *cachingDecorator) GetByUserIDs(ctx context.Context, userIDs []string) ([]*user.User, []string, error) {
+	if softdelete.Included(ctx) {
+		return c.Service.GetByUserIDs(ctx, userIDs)
+	}
+
+	users := make([]*user.User, 0, len(userIDs))
+	var uncached []string
+	for _, id := range userIDs {
+		if usr, ok := c.d.get(ctx, id); ok {
+			atomic.AddUint64(&c.d.hits, 1)
+			users = append(users, usr)
+			continue
+		}
+		uncached = append(uncached, id)
+	}
+
+	if len(uncached) > 0 {
+		atomic.AddUint64(&c.d.misses, uint64(len(uncached)))
+		loaded, _, err := c.Service.GetByUserIDs(ctx, uncached)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, usr := range loaded {
+			usr = withoutPassword(usr)
+			c.d.set(ctx, usr)
+			users = append(users, usr)
+		}
+	}
+
+	users = userutil.OrderByUserIDs(users, userIDs)
+	return users, userutil.MissingUserIDs(users, userIDs), nil
+}
+
+func (c *cachingDecorator) GetByUsername(ctx context.Context, uname string) (*user.User, error) {
+	if softdelete.Included(ctx) {
+		return c.Service.GetByUsername(ctx, uname)
+	}
+	key := usernamePrefix + uname
+	usr, ok := c.d.resolve(ctx, key, func(usr *user.User) bool {
+		return usr.Username == uname
+	})
+	if ok {
+		return c.d.hit(usr)
+	}
+	return c.d.load(ctx, key, func(ctx context.Context) (*user.User, error) {
+		return c.Service.GetByUsername(ctx, uname)
+	})
+}
+
+func (c *cachingDecorator) GetByEmail(ctx context.Context, email string) (*user.User, error) {
+	if softdelete.Included(ctx) {
+		return c.Service.GetByEmail(ctx, email)
+	}
+	key := emailPrefix + email
+	usr, ok := c.d.resolve(ctx, key, func(usr *user.User) bool {
+		return usr.Email == email
+	})
+	if ok {
+		return c.d.hit(usr)
+	}
+	return c.d.load(ctx, key, func(ctx context.Context) (*user.User, error) {
+		return c.Service.GetByEmail(ctx, email)
+	})
+}
+
+// The writes invalidate the user even when they fail since the
+// change may have been applied before the failure.
+
+func (c *cachingDecorator) Update(ctx context.Context, usr *user.User) error {
+	defer c.d.invalidate(ctx, usr.UserID)
+	return c.Service.Update(ctx, usr)
+}
+
+// Delete finds out the user ID of the deleted user since the
+// entries aren't keyed by ID.
+func (c *cachingDecorator) Delete(ctx context.Context, id interface{}) error {
+	if err := c.Service.Delete(ctx, id); err != nil {
+		return err
+	}
+	usr, err := c.Service.GetByID(softdelete.Include(ctx), id)
+	if err != nil {
+		log.FromContext(ctx).WithError(err).Error("user cache: failed finding deleted user")
+		return nil
+	}
+	c.d.invalidate(ctx, usr.UserID)
+	return nil
+}
+
+func (c *cachingDecorator) ChangePassword(ctx context.Context, userID, currentPassword, newPassword string) error {
+	defer c.d.invalidate(ctx, userID)
+	return c.Service.ChangePassword(ctx, userID, currentPassword, newPassword)
+}
+
+func (c *cachingDecorator) DeleteAccount(ctx context.Context, userID, password string) error {
+	defer c.d.invalidate(ctx, userID)
+	return c.Service.DeleteAccount(ctx, userID, password)
+}
+
+func (c *cachingDecorator) RestoreAccount(ctx context.Context, usr *user.User) error {
+	if err := c.Service.RestoreAccount(ctx, usr); err != nil {
+		return err
+	}
+	c.d.invalidate(ctx, usr.UserID)
+	return nil
+}
@@ -0,0 +1,306 @@
+//+build unit
+
+package cache
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gophr.v2/event/bus"
+	"gophr.v2/softdelete"
+	"gophr.v2/user"
+	"gophr.v2/user/cache/lru"
+	"gophr.v2/user/mocks"
+	"sync"
+	"testing"
+	"time"
+)
+
+func newUser() *user.User {
+	return &user.User{
+		ID:       1,
+		UserID:   "luffy-id",
+		Username: "luffy",
+		Email:    "luffy@gophr.com",
+		Password: "hashed",
+	}
+}
+
+func setup() (*Decorator, *mocks.Service, user.Service) {
+	svc := new(mocks.Service)
+	d := New(lru.New(100))
+	return d, svc, user.ApplyDecorators(svc, d.Apply)
+}
+
+func TestDecorator_Lookups(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("GetByUserID", func(t *testing.T) {
+		d, svc, cached := setup()
+		svc.On("GetByUserID", mock.Anything, "luffy-id").Return(newUser(), nil).Once()
+
+		for i := 0; i < 3; i++ {
+			got, err := cached.GetByUserID(ctx, "luffy-id")
+			require.NoError(t, err)
+			assert.Equal(t, "luffy", got.Username)
+			assert.Empty(t, got.Password)
+		}
+		svc.AssertExpectations(t)
+		assert.Equal(t, Stats{Hits: 2, Misses: 1}, d.Stats())
+	})
+
+	t.Run("GetByUsername", func(t *testing.T) {
+		d, svc, cached := setup()
+		svc.On("GetByUsername", mock.Anything, "luffy").Return(newUser(), nil).Once()
+
+		for i := 0; i < 2; i++ {
+			got, err := cached.GetByUsername(ctx, "luffy")
+			require.NoError(t, err)
+			assert.Equal(t, "luffy-id", got.UserID)
+		}
+		// The user is cached under every key once loaded
+		_, err := cached.GetByUserID(ctx, "luffy-id")
+		require.NoError(t, err)
+		_, err = cached.GetByEmail(ctx, "luffy@gophr.com")
+		require.NoError(t, err)
+
+		svc.AssertExpectations(t)
+		assert.Equal(t, Stats{Hits: 3, Misses: 1}, d.Stats())
+	})
+
+	t.Run("GetByEmail", func(t *testing.T) {
+		_, svc, cached := setup()
+		svc.On("GetByEmail", mock.Anything, "luffy@gophr.com").Return(newUser(), nil).Once()
+
+		for i := 0; i < 2; i++ {
+			got, err := cached.GetByEmail(ctx, "luffy@gophr.com")
+			require.NoError(t, err)
+			assert.Equal(t, "luffy-id", got.UserID)
+		}
+		svc.AssertExpectations(t)
+	})
+
+	t.Run("Copies", func(t *testing.T) {
+		_, svc, cached := setup()
+		svc.On("GetByUserID", mock.Anything, "luffy-id").Return(newUser(), nil).Once()
+
+		got, err := cached.GetByUserID(ctx, "luffy-id")
+		require.NoError(t, err)
+		got.Username = "zoro"
+
+		got, err = cached.GetByUserID(ctx, "luffy-id")
+		require.NoError(t, err)
+		assert.Equal(t, "luffy", got.Username)
+	})
+
+	t.Run("Not Found", func(t *testing.T) {
+		d, svc, cached := setup()
+		svc.On("GetByUserID", mock.Anything, "missing").Return(nil, user.ErrNotFound).Twice()
+
+		for i := 0; i < 2; i++ {
+			_, err := cached.GetByUserID(ctx, "missing")
+			assert.Equal(t, user.ErrNotFound, err)
+		}
+		svc.AssertExpectations(t)
+		assert.Equal(t, Stats{Misses: 2}, d.Stats())
+	})
+
+	t.Run("Soft Deleted Included", func(t *testing.T) {
+		_, svc, cached := setup()
+		svc.On("GetByUserID", mock.Anything, "luffy-id").Return(newUser(), nil).Twice()
+
+		for i := 0; i < 2; i++ {
+			_, err := cached.GetByUserID(softdelete.Include(ctx), "luffy-id")
+			require.NoError(t, err)
+		}
+		svc.AssertExpectations(t)
+	})
+
+	t.Run("Cache Failure", func(t *testing.T) {
+		c := new(mocks.Cache)
+		c.On("Get", mock.Anything, mock.Anything).Return(nil, errors.New("cache is down"))
+		c.On("Set", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(errors.New("cache is down"))
+		svc := new(mocks.Service)
+		svc.On("GetByUserID", mock.Anything, "luffy-id").Return(newUser(), nil).Twice()
+		cached := New(c, WithTTL(time.Minute)).Apply(svc)
+
+		for i := 0; i < 2; i++ {
+			got, err := cached.GetByUserID(ctx, "luffy-id")
+			require.NoError(t, err)
+			assert.Equal(t, "luffy", got.Username)
+		}
+		svc.AssertExpectations(t)
+	})
+}
+
+func TestDecorator_GetByUserIDs(t *testing.T) {
+	ctx := context.Background()
+	d, svc, cached := setup()
+	other := &user.User{ID: 2, UserID: "zoro-id", Username: "zoro", Email: "zoro@gophr.com", Password: "hashed"}
+	svc.On("GetByUserID", mock.Anything, "luffy-id").Return(newUser(), nil).Once()
+	svc.On("GetByUserIDs", mock.Anything, []string{"zoro-id", "missing"}).
+		Return([]*user.User{other}, []string{"missing"}, nil).Once()
+
+	_, err := cached.GetByUserID(ctx, "luffy-id")
+	require.NoError(t, err)
+
+	// Only the uncached users are looked up
+	got, missing, err := cached.GetByUserIDs(ctx, []string{"zoro-id", "luffy-id", "missing"})
+	require.NoError(t, err)
+	require.Len(t, got, 2)
+	assert.Equal(t, "zoro", got[0].Username)
+	assert.Empty(t, got[0].Password)
+	assert.Equal(t, "luffy", got[1].Username)
+	assert.Equal(t, []string{"missing"}, missing)
+
+	// The looked up users are cached too
+	got, missing, err = cached.GetByUserIDs(ctx, []string{"luffy-id", "zoro-id"})
+	require.NoError(t, err)
+	assert.Len(t, got, 2)
+	assert.Empty(t, missing)
+
+	svc.AssertExpectations(t)
+	assert.Equal(t, Stats{Hits: 3, Misses: 3}, d.Stats())
+}
+
+func TestDecorator_Singleflight(t *testing.T) {
+	const callers = 10
+	d, svc, cached := setup()
+
+	release := make(chan struct{})
+	svc.On("GetByUserID", mock.Anything, "luffy-id").
+		Run(func(mock.Arguments) { <-release }).
+		Return(newUser(), nil).Once()
+
+	var wg sync.WaitGroup
+	errs := make(chan error, callers)
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := cached.GetByUserID(context.Background(), "luffy-id")
+			errs <- err
+		}()
+	}
+
+	// Let the callers miss before the user is loaded
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		assert.NoError(t, err)
+	}
+	svc.AssertExpectations(t)
+	assert.Equal(t, uint64(callers), d.Stats().Hits+d.Stats().Misses)
+}
+
+func TestDecorator_Invalidation(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Update", func(t *testing.T) {
+		_, svc, cached := setup()
+		renamed := newUser()
+		renamed.Username = "zoro"
+		svc.On("GetByUsername", mock.Anything, "luffy").Return(newUser(), nil).Once()
+		svc.On("Update", mock.Anything, renamed).Return(nil).Once()
+		svc.On("GetByUserID", mock.Anything, "luffy-id").Return(renamed, nil).Once()
+
+		_, err := cached.GetByUsername(ctx, "luffy")
+		require.NoError(t, err)
+		require.NoError(t, cached.Update(ctx, renamed))
+
+		got, err := cached.GetByUserID(ctx, "luffy-id")
+		require.NoError(t, err)
+		assert.Equal(t, "zoro", got.Username)
+
+		// The old username still points to the user, which has
+		// another username now
+		svc.On("GetByUsername", mock.Anything, "luffy").Return(nil, user.ErrNotFound).Once()
+		_, err = cached.GetByUsername(ctx, "luffy")
+		assert.Equal(t, user.ErrNotFound, err)
+		svc.AssertExpectations(t)
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		_, svc, cached := setup()
+		svc.On("GetByUserID", mock.Anything, "luffy-id").Return(newUser(), nil).Once()
+		svc.On("Delete", mock.Anything, uint(1)).Return(nil).Once()
+		svc.On("GetByID", mock.Anything, uint(1)).Return(newUser(), nil).Once()
+
+		_, err := cached.GetByUserID(ctx, "luffy-id")
+		require.NoError(t, err)
+		require.NoError(t, cached.Delete(ctx, uint(1)))
+
+		svc.On("GetByUserID", mock.Anything, "luffy-id").Return(nil, user.ErrNotFound).Once()
+		_, err = cached.GetByUserID(ctx, "luffy-id")
+		assert.Equal(t, user.ErrNotFound, err)
+		svc.AssertExpectations(t)
+	})
+
+	t.Run("DeleteAccount", func(t *testing.T) {
+		_, svc, cached := setup()
+		svc.On("GetByUserID", mock.Anything, "luffy-id").Return(newUser(), nil).Once()
+		svc.On("DeleteAccount", mock.Anything, "luffy-id", "secret").Return(nil).Once()
+
+		_, err := cached.GetByUserID(ctx, "luffy-id")
+		require.NoError(t, err)
+		require.NoError(t, cached.DeleteAccount(ctx, "luffy-id", "secret"))
+
+		svc.On("GetByUserID", mock.Anything, "luffy-id").Return(nil, user.ErrNotFound).Once()
+		_, err = cached.GetByUserID(ctx, "luffy-id")
+		assert.Equal(t, user.ErrNotFound, err)
+		svc.AssertExpectations(t)
+	})
+
+	t.Run("Purged", func(t *testing.T) {
+		events := bus.New()
+		defer events.Close()
+		d, svc, cached := setup()
+		d.Subscribe(events)
+		svc.On("GetByUserID", mock.Anything, "luffy-id").Return(newUser(), nil).Once()
+
+		_, err := cached.GetByUserID(ctx, "luffy-id")
+		require.NoError(t, err)
+		require.NoError(t, events.Publish(ctx, &user.Purged{UserID: "luffy-id"}))
+
+		svc.On("GetByUserID", mock.Anything, "luffy-id").Return(nil, user.ErrNotFound).Once()
+		_, err = cached.GetByUserID(ctx, "luffy-id")
+		assert.Equal(t, user.ErrNotFound, err)
+		svc.AssertExpectations(t)
+	})
+}
+
+func TestDecorator_Singleflight_Canceled(t *testing.T) {
+	_, svc, cached := setup()
+
+	release := make(chan struct{})
+	svc.On("GetByUserID", mock.Anything, "luffy-id").
+		Run(func(mock.Arguments) { <-release }).
+		Return(newUser(), nil).Once()
+
+	// The caller starting the lookup gives up before it's done
+	ctx, cancel := context.WithCancel(context.Background())
+	canceled := make(chan error, 1)
+	go func() {
+		_, err := cached.GetByUserID(ctx, "luffy-id")
+		canceled <- err
+	}()
+	time.Sleep(50 * time.Millisecond)
+
+	waiter := make(chan error, 1)
+	go func() {
+		_, err := cached.GetByUserID(context.Background(), "luffy-id")
+		waiter <- err
+	}()
+	time.Sleep(50 * time.Millisecond)
+
+	cancel()
+	assert.Equal(t, context.Canceled, <-canceled)
+	close(release)
+	assert.NoError(t, <-waiter, "the other callers still get the user")
+	svc.AssertExpectations(t)
+}
@@ -0,0 +1,93 @@
+package instrumenting
+
+import (
+	"context"
+	"github.com/prometheus/client_golang/prometheus"
+	"gophr.v2/metrics"
+	"gophr.v2/user"
+	"time"
+)
+
+// New returns a decorator recording the count, the outcome and the
+// latency of the calls of the user service in reg.
+func New(reg prometheus.Registerer) user.Decorator {
+	calls := metrics.NewCalls(reg, "user")
+	return func(svc user.Service) user.Service {
+		return &instrumentingDecorator{svc: svc, calls: calls}
+	}
+}
+
+type instrumentingDecorator struct {
+	svc   user.Service
+	calls *metrics.Calls
+}
+
+func (i *instrumentingDecorator) GetByID(ctx context.Context, id interface{}) (usr *user.User, err error) {
+	defer i.calls.Observe("GetByID", time.Now(), &err)
+	return i.svc.GetByID(ctx, id)
+}
+
+func (i *instrumentingDecorator) GetByEmail(ctx context.Context, email string) (usr *user.User, err error) {
+	defer i.calls.Observe("GetByEmail", time.Now(), &err)
+	return i.svc.GetByEmail(ctx, email)
+}
+
+func (i *instrumentingDecorator) GetByUserID(ctx context.Context, id string) (usr *user.User, err error) {
+	defer i.calls.Observe("GetByUserID", time.Now(), &err)
+	return i.svc.GetByUserID(ctx, id)
+}
+
+func (i *instrumentingDecorator) GetByUserIDs(ctx context.Context, ids []string) (users []*user.User, missing []string, err error) {
+	defer i.calls.Observe("GetByUserIDs", time.Now(), &err)
+	return i.svc.GetByUserIDs(ctx, ids)
+}
+
+func (i *instrumentingDecorator) GetByUsername(ctx context.Context, username string) (usr *user.User, err error) {
+	defer i.calls.Observe("GetByUsername", time.Now(), &err)
+	return i.svc.GetByUsername(ctx, username)
+}
+
+func (i *instrumentingDecorator) Save(ctx context.Context, usr *user.User) (err error) {
+	defer i.calls.Observe("Save", time.Now(), &err)
+	return i.svc.Save(ctx, usr)
+}
+
+func (i *instrumentingDecorator) GetAll(ctx context.Context, cursor string, num int) (users []*user.User, nextCursor string, err error) {
+	defer i.calls.Observe("GetAll", time.Now(), &err)
+	return i.svc.GetAll(ctx, cursor, num)
+}
+
+func (i *instrumentingDecorator) Delete(ctx context.Context, id interface{}) (err error) {
+	defer i.calls.Observe("Delete", time.Now(), &err)
+	return i.svc.Delete(ctx, id)
+}
+
+func (i *instrumentingDecorator) Update(ctx context.Context, usr *user.User) (err error) {
+	defer i.calls.Observe("Update", time.Now(), &err)
+	return i.svc.Update(ctx, usr)
+}
+
+func (i *instrumentingDecorator) Register(ctx context.Context, usr *user.User) (err error) {
+	defer i.calls.Observe("Register", time.Now(), &err)
+	return i.svc.Register(ctx, usr)
+}
+
+func (i *instrumentingDecorator) Login(ctx context.Context, usr *user.User) (err error) {
+	defer i.calls.Observe("Login", time.Now(), &err)
+	return i.svc.Login(ctx, usr)
+}
+
+func (i *instrumentingDecorator) DeleteAccount(ctx context.Context, userID, password string) (err error) {
+	defer i.calls.Observe("DeleteAccount", time.Now(), &err)
+	return i.svc.DeleteAccount(ctx, userID, password)
+}
+
+func (i *instrumentingDecorator) RestoreAccount(ctx context.Context, usr *user.User) (err error) {
+	defer i.calls.Observe("RestoreAccount", time.Now(), &err)
+	return i.svc.RestoreAccount(ctx, usr)
+}
+
+func (i *instrumentingDecorator) ChangePassword(ctx context.Context, userID, currentPassword, newPassword string) (err error) {
+	defer i.calls.Observe("ChangePassword", time.Now(), &err)
+	return i.svc.ChangePassword(ctx, userID, currentPassword, newPassword)
+}
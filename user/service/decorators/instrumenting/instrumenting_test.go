@@ -0,0 +1,40 @@
+//+build unit
+
+package instrumenting
+
+import (
+	"context"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gophr.v2/user"
+	"gophr.v2/user/mocks"
+	"strings"
+	"testing"
+)
+
+func TestDecorator(t *testing.T) {
+	ctx := context.Background()
+	reg := prometheus.NewRegistry()
+	svc := new(mocks.Service)
+	svc.On("GetByUserID", mock.Anything, "luffy-id").Return(&user.User{UserID: "luffy-id"}, nil).Once()
+	svc.On("GetByUserID", mock.Anything, "missing").Return(nil, user.ErrNotFound).Once()
+	instrumented := user.ApplyDecorators(svc, New(reg))
+
+	got, err := instrumented.GetByUserID(ctx, "luffy-id")
+	require.NoError(t, err)
+	assert.Equal(t, "luffy-id", got.UserID)
+	_, err = instrumented.GetByUserID(ctx, "missing")
+	assert.Equal(t, user.ErrNotFound, err)
+
+	expected := `
+# HELP gophr_service_calls_total Count of the calls of the services by service, method and outcome.
+# TYPE gophr_service_calls_total counter
+gophr_service_calls_total{method="GetByUserID",outcome="error",service="user"} 1
+gophr_service_calls_total{method="GetByUserID",outcome="success",service="user"} 1
+`
+	require.NoError(t, testutil.GatherAndCompare(reg, strings.NewReader(expected), "gophr_service_calls_total"))
+	svc.AssertExpectations(t)
+}
@@ -2,7 +2,7 @@ package logging
 
 import (
 	"context"
-	"github.com/sirupsen/logrus"
+	"gophr.v2/log"
 	"gophr.v2/user"
 )
 
@@ -14,61 +14,88 @@ type loggingDecorator struct {
 	svc user.Service
 }
 
+// logger returns the logger of ctx adding method to its entries. The
+// passwords given to the methods are never logged.
+func logger(ctx context.Context, method string, fields log.Fields) *log.Logger {
+	return log.FromContext(ctx).WithField("method", method).With(fields)
+}
+
+// userFields are the fields identifying usr.
+func userFields(usr *user.User) log.Fields {
+	return log.Fields{
+		log.UserIDKey: usr.UserID,
+		"username":    usr.Username,
+		"email":       usr.Email,
+	}
+}
+
 func (l *loggingDecorator) GetByID(ctx context.Context, id interface{}) (*user.User, error) {
-	logrus.Infof("METHOD: GetByID ID: %v\n", id)
+	logger(ctx, "GetByID", log.Fields{"id": id}).Info("calling user service")
 	return l.svc.GetByID(ctx, id)
 }
 
 func (l *loggingDecorator) GetByEmail(ctx context.Context, email string) (*user.User, error) {
-	logrus.Infof("METHOD: GetByEmail EMAIL: %v\n", email)
+	logger(ctx, "GetByEmail", log.Fields{"email": email}).Info("calling user service")
 	return l.svc.GetByEmail(ctx, email)
 }
 
 func (l *loggingDecorator) GetByUserID(ctx context.Context, id string) (*user.User, error) {
-	logrus.Infof("METHOD: GetByUserID USERID: %v\n", id)
+	logger(ctx, "GetByUserID", log.Fields{log.UserIDKey: id}).Info("calling user service")
 	return l.svc.GetByUserID(ctx, id)
 }
 
+func (l *loggingDecorator) GetByUserIDs(ctx context.Context, ids []string) ([]*user.User, []string, error) {
+	logger(ctx, "GetByUserIDs", log.Fields{"userIds": ids}).Info("calling user service")
+	return l.svc.GetByUserIDs(ctx, ids)
+}
+
 func (l *loggingDecorator) GetByUsername(ctx context.Context, username string) (*user.User, error) {
-	logrus.Infof("METHOD: GetByUsername USERNAME: %v\n", username)
+	logger(ctx, "GetByUsername", log.Fields{"username": username}).Info("calling user service")
 	return l.svc.GetByUsername(ctx, username)
 }
 
 func (l *loggingDecorator) Save(ctx context.Context, usr *user.User) error {
-	logrus.Infof("METHOD: Save "+
-		"\n\tUSERID: %v\n\tUSERNAME: %v\n\tEMAIL: %v\n\t USERID: %v\n",
-		usr.UserID, usr.Username, usr.Email, usr.UserID)
+	logger(ctx, "Save", userFields(usr)).Info("calling user service")
 	return l.svc.Save(ctx, usr)
 }
 
 func (l *loggingDecorator) GetAll(ctx context.Context, cursor string, num int) (user []*user.User, nextCursor string, err error) {
-	logrus.Infof("METHOD: GetAll CURSOR: %v NUM: %v\n", cursor, num)
+	logger(ctx, "GetAll", log.Fields{"cursor": cursor, "num": num}).Info("calling user service")
 	return l.svc.GetAll(ctx, cursor, num)
 }
 
 func (l *loggingDecorator) Delete(ctx context.Context, id interface{}) error {
-	logrus.Infof("METHOD: Delete ID: %v\n", id)
+	logger(ctx, "Delete", log.Fields{"id": id}).Info("calling user service")
 	return l.svc.Delete(ctx, id)
 }
 
 func (l *loggingDecorator) Update(ctx context.Context, usr *user.User) error {
-	logrus.Infof("METHOD: Update "+
-		"\n\tUSERID: %v\n\tUSERNAME: %v\n\tEMAIL: %v\n\t USERID: %v\n",
-		usr.UserID, usr.Username, usr.Email, usr.UserID)
+	logger(ctx, "Update", userFields(usr)).Info("calling user service")
 	return l.svc.Update(ctx, usr)
 }
 
 func (l *loggingDecorator) Register(ctx context.Context, usr *user.User) error {
-	logrus.Infof("METHOD: Register "+
-		"\n\tUSERID: %v\n\tUSERNAME: %v\n\tEMAIL: %v\n\t USERID: %v\n",
-		usr.UserID, usr.Username, usr.Email, usr.UserID)
+	logger(ctx, "Register", userFields(usr)).Info("calling user service")
 	return l.svc.Register(ctx, usr)
 }
 
 func (l *loggingDecorator) Login(ctx context.Context, usr *user.User) error {
-	logrus.Infof("METHOD: Login "+
-		"\n\tUSERID: %v\n\tUSERNAME: %v\n\tEMAIL: %v\n\t USERID: %v\n",
-		usr.UserID, usr.Username, usr.Email, usr.UserID)
+	logger(ctx, "Login", userFields(usr)).Info("calling user service")
 	return l.svc.Login(ctx, usr)
 
 }
+
+func (l *loggingDecorator) DeleteAccount(ctx context.Context, userID, password string) error {
+	logger(ctx, "DeleteAccount", log.Fields{log.UserIDKey: userID}).Info("calling user service")
+	return l.svc.DeleteAccount(ctx, userID, password)
+}
+
+func (l *loggingDecorator) RestoreAccount(ctx context.Context, usr *user.User) error {
+	logger(ctx, "RestoreAccount", userFields(usr)).Info("calling user service")
+	return l.svc.RestoreAccount(ctx, usr)
+}
+
+func (l *loggingDecorator) ChangePassword(ctx context.Context, userID, currentPassword, newPassword string) error {
+	logger(ctx, "ChangePassword", log.Fields{log.UserIDKey: userID}).Info("calling user service")
+	return l.svc.ChangePassword(ctx, userID, currentPassword, newPassword)
+}
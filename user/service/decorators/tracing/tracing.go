@@ -0,0 +1,101 @@
+package tracing
+
+import (
+	"context"
+	gophrtracing "gophr.v2/tracing"
+	"gophr.v2/user"
+)
+
+// Apply decorates svc with a span for every call. It is a
+// user.Decorator.
+func Apply(svc user.Service) user.Service {
+	return &tracingDecorator{svc}
+}
+
+type tracingDecorator struct {
+	svc user.Service
+}
+
+func (t *tracingDecorator) GetByID(ctx context.Context, id interface{}) (usr *user.User, err error) {
+	ctx, span := gophrtracing.Start(ctx, "user.GetByID")
+	defer gophrtracing.End(span, &err)
+	return t.svc.GetByID(ctx, id)
+}
+
+func (t *tracingDecorator) GetByEmail(ctx context.Context, email string) (usr *user.User, err error) {
+	ctx, span := gophrtracing.Start(ctx, "user.GetByEmail")
+	defer gophrtracing.End(span, &err)
+	return t.svc.GetByEmail(ctx, email)
+}
+
+func (t *tracingDecorator) GetByUserID(ctx context.Context, id string) (usr *user.User, err error) {
+	ctx, span := gophrtracing.Start(ctx, "user.GetByUserID")
+	defer gophrtracing.End(span, &err)
+	return t.svc.GetByUserID(ctx, id)
+}
+
+func (t *tracingDecorator) GetByUserIDs(ctx context.Context, ids []string) (users []*user.User, missing []string, err error) {
+	ctx, span := gophrtracing.Start(ctx, "user.GetByUserIDs")
+	defer gophrtracing.End(span, &err)
+	return t.svc.GetByUserIDs(ctx, ids)
+}
+
+func (t *tracingDecorator) GetByUsername(ctx context.Context, username string) (usr *user.User, err error) {
+	ctx, span := gophrtracing.Start(ctx, "user.GetByUsername")
+	defer gophrtracing.End(span, &err)
+	return t.svc.GetByUsername(ctx, username)
+}
+
+func (t *tracingDecorator) Save(ctx context.Context, usr *user.User) (err error) {
+	ctx, span := gophrtracing.Start(ctx, "user.Save")
+	defer gophrtracing.End(span, &err)
+	return t.svc.Save(ctx, usr)
+}
+
+func (t *tracingDecorator) GetAll(ctx context.Context, cursor string, num int) (users []*user.User, nextCursor string, err error) {
+	ctx, span := gophrtracing.Start(ctx, "user.GetAll")
+	defer gophrtracing.End(span, &err)
+	return t.svc.GetAll(ctx, cursor, num)
+}
+
+func (t *tracingDecorator) Delete(ctx context.Context, id interface{}) (err error) {
+	ctx, span := gophrtracing.Start(ctx, "user.Delete")
+	defer gophrtracing.End(span, &err)
+	return t.svc.Delete(ctx, id)
+}
+
+func (t *tracingDecorator) Update(ctx context.Context, usr *user.User) (err error) {
+	ctx, span := gophrtracing.Start(ctx, "user.Update")
+	defer gophrtracing.End(span, &err)
+	return t.svc.Update(ctx, usr)
+}
+
+func (t *tracingDecorator) Register(ctx context.Context, usr *user.User) (err error) {
+	ctx, span := gophrtracing.Start(ctx, "user.Register")
+	defer gophrtracing.End(span, &err)
+	return t.svc.Register(ctx, usr)
+}
+
+func (t *tracingDecorator) Login(ctx context.Context, usr *user.User) (err error) {
+	ctx, span := gophrtracing.Start(ctx, "user.Login")
+	defer gophrtracing.End(span, &err)
+	return t.svc.Login(ctx, usr)
+}
+
+func (t *tracingDecorator) DeleteAccount(ctx context.Context, userID, password string) (err error) {
+	ctx, span := gophrtracing.Start(ctx, "user.DeleteAccount")
+	defer gophrtracing.End(span, &err)
+	return t.svc.DeleteAccount(ctx, userID, password)
+}
+
+func (t *tracingDecorator) RestoreAccount(ctx context.Context, usr *user.User) (err error) {
+	ctx, span := gophrtracing.Start(ctx, "user.RestoreAccount")
+	defer gophrtracing.End(span, &err)
+	return t.svc.RestoreAccount(ctx, usr)
+}
+
+func (t *tracingDecorator) ChangePassword(ctx context.Context, userID, currentPassword, newPassword string) (err error) {
+	ctx, span := gophrtracing.Start(ctx, "user.ChangePassword")
+	defer gophrtracing.End(span, &err)
+	return t.svc.ChangePassword(ctx, userID, currentPassword, newPassword)
+}
@@ -2,6 +2,11 @@ package remote
 
 import (
 	"context"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.4.0"
+	"go.opentelemetry.io/otel/trace"
+	"gophr.v2/tracing"
 	"io"
 	"net/http"
 	"net/url"
@@ -60,11 +65,28 @@ func (c *Client) NewRequest(method string, path string, body io.Reader) (*http.R
 	return req, nil
 }
 
-func (c *Client) Do(ctx context.Context, req *http.Request) (*http.Response, error) {
-	if ctx != nil {
-		req.WithContext(ctx)
+// Do sends req within a span of ctx. The trace continues in the user
+// API through the W3C trace context headers.
+func (c *Client) Do(ctx context.Context, req *http.Request) (resp *http.Response, err error) {
+	if ctx == nil {
+		ctx = context.Background()
 	}
-	return c.client.Do(req)
+	ctx, span := tracing.Start(ctx, "remote "+req.Method,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			semconv.HTTPMethodKey.String(req.Method),
+			semconv.HTTPHostKey.String(req.URL.Host),
+		))
+	defer tracing.End(span, &err)
+
+	req = req.WithContext(ctx)
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(req.Header))
+	resp, err = c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	span.SetAttributes(semconv.HTTPAttributesFromHTTPStatusCode(resp.StatusCode)...)
+	return resp, nil
 }
 
 func SetBaseUrl(rawurl string) func(*Client) error {
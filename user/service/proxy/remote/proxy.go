@@ -17,6 +17,9 @@ import (
 
 const (
 	defaultUrl = "http://127.0.0.1:4401"
+	// lookupBatchSize is the most users the user API looks up
+	// per request.
+	lookupBatchSize = 100
 )
 
 var _ user.Service = (*Service)(nil)
@@ -168,6 +171,66 @@ func (s *Service) GetByUserID(ctx context.Context, id string) (*user.User, error
 	return s.doGet(ctx, fmt.Sprintf("/users/%v", id), nil)
 }
 
+// GetByUserIDs looks up the users with a request per batch of
+// lookupBatchSize IDs.
+func (s *Service) GetByUserIDs(ctx context.Context, ids []string) (users []*user.User, missing []string, err error) {
+	users = make([]*user.User, 0, len(ids))
+	for start := 0; start < len(ids); start += lookupBatchSize {
+		end := start + lookupBatchSize
+		if end > len(ids) {
+			end = len(ids)
+		}
+		found, notFound, err := s.lookup(ctx, ids[start:end])
+		if err != nil {
+			return nil, nil, err
+		}
+		users = append(users, found...)
+		missing = append(missing, notFound...)
+	}
+	return users, missing, nil
+}
+
+func (s *Service) lookup(ctx context.Context, ids []string) (users []*user.User, missing []string, err error) {
+	payload, err := json.Marshal(map[string][]string{"userIds": ids})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	req, err := s.client.NewRequest(http.MethodPost, "/users/lookup", bytes.NewReader(payload))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	resp, err := s.client.Do(ctx, req)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer noOpClose(resp.Body)
+
+	if err := s.checkErr(resp); err != nil {
+		return nil, nil, err
+	}
+
+	var response Response
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, nil, err
+	}
+
+	// The data is decoded again into the lookup result
+	data, err := json.Marshal(response.Data)
+	if err != nil {
+		return nil, nil, err
+	}
+	var result struct {
+		Users   []*user.User `json:"users"`
+		Missing []string     `json:"missing"`
+	}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, nil, err
+	}
+	return result.Users, result.Missing, nil
+}
+
 func (s *Service) GetAll(ctx context.Context, cursor string, num int) (users []*user.User, next string, err error) {
 
 	opt := &struct {
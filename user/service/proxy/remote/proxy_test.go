@@ -3,8 +3,13 @@ package remote
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 	"golang.org/x/crypto/bcrypt"
 	"gophr.v2/user"
 	"gophr.v2/user/userutil"
@@ -329,6 +334,108 @@ func TestGetAll(t *testing.T) {
 	})
 }
 
+func TestGetByUserIDs(t *testing.T) {
+	want := []*user.User{
+		{ID: 1, UserID: userutil.GenerateID(), Email: "unit.test@testing.com"},
+	}
+	ids := []string{want[0].UserID, "unknown"}
+
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/users/lookup", r.URL.Path)
+
+		var body struct {
+			UserIDs []string `json:"userIds"`
+		}
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Equal(t, ids, body.UserIDs)
+
+		payload, err := json.Marshal(&Response{
+			Data: map[string]interface{}{
+				"users":   want,
+				"missing": []string{"unknown"},
+			},
+			Success: true,
+		})
+		assert.NoError(t, err)
+		_, err = w.Write(payload)
+		assert.NoError(t, err)
+	})
+
+	c, teardown := setupClient(t, h)
+	defer teardown()
+
+	got, missing, err := New(c).GetByUserIDs(context.Background(), ids)
+	require.NoError(t, err)
+	assert.Equal(t, want, got)
+	assert.Equal(t, []string{"unknown"}, missing)
+}
+
+func TestGetByUserIDs_Batches(t *testing.T) {
+	ids := make([]string, lookupBatchSize+1)
+	for i := range ids {
+		ids[i] = userutil.GenerateID()
+	}
+
+	var batches [][]string
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			UserIDs []string `json:"userIds"`
+		}
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		batches = append(batches, body.UserIDs)
+
+		payload, err := json.Marshal(&Response{
+			Data:    map[string]interface{}{"users": []*user.User{}, "missing": body.UserIDs},
+			Success: true,
+		})
+		assert.NoError(t, err)
+		_, err = w.Write(payload)
+		assert.NoError(t, err)
+	})
+
+	c, teardown := setupClient(t, h)
+	defer teardown()
+
+	_, missing, err := New(c).GetByUserIDs(context.Background(), ids)
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{ids[:lookupBatchSize], ids[lookupBatchSize:]}, batches)
+	assert.Equal(t, ids, missing)
+}
+
+func TestDoPropagatesTrace(t *testing.T) {
+	provider, propagator := otel.GetTracerProvider(), otel.GetTextMapPropagator()
+	defer func() {
+		otel.SetTracerProvider(provider)
+		otel.SetTextMapPropagator(propagator)
+	}()
+	rec := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(rec)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	var traceparent string
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceparent = r.Header.Get("traceparent")
+		w.WriteHeader(http.StatusOK)
+	})
+	c, teardown := setupClient(t, h)
+	defer teardown()
+
+	ctx, parent := otel.Tracer("test").Start(context.Background(), "cli")
+	req, err := c.NewRequest(http.MethodGet, "/v1/users", nil)
+	require.NoError(t, err)
+	resp, err := c.Do(ctx, req)
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+	parent.End()
+
+	spans := rec.Ended()
+	require.Len(t, spans, 2)
+	assert.Equal(t, "remote GET", spans[0].Name())
+	assert.Equal(t, parent.SpanContext().TraceID(), spans[0].SpanContext().TraceID())
+	assert.Equal(t, fmt.Sprintf("00-%s-%s-01", spans[0].SpanContext().TraceID(), spans[0].SpanContext().SpanID()), traceparent)
+}
+
 func testingHTTPClient(handler http.Handler) (*http.Client, func()) {
 	s := httptest.NewServer(handler)
 	cli := &http.Client{
@@ -2,12 +2,13 @@ package service
 
 import (
 	"context"
-	"fmt"
-	"github.com/jayvib/golog"
-	"github.com/pkg/errors"
-	"golang.org/x/crypto/bcrypt"
-	"golang.org/x/sync/errgroup"
+	"gophr.v2/config"
+	"gophr.v2/event"
+	"gophr.v2/log"
+	"gophr.v2/session"
+	"gophr.v2/softdelete"
 	"gophr.v2/user"
+	"gophr.v2/user/lockout"
 	"gophr.v2/user/userutil"
 	"gophr.v2/util/valueutil"
 	"time"
@@ -15,12 +16,95 @@ import (
 
 var _ user.Service = (*Service)(nil)
 
-func New(repo user.Repository) *Service {
-	return &Service{repo: repo}
+// DefaultDeletionGracePeriod is how long a deleted account can be
+// restored before it's purged.
+const DefaultDeletionGracePeriod = 30 * 24 * time.Hour
+
+// DefaultPurgeInterval is how often the accounts past their grace
+// period are purged.
+const DefaultPurgeInterval = time.Hour
+
+// purgeBatchSize is the number of accounts purged at once.
+const purgeBatchSize = 100
+
+// Option configures the optional collaborators of the Service.
+type Option func(s *Service)
+
+// WithLockout protects Login against brute-force attempts using guard.
+func WithLockout(guard *lockout.Guard) Option {
+	return func(s *Service) {
+		s.guard = guard
+	}
+}
+
+// WithPasswordPolicy replaces the user.DefaultPasswordPolicy.
+func WithPasswordPolicy(policy user.PasswordPolicy) Option {
+	return func(s *Service) {
+		s.policy = policy
+	}
+}
+
+// WithPasswordHasher replaces the user.DefaultPasswordHasher. Passwords
+// hashed differently are upgraded on the next successful login.
+func WithPasswordHasher(hasher user.PasswordHasher) Option {
+	return func(s *Service) {
+		s.hasher = hasher
+	}
+}
+
+// WithBreachChecker rejects new passwords that are known to be breached.
+func WithBreachChecker(checker user.BreachChecker) Option {
+	return func(s *Service) {
+		s.breachChecker = checker
+	}
+}
+
+// WithEvents publishes the user events to publisher.
+func WithEvents(publisher event.Publisher) Option {
+	return func(s *Service) {
+		s.events = publisher
+	}
+}
+
+// WithSessions revokes the sessions of the deleted accounts and of
+// the accounts whose password changed through sessions.
+func WithSessions(sessions session.UserDeleter) Option {
+	return func(s *Service) {
+		s.sessions = sessions
+	}
+}
+
+// WithDeletionGracePeriod replaces the DefaultDeletionGracePeriod.
+func WithDeletionGracePeriod(d time.Duration) Option {
+	return func(s *Service) {
+		s.gracePeriod = d
+	}
+}
+
+func New(repo user.Repository, opts ...Option) *Service {
+	s := &Service{
+		repo:        repo,
+		policy:      user.DefaultPasswordPolicy,
+		hasher:      user.DefaultPasswordHasher,
+		gracePeriod: DefaultDeletionGracePeriod,
+		now:         time.Now,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
 }
 
 type Service struct {
-	repo user.Repository
+	repo          user.Repository
+	guard         *lockout.Guard
+	policy        user.PasswordPolicy
+	hasher        user.PasswordHasher
+	breachChecker user.BreachChecker
+	events        event.Publisher
+	sessions      session.UserDeleter
+	gracePeriod   time.Duration
+	now           func() time.Time
 }
 
 func (s *Service) GetByID(ctx context.Context, id interface{}) (*user.User, error) {
@@ -39,6 +123,16 @@ func (s *Service) GetByUserID(ctx context.Context, userId string) (*user.User, e
 	return usr, nil
 }
 
+// GetByUserIDs returns the users with userIDs in the same order
+// along with the IDs of the missing ones, looked up at once.
+func (s *Service) GetByUserIDs(ctx context.Context, userIDs []string) (users []*user.User, missing []string, err error) {
+	users, err = s.repo.GetByUserIDs(ctx, userIDs)
+	if err != nil {
+		return nil, nil, user.NewError(err)
+	}
+	return users, userutil.MissingUserIDs(users, userIDs), nil
+}
+
 func (s *Service) GetByEmail(ctx context.Context, email string) (*user.User, error) {
 	usr, err := s.repo.GetByEmail(ctx, email)
 	if err != nil {
@@ -62,44 +156,93 @@ func (s *Service) Save(ctx context.Context, usr *user.User) error {
 }
 
 func (s *Service) getAndComparePassword(ctx context.Context, username, password string) (*user.User, error) {
-	// Get the users information
-	usr, err := s.repo.GetByUsername(ctx, username)
+	// Get the users information. The deleted accounts are found so
+	// that they can be told apart and restored.
+	usr, err := s.repo.GetByUsername(softdelete.Include(ctx), username)
 	if err != nil {
 		return nil, err
 	}
 
 	// compare the password
-	golog.Debug(username)
-	golog.Debug(password)
-	golog.Debugf("%#v\n", usr)
-	err = bcrypt.CompareHashAndPassword([]byte(usr.Password), []byte(password))
-	if err != nil {
-		golog.Debug(err)
-		// if not match then return ErrorCredential
-		if err == bcrypt.ErrMismatchedHashAndPassword {
-			return nil, user.ErrInvalidCredentials
-		}
+	err = s.hasher.Compare(usr.Password, password)
+	if err != nil {
+		log.FromContext(ctx).With(log.Fields{log.UserIDKey: usr.UserID}).WithError(err).Debug("password mismatch")
 		return nil, err
 	}
 
+	if s.hasher.NeedsRehash(usr.Password) {
+		s.rehashPassword(ctx, usr, password)
+	}
+
 	// if match then return the user's information excluding the password
 	usr.Password = ""
 
 	return usr, nil
 }
 
+// rehashPassword upgrades the stored hash of usr to the current
+// hasher settings. Failing to do so doesn't prevent the login.
+func (s *Service) rehashPassword(ctx context.Context, usr *user.User, password string) {
+	hash, err := s.hasher.Hash(password)
+	if err != nil {
+		log.FromContext(ctx).WithError(err).Error("failed rehashing password")
+		return
+	}
+
+	usr.Password = hash
+	usr.UpdatedAt = valueutil.TimePointer(time.Now().UTC())
+	if err := s.repo.Update(ctx, usr); err != nil {
+		log.FromContext(ctx).WithError(err).Error("failed saving rehashed password")
+	}
+}
+
+// checkPassword validates password against the policy and the
+// breached password list.
+func (s *Service) checkPassword(ctx context.Context, password string, usr *user.User) error {
+	if err := s.policy.Validate(password, usr); err != nil {
+		return err
+	}
+
+	if s.breachChecker == nil {
+		return nil
+	}
+
+	breached, err := s.breachChecker.IsBreached(ctx, password)
+	if err != nil {
+		return err
+	}
+	if breached {
+		return user.ErrPasswordBreached
+	}
+	return nil
+}
+
 func (s *Service) GetAll(ctx context.Context, cursor string, num int) (user []*user.User, nextCursor string, err error) {
 	return s.repo.GetAll(ctx, cursor, num)
 }
 
+// Delete soft deletes the user with id. It's purged along with the
+// accounts deleted by their owner.
 func (s *Service) Delete(ctx context.Context, id interface{}) error {
 	return s.repo.Delete(ctx, id)
 }
 
+// Restore cancels the deletion of the user with userID, whether it
+// was deleted by its owner or by an admin, as long as it isn't purged.
+func (s *Service) Restore(ctx context.Context, userID string) error {
+	if err := s.repo.Restore(ctx, userID); err != nil {
+		return user.NewError(err).AddContext("User ID", userID)
+	}
+	return nil
+}
+
 func (s *Service) Update(ctx context.Context, usr *user.User) error {
+	if err := usr.ValidateProfile(); err != nil {
+		return user.NewError(err).AddContext("ID", usr.UserID)
+	}
 
 	// Check first if exists
-	_, err := s.repo.GetByUserID(ctx, usr.UserID)
+	existing, err := s.repo.GetByUserID(ctx, usr.UserID)
 	if err != nil {
 		if err == user.ErrNotFound {
 			err = user.ErrUserNotExists
@@ -109,46 +252,261 @@ func (s *Service) Update(ctx context.Context, usr *user.User) error {
 
 	usr.UpdatedAt = valueutil.TimePointer(time.Now().UTC())
 
-	// TODO: Hash the password
+	// Passwords are only changed through ChangePassword
+	if existing != nil {
+		usr.Password = existing.Password
+	}
 
 	return s.repo.Update(ctx, usr)
 }
 
+// ChangePassword replaces the password of the user with userID once
+// currentPassword is confirmed and revokes all of its sessions.
+func (s *Service) ChangePassword(ctx context.Context, userID, currentPassword, newPassword string) error {
+	usr, err := s.repo.GetByUserID(ctx, userID)
+	if err != nil {
+		if err == user.ErrNotFound {
+			err = user.ErrUserNotExists
+		}
+		return user.NewError(err).AddContext("User ID", userID)
+	}
+
+	if err := s.confirmPassword(ctx, usr, currentPassword); err != nil {
+		return user.NewError(err).AddContext("User ID", userID)
+	}
+
+	if err := s.checkPassword(ctx, newPassword, usr); err != nil {
+		return user.NewError(err).AddContext("User ID", userID)
+	}
+
+	hash, err := s.hasher.Hash(newPassword)
+	if err != nil {
+		return user.NewError(err)
+	}
+
+	usr.Password = hash
+	usr.UpdatedAt = valueutil.TimePointer(time.Now().UTC())
+	if err := s.repo.Update(ctx, usr); err != nil {
+		return user.NewError(err).AddContext("User ID", userID)
+	}
+
+	// Whoever knew the old password is logged out everywhere
+	if s.sessions != nil {
+		if err := s.sessions.DeleteAllForUser(ctx, userID); err != nil {
+			return user.NewError(err).AddContext("User ID", userID)
+		}
+	}
+	return nil
+}
+
+// confirmPassword checks that password is the one of the signed in
+// usr before a sensitive change. Failures count as failed logins.
+func (s *Service) confirmPassword(ctx context.Context, usr *user.User, password string) error {
+	ip := lockout.ClientIPFromContext(ctx)
+	if s.guard != nil {
+		if err := s.guard.Check(ctx, usr.Username, ip); err != nil {
+			return err
+		}
+	}
+
+	err := s.hasher.Compare(usr.Password, password)
+	if err != nil && s.guard != nil && err == user.ErrInvalidCredentials {
+		if e := s.guard.Fail(ctx, usr.Username, ip); e != nil {
+			log.FromContext(ctx).WithError(e).Error("failed recording login attempt")
+		}
+	}
+	return err
+}
+
+// DeleteAccount soft deletes the account with userID once password is
+// confirmed and revokes all of its sessions. The account is purged
+// once the grace period is over unless it's restored before.
+func (s *Service) DeleteAccount(ctx context.Context, userID, password string) error {
+	usr, err := s.repo.GetByUserID(softdelete.Include(ctx), userID)
+	if err != nil {
+		if err == user.ErrNotFound {
+			err = user.ErrUserNotExists
+		}
+		return user.NewError(err).AddContext("User ID", userID)
+	}
+	if usr.IsDeleted() {
+		return user.NewError(user.ErrAccountDeleted).AddContext("User ID", userID)
+	}
+
+	if err := s.confirmPassword(ctx, usr, password); err != nil {
+		return user.NewError(err).AddContext("User ID", userID)
+	}
+
+	if err := s.repo.SoftDelete(ctx, userID, s.now().UTC()); err != nil {
+		return user.NewError(err).AddContext("User ID", userID)
+	}
+
+	if s.sessions != nil {
+		if err := s.sessions.DeleteAllForUser(ctx, userID); err != nil {
+			return user.NewError(err).AddContext("User ID", userID)
+		}
+	}
+	return nil
+}
+
+// RestoreAccount cancels the deletion of the account identified by
+// the credentials of usr. Accounts past their grace period are about
+// to be purged and can't be restored anymore.
+func (s *Service) RestoreAccount(ctx context.Context, usr *user.User) error {
+	u, err := s.authenticate(ctx, usr.Username, usr.Password)
+	if err != nil {
+		return err
+	}
+
+	if !u.IsDeleted() {
+		return user.NewError(user.ErrAccountNotDeleted).AddContext("Username", usr.Username)
+	}
+	if !s.now().Before(u.DeletedAt.Add(s.gracePeriod)) {
+		return user.NewError(user.ErrNotFound).AddContext("Username", usr.Username)
+	}
+
+	if err := s.repo.Restore(ctx, u.UserID); err != nil {
+		return user.NewError(err).AddContext("Username", usr.Username)
+	}
+
+	usr.Password = ""
+	usr.UserID = u.UserID
+	return nil
+}
+
+// Run purges the accounts past their grace period every interval
+// until ctx is done.
+func (s *Service) Run(ctx context.Context, interval time.Duration) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		for {
+			n, err := s.Purge(ctx)
+			if err != nil {
+				log.FromContext(ctx).WithError(err).Error("failed purging deleted accounts")
+			}
+			if err != nil || n < purgeBatchSize {
+				break
+			}
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// Purge permanently deletes a batch of accounts past their grace period
+// and returns how many were purged. The records the other domains hold
+// about the accounts are deleted when they handle user.Purged.
+func (s *Service) Purge(ctx context.Context) (int, error) {
+	before := s.now().UTC().Add(-s.gracePeriod)
+	users, err := s.repo.GetAllDeleted(ctx, before, purgeBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	for i, usr := range users {
+		ctx := event.Record(ctx, &user.Purged{UserID: usr.UserID})
+		if err := s.repo.Purge(ctx, usr.ID); err != nil {
+			return i, err
+		}
+		event.DispatchCommitted(ctx, s.events)
+	}
+	return len(users), nil
+}
+
 func (s *Service) Register(ctx context.Context, usr *user.User) error {
 	if err := validateUser(usr); err != nil {
 		return user.NewError(err)
 	}
 
-	// Check first the usr if already exists
-	_, err := s.repo.GetByEmail(ctx, usr.Email)
+	// Check first the usr if already exists. The deleted accounts
+	// keep their email until purged.
+	_, err := s.repo.GetByEmail(softdelete.Include(ctx), usr.Email)
 	if err == nil {
 		return user.NewError(user.ErrUserExists)
 	}
 
+	if err := s.checkPassword(ctx, usr.Password, usr); err != nil {
+		return user.NewError(err)
+	}
+
 	usr.CreatedAt = valueutil.TimePointer(time.Now().UTC())
 	usr.UserID = userutil.GenerateID()
 	// Create a password
-	hash, err := bcrypt.GenerateFromPassword([]byte(usr.Password), bcrypt.DefaultCost)
+	hash, err := s.hasher.Hash(usr.Password)
 	if err != nil {
 		return user.NewError(err)
 	}
 
-	usr.Password = string(hash)
+	usr.Password = hash
+
+	// The handlers must never learn the password hash
+	registered := usr.Clone()
+	registered.Password = ""
+	ctx = event.Record(ctx, &user.Registered{User: registered})
+	if err := s.repo.Save(ctx, usr); err != nil {
+		switch err {
+		case user.ErrUserNameExists, user.ErrEmailExists, user.ErrUserExists:
+			// The repository enforces the uniqueness the check above
+			// can't guarantee on concurrent registrations
+			return user.NewError(user.ErrUserExists)
+		}
+		return err
+	}
 
-	return s.repo.Save(ctx, usr)
+	event.DispatchCommitted(ctx, s.events)
+	return nil
 }
 
 func (s *Service) Login(ctx context.Context, usr *user.User) error {
-	// Compare the value of user password and the existing user password
-	u, err := s.getAndComparePassword(ctx, usr.Username, usr.Password)
+	u, err := s.authenticate(ctx, usr.Username, usr.Password)
 	if err != nil {
-		return user.NewError(err)
+		return err
 	}
+
+	// Deleted accounts have to be restored first
+	if u.IsDeleted() {
+		return user.NewError(user.ErrAccountDeleted).AddContext("Username", usr.Username)
+	}
+
 	usr.Password = ""
 	usr.UserID = u.UserID // I don't know if it is right
 	return nil
 }
 
+// authenticate returns the user with the given credentials, guarding
+// against brute-force attempts when a lockout guard is set.
+func (s *Service) authenticate(ctx context.Context, username, password string) (*user.User, error) {
+	ip := lockout.ClientIPFromContext(ctx)
+	if s.guard != nil {
+		if err := s.guard.Check(ctx, username, ip); err != nil {
+			return nil, user.NewError(err).AddContext("Username", username)
+		}
+	}
+
+	// Compare the value of user password and the existing user password
+	u, err := s.getAndComparePassword(ctx, username, password)
+	if err != nil {
+		if s.guard != nil && (err == user.ErrInvalidCredentials || err == user.ErrNotFound) {
+			if e := s.guard.Fail(ctx, username, ip); e != nil {
+				log.FromContext(ctx).WithError(e).Error("failed recording login attempt")
+			}
+		}
+		return nil, user.NewError(err)
+	}
+
+	if s.guard != nil {
+		if err := s.guard.Succeed(ctx, username); err != nil {
+			log.FromContext(ctx).WithError(err).Error("failed resetting login attempts")
+		}
+	}
+	return u, nil
+}
+
 func validateUser(usr *user.User) error {
 	if usr.Username == "" {
 		return user.ErrEmptyUsername
@@ -164,55 +522,27 @@ func validateUser(usr *user.User) error {
 	return nil
 }
 
-func GetByUserIDs(ctx context.Context, svc user.GetterByUserID, ids ...string) ([]*user.User, error) {
-	var users []*user.User
-
-	g, ctx := errgroup.WithContext(ctx)
-
-	type result struct {
-		usr *user.User
-		err error
-		id  string
+// PasswordOptions creates the password related options from conf.
+func PasswordOptions(conf config.Password) ([]Option, error) {
+	policy := user.DefaultPasswordPolicy
+	if conf.MinLength > 0 {
+		policy.MinLength = conf.MinLength
 	}
-
-	resultChan := make(chan *result)
-	for _, id := range ids {
-		id := id
-		g.Go(func() error {
-			res, err := svc.GetByUserID(ctx, id)
-			select {
-			case <-ctx.Done():
-				return ctx.Err()
-			case resultChan <- &result{usr: res, err: err, id: id}:
-			}
-			return nil
-		})
+	if conf.MinClasses > 0 {
+		policy.MinClasses = conf.MinClasses
 	}
 
-	go func() {
-		if err := g.Wait(); err != nil {
-			golog.Error(err)
-			return
-		}
-		close(resultChan)
-	}()
-
-	errMsg := ""
-	for res := range resultChan {
-		if res.err == nil {
-			users = append(users, res.usr)
-		} else {
-			if res.err == user.ErrNotFound {
-				errMsg += fmt.Sprintf("user with id '%s' not exist\n", res.id)
-			} else {
-				errMsg += fmt.Sprintf("%s\n", res.err.Error())
-			}
-		}
+	opts := []Option{
+		WithPasswordPolicy(policy),
+		WithPasswordHasher(user.NewBcryptHasher(conf.Cost)),
 	}
 
-	if errMsg != "" {
-		return users, errors.New(errMsg)
+	if conf.BreachList != "" {
+		list, err := user.LoadHashList(conf.BreachList)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, WithBreachChecker(list))
 	}
-
-	return users, nil
+	return opts, nil
 }
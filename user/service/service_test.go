@@ -4,10 +4,18 @@ package service
 
 import (
 	"context"
+	"crypto/sha1"
+	"encoding/hex"
 	"errors"
 	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+	"gophr.v2/user/lockout"
+	"gophr.v2/user/lockout/memory"
 	"gophr.v2/user/userutil"
 	"gophr.v2/util/valueutil"
+	eventmocks "gophr.v2/event/mocks"
+	sessionmocks "gophr.v2/session/mocks"
+	"strings"
 	"testing"
 	"time"
 
@@ -155,6 +163,44 @@ func TestService_Register(t *testing.T) {
 		repo.AssertExpectations(t)
 	})
 
+	t.Run("Emits Registered Without The Password", func(t *testing.T) {
+		repo := new(mocks.Repository)
+		repo.On("Save", mock.Anything, mock.AnythingOfType("*user.User")).Return(nil).Once()
+		repo.On("GetByEmail", mock.Anything, mock.AnythingOfType("string")).Return(nil, user.ErrNotFound).Once()
+		publisher := new(eventmocks.Publisher)
+		publisher.On("Publish", mock.Anything, mock.MatchedBy(func(e *user.Registered) bool {
+			return e.User.Username == "luffy.monkey" && e.User.Password == ""
+		})).Return(nil).Once()
+		svc := New(repo, WithEvents(publisher))
+
+		input := &user.User{
+			Username: "luffy.monkey",
+			Email:    "luffy.monkey@gmail.com",
+			Password: "iampirateking",
+		}
+		err := svc.Register(context.Background(), input)
+		assert.NoError(t, err)
+		assert.NotEmpty(t, input.Password)
+		publisher.AssertExpectations(t)
+	})
+
+	t.Run("Username Already Taken", func(t *testing.T) {
+		repo := new(mocks.Repository)
+		repo.On("GetByEmail", mock.Anything, mock.AnythingOfType("string")).Return(nil, user.ErrNotFound).Once()
+		repo.On("Save", mock.Anything, mock.AnythingOfType("*user.User")).Return(user.ErrUserNameExists).Once()
+		svc := New(repo)
+
+		input := &user.User{
+			Username: "luffy.monkey",
+			Email:    "luffy.monkey@gmail.com",
+			Password: "iampirateking",
+		}
+		err := svc.Register(context.Background(), input)
+		require.IsType(t, new(user.Error), err)
+		assert.Equal(t, user.ErrUserExists, errors.Unwrap(err))
+		repo.AssertExpectations(t)
+	})
+
 	t.Run("During Registration Username is Empty", func(t *testing.T) {
 		repo := new(mocks.Repository)
 		svc := New(repo)
@@ -274,6 +320,128 @@ func TestService_Login(t *testing.T) {
 		repo.AssertExpectations(t)
 	})
 
+	t.Run("Locked Account", func(t *testing.T) {
+		hash, err := bcrypt.GenerateFromPassword([]byte("iampirateking"), bcrypt.MinCost)
+		require.NoError(t, err)
+
+		repo := new(mocks.Repository)
+		repo.On("GetByUsername", mock.Anything, "luffy.monkey").Return(func(context.Context, string) *user.User {
+			return &user.User{Username: "luffy.monkey", Password: string(hash)}
+		}, nil)
+
+		policy := lockout.DefaultAccountPolicy
+		policy.FreeAttempts = 2
+		policy.MaxAttempts = 3
+		guard := lockout.New(memory.New(), policy, lockout.DefaultIPPolicy)
+		svc := New(repo, WithLockout(guard))
+		ctx := lockout.WithClientIP(context.Background(), "127.0.0.1")
+
+		for i := 0; i < policy.MaxAttempts; i++ {
+			err = svc.Login(ctx, &user.User{Username: "luffy.monkey", Password: "invalidpassword"})
+			require.Error(t, err)
+			assert.Equal(t, user.ErrInvalidCredentials, errors.Unwrap(err))
+		}
+
+		// Even the valid password is rejected while locked
+		err = svc.Login(ctx, &user.User{Username: "luffy.monkey", Password: "iampirateking"})
+		require.Error(t, err)
+		assert.Equal(t, user.ErrAccountLocked, errors.Unwrap(err))
+	})
+	t.Run("Outdated Hash Is Upgraded", func(t *testing.T) {
+		hash, err := bcrypt.GenerateFromPassword([]byte("iampirateking"), bcrypt.MinCost)
+		require.NoError(t, err)
+
+		stored := &user.User{ID: 1, Username: "luffy.monkey", Password: string(hash)}
+		repo := new(mocks.Repository)
+		repo.On("GetByUsername", mock.Anything, "luffy.monkey").Return(stored, nil).Once()
+		repo.On("Update", mock.Anything, mock.MatchedBy(func(u *user.User) bool {
+			cost, err := bcrypt.Cost([]byte(u.Password))
+			return err == nil && cost == bcrypt.MinCost+1
+		})).Return(nil).Once()
+
+		svc := New(repo, WithPasswordHasher(user.NewBcryptHasher(bcrypt.MinCost+1)))
+		err = svc.Login(context.Background(), &user.User{Username: "luffy.monkey", Password: "iampirateking"})
+		assert.NoError(t, err)
+		repo.AssertExpectations(t)
+	})
+}
+
+func TestService_ChangePassword(t *testing.T) {
+	hasher := user.NewBcryptHasher(bcrypt.MinCost)
+	newRepo := func(t *testing.T) *mocks.Repository {
+		hash, err := hasher.Hash("iampirateking")
+		require.NoError(t, err)
+		repo := new(mocks.Repository)
+		repo.On("GetByUserID", mock.Anything, "userid123").Return(&user.User{
+			UserID:   "userid123",
+			Username: "luffy.monkey",
+			Email:    "luffy.monkey@gmail.com",
+			Password: hash,
+		}, nil).Once()
+		return repo
+	}
+
+	t.Run("Valid", func(t *testing.T) {
+		repo := newRepo(t)
+		repo.On("Update", mock.Anything, mock.MatchedBy(func(u *user.User) bool {
+			return hasher.Compare(u.Password, "Gum-Gum-Pistol") == nil && u.UpdatedAt != nil
+		})).Return(nil).Once()
+		sessions := new(sessionmocks.Service)
+		sessions.On("DeleteAllForUser", mock.Anything, "userid123").Return(nil).Once()
+
+		svc := New(repo, WithPasswordHasher(hasher), WithSessions(sessions))
+		err := svc.ChangePassword(context.Background(), "userid123", "iampirateking", "Gum-Gum-Pistol")
+		assert.NoError(t, err)
+		repo.AssertExpectations(t)
+		sessions.AssertExpectations(t)
+	})
+
+	t.Run("Wrong Current Password", func(t *testing.T) {
+		repo := newRepo(t)
+		sessions := new(sessionmocks.Service)
+		svc := New(repo, WithPasswordHasher(hasher), WithSessions(sessions))
+		err := svc.ChangePassword(context.Background(), "userid123", "wrongpassword", "Gum-Gum-Pistol")
+		require.Error(t, err)
+		assert.Equal(t, user.ErrInvalidCredentials, errors.Unwrap(err))
+		repo.AssertExpectations(t)
+		sessions.AssertNotCalled(t, "DeleteAllForUser", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Weak New Password", func(t *testing.T) {
+		repo := newRepo(t)
+		svc := New(repo, WithPasswordHasher(hasher))
+		err := svc.ChangePassword(context.Background(), "userid123", "iampirateking", "luffy.monkey!")
+		require.Error(t, err)
+		assert.Equal(t, user.ErrPasswordContainsUserInfo, errors.Unwrap(err))
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("Failing Update", func(t *testing.T) {
+		repo := newRepo(t)
+		repo.On("Update", mock.Anything, mock.AnythingOfType("*user.User")).Return(user.ErrNotFound).Once()
+		sessions := new(sessionmocks.Service)
+
+		svc := New(repo, WithPasswordHasher(hasher), WithSessions(sessions))
+		err := svc.ChangePassword(context.Background(), "userid123", "iampirateking", "Gum-Gum-Pistol")
+		require.Error(t, err)
+		assert.IsType(t, &user.Error{}, err)
+		assert.Equal(t, user.ErrNotFound, errors.Unwrap(err))
+		repo.AssertExpectations(t)
+		sessions.AssertNotCalled(t, "DeleteAllForUser", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Breached New Password", func(t *testing.T) {
+		repo := newRepo(t)
+		sum := sha1.Sum([]byte("Gum-Gum-Pistol"))
+		list, err := user.NewHashList(strings.NewReader(hex.EncodeToString(sum[:])))
+		require.NoError(t, err)
+
+		svc := New(repo, WithPasswordHasher(hasher), WithBreachChecker(list))
+		err = svc.ChangePassword(context.Background(), "userid123", "iampirateking", "Gum-Gum-Pistol")
+		require.Error(t, err)
+		assert.Equal(t, user.ErrPasswordBreached, errors.Unwrap(err))
+		repo.AssertExpectations(t)
+	})
 }
 
 func TestService_Update(t *testing.T) {
@@ -312,6 +480,23 @@ func TestService_Update(t *testing.T) {
 		assert.IsType(t, new(user.Error), err)
 		assert.Equal(t, user.ErrUserNotExists, errors.Unwrap(err))
 	})
+
+	t.Run("Invalid Profile", func(t *testing.T) {
+		repo := new(mocks.Repository)
+		input := &user.User{
+			ID:       12345,
+			UserID:   userutil.GenerateID(),
+			Username: "luffy.monkey",
+			Email:    "luffy.monkey@gmail.com",
+			Website:  "javascript:alert(1)",
+		}
+
+		svc := New(repo)
+		err := svc.Update(context.Background(), input)
+		require.Error(t, err)
+		assert.Equal(t, user.ErrInvalidWebsite, errors.Unwrap(err))
+		repo.AssertExpectations(t)
+	})
 }
 
 func TestService_Delete(t *testing.T) {
@@ -330,6 +515,138 @@ func TestService_Delete(t *testing.T) {
 	repo.AssertExpectations(t)
 }
 
+func TestService_DeleteAccount(t *testing.T) {
+	hasher := user.NewBcryptHasher(bcrypt.MinCost)
+	hash, err := hasher.Hash("iampirateking")
+	require.NoError(t, err)
+	newRepo := func(deletedAt *time.Time) *mocks.Repository {
+		repo := new(mocks.Repository)
+		repo.On("GetByUserID", mock.Anything, "userid123").Return(&user.User{
+			UserID:    "userid123",
+			Username:  "luffy.monkey",
+			Password:  hash,
+			DeletedAt: deletedAt,
+		}, nil).Once()
+		return repo
+	}
+
+	t.Run("Valid", func(t *testing.T) {
+		now := time.Now()
+		repo := newRepo(nil)
+		repo.On("SoftDelete", mock.Anything, "userid123", now.UTC()).Return(nil).Once()
+		sessions := new(sessionmocks.Service)
+		sessions.On("DeleteAllForUser", mock.Anything, "userid123").Return(nil).Once()
+
+		svc := New(repo, WithPasswordHasher(hasher), WithSessions(sessions))
+		svc.now = func() time.Time { return now }
+		err := svc.DeleteAccount(context.Background(), "userid123", "iampirateking")
+		assert.NoError(t, err)
+		repo.AssertExpectations(t)
+		sessions.AssertExpectations(t)
+	})
+
+	t.Run("Wrong Password", func(t *testing.T) {
+		repo := newRepo(nil)
+		sessions := new(sessionmocks.Service)
+		svc := New(repo, WithPasswordHasher(hasher), WithSessions(sessions))
+		err := svc.DeleteAccount(context.Background(), "userid123", "wrongpassword")
+		require.Error(t, err)
+		assert.Equal(t, user.ErrInvalidCredentials, errors.Unwrap(err))
+		repo.AssertNotCalled(t, "SoftDelete", mock.Anything, mock.Anything, mock.Anything)
+		sessions.AssertNotCalled(t, "DeleteAllForUser", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Already Deleted", func(t *testing.T) {
+		repo := newRepo(valueutil.TimePointer(time.Now()))
+		svc := New(repo, WithPasswordHasher(hasher))
+		err := svc.DeleteAccount(context.Background(), "userid123", "iampirateking")
+		require.Error(t, err)
+		assert.Equal(t, user.ErrAccountDeleted, errors.Unwrap(err))
+	})
+}
+
+func TestService_RestoreAccount(t *testing.T) {
+	hasher := user.NewBcryptHasher(bcrypt.MinCost)
+	hash, err := hasher.Hash("iampirateking")
+	require.NoError(t, err)
+	now := time.Now()
+	newRepo := func(deletedAt *time.Time) *mocks.Repository {
+		repo := new(mocks.Repository)
+		repo.On("GetByUsername", mock.Anything, "luffy.monkey").Return(&user.User{
+			UserID:    "userid123",
+			Username:  "luffy.monkey",
+			Password:  hash,
+			DeletedAt: deletedAt,
+		}, nil).Once()
+		return repo
+	}
+	newService := func(repo user.Repository) *Service {
+		svc := New(repo, WithPasswordHasher(hasher), WithDeletionGracePeriod(24*time.Hour))
+		svc.now = func() time.Time { return now }
+		return svc
+	}
+
+	t.Run("Within Grace Period", func(t *testing.T) {
+		repo := newRepo(valueutil.TimePointer(now.Add(-23 * time.Hour)))
+		repo.On("Restore", mock.Anything, "userid123").Return(nil).Once()
+
+		usr := &user.User{Username: "luffy.monkey", Password: "iampirateking"}
+		err := newService(repo).RestoreAccount(context.Background(), usr)
+		assert.NoError(t, err)
+		assert.Equal(t, "userid123", usr.UserID)
+		assert.Empty(t, usr.Password)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("Grace Period Over", func(t *testing.T) {
+		repo := newRepo(valueutil.TimePointer(now.Add(-24 * time.Hour)))
+		err := newService(repo).RestoreAccount(context.Background(), &user.User{Username: "luffy.monkey", Password: "iampirateking"})
+		require.Error(t, err)
+		assert.Equal(t, user.ErrNotFound, errors.Unwrap(err))
+		repo.AssertNotCalled(t, "Restore", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Not Deleted", func(t *testing.T) {
+		repo := newRepo(nil)
+		err := newService(repo).RestoreAccount(context.Background(), &user.User{Username: "luffy.monkey", Password: "iampirateking"})
+		require.Error(t, err)
+		assert.Equal(t, user.ErrAccountNotDeleted, errors.Unwrap(err))
+	})
+
+	t.Run("Deleted Account Can't Login", func(t *testing.T) {
+		repo := newRepo(valueutil.TimePointer(now))
+		err := newService(repo).Login(context.Background(), &user.User{Username: "luffy.monkey", Password: "iampirateking"})
+		require.Error(t, err)
+		assert.Equal(t, user.ErrAccountDeleted, errors.Unwrap(err))
+	})
+}
+
+func TestService_Purge(t *testing.T) {
+	now := time.Now()
+	deleted := []*user.User{
+		{ID: 1, UserID: "userid1"},
+		{ID: 2, UserID: "userid2"},
+	}
+
+	repo := new(mocks.Repository)
+	repo.On("GetAllDeleted", mock.Anything, now.UTC().Add(-time.Hour), purgeBatchSize).Return(deleted, nil).Once()
+	repo.On("Purge", mock.Anything, uint(1)).Return(nil).Once()
+	repo.On("Purge", mock.Anything, uint(2)).Return(nil).Once()
+
+	publisher := new(eventmocks.Publisher)
+	for _, usr := range deleted {
+		publisher.On("Publish", mock.Anything, &user.Purged{UserID: usr.UserID}).Return(nil).Once()
+	}
+
+	svc := New(repo, WithEvents(publisher), WithDeletionGracePeriod(time.Hour))
+	svc.now = func() time.Time { return now }
+	n, err := svc.Purge(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 2, n)
+	repo.AssertExpectations(t)
+	publisher.AssertExpectations(t)
+}
+
 func TestService_GetAll(t *testing.T) {
 	// Add the mock users to the rows
 	mockUsers := []*user.User{
@@ -372,24 +689,13 @@ func TestService_GetAll(t *testing.T) {
 	repo.AssertExpectations(t)
 }
 
-type svcStub struct {
-	user.Service
-	data map[string]*user.User
-}
-
-func (s *svcStub) GetByUserID(ctx context.Context, id string) (*user.User, error) {
-	u := s.data[id]
-	return u, nil
-}
-
-func TestGetByUserIDs(t *testing.T) {
+func TestService_GetByUserIDs(t *testing.T) {
 	want := []*user.User{
 		{
 			ID:        1,
 			UserID:    userutil.GenerateID(),
 			Username:  "luffy.monkey",
 			Email:     "luffy.monkey@gmail.com",
-			Password:  "qwqewrt",
 			CreatedAt: valueutil.TimePointer(time.Now()),
 		},
 		{
@@ -397,20 +703,38 @@ func TestGetByUserIDs(t *testing.T) {
 			UserID:    userutil.GenerateID(),
 			Username:  "sanji.vinsmoke",
 			Email:     "sanji.vinsmoke@gmail.com",
-			Password:  "qwqewrt",
 			CreatedAt: valueutil.TimePointer(time.Now()),
 		},
 	}
-	svc := &svcStub{
-		data: map[string]*user.User{
-			want[0].UserID: want[0],
-			want[1].UserID: want[1],
-		},
-	}
 
-	got, err := GetByUserIDs(context.Background(), svc, want[0].UserID, want[1].UserID)
-	assert.NoError(t, err)
+	t.Run("Found", func(t *testing.T) {
+		ids := []string{want[0].UserID, want[1].UserID}
+		repo := new(mocks.Repository)
+		repo.On("GetByUserIDs", mock.Anything, ids).Return(want, nil).Once()
+
+		got, missing, err := New(repo).GetByUserIDs(context.Background(), ids)
+		require.NoError(t, err)
+		assert.Equal(t, want, got)
+		assert.Empty(t, missing)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("Missing", func(t *testing.T) {
+		ids := []string{"missing", want[0].UserID, "missing", "gone"}
+		repo := new(mocks.Repository)
+		repo.On("GetByUserIDs", mock.Anything, ids).Return(want[:1], nil).Once()
 
-	assert.Equal(t, want, got)
+		got, missing, err := New(repo).GetByUserIDs(context.Background(), ids)
+		require.NoError(t, err)
+		assert.Equal(t, want[:1], got)
+		assert.Equal(t, []string{"missing", "gone"}, missing)
+	})
 
+	t.Run("Error", func(t *testing.T) {
+		repo := new(mocks.Repository)
+		repo.On("GetByUserIDs", mock.Anything, mock.Anything).Return(nil, errors.New("unexpected")).Once()
+
+		_, _, err := New(repo).GetByUserIDs(context.Background(), []string{want[0].UserID})
+		require.IsType(t, new(user.Error), err)
+	})
 }
@@ -5,18 +5,30 @@ import (
 	"fmt"
 	"github.com/go-playground/validator/v10"
 	"github.com/rs/xid"
-	"golang.org/x/crypto/bcrypt"
+	"net/url"
 	"time"
+	"unicode/utf8"
 )
 
 var validate = validator.New()
 
+// Limits of the profile fields.
+const (
+	MaxDisplayNameLength = 50
+	MaxBioLength         = 160
+)
+
 type User struct {
 	UserID   string `json:"userId,omitempty" gorm:"user_id"`
 	Username string `json:"username,omitempty" validate:"required" gorm:"username"`
 	Email    string `json:"email,omitempty" validate:"required,email" gorm:"email"`
 	Password string `json:"password,omitempty" validate:"required,gte=8,lte=130" gorm:"password"`
 
+	// Profile
+	DisplayName string `json:"displayName,omitempty" gorm:"display_name"`
+	Bio         string `json:"bio,omitempty" gorm:"bio"`
+	Website     string `json:"website,omitempty" gorm:"website"`
+
 	// Base
 	ID        uint       `json:"id,omitempty"`
 	CreatedAt *time.Time `json:"createdAt,omitempty"`
@@ -29,12 +41,50 @@ func (u *User) Clone() *User {
 	return &cpy
 }
 
+// IsDeleted tells whether the user is scheduled for deletion.
+func (u *User) IsDeleted() bool {
+	return u.DeletedAt != nil
+}
+
 func (u *User) AvatarURL() string {
 	return fmt.Sprintf("//www.gravatar.com/avatar/%x", md5.Sum([]byte(u.Email)))
 }
 
+// Name returns the display name of the user, or the
+// username when no display name is set.
+func (u *User) Name() string {
+	if u.DisplayName != "" {
+		return u.DisplayName
+	}
+	return u.Username
+}
+
+// ProfileRoute returns the public profile page of the user.
+func (u *User) ProfileRoute() string {
+	return "/users/" + url.PathEscape(u.Username)
+}
+
+// ImagesRoute returns the page listing the images of the user,
+// which is the gallery on the user's profile.
 func (u *User) ImagesRoute() string {
-	return "/v1/user/" + u.UserID
+	return u.ProfileRoute()
+}
+
+// ValidateProfile checks the editable profile fields.
+func (u *User) ValidateProfile() error {
+	if utf8.RuneCountInString(u.DisplayName) > MaxDisplayNameLength {
+		return ErrDisplayNameTooLong
+	}
+	if utf8.RuneCountInString(u.Bio) > MaxBioLength {
+		return ErrBioTooLong
+	}
+	if u.Website != "" {
+		site, err := url.Parse(u.Website)
+		if err != nil || (site.Scheme != "http" && site.Scheme != "https") || site.Host == "" {
+			return ErrInvalidWebsite
+		}
+	}
+	return nil
 }
 
 func GenerateID() string {
@@ -53,12 +103,17 @@ func NewUser(username, email, password string) (*User, error) {
 		return nil, err
 	}
 
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), 10)
+	err = DefaultPasswordPolicy.Validate(password, user)
+	if err != nil {
+		return nil, err
+	}
+
+	hashedPassword, err := DefaultPasswordHasher.Hash(password)
 	if err != nil {
 		return nil, err
 	}
 
-	user.Password = string(hashedPassword)
+	user.Password = hashedPassword
 	user.UserID = GenerateID()
 	return user, nil
 }
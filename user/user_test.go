@@ -0,0 +1,44 @@
+//+build unit
+
+package user
+
+import (
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func TestUser_ValidateProfile(t *testing.T) {
+	tests := map[string]struct {
+		usr  *User
+		want error
+	}{
+		"Empty":             {usr: &User{}, want: nil},
+		"Valid":             {usr: &User{DisplayName: "Monkey D. Luffy", Bio: "Future pirate king", Website: "https://gophr.com/luffy"}, want: nil},
+		"Long Display Name": {usr: &User{DisplayName: strings.Repeat("a", MaxDisplayNameLength+1)}, want: ErrDisplayNameTooLong},
+		"Long Bio":          {usr: &User{Bio: strings.Repeat("海", MaxBioLength+1)}, want: ErrBioTooLong},
+		"Multibyte Bio":     {usr: &User{Bio: strings.Repeat("海", MaxBioLength)}, want: nil},
+		"Script Website":    {usr: &User{Website: "javascript:alert(1)"}, want: ErrInvalidWebsite},
+		"Relative Website":  {usr: &User{Website: "gophr.com"}, want: ErrInvalidWebsite},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.usr.ValidateProfile())
+		})
+	}
+}
+
+func TestUser_Name(t *testing.T) {
+	usr := &User{Username: "luffy"}
+	assert.Equal(t, "luffy", usr.Name())
+
+	usr.DisplayName = "Monkey D. Luffy"
+	assert.Equal(t, "Monkey D. Luffy", usr.Name())
+}
+
+func TestUser_ProfileRoute(t *testing.T) {
+	usr := &User{Username: "luffy monkey"}
+	assert.Equal(t, "/users/luffy%20monkey", usr.ProfileRoute())
+	assert.Equal(t, usr.ProfileRoute(), usr.ImagesRoute())
+}
@@ -1,7 +1,47 @@
 package userutil
 
-import "gophr.v2/util/randutil"
+import (
+	"gophr.v2/user"
+	"gophr.v2/util/randutil"
+)
 
 func GenerateID() string {
 	return randutil.GenerateID("user")
 }
+
+// OrderByUserIDs returns the users in the order of userIDs. The
+// users not in userIDs are left out, and a user whose ID is listed
+// more than once is returned once.
+func OrderByUserIDs(users []*user.User, userIDs []string) []*user.User {
+	byID := make(map[string]*user.User, len(users))
+	for _, usr := range users {
+		byID[usr.UserID] = usr
+	}
+
+	ordered := make([]*user.User, 0, len(users))
+	for _, id := range userIDs {
+		if usr, ok := byID[id]; ok {
+			ordered = append(ordered, usr)
+			delete(byID, id)
+		}
+	}
+	return ordered
+}
+
+// MissingUserIDs returns the IDs of userIDs which none of the users
+// have, once each and in order.
+func MissingUserIDs(users []*user.User, userIDs []string) []string {
+	seen := make(map[string]bool, len(userIDs))
+	for _, usr := range users {
+		seen[usr.UserID] = true
+	}
+
+	missing := make([]string, 0)
+	for _, id := range userIDs {
+		if !seen[id] {
+			missing = append(missing, id)
+			seen[id] = true
+		}
+	}
+	return missing
+}
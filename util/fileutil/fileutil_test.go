@@ -0,0 +1,39 @@
+//+build unit
+
+package fileutil
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWriteFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gophr")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	filename := filepath.Join(dir, "users.db")
+	require.NoError(t, ioutil.WriteFile(filename, []byte("old"), 0644))
+	require.NoError(t, WriteFile(filename, []byte("new"), 0600))
+
+	got, err := ioutil.ReadFile(filename)
+	require.NoError(t, err)
+	assert.Equal(t, "new", string(got))
+
+	info, err := os.Stat(filename)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	files, err := ioutil.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Len(t, files, 1, "no temporary file is left behind")
+
+	t.Run("Missing directory", func(t *testing.T) {
+		err := WriteFile(filepath.Join(dir, "missing", "users.db"), []byte("new"), 0644)
+		assert.Error(t, err)
+	})
+}
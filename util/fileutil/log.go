@@ -0,0 +1,290 @@
+package fileutil
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+)
+
+// Compaction of the logs. A log is rewritten with only its live values
+// once it holds more than CompactRatio records per live value, and at
+// least MinCompactRecords records.
+const (
+	MinCompactRecords = 1000
+	CompactRatio      = 2
+)
+
+const filePerm = 0666
+
+// ErrCorrupted is returned when a log has an unreadable record
+// before its last one.
+var ErrCorrupted = errors.New("fileutil: corrupted log")
+
+// record is a line of a log. It sets the value of the key, or
+// removes it when deleted.
+type record struct {
+	Key     string          `json:"key"`
+	Value   json.RawMessage `json:"value,omitempty"`
+	Deleted bool            `json:"deleted,omitempty"`
+}
+
+// Log persists JSON values by key in an append-only file, one
+// record per line. Every change is synced to the disk before it
+// returns, and a record torn by a crash is dropped when the log
+// is opened again.
+//
+// A Log is safe for concurrent use.
+type Log struct {
+	mu         sync.Mutex
+	path       string
+	file       *os.File
+	size       int64
+	live       map[string]json.RawMessage
+	records    int
+	minCompact int
+}
+
+// OpenLog opens the log at path, creating it when it doesn't exist.
+//
+// A file holding a single JSON object, like the ones written before
+// the logs, is read as the values by key and rewritten as a log.
+func OpenLog(path string) (*Log, error) {
+	if dir := filepath.Dir(path); dir != "." {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return nil, err
+		}
+	}
+
+	l := &Log{
+		path:       path,
+		live:       make(map[string]json.RawMessage),
+		minCompact: MinCompactRecords,
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	var rewrite bool
+	if isObject(data) {
+		if err := json.Unmarshal(data, &l.live); err != nil {
+			return nil, fmt.Errorf("%w: %s: %v", ErrCorrupted, path, err)
+		}
+		rewrite = true
+	} else if rewrite, err = l.replay(data); err != nil {
+		return nil, err
+	}
+
+	if rewrite {
+		if err := l.compact(); err != nil {
+			return nil, err
+		}
+		return l, nil
+	}
+	if err := l.open(); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+// isObject tells whether data is an indented JSON object rather than
+// records. A record always fits on a single line.
+func isObject(data []byte) bool {
+	line := data
+	if i := bytes.IndexByte(data, '\n'); i >= 0 {
+		line = data[:i]
+	}
+	line = bytes.TrimSpace(line)
+	return bytes.Equal(line, []byte("{")) || bytes.Equal(line, []byte("{}"))
+}
+
+// replay applies the records of data. It tells whether the log has
+// to be rewritten because its last record was torn.
+func (l *Log) replay(data []byte) (torn bool, err error) {
+	lines := bytes.Split(data, []byte("\n"))
+	for i, line := range lines {
+		if len(line) == 0 {
+			continue
+		}
+
+		var rec record
+		if err := json.Unmarshal(line, &rec); err != nil {
+			// Only the last record can be torn by a crash
+			if i == len(lines)-1 {
+				return true, nil
+			}
+			return false, fmt.Errorf("%w: %s:%d: %v", ErrCorrupted, l.path, i+1, err)
+		}
+		l.apply(rec)
+	}
+	return false, nil
+}
+
+func (l *Log) apply(rec record) {
+	if rec.Deleted {
+		delete(l.live, rec.Key)
+	} else {
+		l.live[rec.Key] = rec.Value
+	}
+	l.records++
+}
+
+// open opens the file of the log for appending.
+func (l *Log) open() error {
+	file, err := os.OpenFile(l.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, filePerm)
+	if err != nil {
+		return err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return err
+	}
+	l.file, l.size = file, info.Size()
+	return nil
+}
+
+// Values returns the live values by key.
+func (l *Log) Values() map[string]json.RawMessage {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	values := make(map[string]json.RawMessage, len(l.live))
+	for k, v := range l.live {
+		values[k] = v
+	}
+	return values
+}
+
+// Put sets the value of key to v encoded as JSON.
+func (l *Log) Put(key string, v interface{}) error {
+	value, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.append(record{Key: key, Value: value})
+}
+
+// Delete removes the values of keys. The unknown keys are ignored.
+func (l *Log) Delete(keys ...string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	records := make([]record, 0, len(keys))
+	for _, key := range keys {
+		if _, ok := l.live[key]; ok {
+			records = append(records, record{Key: key, Deleted: true})
+		}
+	}
+	return l.append(records...)
+}
+
+// append writes the records at once and applies them when they're
+// on the disk.
+func (l *Log) append(records ...record) error {
+	if len(records) == 0 {
+		return nil
+	}
+	if l.file == nil {
+		return os.ErrClosed
+	}
+
+	var buf bytes.Buffer
+	for _, rec := range records {
+		line, err := json.Marshal(rec)
+		if err != nil {
+			return err
+		}
+		buf.Write(line)
+		buf.WriteByte('\n')
+	}
+
+	if err := l.write(buf.Bytes()); err != nil {
+		return err
+	}
+	for _, rec := range records {
+		l.apply(rec)
+	}
+
+	if l.records >= l.minCompact && l.records > CompactRatio*len(l.live) {
+		return l.compact()
+	}
+	return nil
+}
+
+// write appends data to the file. A partial write is truncated so
+// that the next records don't follow a torn one.
+func (l *Log) write(data []byte) error {
+	n, err := l.file.Write(data)
+	if err == nil {
+		err = l.file.Sync()
+	}
+	if err != nil {
+		if n > 0 {
+			_ = l.file.Truncate(l.size)
+		}
+		return err
+	}
+	l.size += int64(n)
+	return nil
+}
+
+// Compact rewrites the log with only the live values.
+func (l *Log) Compact() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.compact()
+}
+
+func (l *Log) compact() error {
+	keys := make([]string, 0, len(l.live))
+	for key := range l.live {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var buf bytes.Buffer
+	for _, key := range keys {
+		line, err := json.Marshal(record{Key: key, Value: l.live[key]})
+		if err != nil {
+			return err
+		}
+		buf.Write(line)
+		buf.WriteByte('\n')
+	}
+
+	if err := WriteFile(l.path, buf.Bytes(), filePerm); err != nil {
+		return err
+	}
+
+	// The file was replaced so the old one is closed
+	if l.file != nil {
+		_ = l.file.Close()
+		l.file = nil
+	}
+	l.records = len(keys)
+	return l.open()
+}
+
+// Close closes the file of the log.
+func (l *Log) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.file == nil {
+		return nil
+	}
+	err := l.file.Close()
+	l.file = nil
+	return err
+}
@@ -0,0 +1,148 @@
+//+build unit
+
+package fileutil
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func tempPath(t *testing.T) (string, func()) {
+	dir, err := ioutil.TempDir("", "gophr")
+	require.NoError(t, err)
+	return filepath.Join(dir, "data", "test.db"), func() { _ = os.RemoveAll(dir) }
+}
+
+func values(t *testing.T, l *Log) map[string]string {
+	t.Helper()
+	res := make(map[string]string)
+	for k, v := range l.Values() {
+		var s string
+		require.NoError(t, json.Unmarshal(v, &s))
+		res[k] = s
+	}
+	return res
+}
+
+func lines(t *testing.T, path string) []string {
+	t.Helper()
+	data, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+	return strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+}
+
+func TestLog(t *testing.T) {
+	path, teardown := tempPath(t)
+	defer teardown()
+
+	l, err := OpenLog(path)
+	require.NoError(t, err)
+	require.NoError(t, l.Put("luffy", "captain"))
+	require.NoError(t, l.Put("zoro", "swordsman"))
+	require.NoError(t, l.Put("luffy", "pirate king"))
+	require.NoError(t, l.Delete("zoro", "unknown"))
+	assert.Equal(t, map[string]string{"luffy": "pirate king"}, values(t, l))
+	require.NoError(t, l.Close())
+
+	t.Run("Reopen", func(t *testing.T) {
+		l, err := OpenLog(path)
+		require.NoError(t, err)
+		defer l.Close()
+		assert.Equal(t, map[string]string{"luffy": "pirate king"}, values(t, l))
+	})
+
+	t.Run("Closed", func(t *testing.T) {
+		assert.Equal(t, os.ErrClosed, l.Put("nami", "navigator"))
+	})
+}
+
+func TestLog_Torn(t *testing.T) {
+	path, teardown := tempPath(t)
+	defer teardown()
+
+	l, err := OpenLog(path)
+	require.NoError(t, err)
+	require.NoError(t, l.Put("luffy", "captain"))
+	require.NoError(t, l.Close())
+
+	// A crash in the middle of a write
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0)
+	require.NoError(t, err)
+	_, err = f.WriteString(`{"key":"zoro","val`)
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	l, err = OpenLog(path)
+	require.NoError(t, err)
+	defer l.Close()
+	assert.Equal(t, map[string]string{"luffy": "captain"}, values(t, l))
+
+	require.NoError(t, l.Put("zoro", "swordsman"))
+	reopened, err := OpenLog(path)
+	require.NoError(t, err)
+	defer reopened.Close()
+	assert.Equal(t, map[string]string{"luffy": "captain", "zoro": "swordsman"}, values(t, reopened))
+}
+
+func TestLog_Corrupted(t *testing.T) {
+	path, teardown := tempPath(t)
+	defer teardown()
+
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	content := `{"key":"luffy","value":"captain"}` + "\nnot a record\n" + `{"key":"zoro","value":"swordsman"}` + "\n"
+	require.NoError(t, ioutil.WriteFile(path, []byte(content), 0644))
+
+	_, err := OpenLog(path)
+	assert.True(t, errors.Is(err, ErrCorrupted))
+}
+
+func TestLog_Object(t *testing.T) {
+	path, teardown := tempPath(t)
+	defer teardown()
+
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	content := "{\n  \"luffy\": \"captain\",\n  \"zoro\": \"swordsman\"\n}"
+	require.NoError(t, ioutil.WriteFile(path, []byte(content), 0644))
+
+	l, err := OpenLog(path)
+	require.NoError(t, err)
+	defer l.Close()
+	assert.Equal(t, map[string]string{"luffy": "captain", "zoro": "swordsman"}, values(t, l))
+	assert.Equal(t, []string{
+		`{"key":"luffy","value":"captain"}`,
+		`{"key":"zoro","value":"swordsman"}`,
+	}, lines(t, path), "the file is rewritten as a log")
+}
+
+func TestLog_Compact(t *testing.T) {
+	path, teardown := tempPath(t)
+	defer teardown()
+
+	l, err := OpenLog(path)
+	require.NoError(t, err)
+	defer l.Close()
+	l.minCompact = 10
+
+	require.NoError(t, l.Put("zoro", "swordsman"))
+	for i := 0; i < 9; i++ {
+		require.NoError(t, l.Put("luffy", i))
+	}
+	assert.Len(t, lines(t, path), 2, "the overwritten records are dropped")
+
+	require.NoError(t, l.Put("nami", "navigator"))
+	assert.Len(t, lines(t, path), 3, "the log is appended to after compacting")
+
+	require.NoError(t, l.Delete("zoro"))
+	require.NoError(t, l.Compact())
+	assert.Equal(t, []string{
+		`{"key":"luffy","value":8}`,
+		`{"key":"nami","value":"navigator"}`,
+	}, lines(t, path))
+}
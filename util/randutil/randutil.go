@@ -1,6 +1,8 @@
 package randutil
 
 import (
+	"crypto/rand"
+	"encoding/base64"
 	"encoding/hex"
 	"github.com/satori/go.uuid"
 )
@@ -9,3 +11,12 @@ func GenerateID(name string) string {
 	gen := uuid.NewV5(uuid.NewV4(), name)
 	return hex.EncodeToString(gen.Bytes())
 }
+
+// GenerateToken generates a URL safe secret of n random bytes.
+func GenerateToken(n int) string {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b)
+}
@@ -0,0 +1,123 @@
+package view
+
+import (
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"gophr.v2/log"
+	"gophr.v2/util/randutil"
+	"gophr.v2/view/middleware"
+	"html/template"
+	"net/http"
+)
+
+const (
+	// CSRFFieldName is the name of the hidden form field
+	// carrying the CSRF token.
+	CSRFFieldName = "csrf_token"
+
+	// CSRFHeaderName is the header checked when the
+	// request has no CSRF form field.
+	CSRFHeaderName = "X-CSRF-Token"
+
+	// CSRFCookieName is the cookie holding the CSRF token
+	// of visitors without a session.
+	CSRFCookieName = "GophrCSRF"
+
+	csrfTokenKey  = "csrfToken"
+	csrfTokenSize = 32
+)
+
+var (
+	ErrCSRFTokenMissing = errors.New("view: csrf token is missing")
+	ErrCSRFTokenInvalid = errors.New("view: csrf token is invalid")
+)
+
+// VerifyCSRF rejects unsafe requests whose CSRF token doesn't match
+// the token of the session, or of the CSRF cookie when the visitor
+// has no session.
+func (v *ViewHandler) VerifyCSRF(c *gin.Context) {
+	switch c.Request.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace:
+		c.Next()
+		return
+	}
+
+	submitted := c.PostForm(CSRFFieldName)
+	if submitted == "" {
+		submitted = c.GetHeader(CSRFHeaderName)
+	}
+	if submitted == "" {
+		v.rejectCSRF(c, ErrCSRFTokenMissing)
+		return
+	}
+
+	expected := v.storedCSRFToken(c)
+	if expected == "" || subtle.ConstantTimeCompare([]byte(submitted), []byte(expected)) != 1 {
+		v.rejectCSRF(c, ErrCSRFTokenInvalid)
+		return
+	}
+	c.Next()
+}
+
+func (v *ViewHandler) rejectCSRF(c *gin.Context, err error) {
+	log.FromContext(c.Request.Context()).WithError(err).Debug("rejected request without a valid csrf token")
+
+	message := "This form was submitted from another site or has expired. Please reload the page and try again."
+	if err == ErrCSRFTokenMissing {
+		message = "This form is missing its security token. Please reload the page and try again."
+	}
+
+	c.Status(http.StatusForbidden)
+	v.renderTemplate(c, "other/csrf", map[string]interface{}{
+		"Error": message,
+	})
+	c.Abort()
+}
+
+// storedCSRFToken returns the token the request is expected to carry.
+func (v *ViewHandler) storedCSRFToken(c *gin.Context) string {
+	if sess := v.getSessionFromRequest(c); sess != nil {
+		return sess.CSRFToken
+	}
+	token, _ := c.Cookie(CSRFCookieName)
+	return token
+}
+
+// csrfToken returns the token of the request. Sessions without a
+// token get one and visitors without a session get a CSRF cookie.
+func (v *ViewHandler) csrfToken(c *gin.Context) string {
+	if token := c.GetString(csrfTokenKey); token != "" {
+		return token
+	}
+
+	token := v.storedCSRFToken(c)
+	if token == "" {
+		token = randutil.GenerateToken(csrfTokenSize)
+		if sess := v.getSessionFromRequest(c); sess != nil {
+			// Saved along with the other changes of the request
+			sess.CSRFToken = token
+			middleware.SessionChanged(c)
+		} else {
+			http.SetCookie(c.Writer, &http.Cookie{
+				Name:     CSRFCookieName,
+				Value:    token,
+				Path:     v.cookie.Path,
+				Domain:   v.cookie.Domain,
+				Secure:   v.cookie.Secure,
+				HttpOnly: true,
+				SameSite: v.cookie.SameSite,
+			})
+		}
+	}
+
+	c.Set(csrfTokenKey, token)
+	return token
+}
+
+// csrfField returns the hidden form field carrying token.
+func csrfField(token string) template.HTML {
+	return template.HTML(fmt.Sprintf(`<input type="hidden" name="%s" value="%s">`,
+		CSRFFieldName, template.HTMLEscapeString(token)))
+}
@@ -0,0 +1,137 @@
+//+build unit
+
+package view
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gophr.v2/http/httputil"
+	notificationmemory "gophr.v2/notification/repository/memory"
+	notificationservice "gophr.v2/notification/service"
+	"gophr.v2/session"
+	sessionmocks "gophr.v2/session/mocks"
+	"gophr.v2/session/sessionutil"
+	"gophr.v2/user"
+	usermocks "gophr.v2/user/mocks"
+	"html/template"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestMain(m *testing.M) {
+	gin.SetMode(gin.TestMode)
+	code := m.Run()
+	os.Exit(code)
+}
+
+func newTestHandler(sessionService session.Service, userService user.Service) *ViewHandler {
+	return &ViewHandler{
+		usrService:     userService,
+		sessionService: sessionService,
+		notifService:   notificationservice.New(notificationmemory.New()),
+		cookie:         sessionutil.DefaultCookie,
+		templs: template.Must(template.New("").Parse(
+			`{{ define "index/navbar" }}{{ end }}` +
+				`{{ define "other/csrf" }}{{ .Error }}{{ end }}` +
+				`{{ define "test/form" }}<form method="post">{{ .CSRFField }}</form>{{ end }}`)),
+		layout: template.Must(template.New("layout.html").Funcs(funcs).Parse(`{{ yield }}`)),
+	}
+}
+
+func newCSRFRouter(h *ViewHandler) *gin.Engine {
+	r := gin.New()
+	r.GET("/form", func(c *gin.Context) { h.renderTemplate(c, "test/form", nil) })
+	r.POST("/form", h.VerifyCSRF, func(c *gin.Context) { c.Status(http.StatusOK) })
+	return r
+}
+
+func postForm(cookies ...*http.Cookie) func(r *http.Request) {
+	return func(r *http.Request) {
+		r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		for _, cookie := range cookies {
+			r.AddCookie(cookie)
+		}
+	}
+}
+
+func formBody(token string) *strings.Reader {
+	return strings.NewReader(url.Values{CSRFFieldName: {token}}.Encode())
+}
+
+func TestViewHandler_VerifyCSRF(t *testing.T) {
+	t.Run("Anonymous Visitor", func(t *testing.T) {
+		svc := new(sessionmocks.Service)
+		svc.On("Find", mock.Anything, mock.Anything).Return(nil, session.ErrNotFound)
+		r := newCSRFRouter(newTestHandler(svc, nil))
+
+		resp := httputil.PerformRequest(r, http.MethodGet, "/form", nil)
+		assert.Equal(t, http.StatusOK, resp.Code)
+		cookies := resp.Result().Cookies()
+		if assert.Len(t, cookies, 1) {
+			assert.Equal(t, CSRFCookieName, cookies[0].Name)
+			assert.True(t, cookies[0].HttpOnly)
+			assert.Contains(t, resp.Body.String(), `value="`+cookies[0].Value+`"`)
+
+			resp = httputil.PerformRequest(r, http.MethodPost, "/form", formBody(cookies[0].Value), postForm(cookies[0]))
+			assert.Equal(t, http.StatusOK, resp.Code)
+		}
+	})
+
+	t.Run("Session Token", func(t *testing.T) {
+		sess := &session.Session{ID: "sess123", UserID: "user123", CSRFToken: "token123"}
+		svc := new(sessionmocks.Service)
+		svc.On("Find", mock.Anything, sess.ID).Return(sess, nil)
+		usrSvc := new(usermocks.Service)
+		usrSvc.On("GetByUserID", mock.Anything, sess.UserID).Return(&user.User{UserID: sess.UserID}, nil)
+		r := newCSRFRouter(newTestHandler(svc, usrSvc))
+		cookie := &http.Cookie{Name: session.CookieName, Value: sess.ID}
+
+		resp := httputil.PerformRequest(r, http.MethodPost, "/form", formBody("token123"), postForm(cookie))
+		assert.Equal(t, http.StatusOK, resp.Code)
+
+		resp = httputil.PerformRequest(r, http.MethodPost, "/form", formBody("forged"), postForm(cookie))
+		assert.Equal(t, http.StatusForbidden, resp.Code)
+	})
+
+	t.Run("Token Is Created For Session", func(t *testing.T) {
+		sess := &session.Session{ID: "sess123", UserID: "user123"}
+		svc := new(sessionmocks.Service)
+		svc.On("Find", mock.Anything, sess.ID).Return(sess, nil)
+		svc.On("Update", mock.Anything, sess).Return(nil).Once()
+		usrSvc := new(usermocks.Service)
+		usrSvc.On("GetByUserID", mock.Anything, sess.UserID).Return(&user.User{UserID: sess.UserID}, nil)
+		r := newCSRFRouter(newTestHandler(svc, usrSvc))
+
+		resp := httputil.PerformRequest(r, http.MethodGet, "/form", nil, func(r *http.Request) {
+			r.AddCookie(&http.Cookie{Name: session.CookieName, Value: sess.ID})
+		})
+		assert.Equal(t, http.StatusOK, resp.Code)
+		assert.NotEmpty(t, sess.CSRFToken)
+		assert.Contains(t, resp.Body.String(), `value="`+sess.CSRFToken+`"`)
+		assert.Empty(t, resp.Result().Cookies())
+		svc.AssertExpectations(t)
+	})
+
+	t.Run("Missing Token", func(t *testing.T) {
+		svc := new(sessionmocks.Service)
+		svc.On("Find", mock.Anything, mock.Anything).Return(nil, session.ErrNotFound)
+		r := newCSRFRouter(newTestHandler(svc, nil))
+
+		resp := httputil.PerformRequest(r, http.MethodPost, "/form", formBody(""), postForm())
+		assert.Equal(t, http.StatusForbidden, resp.Code)
+		assert.Contains(t, resp.Body.String(), "missing its security token")
+	})
+
+	t.Run("Token Without Cookie", func(t *testing.T) {
+		svc := new(sessionmocks.Service)
+		svc.On("Find", mock.Anything, mock.Anything).Return(nil, session.ErrNotFound)
+		r := newCSRFRouter(newTestHandler(svc, nil))
+
+		resp := httputil.PerformRequest(r, http.MethodPost, "/form", formBody("token123"), postForm())
+		assert.Equal(t, http.StatusForbidden, resp.Code)
+	})
+}
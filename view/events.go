@@ -0,0 +1,80 @@
+package view
+
+import (
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"gophr.v2/log"
+	"gophr.v2/realtime"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	// heartbeatInterval keeps idle connections from being closed
+	// by proxies.
+	heartbeatInterval = 30 * time.Second
+	// reconnectDelay is how long browsers wait before reconnecting.
+	reconnectDelay = 3 * time.Second
+)
+
+// Events streams the new images and the notifications of the current
+// user as Server-Sent Events. Reconnecting browsers send the ID of the
+// last event they got so that the ones they missed are replayed.
+func (v *ViewHandler) Events(c *gin.Context) {
+	var userID string
+	if usr := v.getUserFromCookie(c); usr != nil {
+		userID = usr.UserID
+	}
+
+	lastEventID := c.GetHeader("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = c.Query("lastEventId")
+	}
+
+	events, err := v.events.Subscribe(c.Request.Context(), userID, lastEventID)
+	if err != nil {
+		log.FromContext(c.Request.Context()).WithError(err).Error("failed subscribing to events")
+		c.Status(http.StatusServiceUnavailable)
+		return
+	}
+
+	header := c.Writer.Header()
+	header.Set("Content-Type", "text/event-stream")
+	header.Set("Cache-Control", "no-cache")
+	header.Set("Connection", "keep-alive")
+	header.Set("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+	_, _ = fmt.Fprintf(c.Writer, "retry: %d\n\n", reconnectDelay.Milliseconds())
+	c.Writer.Flush()
+
+	heartbeat := time.NewTicker(heartbeatInterval)
+	defer heartbeat.Stop()
+
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case e, ok := <-events:
+			if !ok {
+				return false
+			}
+			return writeEvent(w, e) == nil
+		case <-heartbeat.C:
+			_, err := io.WriteString(w, ": heartbeat\n\n")
+			return err == nil
+		}
+	})
+}
+
+// writeEvent writes e in the text/event-stream format.
+func writeEvent(w io.Writer, e *realtime.Event) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "id: %s\n", e.ID)
+	fmt.Fprintf(&b, "event: %s\n", e.Type)
+	for _, line := range strings.Split(string(e.Data), "\n") {
+		fmt.Fprintf(&b, "data: %s\n", line)
+	}
+	b.WriteString("\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
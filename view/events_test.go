@@ -0,0 +1,101 @@
+//+build unit
+
+package view
+
+import (
+	"bufio"
+	"context"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gophr.v2/realtime"
+	"gophr.v2/realtime/hub"
+	"gophr.v2/session"
+	sessionmocks "gophr.v2/session/mocks"
+	"gophr.v2/user"
+	usermocks "gophr.v2/user/mocks"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// readEvent reads the next event of an event stream skipping the
+// comments and the retry field.
+func readEvent(t *testing.T, r *bufio.Reader) []string {
+	t.Helper()
+	var lines []string
+	for {
+		line, err := r.ReadString('\n')
+		require.NoError(t, err)
+		line = strings.TrimSuffix(line, "\n")
+		switch {
+		case line == "" && len(lines) > 0:
+			return lines
+		case line == "", strings.HasPrefix(line, ":"), strings.HasPrefix(line, "retry:"):
+			continue
+		}
+		lines = append(lines, line)
+	}
+}
+
+func TestViewHandler_Events(t *testing.T) {
+	sess := &session.Session{ID: "sess123", UserID: "user123"}
+	svc := new(sessionmocks.Service)
+	svc.On("Find", mock.Anything, sess.ID).Return(sess, nil)
+	svc.On("Find", mock.Anything, mock.Anything).Return(nil, session.ErrNotFound)
+	usrSvc := new(usermocks.Service)
+	usrSvc.On("GetByUserID", mock.Anything, sess.UserID).Return(&user.User{UserID: sess.UserID}, nil)
+
+	h := newTestHandler(svc, usrSvc)
+	broker := hub.New(hub.DefaultHistorySize)
+	h.events = broker
+
+	r := gin.New()
+	r.GET("/events", h.Events)
+	srv := httptest.NewServer(r)
+	defer srv.Close()
+
+	ctx := context.Background()
+	missed := &realtime.Event{ID: "1", Type: realtime.TypeImage, Data: []byte(`{"imageId":"img1"}`)}
+	other := &realtime.Event{ID: "2", Type: realtime.TypeNotification, UserID: "user456", Data: []byte(`{}`)}
+	mine := &realtime.Event{ID: "3", Type: realtime.TypeNotification, UserID: sess.UserID, Data: []byte(`{"id":"n1"}`)}
+	for _, e := range []*realtime.Event{missed, other, mine} {
+		require.NoError(t, broker.Publish(ctx, e))
+	}
+
+	stream := func(t *testing.T, lastEventID string, cookies ...*http.Cookie) *http.Response {
+		req, err := http.NewRequest(http.MethodGet, srv.URL+"/events", nil)
+		require.NoError(t, err)
+		req.Header.Set("Last-Event-ID", lastEventID)
+		for _, cookie := range cookies {
+			req.AddCookie(cookie)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+		return resp
+	}
+
+	t.Run("Replays Missed Events Of The User", func(t *testing.T) {
+		resp := stream(t, "1", &http.Cookie{Name: session.CookieName, Value: sess.ID})
+		defer resp.Body.Close()
+
+		body := bufio.NewReader(resp.Body)
+		assert.Equal(t, []string{"id: 3", "event: notification", `data: {"id":"n1"}`}, readEvent(t, body))
+	})
+
+	t.Run("Anonymous Visitors Get Broadcasts Only", func(t *testing.T) {
+		resp := stream(t, "")
+		defer resp.Body.Close()
+
+		body := bufio.NewReader(resp.Body)
+
+		broadcast := &realtime.Event{ID: "4", Type: realtime.TypeImage, Data: []byte(`{"imageId":"img2"}`)}
+		require.NoError(t, broker.Publish(ctx, &realtime.Event{ID: "5", Type: realtime.TypeNotification, UserID: sess.UserID, Data: []byte(`{}`)}))
+		require.NoError(t, broker.Publish(ctx, broadcast))
+		assert.Equal(t, []string{"id: 4", "event: image", `data: {"imageId":"img2"}`}, readEvent(t, body))
+	})
+}
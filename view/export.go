@@ -0,0 +1,90 @@
+package view
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"gophr.v2/export"
+	"gophr.v2/session"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// WithExports lets the users download an archive of their personal data.
+func WithExports(service export.Service) Option {
+	return func(v *ViewHandler) {
+		v.exportService = service
+	}
+}
+
+// exportItem is an export along with its download link.
+type exportItem struct {
+	*export.Export
+	Link    string
+	Expired bool
+}
+
+func (v *ViewHandler) ExportsPage(c *gin.Context) {
+	usr := v.getUserFromCookie(c)
+	exports, err := v.exportService.List(c.Request.Context(), usr.UserID)
+	if err != nil {
+		v.renderErrorTemplate(c, err)
+		return
+	}
+
+	now := time.Now()
+	items := make([]exportItem, 0, len(exports))
+	for _, e := range exports {
+		item := exportItem{Export: e, Expired: e.IsExpired(now)}
+		if e.IsReady() && !item.Expired {
+			item.Link = v.exportService.Link(e)
+		}
+		items = append(items, item)
+	}
+	v.renderTemplate(c, "exports/list", map[string]interface{}{
+		"Exports": items,
+	})
+}
+
+func (v *ViewHandler) HandleRequestExport(c *gin.Context) {
+	usr := v.getUserFromCookie(c)
+	sess := v.getSessionFromRequest(c)
+	_, err := v.exportService.Request(c.Request.Context(), usr.UserID)
+	switch {
+	case errors.Is(err, export.ErrInProgress):
+		v.flash(c, sess, session.FlashWarning, "Your previous export is still being prepared")
+	case err != nil:
+		v.renderErrorTemplate(c, err)
+		return
+	default:
+		v.flash(c, sess, session.FlashSuccess, "Your export is being prepared. You'll be notified when it's ready")
+	}
+	c.Redirect(http.StatusFound, "/v1/account/export")
+}
+
+// DownloadExport sends the archive of a signed download link. The
+// link works without signing in so that it can be shared with a
+// download manager.
+func (v *ViewHandler) DownloadExport(c *gin.Context) {
+	expires, _ := strconv.ParseInt(c.Query("expires"), 10, 64)
+	e, archive, err := v.exportService.Open(c.Request.Context(), c.Param("id"), expires, c.Query("signature"))
+	if err != nil {
+		switch {
+		case errors.Is(err, export.ErrInvalidLink):
+			c.Status(http.StatusForbidden)
+		case errors.Is(err, export.ErrNotFound), errors.Is(err, export.ErrNotReady):
+			c.Status(http.StatusNotFound)
+		}
+		v.renderErrorTemplate(c, err)
+		return
+	}
+	defer archive.Close()
+
+	c.Header("Content-Disposition", `attachment; filename="`+e.Filename()+`"`)
+	c.Header("Cache-Control", "private, no-store")
+	c.Header("Content-Type", "application/zip")
+	c.Header("Content-Length", strconv.FormatInt(e.Size, 10))
+	c.Status(http.StatusOK)
+	_, _ = io.Copy(c.Writer, archive)
+}
@@ -0,0 +1,84 @@
+//+build unit
+
+package view
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gophr.v2/http/httputil"
+	"gophr.v2/session"
+	sessionmocks "gophr.v2/session/mocks"
+	"gophr.v2/user"
+	usermocks "gophr.v2/user/mocks"
+	"gophr.v2/view/middleware"
+	"html/template"
+	"net/http"
+	"testing"
+)
+
+func TestViewHandler_Flash(t *testing.T) {
+	sess := &session.Session{ID: "sess123", UserID: "user123", CSRFToken: "token123"}
+	svc := new(sessionmocks.Service)
+	svc.On("Find", mock.Anything, sess.ID).Return(sess, nil)
+	svc.On("Update", mock.Anything, sess).Return(nil).Twice()
+	usrSvc := new(usermocks.Service)
+	usrSvc.On("GetByUserID", mock.Anything, sess.UserID).Return(&user.User{UserID: sess.UserID}, nil)
+
+	h := newTestHandler(svc, usrSvc)
+	h.templs = template.Must(h.templs.New("test/flash").Parse(`{{ range .Flashes }}[{{ .Level }}:{{ .Message }}]{{ end }}`))
+
+	r := gin.New()
+	r.Use(middleware.SaveSession(svc))
+	r.POST("/flash", func(c *gin.Context) {
+		h.flash(c, h.getSessionFromRequest(c), session.FlashSuccess, "Image Uploaded Successfully")
+		c.Redirect(http.StatusFound, "/")
+	})
+	r.GET("/", func(c *gin.Context) { h.renderTemplate(c, "test/flash", nil) })
+	withSession := func(r *http.Request) {
+		r.AddCookie(&http.Cookie{Name: session.CookieName, Value: sess.ID})
+	}
+
+	resp := httputil.PerformRequest(r, http.MethodPost, "/flash", nil, withSession)
+	assert.Equal(t, http.StatusFound, resp.Code)
+	assert.Equal(t, "/", resp.Header().Get("Location"))
+
+	resp = httputil.PerformRequest(r, http.MethodGet, "/", nil, withSession)
+	assert.Equal(t, "[success:Image Uploaded Successfully]", resp.Body.String())
+
+	t.Run("Shown Only Once", func(t *testing.T) {
+		resp := httputil.PerformRequest(r, http.MethodGet, "/", nil, withSession)
+		assert.Empty(t, resp.Body.String())
+		svc.AssertExpectations(t)
+	})
+
+	t.Run("Query Is Ignored", func(t *testing.T) {
+		resp := httputil.PerformRequest(r, http.MethodGet, "/?flash=Hacked", nil, withSession)
+		assert.Empty(t, resp.Body.String())
+	})
+}
+
+func TestViewHandler_RenderSavesSessionOnce(t *testing.T) {
+	// The flashes are popped and the CSRF token is minted by the same render
+	sess := &session.Session{ID: "sess123", UserID: "user123"}
+	sess.AddFlash(session.FlashSuccess, "Signed in")
+	svc := new(sessionmocks.Service)
+	svc.On("Find", mock.Anything, sess.ID).Return(sess, nil).Once()
+	svc.On("Update", mock.Anything, mock.MatchedBy(func(s *session.Session) bool {
+		return len(s.Flashes) == 0 && s.CSRFToken != ""
+	})).Return(nil).Once()
+	usrSvc := new(usermocks.Service)
+	usrSvc.On("GetByUserID", mock.Anything, sess.UserID).Return(&user.User{UserID: sess.UserID}, nil)
+
+	h := newTestHandler(svc, usrSvc)
+	h.templs = template.Must(h.templs.New("test/flash").Parse(`{{ range .Flashes }}[{{ .Level }}:{{ .Message }}]{{ end }}`))
+	r := gin.New()
+	r.Use(middleware.SaveSession(svc))
+	r.GET("/", func(c *gin.Context) { h.renderTemplate(c, "test/flash", nil) })
+
+	resp := httputil.PerformRequest(r, http.MethodGet, "/", nil, func(r *http.Request) {
+		r.AddCookie(&http.Cookie{Name: session.CookieName, Value: sess.ID})
+	})
+	assert.Equal(t, "[success:Signed in]", resp.Body.String())
+	svc.AssertExpectations(t)
+}
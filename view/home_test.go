@@ -0,0 +1,37 @@
+//+build unit
+
+package view
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gophr.v2/image"
+	"gophr.v2/user"
+	usermocks "gophr.v2/user/mocks"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestViewHandler_ImageOwners(t *testing.T) {
+	// zoro asked to delete the account and is in the grace period
+	usrSvc := new(usermocks.Service)
+	usrSvc.On("GetByUserIDs", mock.Anything, []string{"luffy", "zoro", "luffy"}).
+		Return([]*user.User{{UserID: "luffy", Username: "luffy"}}, []string{"zoro"}, nil).Once()
+	h := newTestHandler(nil, usrSvc)
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+
+	images := []*image.Image{
+		{ImageID: "img1", UserID: "luffy"},
+		{ImageID: "img2", UserID: "zoro"},
+		{ImageID: "img3", UserID: "luffy"},
+	}
+	visible, owners, err := h.imageOwners(c, images)
+	require.NoError(t, err)
+	assert.Equal(t, []*image.Image{images[0], images[2]}, visible)
+	assert.Len(t, owners, 1)
+	usrSvc.AssertExpectations(t)
+}
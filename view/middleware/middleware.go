@@ -1,49 +1,56 @@
 package middleware
 
 import (
+	"errors"
 	"github.com/gin-gonic/gin"
-	"github.com/jayvib/golog"
+	"gophr.v2/log"
 	"gophr.v2/session"
+	"gophr.v2/session/sessionutil"
 	"net/http"
 	"net/url"
-	"strings"
 )
 
-func RequireLogin(sessionService session.Service) gin.HandlerFunc {
+// RequireLogin redirects to the login page when the request has no
+// active session. Sessions in use are renewed and their cookie is
+// rewritten with the new expiry. The renewal is saved along with the
+// other changes of the request, which is why it goes after SaveSession.
+func RequireLogin(sessionService session.Service, cookie sessionutil.Cookie) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		// Get the cookie
-		golog.Debug(c.Request.Cookies())
-		cookieValue, err := c.Cookie(session.CookieName)
-		golog.Debug(cookieValue, err)
-		if err != nil {
-			redirectToLogin(c)
-			return
-		}
-		golog.Debugf("%#v", cookieValue)
-		// Get the detail of the session
-		sess, err := sessionService.Find(c.Request.Context(), cookieValue)
-		if err != nil {
+		sess := Session(c, sessionService)
+		if sess == nil {
 			redirectToLogin(c)
 			return
 		}
-		golog.Debugf("%#v", sess)
 		if sess.IsExpired() {
 			redirectToLogin(c)
 			return
 		}
+
+		// The entries written from now on are the user's
+		ctx := log.WithFields(c.Request.Context(), log.Fields{log.UserIDKey: sess.UserID})
+		c.Request = c.Request.WithContext(ctx)
+
+		sess.IP = c.ClientIP()
+		renewed, err := sessionService.Renew(c.Request.Context(), sess)
+		if err != nil {
+			if errors.Is(err, session.ErrExpired) {
+				sessionutil.ClearCookie(c.Writer, cookie)
+				redirectToLogin(c)
+				return
+			}
+			log.FromContext(ctx).WithError(err).Error("failed renewing session")
+		}
+		if renewed {
+			SessionChanged(c)
+			sessionutil.WriteCookie(c.Writer, sess, cookie)
+		}
 		c.Next()
 	}
 }
 
 func redirectToLogin(c *gin.Context) {
 	next := url.Values{}
-	trimmedUrl := strings.TrimLeftFunc(c.Request.URL.String(), func(r rune) bool {
-		if r == '/' {
-			return true
-		}
-		return false
-	})
-	next.Add("next", url.QueryEscape(trimmedUrl))
+	next.Add("next", c.Request.URL.RequestURI())
 	c.Redirect(http.StatusTemporaryRedirect, "/login?"+next.Encode())
 	c.Abort()
 }
@@ -9,6 +9,7 @@ import (
 	"gophr.v2/http/httputil"
 	"gophr.v2/session"
 	"gophr.v2/session/mocks"
+	"gophr.v2/session/sessionutil"
 	"net/http"
 	"os"
 	"testing"
@@ -30,8 +31,9 @@ func TestRequireLogin(t *testing.T) {
 		}
 		svc := new(mocks.Service)
 		svc.On("Find", mock.Anything, mock.AnythingOfType("string")).Return(sess, nil).Once()
+		svc.On("Renew", mock.Anything, sess).Return(true, nil).Once()
 		r := gin.New()
-		authRouter := r.Use(RequireLogin(svc))
+		authRouter := r.Use(RequireLogin(svc, sessionutil.DefaultCookie))
 		authRouter.GET("/hello", func(c *gin.Context) { c.Status(http.StatusOK) })
 		resp := httputil.PerformRequest(r, http.MethodGet, "/hello", nil, func(r *http.Request) {
 			cookie := &http.Cookie{
@@ -42,6 +44,74 @@ func TestRequireLogin(t *testing.T) {
 			r.AddCookie(cookie)
 		})
 		assert.Equal(t, http.StatusOK, resp.Code)
+		assert.Contains(t, resp.Header().Get("Set-Cookie"), session.CookieName+"="+sess.ID)
+		assert.Contains(t, resp.Header().Get("Set-Cookie"), "HttpOnly")
+		svc.AssertExpectations(t)
+	})
+
+	t.Run("Renewal Saved Once", func(t *testing.T) {
+		sess := &session.Session{
+			ID:     "testing123",
+			Expiry: time.Now().Add(session.Duration),
+		}
+		svc := new(mocks.Service)
+		svc.On("Find", mock.Anything, sess.ID).Return(sess, nil).Once()
+		svc.On("Renew", mock.Anything, sess).Return(true, nil).Once()
+		svc.On("Update", mock.Anything, sess).Return(nil).Once()
+		r := gin.New()
+		r.Use(SaveSession(svc))
+		authRouter := r.Use(RequireLogin(svc, sessionutil.DefaultCookie))
+		authRouter.GET("/hello", func(c *gin.Context) {
+			Session(c, svc).AddFlash(session.FlashInfo, "Hello")
+			SessionChanged(c)
+			c.Status(http.StatusOK)
+		})
+		resp := httputil.PerformRequest(r, http.MethodGet, "/hello", nil, func(r *http.Request) {
+			r.AddCookie(&http.Cookie{Name: session.CookieName, Value: sess.ID})
+		})
+		assert.Equal(t, http.StatusOK, resp.Code)
+		assert.Len(t, sess.Flashes, 1)
+		svc.AssertExpectations(t)
+	})
+
+	t.Run("Recently Seen Session", func(t *testing.T) {
+		sess := &session.Session{
+			ID:       "testing123",
+			Expiry:   time.Now().Add(session.Duration),
+			LastSeen: time.Now(),
+		}
+		svc := new(mocks.Service)
+		svc.On("Find", mock.Anything, mock.AnythingOfType("string")).Return(sess, nil).Once()
+		svc.On("Renew", mock.Anything, sess).Return(false, nil).Once()
+		r := gin.New()
+		authRouter := r.Use(RequireLogin(svc, sessionutil.DefaultCookie))
+		authRouter.GET("/hello", func(c *gin.Context) { c.Status(http.StatusOK) })
+		resp := httputil.PerformRequest(r, http.MethodGet, "/hello", nil, func(r *http.Request) {
+			r.AddCookie(&http.Cookie{Name: session.CookieName, Value: sess.ID})
+		})
+		assert.Equal(t, http.StatusOK, resp.Code)
+		assert.Empty(t, resp.Header().Get("Set-Cookie"))
+		svc.AssertExpectations(t)
+	})
+
+	t.Run("Idle Session", func(t *testing.T) {
+		sess := &session.Session{
+			ID:       "testing123",
+			Expiry:   time.Now().Add(session.Duration),
+			LastSeen: time.Now().Add(-session.Duration),
+		}
+		svc := new(mocks.Service)
+		svc.On("Find", mock.Anything, mock.AnythingOfType("string")).Return(sess, nil).Once()
+		svc.On("Renew", mock.Anything, sess).Return(false, session.ErrExpired).Once()
+		r := gin.New()
+		authRouter := r.Use(RequireLogin(svc, sessionutil.DefaultCookie))
+		authRouter.GET("/hello", func(c *gin.Context) { c.Status(http.StatusOK) })
+		resp := httputil.PerformRequest(r, http.MethodGet, "/hello", nil, func(r *http.Request) {
+			r.AddCookie(&http.Cookie{Name: session.CookieName, Value: sess.ID})
+		})
+		assert.Equal(t, http.StatusTemporaryRedirect, resp.Code)
+		assert.Contains(t, resp.Header().Get("Set-Cookie"), "Max-Age=0")
+		svc.AssertExpectations(t)
 	})
 
 	t.Run("Expired Session", func(t *testing.T) {
@@ -52,7 +122,7 @@ func TestRequireLogin(t *testing.T) {
 		svc := new(mocks.Service)
 		svc.On("Find", mock.Anything, mock.AnythingOfType("string")).Return(sess, nil).Once()
 		r := gin.New()
-		authRouter := r.Use(RequireLogin(svc))
+		authRouter := r.Use(RequireLogin(svc, sessionutil.DefaultCookie))
 		authRouter.GET("/hello", func(c *gin.Context) { c.Status(http.StatusOK) })
 		resp := httputil.PerformRequest(r, http.MethodGet, "/hello", nil, func(r *http.Request) {
 			cookie := &http.Cookie{
@@ -73,10 +143,11 @@ func TestRequireLogin(t *testing.T) {
 		svc := new(mocks.Service)
 		svc.On("Find", mock.Anything, mock.AnythingOfType("string")).Return(sess, nil).Once()
 		r := gin.New()
-		authRouter := r.Use(RequireLogin(svc))
+		authRouter := r.Use(RequireLogin(svc, sessionutil.DefaultCookie))
 		authRouter.GET("/hello", func(c *gin.Context) { c.Status(http.StatusOK) })
 		resp := httputil.PerformRequest(r, http.MethodGet, "/hello", nil)
 		assert.Equal(t, http.StatusTemporaryRedirect, resp.Code)
+		assert.Equal(t, "/login?next=%2Fhello", resp.Header().Get("Location"))
 	})
 
 	t.Run("Session doesn't exists", func(t *testing.T) {
@@ -87,7 +158,7 @@ func TestRequireLogin(t *testing.T) {
 		svc := new(mocks.Service)
 		svc.On("Find", mock.Anything, mock.AnythingOfType("string")).Return(nil, session.ErrNotFound).Once()
 		r := gin.New()
-		authRouter := r.Use(RequireLogin(svc))
+		authRouter := r.Use(RequireLogin(svc, sessionutil.DefaultCookie))
 		authRouter.GET("/hello", func(c *gin.Context) { c.Status(http.StatusOK) })
 		resp := httputil.PerformRequest(r, http.MethodGet, "/hello", nil, func(r *http.Request) {
 			cookie := &http.Cookie{
@@ -0,0 +1,73 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"gophr.v2/log"
+	"gophr.v2/session"
+)
+
+// sessionKey is the key of the session of the request in the gin.Context.
+const sessionKey = "gophr.session"
+
+// requestSession is the session of a request along with whether it
+// changed since it was found.
+type requestSession struct {
+	sess    *session.Session
+	changed bool
+}
+
+// Session returns the session of the request, found from its cookie
+// on first use only. It returns nil when the request has no session.
+//
+// The changes made to the session are not saved until SaveSession,
+// so that a request never overwrites the session more than once.
+func Session(c *gin.Context, finder session.Finder) *session.Session {
+	if rs, ok := c.Get(sessionKey); ok {
+		return rs.(*requestSession).sess
+	}
+
+	var sess *session.Session
+	if cookieValue, err := c.Cookie(session.CookieName); err == nil {
+		if sess, err = finder.Find(c.Request.Context(), cookieValue); err != nil {
+			sess = nil
+		}
+	}
+	c.Set(sessionKey, &requestSession{sess: sess})
+	return sess
+}
+
+// SetSession replaces the session of the request with sess, like
+// when the user signs in or out. sess is expected to be saved already.
+func SetSession(c *gin.Context, sess *session.Session) {
+	c.Set(sessionKey, &requestSession{sess: sess})
+}
+
+// SessionChanged marks the session of the request as changed.
+func SessionChanged(c *gin.Context) {
+	if rs, ok := c.Get(sessionKey); ok && rs.(*requestSession).sess != nil {
+		rs.(*requestSession).changed = true
+	}
+}
+
+// SaveChangedSession saves the session of the request when it changed
+// since it was found or last saved.
+func SaveChangedSession(c *gin.Context, updater session.Updater) error {
+	rs, ok := c.Get(sessionKey)
+	if !ok || !rs.(*requestSession).changed {
+		return nil
+	}
+	rs.(*requestSession).changed = false
+	return updater.Update(c.Request.Context(), rs.(*requestSession).sess)
+}
+
+// SaveSession saves the changes of the session once the request is
+// handled. The pages rendered save it before they are written, the
+// redirects are still buffered when it runs.
+func SaveSession(updater session.Updater) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+		if err := SaveChangedSession(c, updater); err != nil {
+			log.FromContext(c.Request.Context()).WithError(err).Error("failed saving session")
+		}
+	}
+}
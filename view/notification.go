@@ -0,0 +1,142 @@
+package view
+
+import (
+	"github.com/gin-gonic/gin"
+	"gophr.v2/log"
+	"gophr.v2/notification"
+	"gophr.v2/user"
+	"net/http"
+)
+
+// notificationsPageSize is the number of notifications per page.
+const notificationsPageSize = 20
+
+// notificationItem is a notification along with the user who
+// caused it.
+type notificationItem struct {
+	*notification.Notification
+	Actor *actor `json:"actor,omitempty"`
+}
+
+// actor is the public part of the user who caused a notification.
+// Users are never rendered as JSON as they are since they hold the
+// email and the password hash.
+type actor struct {
+	UserID       string `json:"userId"`
+	Username     string `json:"username"`
+	DisplayName  string `json:"displayName,omitempty"`
+	ProfileRoute string `json:"profileRoute"`
+}
+
+func newActor(usr *user.User) *actor {
+	return &actor{
+		UserID:       usr.UserID,
+		Username:     usr.Username,
+		DisplayName:  usr.DisplayName,
+		ProfileRoute: usr.ProfileRoute(),
+	}
+}
+
+// Name returns the display name of the actor, or the username when
+// no display name is set.
+func (a *actor) Name() string {
+	if a.DisplayName != "" {
+		return a.DisplayName
+	}
+	return a.Username
+}
+
+func (v *ViewHandler) NotificationsPage(c *gin.Context) {
+	usr := v.getUserFromCookie(c)
+	page := getPage(c)
+
+	items, err := v.listNotifications(c, usr.UserID, (page-1)*notificationsPageSize, notificationsPageSize)
+	if err != nil {
+		v.renderErrorTemplate(c, err)
+		return
+	}
+
+	data := map[string]interface{}{
+		"Notifications": items,
+		"Page":          page,
+	}
+	if page > 1 {
+		data["PrevPage"] = page - 1
+	}
+	if len(items) == notificationsPageSize {
+		data["NextPage"] = page + 1
+	}
+	v.renderTemplate(c, "notifications/list", data)
+}
+
+// NotificationsJSON returns the unread count and the latest
+// notifications for clients polling for new ones.
+func (v *ViewHandler) NotificationsJSON(c *gin.Context) {
+	usr := v.getUserFromCookie(c)
+
+	unread, err := v.notifService.CountUnread(c.Request.Context(), usr.UserID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": getMessage(err)})
+		return
+	}
+
+	items, err := v.listNotifications(c, usr.UserID, 0, notificationsPageSize)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": getMessage(err)})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"unread":        unread,
+		"notifications": items,
+	})
+}
+
+// HandleMarkNotificationsRead marks the notification with the posted
+// id as read, or all of them when no id is posted.
+func (v *ViewHandler) HandleMarkNotificationsRead(c *gin.Context) {
+	usr := v.getUserFromCookie(c)
+
+	var ids []string
+	if id := c.PostForm("id"); id != "" {
+		ids = append(ids, id)
+	}
+
+	err := v.notifService.MarkRead(c.Request.Context(), usr.UserID, ids...)
+	if err != nil {
+		v.renderErrorTemplate(c, err)
+		return
+	}
+	c.Redirect(http.StatusFound, "/v1/notifications")
+}
+
+func (v *ViewHandler) listNotifications(c *gin.Context, userID string, offset, num int) ([]*notificationItem, error) {
+	notifications, err := v.notifService.List(c.Request.Context(), userID, offset, num)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, 0, len(notifications))
+	for _, n := range notifications {
+		if n.ActorID != "" {
+			ids = append(ids, n.ActorID)
+		}
+	}
+
+	actors := make(map[string]*actor, len(ids))
+	if len(ids) > 0 {
+		usrs, _, err := v.usrService.GetByUserIDs(c.Request.Context(), ids)
+		if err != nil {
+			log.FromContext(c.Request.Context()).WithError(err).Debug("failed getting the actors of the notifications")
+		}
+		for _, usr := range usrs {
+			actors[usr.UserID] = newActor(usr)
+		}
+	}
+
+	items := make([]*notificationItem, 0, len(notifications))
+	for _, n := range notifications {
+		items = append(items, &notificationItem{Notification: n, Actor: actors[n.ActorID]})
+	}
+	return items, nil
+}
@@ -0,0 +1,65 @@
+//+build unit
+
+package view
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gophr.v2/http/httputil"
+	"gophr.v2/notification"
+	"gophr.v2/session"
+	sessionmocks "gophr.v2/session/mocks"
+	"gophr.v2/user"
+	usermocks "gophr.v2/user/mocks"
+	"net/http"
+	"testing"
+)
+
+func TestViewHandler_NotificationsJSON(t *testing.T) {
+	sess := &session.Session{ID: "sess123", UserID: "nami", CSRFToken: "token123"}
+	svc := new(sessionmocks.Service)
+	svc.On("Find", mock.Anything, sess.ID).Return(sess, nil)
+	usrSvc := new(usermocks.Service)
+	usrSvc.On("GetByUserID", mock.Anything, "nami").Return(&user.User{UserID: "nami"}, nil)
+	usrSvc.On("GetByUserIDs", mock.Anything, []string{"luffy"}).Return([]*user.User{{
+		UserID:      "luffy",
+		Username:    "luffy.monkey",
+		Email:       "luffy@strawhat.sea",
+		Password:    "$2a$10$secrethash",
+		DisplayName: "Luffy",
+	}}, []string{}, nil)
+
+	h := newTestHandler(svc, usrSvc)
+	require.NoError(t, h.notifService.Notify(context.Background(), &notification.Notification{
+		UserID:  "nami",
+		ActorID: "luffy",
+		Type:    notification.TypeFollow,
+	}))
+	r := gin.New()
+	r.GET("/notifications.json", h.NotificationsJSON)
+
+	resp := httputil.PerformRequest(r, http.MethodGet, "/notifications.json", nil, func(r *http.Request) {
+		r.AddCookie(&http.Cookie{Name: session.CookieName, Value: sess.ID})
+	})
+	require.Equal(t, http.StatusOK, resp.Code)
+	assert.NotContains(t, resp.Body.String(), "secrethash")
+	assert.NotContains(t, resp.Body.String(), "luffy@strawhat.sea")
+
+	var body struct {
+		Notifications []struct {
+			Actor map[string]interface{} `json:"actor"`
+		} `json:"notifications"`
+	}
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &body))
+	require.Len(t, body.Notifications, 1)
+	assert.Equal(t, map[string]interface{}{
+		"userId":       "luffy",
+		"username":     "luffy.monkey",
+		"displayName":  "Luffy",
+		"profileRoute": "/users/luffy.monkey",
+	}, body.Notifications[0].Actor)
+}
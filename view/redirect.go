@@ -0,0 +1,29 @@
+package view
+
+import (
+	"net/url"
+	"strings"
+)
+
+// defaultRedirect is where users land when no safe redirect is given.
+const defaultRedirect = "/"
+
+// safeRedirect returns next when it is a path on this site, or the
+// defaultRedirect otherwise. Absolute and protocol-relative URLs are
+// refused so the login form can't be used as an open redirect.
+func safeRedirect(next string) string {
+	if next == "" || !strings.HasPrefix(next, "/") {
+		return defaultRedirect
+	}
+
+	// Browsers treat "//host" and "/\host" as protocol-relative URLs
+	if strings.HasPrefix(next, "//") || strings.ContainsAny(next, "\\\r\n\t") {
+		return defaultRedirect
+	}
+
+	u, err := url.Parse(next)
+	if err != nil || u.IsAbs() || u.Host != "" || u.User != nil {
+		return defaultRedirect
+	}
+	return u.RequestURI()
+}
@@ -0,0 +1,31 @@
+//+build unit
+
+package view
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestSafeRedirect(t *testing.T) {
+	tests := map[string]struct {
+		next string
+		want string
+	}{
+		"Empty":             {next: "", want: "/"},
+		"Path":              {next: "/v1/account", want: "/v1/account"},
+		"Path With Query":   {next: "/v1/images/new?ref=home", want: "/v1/images/new?ref=home"},
+		"Relative Path":     {next: "v1/account", want: "/"},
+		"Absolute URL":      {next: "https://evil.com/v1/account", want: "/"},
+		"Protocol Relative": {next: "//evil.com", want: "/"},
+		"Backslash":         {next: "/\\evil.com", want: "/"},
+		"Scheme Only":       {next: "javascript:alert(1)", want: "/"},
+		"Line Break":        {next: "/v1/account\r\nLocation: https://evil.com", want: "/"},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tt.want, safeRedirect(tt.next))
+		})
+	}
+}
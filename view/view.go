@@ -2,15 +2,26 @@ package view
 
 import (
 	"bytes"
+	"errors"
 	"github.com/gin-gonic/gin"
-	"github.com/jayvib/golog"
-	"golang.org/x/crypto/bcrypt"
+	"gophr.v2/export"
+	"gophr.v2/feed"
+	"gophr.v2/follow"
 	"gophr.v2/image"
+	"gophr.v2/log"
+	"gophr.v2/notification"
+	"gophr.v2/realtime"
 	"gophr.v2/session"
 	"gophr.v2/session/sessionutil"
 	"gophr.v2/user"
+	"gophr.v2/user/lockout"
+	"gophr.v2/view/middleware"
+	"gophr.v2/webhook"
 	"html/template"
 	"net/http"
+	"sort"
+	"strconv"
+	"strings"
 )
 
 var funcs = template.FuncMap{
@@ -22,8 +33,27 @@ var funcs = template.FuncMap{
 	},
 }
 
-func RegisterRoutes(unsecuredRouter, securedRouter gin.IRoutes, userService user.Service, sessionService session.Service, imageService image.Service, templatesGlob, layoutPath, assetsPath, imagesPath string) {
-	h := NewHandler(userService, sessionService, imageService, templatesGlob, layoutPath)
+// Option configures the ViewHandler.
+type Option func(v *ViewHandler)
+
+// WithCookie sets the attributes of the session cookie. The
+// sessionutil.DefaultCookie is used by default.
+func WithCookie(cookie sessionutil.Cookie) Option {
+	return func(v *ViewHandler) {
+		v.cookie = cookie
+	}
+}
+
+// WithEvents streams the events of subscriber to the browsers
+// connected to /events.
+func WithEvents(subscriber realtime.Subscriber) Option {
+	return func(v *ViewHandler) {
+		v.events = subscriber
+	}
+}
+
+func RegisterRoutes(unsecuredRouter, securedRouter gin.IRoutes, userService user.Service, sessionService session.Service, imageService image.Service, followService follow.Service, feedService feed.Service, notificationService notification.Service, templatesGlob, layoutPath, assetsPath, imagesPath string, opts ...Option) {
+	h := NewHandler(userService, sessionService, imageService, followService, feedService, notificationService, templatesGlob, layoutPath, opts...)
 
 	// Asset handler
 	unsecuredRouter.StaticFS("/assets", http.Dir(assetsPath))
@@ -31,26 +61,64 @@ func RegisterRoutes(unsecuredRouter, securedRouter gin.IRoutes, userService user
 	unsecuredRouter.GET("/", h.HomePage)
 	unsecuredRouter.GET("/signup", h.SignupPage)
 	unsecuredRouter.GET("/login", h.LoginPage)
-	unsecuredRouter.POST("/signup", h.HandleSignUp)
-	unsecuredRouter.POST("/login", h.HandleLogin)
+	unsecuredRouter.GET("/restore", h.RestoreAccountPage)
+	unsecuredRouter.GET("/users/:username", h.DisplayUserDetails)
+	unsecuredRouter.POST("/signup", h.VerifyCSRF, h.HandleSignUp)
+	unsecuredRouter.POST("/login", h.VerifyCSRF, h.HandleLogin)
+	unsecuredRouter.POST("/restore", h.VerifyCSRF, h.HandleRestoreAccount)
+	if h.events != nil {
+		unsecuredRouter.GET("/events", h.Events)
+	}
+	if h.exportService != nil {
+		unsecuredRouter.GET("/exports/:id", h.DownloadExport)
+	}
 
 	securedRouter.GET("/account", h.EditUserPage)
 	securedRouter.GET("/signout", h.SignOutPage)
 	securedRouter.GET("/images/new", h.UploadImagePage)
 	securedRouter.GET("/images/id/:imageID", h.ShowImage)
-	securedRouter.POST("/account", h.HandleEditUser)
-	securedRouter.POST("/images/new", h.HandleImageUpload)
+	securedRouter.GET("/account/sessions", h.SessionsPage)
+	securedRouter.GET("/notifications", h.NotificationsPage)
+	securedRouter.GET("/notifications.json", h.NotificationsJSON)
+	securedRouter.POST("/account", h.VerifyCSRF, h.HandleEditUser)
+	securedRouter.POST("/account/delete", h.VerifyCSRF, h.HandleDeleteAccount)
+	securedRouter.POST("/account/sessions/revoke", h.VerifyCSRF, h.HandleRevokeSession)
+	securedRouter.POST("/account/sessions/revoke-all", h.VerifyCSRF, h.HandleRevokeAllSessions)
+	securedRouter.POST("/images/new", h.VerifyCSRF, h.HandleImageUpload)
+	securedRouter.POST("/users/:username/follow", h.VerifyCSRF, h.HandleFollow)
+	securedRouter.POST("/users/:username/unfollow", h.VerifyCSRF, h.HandleUnfollow)
+	securedRouter.POST("/notifications/read", h.VerifyCSRF, h.HandleMarkNotificationsRead)
+	securedRouter.POST("/images/id/:imageID/delete", h.VerifyCSRF, h.HandleDeleteImage)
+	if h.webhookService != nil {
+		securedRouter.GET("/account/webhooks", h.WebhooksPage)
+		securedRouter.GET("/account/webhooks/:id", h.WebhookDeliveriesPage)
+		securedRouter.POST("/account/webhooks", h.VerifyCSRF, h.HandleCreateWebhook)
+		securedRouter.POST("/account/webhooks/:id/delete", h.VerifyCSRF, h.HandleDeleteWebhook)
+		securedRouter.POST("/account/webhooks/:id/deliveries/:deliveryID/redeliver", h.VerifyCSRF, h.HandleRedeliverWebhook)
+	}
+	if h.exportService != nil {
+		securedRouter.GET("/account/export", h.ExportsPage)
+		securedRouter.POST("/account/export", h.VerifyCSRF, h.HandleRequestExport)
+	}
 }
 
-func NewHandler(userService user.Service, sessionService session.Service, imageService image.Service, templatesGlob, layoutPath string) *ViewHandler {
-	return &ViewHandler{
+func NewHandler(userService user.Service, sessionService session.Service, imageService image.Service, followService follow.Service, feedService feed.Service, notificationService notification.Service, templatesGlob, layoutPath string, opts ...Option) *ViewHandler {
+	v := &ViewHandler{
 		usrService:     userService,
 		sessionService: sessionService,
 		imageService:   imageService,
+		followService:  followService,
+		feedService:    feedService,
+		notifService:   notificationService,
+		cookie:         sessionutil.DefaultCookie,
 		templs:         template.Must(template.ParseGlob(templatesGlob)),
 		layout: template.Must(template.New("layout.html").
 			Funcs(funcs).ParseFiles(layoutPath)),
 	}
+	for _, opt := range opts {
+		opt(v)
+	}
+	return v
 }
 
 type ViewHandler struct {
@@ -59,6 +127,14 @@ type ViewHandler struct {
 	usrService     user.Service
 	sessionService session.Service
 	imageService   image.Service
+	followService  follow.Service
+	feedService    feed.Service
+	notifService   notification.Service
+	cookie         sessionutil.Cookie
+	events         realtime.Subscriber
+	webhookService webhook.Service
+	admins         map[string]bool
+	exportService  export.Service
 }
 
 // #################CONTROLLERS################
@@ -81,16 +157,14 @@ func (v *ViewHandler) HandleSignUp(c *gin.Context) {
 		return
 	}
 
-	sess := sessionutil.WriteSessionTo(c.Writer)
-	sess.UserID = usr.UserID
-
-	err = v.sessionService.Save(c.Request.Context(), sess)
+	sess, err := v.createSession(c, usr.UserID)
 	if err != nil {
 		v.renderErrorTemplate(c, err)
 		return
 	}
 
-	c.Redirect(http.StatusFound, "/?flash=User+created")
+	v.flash(c, sess, session.FlashSuccess, "User created")
+	c.Redirect(http.StatusFound, "/")
 }
 
 func (v *ViewHandler) HandleImageUpload(c *gin.Context) {
@@ -118,7 +192,8 @@ func (v *ViewHandler) createImageFromURL(c *gin.Context) {
 		})
 		return
 	}
-	c.Redirect(http.StatusFound, "/?flash=Image+Uploaded+Successfully")
+	v.flash(c, v.getSessionFromRequest(c), session.FlashSuccess, "Image Uploaded Successfully")
+	c.Redirect(http.StatusFound, "/")
 }
 
 func (v *ViewHandler) createImageFromFile(c *gin.Context) {
@@ -144,76 +219,84 @@ func (v *ViewHandler) createImageFromFile(c *gin.Context) {
 
 	img, err := v.imageService.CreateImageFromFile(c.Request.Context(), f, formFile.Filename, desc, usr.UserID)
 	if err != nil {
-		golog.Error(err)
+		log.FromContext(c.Request.Context()).WithError(err).Error("failed creating image from file")
 		v.renderTemplate(c, "images/new", map[string]interface{}{
 			"Error": err,
 			"Image": img,
 		})
 		return
 	}
-	c.Redirect(http.StatusFound, "/?flash=Image+Uploaded+Successfully")
+	v.flash(c, v.getSessionFromRequest(c), session.FlashSuccess, "Image Uploaded Successfully")
+	c.Redirect(http.StatusFound, "/")
 }
 
 func (v *ViewHandler) HandleLogin(c *gin.Context) {
 	// Get the credentials
 	username := c.PostForm("username")
 	password := c.PostForm("password")
-	next := c.PostForm("next")
+	next := safeRedirect(c.PostForm("next"))
 	usr := &user.User{
 		Username: username,
 		Password: password,
 	}
-	golog.Debug("password:", password)
 	// Get the user detail through username
-	err := v.usrService.Login(c.Request.Context(), usr)
+	ctx := lockout.WithClientIP(c.Request.Context(), c.ClientIP())
+	err := v.usrService.Login(ctx, usr)
 	if err != nil {
+		if errors.Is(err, user.ErrAccountLocked) {
+			c.Status(http.StatusTooManyRequests)
+		}
 		v.renderTemplate(c, "sessions/login", map[string]interface{}{
-			"Error": getMessage(err),
-			"User":  usr,
-			"Next":  next,
+			"Error":   getMessage(err),
+			"User":    usr,
+			"Next":    next,
+			"Restore": errors.Is(err, user.ErrAccountDeleted),
 		})
 		return
 	}
 
 	// Create a session
-	sess := sessionutil.WriteSessionTo(c.Writer)
-	sess.UserID = usr.UserID
-
-	// Save the session
-
-	err = v.sessionService.Save(c.Request.Context(), sess)
+	sess, err := v.createSession(c, usr.UserID)
 	if err != nil {
 		v.renderErrorTemplate(c, err)
 		return
 	}
 
-	// When next is empty string then set it to '/' as default
-	if next == "" {
-		next = "/"
-	}
-
-	c.Redirect(http.StatusFound, next+"?flash=Signed+in")
+	v.flash(c, sess, session.FlashSuccess, "Signed in")
+	c.Redirect(http.StatusFound, next)
 }
 
 func (v *ViewHandler) HandleEditUser(c *gin.Context) {
 	// Get the current user
 	usr := v.getUserFromCookie(c)
+	sess := v.getSessionFromRequest(c)
 
 	// Get the updated information
 	email := c.PostForm("email")
 	newPassword := c.PostForm("newPassword")
 	currentPassword := c.PostForm("currentPassword")
 
-	// Check first if the current password is correct
-
 	tmpUser := &user.User{
-		Email:    email,
-		Username: usr.Username,
-		Password: currentPassword,
+		Email:       email,
+		Username:    usr.Username,
+		DisplayName: strings.TrimSpace(c.PostForm("displayName")),
+		Bio:         strings.TrimSpace(c.PostForm("bio")),
+		Website:     strings.TrimSpace(c.PostForm("website")),
+	}
+
+	// Check the profile before the password is changed
+	if err := tmpUser.ValidateProfile(); err != nil {
+		v.renderTemplate(c, "users/edit", map[string]interface{}{
+			"Error": getMessage(user.NewError(err)),
+			"User":  tmpUser,
+		})
+		return
 	}
 
 	if newPassword != "" {
-		err := v.usrService.Login(c.Request.Context(), tmpUser)
+		// The service checks the current password before changing it
+		ctx := lockout.WithClientIP(c.Request.Context(), c.ClientIP())
+		err := v.usrService.ChangePassword(ctx, usr.UserID, currentPassword, newPassword)
 		if err != nil {
 			v.renderTemplate(c, "users/edit", map[string]interface{}{
 				"Error": getMessage(err),
@@ -222,22 +305,21 @@ func (v *ViewHandler) HandleEditUser(c *gin.Context) {
 			return
 		}
 
-		// ===============NOT SURE IF IT BELONGS HERE==============
-		hash, err := bcrypt.GenerateFromPassword([]byte(newPassword), bcrypt.DefaultCost)
+		// The service logged out everywhere, start a fresh session for
+		// this device
+		sess, err = v.createSession(c, usr.UserID)
 		if err != nil {
-			err := user.NewError(err)
-			v.renderTemplate(c, "users/edit", map[string]interface{}{
-				"Error": getMessage(err),
-				"User":  tmpUser,
-			})
+			v.renderErrorTemplate(c, err)
+			return
 		}
-		usr.Password = string(hash)
-		// ========================================================
 	}
 
 	usr.Email = email
+	usr.DisplayName = tmpUser.DisplayName
+	usr.Bio = tmpUser.Bio
+	usr.Website = tmpUser.Website
 
-	golog.Tracef("Updating: %#v\n", usr)
+	log.FromContext(c.Request.Context()).Debug("updating user")
 	// Save to repository
 	err := v.usrService.Update(c.Request.Context(), usr)
 	if err != nil {
@@ -245,10 +327,175 @@ func (v *ViewHandler) HandleEditUser(c *gin.Context) {
 			"Error": getMessage(err),
 			"User":  tmpUser,
 		})
+		return
 	}
 
 	// redirect to "/account"
-	c.Redirect(http.StatusFound, "/account?flash=User+updated")
+	v.flash(c, sess, session.FlashSuccess, "User updated")
+	c.Redirect(http.StatusFound, "/v1/account")
+}
+
+// HandleDeleteAccount schedules the deletion of the account of the
+// current user. The service revokes all of the user's sessions.
+func (v *ViewHandler) HandleDeleteAccount(c *gin.Context) {
+	usr := v.getUserFromCookie(c)
+
+	ctx := lockout.WithClientIP(c.Request.Context(), c.ClientIP())
+	err := v.usrService.DeleteAccount(ctx, usr.UserID, c.PostForm("password"))
+	if err != nil {
+		if errors.Is(err, user.ErrAccountLocked) {
+			c.Status(http.StatusTooManyRequests)
+		}
+		v.renderTemplate(c, "users/edit", map[string]interface{}{
+			"DeleteError": getMessage(err),
+			"User":        usr,
+		})
+		return
+	}
+
+	middleware.SetSession(c, nil)
+	sessionutil.ClearCookie(c.Writer, v.cookie)
+	v.renderTemplate(c, "users/deleted", nil)
+}
+
+// HandleRestoreAccount cancels the deletion of the account whose
+// credentials are posted and signs the user in.
+func (v *ViewHandler) HandleRestoreAccount(c *gin.Context) {
+	usr := &user.User{
+		Username: c.PostForm("username"),
+		Password: c.PostForm("password"),
+	}
+
+	ctx := lockout.WithClientIP(c.Request.Context(), c.ClientIP())
+	err := v.usrService.RestoreAccount(ctx, usr)
+	if err != nil {
+		if errors.Is(err, user.ErrAccountLocked) {
+			c.Status(http.StatusTooManyRequests)
+		}
+		v.renderTemplate(c, "users/restore", map[string]interface{}{
+			"Error": getMessage(err),
+			"User":  usr,
+		})
+		return
+	}
+
+	sess, err := v.createSession(c, usr.UserID)
+	if err != nil {
+		v.renderErrorTemplate(c, err)
+		return
+	}
+
+	v.flash(c, sess, session.FlashSuccess, "Welcome back! Your account is restored")
+	c.Redirect(http.StatusFound, "/")
+}
+
+func (v *ViewHandler) HandleFollow(c *gin.Context) {
+	v.handleFollow(c, true)
+}
+
+func (v *ViewHandler) HandleUnfollow(c *gin.Context) {
+	v.handleFollow(c, false)
+}
+
+func (v *ViewHandler) handleFollow(c *gin.Context, follow bool) {
+	usr := v.getUserFromCookie(c)
+	followee, err := v.usrService.GetByUsername(c.Request.Context(), c.Param("username"))
+	if err != nil {
+		if errors.Is(err, user.ErrNotFound) {
+			c.Status(http.StatusNotFound)
+		}
+		v.renderErrorTemplate(c, err)
+		return
+	}
+
+	message := "You are now following " + followee.Name()
+	if follow {
+		err = v.followService.Follow(c.Request.Context(), usr.UserID, followee.UserID)
+	} else {
+		message = "You are no longer following " + followee.Name()
+		err = v.followService.Unfollow(c.Request.Context(), usr.UserID, followee.UserID)
+	}
+	if err != nil {
+		v.flash(c, v.getSessionFromRequest(c), session.FlashError, getMessage(err))
+	} else {
+		v.flash(c, v.getSessionFromRequest(c), session.FlashSuccess, message)
+	}
+	c.Redirect(http.StatusFound, followee.ProfileRoute())
+}
+
+func (v *ViewHandler) HandleRevokeSession(c *gin.Context) {
+	usr := v.getUserFromCookie(c)
+	id := c.PostForm("id")
+
+	// Only the owner of the session can revoke it
+	sess, err := v.sessionService.Find(c.Request.Context(), id)
+	if err != nil || sess.UserID != usr.UserID {
+		c.Status(http.StatusNotFound)
+		v.renderErrorTemplate(c, session.NewError(session.ErrNotFound, "Session not found"))
+		return
+	}
+
+	err = v.sessionService.Delete(c.Request.Context(), sess.ID)
+	if err != nil {
+		v.renderErrorTemplate(c, err)
+		return
+	}
+	if current := v.getSessionFromRequest(c); current != nil && current.ID == sess.ID {
+		middleware.SetSession(c, nil)
+	}
+
+	v.flash(c, v.getSessionFromRequest(c), session.FlashSuccess, "Session revoked")
+	c.Redirect(http.StatusFound, "/v1/account/sessions")
+}
+
+func (v *ViewHandler) HandleRevokeAllSessions(c *gin.Context) {
+	usr := v.getUserFromCookie(c)
+
+	err := v.sessionService.DeleteAllForUser(c.Request.Context(), usr.UserID)
+	if err != nil {
+		v.renderErrorTemplate(c, err)
+		return
+	}
+	middleware.SetSession(c, nil)
+
+	c.Redirect(http.StatusFound, "/login")
+}
+
+// createSession starts a new session of the user identified by
+// userID for the device of the request.
+func (v *ViewHandler) createSession(c *gin.Context, userID string) (*session.Session, error) {
+	sess := sessionutil.New(userID)
+	sess.IP = c.ClientIP()
+	sess.UserAgent = c.Request.UserAgent()
+	if err := v.sessionService.Save(c.Request.Context(), sess); err != nil {
+		return nil, err
+	}
+	middleware.SetSession(c, sess)
+	sessionutil.WriteCookie(c.Writer, sess, v.cookie)
+	return sess, nil
+}
+
+// flash queues a message shown on the next page rendered within sess,
+// the session of the request. It's saved once the request is handled.
+func (v *ViewHandler) flash(c *gin.Context, sess *session.Session, level session.FlashLevel, message string) {
+	if sess == nil {
+		log.FromContext(c.Request.Context()).WithField("message", message).Debug("dropping flash message without session")
+		return
+	}
+	sess.AddFlash(level, message)
+	middleware.SessionChanged(c)
+}
+
+// popFlashes returns the flash messages waiting in the session
+// of the request. They are removed so they are only shown once.
+func (v *ViewHandler) popFlashes(c *gin.Context) []session.Flash {
+	sess := v.getSessionFromRequest(c)
+	if sess == nil || len(sess.Flashes) == 0 {
+		return nil
+	}
+	flashes := sess.PopFlashes()
+	middleware.SessionChanged(c)
+	return flashes
 }
 
 func (v *ViewHandler) renderErrorTemplate(c *gin.Context, err error) {
@@ -270,7 +517,38 @@ func getMessage(err error) string {
 	return message
 }
 
+// HomePage shows the images of the followed users to signed in users,
+// and the latest images of everyone otherwise or when ?view=all.
 func (v *ViewHandler) HomePage(c *gin.Context) {
+	if usr := v.getUserFromCookie(c); usr != nil && c.Query("view") != "all" {
+		cursor := c.Query("cursor")
+		page, err := v.feedService.Following(c.Request.Context(), usr.UserID, cursor, 0)
+		if err != nil {
+			v.renderTemplate(c, "index/home", map[string]interface{}{
+				"Error": getMessage(err),
+			})
+			return
+		}
+
+		// Users following nobody yet get the global images
+		if len(page.Images) > 0 || cursor != "" {
+			images, owners, err := v.imageOwners(c, page.Images)
+			if err != nil {
+				v.renderTemplate(c, "index/home", map[string]interface{}{
+					"Error": getMessage(err),
+				})
+				return
+			}
+			v.renderTemplate(c, "index/home", map[string]interface{}{
+				"Images":     images,
+				"Owners":     owners,
+				"NextCursor": page.NextCursor,
+				"Feed":       true,
+			})
+			return
+		}
+	}
+
 	images, err := v.imageService.FindAll(c.Request.Context(), 0)
 	if err != nil {
 		v.renderTemplate(c, "index/home", map[string]interface{}{
@@ -278,12 +556,50 @@ func (v *ViewHandler) HomePage(c *gin.Context) {
 		})
 		return
 	}
+	images, owners, err := v.imageOwners(c, images)
+	if err != nil {
+		v.renderTemplate(c, "index/home", map[string]interface{}{
+			"Error": getMessage(err),
+		})
+		return
+	}
 
 	v.renderTemplate(c, "index/home", map[string]interface{}{
 		"Images": images,
+		"Owners": owners,
 	})
 }
 
+// imageOwners returns the owners of images by user ID, looked up at
+// once for the grid, along with the images whose owner was found. The
+// images of the accounts being deleted are left out with them.
+func (v *ViewHandler) imageOwners(c *gin.Context, images []*image.Image) ([]*image.Image, map[string]*user.User, error) {
+	ids := make([]string, 0, len(images))
+	for _, img := range images {
+		ids = append(ids, img.UserID)
+	}
+
+	owners := make(map[string]*user.User, len(ids))
+	if len(ids) == 0 {
+		return images, owners, nil
+	}
+	usrs, _, err := v.usrService.GetByUserIDs(c.Request.Context(), ids)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, usr := range usrs {
+		owners[usr.UserID] = usr
+	}
+
+	visible := make([]*image.Image, 0, len(images))
+	for _, img := range images {
+		if owners[img.UserID] != nil {
+			visible = append(visible, img)
+		}
+	}
+	return visible, owners, nil
+}
+
 // ###################VIEW####################
 
 func (v *ViewHandler) SignupPage(c *gin.Context) {
@@ -291,12 +607,16 @@ func (v *ViewHandler) SignupPage(c *gin.Context) {
 }
 
 func (v *ViewHandler) LoginPage(c *gin.Context) {
-	next := c.Query("next")
+	next := safeRedirect(c.Query("next"))
 	v.renderTemplate(c, "sessions/login", map[string]interface{}{
 		"Next": next,
 	})
 }
 
+func (v *ViewHandler) RestoreAccountPage(c *gin.Context) {
+	v.renderTemplate(c, "users/restore", nil)
+}
+
 func (v *ViewHandler) EditUserPage(c *gin.Context) {
 	usr := v.getUserFromCookie(c)
 	v.renderTemplate(c, "users/edit", map[string]interface{}{
@@ -312,19 +632,102 @@ func (v *ViewHandler) SignOutPage(c *gin.Context) {
 	// Delete session if not empty
 	if sess != nil {
 		_ = v.sessionService.Delete(c.Request.Context(), sess.ID)
+		middleware.SetSession(c, nil)
 	}
+	sessionutil.ClearCookie(c.Writer, v.cookie)
 
 	// Render the signout template
 	v.renderTemplate(c, "sessions/signout", nil)
 }
 
+func (v *ViewHandler) SessionsPage(c *gin.Context) {
+	usr := v.getUserFromCookie(c)
+	sessions, err := v.sessionService.FindByUser(c.Request.Context(), usr.UserID)
+	if err != nil {
+		v.renderErrorTemplate(c, err)
+		return
+	}
+
+	// Most recently used first
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].LastSeen.After(sessions[j].LastSeen)
+	})
+
+	var currentID string
+	if sess := v.getSessionFromRequest(c); sess != nil {
+		currentID = sess.ID
+	}
+
+	v.renderTemplate(c, "sessions/list", map[string]interface{}{
+		"Sessions":         sessions,
+		"CurrentSessionID": currentID,
+	})
+}
+
 func (v *ViewHandler) UploadImagePage(c *gin.Context) {
 	v.renderTemplate(c, "images/new", nil)
 }
 
-func (v *ViewHandler) UserEditPage(c *gin.Context) {}
+// DisplayUserDetails renders the public profile of a user
+// along with a page of the user's images.
+func (v *ViewHandler) DisplayUserDetails(c *gin.Context) {
+	usr, err := v.usrService.GetByUsername(c.Request.Context(), c.Param("username"))
+	if err != nil {
+		if errors.Is(err, user.ErrNotFound) {
+			c.Status(http.StatusNotFound)
+		}
+		v.renderErrorTemplate(c, err)
+		return
+	}
+
+	page := getPage(c)
+	images, err := v.imageService.FindAllByUser(c.Request.Context(), usr.UserID, (page-1)*image.PageSize)
+	if err != nil {
+		v.renderErrorTemplate(c, err)
+		return
+	}
+
+	counts, err := v.followService.Counts(c.Request.Context(), usr.UserID)
+	if err != nil {
+		v.renderErrorTemplate(c, err)
+		return
+	}
+
+	var currentUserID string
+	if current := v.getUserFromCookie(c); current != nil {
+		currentUserID = current.UserID
+	}
+	following, err := v.followService.IsFollowing(c.Request.Context(), currentUserID, usr.UserID)
+	if err != nil {
+		v.renderErrorTemplate(c, err)
+		return
+	}
+
+	data := map[string]interface{}{
+		"User":        usr,
+		"Images":      images,
+		"Page":        page,
+		"Counts":      counts,
+		"IsFollowing": following,
+		"IsSelf":      currentUserID == usr.UserID,
+	}
+	if page > 1 {
+		data["PrevPage"] = page - 1
+	}
+	if len(images) == image.PageSize {
+		data["NextPage"] = page + 1
+	}
+	v.renderTemplate(c, "users/profile", data)
+}
 
-func (v *ViewHandler) DisplayUserDetails(c *gin.Context) {}
+// getPage returns the 1-based page number of the request.
+func getPage(c *gin.Context) int {
+	page, err := strconv.Atoi(c.Query("page"))
+	if err != nil || page < 1 {
+		return 1
+	}
+	return page
+}
 
 func (v *ViewHandler) ShowImage(c *gin.Context) {
 	// Get image by image ID
@@ -350,14 +753,55 @@ func (v *ViewHandler) ShowImage(c *gin.Context) {
 
 }
 
+// HandleDeleteImage deletes an image of the current user.
+func (v *ViewHandler) HandleDeleteImage(c *gin.Context) {
+	usr := v.getUserFromCookie(c)
+	img, err := v.imageService.Find(c.Request.Context(), c.Param("imageID"))
+	if err == nil && img.UserID != usr.UserID {
+		// Images of others are hidden rather than forbidden
+		err = image.ErrNotFound
+	}
+	if err == nil {
+		err = v.imageService.Delete(c.Request.Context(), img.ImageID)
+	}
+	if err != nil {
+		if errors.Is(err, image.ErrNotFound) {
+			c.Status(http.StatusNotFound)
+		}
+		v.renderErrorTemplate(c, err)
+		return
+	}
+	v.flash(c, v.getSessionFromRequest(c), session.FlashSuccess, "Image deleted")
+	c.Redirect(http.StatusFound, usr.ProfileRoute())
+}
+
 func (v *ViewHandler) renderTemplate(c *gin.Context, name string, data map[string]interface{}) {
 	// Always attach the user's information
 	if data == nil {
 		data = make(map[string]interface{})
 	}
 
-	data["CurrentUser"] = v.getUserFromCookie(c)
-	data["Flash"] = c.Query("flash")
+	currentUser := v.getUserFromCookie(c)
+	data["CurrentUser"] = currentUser
+	if currentUser != nil {
+		unread, err := v.notifService.CountUnread(c.Request.Context(), currentUser.UserID)
+		if err != nil {
+			log.FromContext(c.Request.Context()).WithError(err).Error("failed counting unread notifications")
+		}
+		data["UnreadNotifications"] = unread
+	}
+	data["Flashes"] = v.popFlashes(c)
+
+	// Every form must post the CSRF token back
+	token := v.csrfToken(c)
+	data["CSRFToken"] = token
+	data["CSRFField"] = csrfField(token)
+
+	// The page may be flushed before the request is done, the
+	// flashes and the token are saved in a single update before
+	if err := middleware.SaveChangedSession(c, v.sessionService); err != nil {
+		log.FromContext(c.Request.Context()).WithError(err).Error("failed saving session")
+	}
 
 	f := template.FuncMap{
 		"navbar": func() (template.HTML, error) {
@@ -385,7 +829,7 @@ func (v *ViewHandler) renderTemplate(c *gin.Context, name string, data map[strin
 			"Error": err.Error(),
 		})
 		if e != nil {
-			golog.Error(err)
+			log.FromContext(c.Request.Context()).WithError(e).Error("failed rendering error page")
 		}
 	}
 }
@@ -398,17 +842,14 @@ func (v *ViewHandler) getUserFromCookie(c *gin.Context) *user.User {
 
 	usr, err := v.usrService.GetByUserID(c.Request.Context(), sess.UserID)
 	if err != nil {
-		golog.Debug("while getting user by ID:", err)
+		log.FromContext(c.Request.Context()).WithError(err).Debug("failed getting user by ID")
 		return nil
 	}
 	return usr
 }
 
+// getSessionFromRequest returns the session of the request, found
+// only once per request.
 func (v *ViewHandler) getSessionFromRequest(c *gin.Context) *session.Session {
-	cookieVal, _ := c.Cookie(session.CookieName)
-	sess, err := v.sessionService.Find(c.Request.Context(), cookieVal)
-	if err != nil {
-		return nil
-	}
-	return sess
+	return middleware.Session(c, v.sessionService)
 }
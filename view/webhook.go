@@ -0,0 +1,130 @@
+package view
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"gophr.v2/session"
+	"gophr.v2/user"
+	"gophr.v2/webhook"
+	"net/http"
+)
+
+// deliveriesPageSize is the number of deliveries per page of the log.
+const deliveriesPageSize = 25
+
+// WithWebhooks lets the users manage their webhooks. The users with
+// one of the admins usernames may register global hooks.
+func WithWebhooks(service webhook.Service, admins []string) Option {
+	return func(v *ViewHandler) {
+		v.webhookService = service
+		v.admins = make(map[string]bool, len(admins))
+		for _, username := range admins {
+			v.admins[username] = true
+		}
+	}
+}
+
+func (v *ViewHandler) isAdmin(usr *user.User) bool {
+	return usr != nil && v.admins[usr.Username]
+}
+
+func (v *ViewHandler) WebhooksPage(c *gin.Context) {
+	v.renderWebhooks(c, nil, "")
+}
+
+func (v *ViewHandler) renderWebhooks(c *gin.Context, hook *webhook.Hook, errMessage string) {
+	usr := v.getUserFromCookie(c)
+	hooks, err := v.webhookService.ListHooks(c.Request.Context(), usr.UserID)
+	if err != nil {
+		v.renderErrorTemplate(c, err)
+		return
+	}
+
+	v.renderTemplate(c, "webhooks/list", map[string]interface{}{
+		"Hooks":   hooks,
+		"Hook":    hook,
+		"Events":  webhook.Events,
+		"IsAdmin": v.isAdmin(usr),
+		"Error":   errMessage,
+	})
+}
+
+func (v *ViewHandler) HandleCreateWebhook(c *gin.Context) {
+	usr := v.getUserFromCookie(c)
+	hook := &webhook.Hook{
+		UserID: usr.UserID,
+		URL:    c.PostForm("url"),
+		Global: c.PostForm("global") != "" && v.isAdmin(usr),
+	}
+	for _, e := range c.PostFormArray("events") {
+		hook.Events = append(hook.Events, webhook.Event(e))
+	}
+
+	if err := v.webhookService.CreateHook(c.Request.Context(), hook); err != nil {
+		c.Status(http.StatusBadRequest)
+		v.renderWebhooks(c, hook, getMessage(err))
+		return
+	}
+	v.flash(c, v.getSessionFromRequest(c), session.FlashSuccess, "Webhook created")
+	c.Redirect(http.StatusFound, "/v1/account/webhooks")
+}
+
+func (v *ViewHandler) HandleDeleteWebhook(c *gin.Context) {
+	usr := v.getUserFromCookie(c)
+	err := v.webhookService.DeleteHook(c.Request.Context(), usr.UserID, c.Param("id"))
+	if err != nil {
+		v.renderWebhookError(c, err)
+		return
+	}
+	v.flash(c, v.getSessionFromRequest(c), session.FlashSuccess, "Webhook deleted")
+	c.Redirect(http.StatusFound, "/v1/account/webhooks")
+}
+
+// WebhookDeliveriesPage renders the delivery log of a hook.
+func (v *ViewHandler) WebhookDeliveriesPage(c *gin.Context) {
+	usr := v.getUserFromCookie(c)
+	ctx := c.Request.Context()
+	hook, err := v.webhookService.GetHook(ctx, usr.UserID, c.Param("id"))
+	if err != nil {
+		v.renderWebhookError(c, err)
+		return
+	}
+
+	page := getPage(c)
+	deliveries, err := v.webhookService.Deliveries(ctx, usr.UserID, hook.ID, (page-1)*deliveriesPageSize, deliveriesPageSize)
+	if err != nil {
+		v.renderErrorTemplate(c, err)
+		return
+	}
+
+	data := map[string]interface{}{
+		"Hook":       hook,
+		"Deliveries": deliveries,
+	}
+	if page > 1 {
+		data["PrevPage"] = page - 1
+	}
+	if len(deliveries) == deliveriesPageSize {
+		data["NextPage"] = page + 1
+	}
+	v.renderTemplate(c, "webhooks/deliveries", data)
+}
+
+func (v *ViewHandler) HandleRedeliverWebhook(c *gin.Context) {
+	usr := v.getUserFromCookie(c)
+	hookID := c.Param("id")
+	_, err := v.webhookService.Redeliver(c.Request.Context(), usr.UserID, c.Param("deliveryID"))
+	if err != nil {
+		v.renderWebhookError(c, err)
+		return
+	}
+	v.flash(c, v.getSessionFromRequest(c), session.FlashSuccess, "The delivery was queued again")
+	c.Redirect(http.StatusFound, "/v1/account/webhooks/"+hookID)
+}
+
+func (v *ViewHandler) renderWebhookError(c *gin.Context, err error) {
+	if errors.Is(err, webhook.ErrNotFound) {
+		c.Status(http.StatusNotFound)
+	}
+	v.renderErrorTemplate(c, err)
+}
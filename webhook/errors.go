@@ -0,0 +1,16 @@
+package webhook
+
+import "errors"
+
+var (
+	ErrNotFound     = errors.New("webhook: item not found")
+	ErrInvalidURL   = errors.New("webhook: url must be an absolute http or https url")
+	ErrNoEvents     = errors.New("webhook: at least one event must be subscribed to")
+	ErrUnknownEvent = errors.New("webhook: unknown event")
+	// ErrForbiddenAddress is returned for the hooks on loopback,
+	// private, link-local or unspecified addresses.
+	ErrForbiddenAddress = errors.New("webhook: url must point to a public address")
+	// ErrUnreachable is recorded in place of the errors of the
+	// transport, which tell too much about the network to the user.
+	ErrUnreachable = errors.New("webhook: the hook could not be reached")
+)
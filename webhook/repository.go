@@ -0,0 +1,28 @@
+package webhook
+
+import (
+	"context"
+	"time"
+)
+
+//go:generate mockery --name=Repository
+
+type Repository interface {
+	SaveHook(ctx context.Context, h *Hook) error
+	FindHook(ctx context.Context, id string) (*Hook, error)
+	FindHooksByUser(ctx context.Context, userID string) ([]*Hook, error)
+	// FindHooksForEvent returns the active hooks subscribed to e that
+	// belong to userID or are global.
+	FindHooksForEvent(ctx context.Context, e Event, userID string) ([]*Hook, error)
+	DeleteHook(ctx context.Context, id string) error
+
+	SaveDelivery(ctx context.Context, d *Delivery) error
+	UpdateDelivery(ctx context.Context, d *Delivery) error
+	FindDelivery(ctx context.Context, id string) (*Delivery, error)
+	// FindDeliveriesByHook returns the deliveries of a hook, newest first.
+	FindDeliveriesByHook(ctx context.Context, hookID string, offset, num int) ([]*Delivery, error)
+	// ClaimDue returns up to num pending deliveries due at now, oldest
+	// first. Their next attempt is pushed to now plus lease so that
+	// other workers leave them alone while they are being sent.
+	ClaimDue(ctx context.Context, now time.Time, lease time.Duration, num int) ([]*Delivery, error)
+}
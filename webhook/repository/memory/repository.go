@@ -0,0 +1,161 @@
+package memory
+
+import (
+	"context"
+	"gophr.v2/util/valueutil"
+	"gophr.v2/webhook"
+	"sort"
+	"sync"
+	"time"
+)
+
+var _ webhook.Repository = (*Repository)(nil)
+
+// New creates a webhook repository kept in memory. It's meant
+// for tests and single instance deployments.
+func New() *Repository {
+	return &Repository{
+		hooks:      make(map[string]*webhook.Hook),
+		deliveries: make(map[string]*webhook.Delivery),
+	}
+}
+
+type Repository struct {
+	mu         sync.RWMutex
+	hooks      map[string]*webhook.Hook
+	deliveries map[string]*webhook.Delivery
+	// order keeps the delivery IDs in insertion order
+	order []string
+}
+
+func (r *Repository) SaveHook(ctx context.Context, h *webhook.Hook) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.hooks[h.ID] = copyHook(h)
+	return nil
+}
+
+func (r *Repository) FindHook(ctx context.Context, id string) (*webhook.Hook, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	h, ok := r.hooks[id]
+	if !ok {
+		return nil, webhook.ErrNotFound
+	}
+	return copyHook(h), nil
+}
+
+func (r *Repository) FindHooksByUser(ctx context.Context, userID string) ([]*webhook.Hook, error) {
+	return r.findHooks(func(h *webhook.Hook) bool {
+		return h.UserID == userID
+	}), nil
+}
+
+func (r *Repository) FindHooksForEvent(ctx context.Context, e webhook.Event, userID string) ([]*webhook.Hook, error) {
+	return r.findHooks(func(h *webhook.Hook) bool {
+		return h.Receives(e, userID)
+	}), nil
+}
+
+func (r *Repository) findHooks(match func(h *webhook.Hook) bool) []*webhook.Hook {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	hooks := make([]*webhook.Hook, 0)
+	for _, h := range r.hooks {
+		if match(h) {
+			hooks = append(hooks, copyHook(h))
+		}
+	}
+	sort.Slice(hooks, func(i, j int) bool {
+		return hooks[i].CreatedAt.Before(*hooks[j].CreatedAt)
+	})
+	return hooks
+}
+
+func (r *Repository) DeleteHook(ctx context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.hooks[id]; !ok {
+		return webhook.ErrNotFound
+	}
+	delete(r.hooks, id)
+	return nil
+}
+
+func (r *Repository) SaveDelivery(ctx context.Context, d *webhook.Delivery) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	cpy := *d
+	r.deliveries[d.ID] = &cpy
+	r.order = append(r.order, d.ID)
+	return nil
+}
+
+func (r *Repository) UpdateDelivery(ctx context.Context, d *webhook.Delivery) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.deliveries[d.ID]; !ok {
+		return webhook.ErrNotFound
+	}
+	cpy := *d
+	r.deliveries[d.ID] = &cpy
+	return nil
+}
+
+func (r *Repository) FindDelivery(ctx context.Context, id string) (*webhook.Delivery, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	d, ok := r.deliveries[id]
+	if !ok {
+		return nil, webhook.ErrNotFound
+	}
+	cpy := *d
+	return &cpy, nil
+}
+
+func (r *Repository) FindDeliveriesByHook(ctx context.Context, hookID string, offset, num int) ([]*webhook.Delivery, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	deliveries := make([]*webhook.Delivery, 0)
+	for i := len(r.order) - 1; i >= 0 && len(deliveries) < num; i-- {
+		d := r.deliveries[r.order[i]]
+		if d.HookID != hookID {
+			continue
+		}
+		if offset > 0 {
+			offset--
+			continue
+		}
+		cpy := *d
+		deliveries = append(deliveries, &cpy)
+	}
+	return deliveries, nil
+}
+
+func (r *Repository) ClaimDue(ctx context.Context, now time.Time, lease time.Duration, num int) ([]*webhook.Delivery, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	deliveries := make([]*webhook.Delivery, 0)
+	for _, id := range r.order {
+		if len(deliveries) == num {
+			break
+		}
+		d := r.deliveries[id]
+		if d.Status != webhook.StatusPending || d.NextAttemptAt.After(now) {
+			continue
+		}
+		d.NextAttemptAt = valueutil.TimePointer(now.Add(lease))
+		cpy := *d
+		deliveries = append(deliveries, &cpy)
+	}
+	return deliveries, nil
+}
+
+func copyHook(h *webhook.Hook) *webhook.Hook {
+	cpy := *h
+	cpy.Events = append([]webhook.Event(nil), h.Events...)
+	return &cpy
+}
@@ -0,0 +1,271 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"gophr.v2/webhook"
+	"strings"
+	"time"
+)
+
+var _ webhook.Repository = (*Repository)(nil)
+
+func New(conn *sql.DB) *Repository {
+	return &Repository{conn: conn}
+}
+
+type Repository struct {
+	conn *sql.DB
+}
+
+const hookColumns = "hookId, userId, url, secret, events, global, active, created_at"
+
+const deliveryColumns = "deliveryId, hookId, event, payload, status, attempts, status_code, error, next_attempt_at, created_at, updated_at"
+
+func (r *Repository) SaveHook(ctx context.Context, h *webhook.Hook) error {
+	query := "INSERT INTO webhooks(" + hookColumns + ") VALUES(?,?,?,?,?,?,?,?)"
+	_, err := r.conn.ExecContext(ctx, query,
+		h.ID,
+		h.UserID,
+		h.URL,
+		h.Secret,
+		joinEvents(h.Events),
+		h.Global,
+		h.Active,
+		h.CreatedAt,
+	)
+	return r.checkError(err)
+}
+
+func (r *Repository) FindHook(ctx context.Context, id string) (*webhook.Hook, error) {
+	query := "SELECT " + hookColumns + " FROM webhooks WHERE hookId = ?"
+	hooks, err := r.doQueryHooks(ctx, query, id)
+	if err != nil {
+		return nil, err
+	}
+	if len(hooks) == 0 {
+		return nil, webhook.ErrNotFound
+	}
+	return hooks[0], nil
+}
+
+func (r *Repository) FindHooksByUser(ctx context.Context, userID string) ([]*webhook.Hook, error) {
+	query := "SELECT " + hookColumns + " FROM webhooks WHERE userId = ? ORDER BY created_at"
+	return r.doQueryHooks(ctx, query, userID)
+}
+
+func (r *Repository) FindHooksForEvent(ctx context.Context, e webhook.Event, userID string) ([]*webhook.Hook, error) {
+	query := `SELECT ` + hookColumns + `
+						FROM webhooks
+						WHERE active = 1
+						AND (global = 1 OR userId = ?)
+						AND FIND_IN_SET(?, events) > 0
+						ORDER BY created_at`
+	return r.doQueryHooks(ctx, query, userID, e)
+}
+
+func (r *Repository) DeleteHook(ctx context.Context, id string) error {
+	query := "DELETE FROM webhooks WHERE hookId = ?"
+	res, err := r.conn.ExecContext(ctx, query, id)
+	if err != nil {
+		return r.checkError(err)
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return r.checkError(err)
+	}
+	if affected == 0 {
+		return webhook.ErrNotFound
+	}
+	return nil
+}
+
+func (r *Repository) SaveDelivery(ctx context.Context, d *webhook.Delivery) error {
+	query := "INSERT INTO webhook_deliveries(" + deliveryColumns + ") VALUES(?,?,?,?,?,?,?,?,?,?,?)"
+	_, err := r.conn.ExecContext(ctx, query,
+		d.ID,
+		d.HookID,
+		d.Event,
+		string(d.Payload),
+		d.Status,
+		d.Attempts,
+		d.StatusCode,
+		d.Error,
+		d.NextAttemptAt,
+		d.CreatedAt,
+		d.UpdatedAt,
+	)
+	return r.checkError(err)
+}
+
+func (r *Repository) UpdateDelivery(ctx context.Context, d *webhook.Delivery) error {
+	query := `UPDATE webhook_deliveries
+						SET status = ?, attempts = ?, status_code = ?, error = ?, next_attempt_at = ?, updated_at = ?
+						WHERE deliveryId = ?`
+	res, err := r.conn.ExecContext(ctx, query,
+		d.Status,
+		d.Attempts,
+		d.StatusCode,
+		d.Error,
+		d.NextAttemptAt,
+		d.UpdatedAt,
+		d.ID,
+	)
+	if err != nil {
+		return r.checkError(err)
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return r.checkError(err)
+	}
+	if affected == 0 {
+		return webhook.ErrNotFound
+	}
+	return nil
+}
+
+func (r *Repository) FindDelivery(ctx context.Context, id string) (*webhook.Delivery, error) {
+	query := "SELECT " + deliveryColumns + " FROM webhook_deliveries WHERE deliveryId = ?"
+	deliveries, err := r.doQueryDeliveries(ctx, query, id)
+	if err != nil {
+		return nil, err
+	}
+	if len(deliveries) == 0 {
+		return nil, webhook.ErrNotFound
+	}
+	return deliveries[0], nil
+}
+
+func (r *Repository) FindDeliveriesByHook(ctx context.Context, hookID string, offset, num int) ([]*webhook.Delivery, error) {
+	query := `SELECT ` + deliveryColumns + `
+						FROM webhook_deliveries
+						WHERE hookId = ?
+						ORDER BY created_at DESC
+						LIMIT ?
+						OFFSET ?`
+	return r.doQueryDeliveries(ctx, query, hookID, num, offset)
+}
+
+// ClaimDue claims each due delivery with a conditional update so that
+// a delivery picked by several instances at once is only sent by one.
+func (r *Repository) ClaimDue(ctx context.Context, now time.Time, lease time.Duration, num int) ([]*webhook.Delivery, error) {
+	query := `SELECT ` + deliveryColumns + `
+						FROM webhook_deliveries
+						WHERE status = ?
+						AND next_attempt_at <= ?
+						ORDER BY next_attempt_at
+						LIMIT ?`
+	due, err := r.doQueryDeliveries(ctx, query, webhook.StatusPending, now, num)
+	if err != nil {
+		return nil, err
+	}
+
+	claim := `UPDATE webhook_deliveries
+						SET next_attempt_at = ?
+						WHERE deliveryId = ? AND status = ? AND next_attempt_at = ?`
+	leased := now.Add(lease)
+	claimed := make([]*webhook.Delivery, 0, len(due))
+	for _, d := range due {
+		res, err := r.conn.ExecContext(ctx, claim, leased, d.ID, webhook.StatusPending, d.NextAttemptAt)
+		if err != nil {
+			return nil, r.checkError(err)
+		}
+		affected, err := res.RowsAffected()
+		if err != nil {
+			return nil, r.checkError(err)
+		}
+		if affected == 1 {
+			d.NextAttemptAt = &leased
+			claimed = append(claimed, d)
+		}
+	}
+	return claimed, nil
+}
+
+func (r *Repository) checkError(err error) error {
+	switch err {
+	case nil:
+		return nil
+	case sql.ErrNoRows:
+		return webhook.ErrNotFound
+	default:
+		return fmt.Errorf("mysql: unexpected error %w", err)
+	}
+}
+
+func (r *Repository) doQueryHooks(ctx context.Context, query string, args ...interface{}) (hooks []*webhook.Hook, err error) {
+	rows, err := r.conn.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, r.checkError(err)
+	}
+	defer func() {
+		if e := rows.Close(); err == nil && e != nil {
+			err = e
+		}
+	}()
+
+	hooks = make([]*webhook.Hook, 0)
+	for rows.Next() {
+		var h webhook.Hook
+		var events string
+		err = rows.Scan(&h.ID, &h.UserID, &h.URL, &h.Secret, &events, &h.Global, &h.Active, &h.CreatedAt)
+		if err != nil {
+			return nil, r.checkError(err)
+		}
+		h.Events = splitEvents(events)
+		hooks = append(hooks, &h)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, r.checkError(err)
+	}
+	return hooks, nil
+}
+
+func (r *Repository) doQueryDeliveries(ctx context.Context, query string, args ...interface{}) (deliveries []*webhook.Delivery, err error) {
+	rows, err := r.conn.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, r.checkError(err)
+	}
+	defer func() {
+		if e := rows.Close(); err == nil && e != nil {
+			err = e
+		}
+	}()
+
+	deliveries = make([]*webhook.Delivery, 0)
+	for rows.Next() {
+		var d webhook.Delivery
+		var payload string
+		err = rows.Scan(&d.ID, &d.HookID, &d.Event, &payload, &d.Status, &d.Attempts, &d.StatusCode, &d.Error, &d.NextAttemptAt, &d.CreatedAt, &d.UpdatedAt)
+		if err != nil {
+			return nil, r.checkError(err)
+		}
+		d.Payload = []byte(payload)
+		deliveries = append(deliveries, &d)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, r.checkError(err)
+	}
+	return deliveries, nil
+}
+
+func joinEvents(events []webhook.Event) string {
+	s := make([]string, len(events))
+	for i, e := range events {
+		s[i] = string(e)
+	}
+	return strings.Join(s, ",")
+}
+
+func splitEvents(s string) []webhook.Event {
+	if s == "" {
+		return nil
+	}
+	parts := strings.Split(s, ",")
+	events := make([]webhook.Event, len(parts))
+	for i, p := range parts {
+		events[i] = webhook.Event(p)
+	}
+	return events
+}
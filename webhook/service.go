@@ -0,0 +1,28 @@
+package webhook
+
+import "context"
+
+//go:generate mockery --name=Service
+
+type Service interface {
+	Trigger
+	// CreateHook registers h, generating its ID and secret.
+	CreateHook(ctx context.Context, h *Hook) error
+	ListHooks(ctx context.Context, userID string) ([]*Hook, error)
+	// GetHook returns the hook with hookID when it belongs to userID.
+	GetHook(ctx context.Context, userID, hookID string) (*Hook, error)
+	DeleteHook(ctx context.Context, userID, hookID string) error
+	// Deliveries returns the delivery log of a hook of userID.
+	Deliveries(ctx context.Context, userID, hookID string, offset, num int) ([]*Delivery, error)
+	// Redeliver queues the payload of a past delivery again.
+	Redeliver(ctx context.Context, userID, deliveryID string) (*Delivery, error)
+}
+
+//go:generate mockery --name=Trigger
+
+// Trigger queues the deliveries of an event.
+type Trigger interface {
+	// Trigger queues e about the user identified by userID for every
+	// hook subscribed to it. data is sent as the payload data.
+	Trigger(ctx context.Context, e Event, userID string, data interface{}) error
+}
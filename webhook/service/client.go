@@ -0,0 +1,43 @@
+package service
+
+import (
+	"gophr.v2/webhook"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+)
+
+// newClient creates the client the payloads are posted with. It only
+// connects to public addresses, checked once the host is resolved so
+// that a hook can't be rebound to a private address, and it never
+// follows the redirects. The proxies are ignored since they would
+// connect on its behalf.
+func newClient(timeout time.Duration) *http.Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = (&net.Dialer{
+		Timeout: timeout,
+		Control: dialPublicOnly,
+	}).DialContext
+
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: transport,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+// dialPublicOnly refuses to connect to address unless it's public.
+func dialPublicOnly(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	if ip := net.ParseIP(host); ip == nil || !webhook.IsPublicIP(ip) {
+		return webhook.ErrForbiddenAddress
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+package service
+
+import (
+	"context"
+	"gophr.v2/event"
+	"gophr.v2/image"
+	"gophr.v2/user"
+	"gophr.v2/webhook"
+)
+
+// Subscribe triggers the hooks on the domain events they can
+// subscribe to. The deliveries are queued as part of the publication
+// so that the events are published again when queueing fails. The
+// hooks of the purged users are deleted.
+func (s *Service) Subscribe(sub event.Subscriber) {
+	sub.Subscribe(user.EventRegistered, s.handleEvent)
+	sub.Subscribe(image.EventCreated, s.handleEvent)
+	sub.Subscribe(image.EventDeleted, s.handleEvent)
+	sub.Subscribe(user.EventPurged, s.handleEvent)
+}
+
+func (s *Service) handleEvent(ctx context.Context, e event.Event) error {
+	switch e := e.(type) {
+	case *user.Registered:
+		return s.Trigger(ctx, webhook.EventUserRegistered, e.User.UserID, e.User)
+	case *image.Created:
+		return s.Trigger(ctx, webhook.EventImageCreated, e.Image.UserID, e.Image)
+	case *image.Deleted:
+		return s.Trigger(ctx, webhook.EventImageDeleted, e.Image.UserID, e.Image)
+	case *user.Purged:
+		return s.deleteAllForUser(ctx, e.UserID)
+	}
+	return nil
+}
+
+func (s *Service) deleteAllForUser(ctx context.Context, userID string) error {
+	hooks, err := s.repo.FindHooksByUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+	for _, h := range hooks {
+		if err := s.repo.DeleteHook(ctx, h.ID); err != nil && err != webhook.ErrNotFound {
+			return err
+		}
+	}
+	return nil
+}
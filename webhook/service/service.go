@@ -0,0 +1,320 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"gophr.v2/config"
+	"gophr.v2/log"
+	"gophr.v2/util/valueutil"
+	"gophr.v2/webhook"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"time"
+)
+
+// Defaults of the delivery queue.
+const (
+	DefaultMaxAttempts  = 8
+	DefaultBaseBackoff  = 30 * time.Second
+	DefaultMaxBackoff   = 6 * time.Hour
+	DefaultTimeout      = 10 * time.Second
+	DefaultPollInterval = 5 * time.Second
+
+	// batchSize is the number of deliveries sent per poll.
+	batchSize = 20
+	// maxErrorLength is the number of characters of an error kept
+	// in the delivery log.
+	maxErrorLength = 255
+)
+
+var _ webhook.Service = (*Service)(nil)
+
+// Option configures the Service.
+type Option func(s *Service)
+
+// WithClient replaces the HTTP client the payloads are posted with,
+// which only connects to public addresses and follows no redirects.
+func WithClient(client *http.Client) Option {
+	return func(s *Service) {
+		s.client = client
+	}
+}
+
+// WithRetries sets how many times a delivery is attempted and the
+// backoff between the attempts. The backoff doubles after every
+// failed attempt starting from base up to max.
+func WithRetries(maxAttempts int, base, max time.Duration) Option {
+	return func(s *Service) {
+		s.maxAttempts = maxAttempts
+		s.baseBackoff = base
+		s.maxBackoff = max
+	}
+}
+
+// OptionsFromConfig creates the options of the service from conf.
+// Zero values fall back to the defaults.
+func OptionsFromConfig(conf config.Webhook) []Option {
+	timeout := DefaultTimeout
+	if conf.Timeout > 0 {
+		timeout = conf.Timeout
+	}
+	maxAttempts := DefaultMaxAttempts
+	if conf.MaxAttempts > 0 {
+		maxAttempts = conf.MaxAttempts
+	}
+	return []Option{
+		WithClient(newClient(timeout)),
+		WithRetries(maxAttempts, DefaultBaseBackoff, DefaultMaxBackoff),
+	}
+}
+
+func New(repo webhook.Repository, opts ...Option) *Service {
+	s := &Service{
+		repo:        repo,
+		client:      newClient(DefaultTimeout),
+		maxAttempts: DefaultMaxAttempts,
+		baseBackoff: DefaultBaseBackoff,
+		maxBackoff:  DefaultMaxBackoff,
+		now:         time.Now,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+type Service struct {
+	repo        webhook.Repository
+	client      *http.Client
+	maxAttempts int
+	baseBackoff time.Duration
+	maxBackoff  time.Duration
+	now         func() time.Time
+}
+
+func (s *Service) CreateHook(ctx context.Context, h *webhook.Hook) error {
+	if err := h.Validate(); err != nil {
+		return err
+	}
+	h.ID = webhook.GenerateID()
+	h.Secret = webhook.GenerateSecret()
+	h.Active = true
+	h.CreatedAt = valueutil.TimePointer(s.now().UTC())
+	return s.repo.SaveHook(ctx, h)
+}
+
+func (s *Service) ListHooks(ctx context.Context, userID string) ([]*webhook.Hook, error) {
+	return s.repo.FindHooksByUser(ctx, userID)
+}
+
+func (s *Service) GetHook(ctx context.Context, userID, hookID string) (*webhook.Hook, error) {
+	h, err := s.repo.FindHook(ctx, hookID)
+	if err != nil {
+		return nil, err
+	}
+	// Hooks of others are hidden rather than forbidden
+	if h.UserID != userID {
+		return nil, webhook.ErrNotFound
+	}
+	return h, nil
+}
+
+func (s *Service) DeleteHook(ctx context.Context, userID, hookID string) error {
+	if _, err := s.GetHook(ctx, userID, hookID); err != nil {
+		return err
+	}
+	return s.repo.DeleteHook(ctx, hookID)
+}
+
+func (s *Service) Deliveries(ctx context.Context, userID, hookID string, offset, num int) ([]*webhook.Delivery, error) {
+	if _, err := s.GetHook(ctx, userID, hookID); err != nil {
+		return nil, err
+	}
+	return s.repo.FindDeliveriesByHook(ctx, hookID, offset, num)
+}
+
+func (s *Service) Redeliver(ctx context.Context, userID, deliveryID string) (*webhook.Delivery, error) {
+	past, err := s.repo.FindDelivery(ctx, deliveryID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.GetHook(ctx, userID, past.HookID); err != nil {
+		return nil, err
+	}
+
+	d := s.newDelivery(past.HookID, past.Event, past.Payload)
+	if err := s.repo.SaveDelivery(ctx, d); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+func (s *Service) Trigger(ctx context.Context, e webhook.Event, userID string, data interface{}) error {
+	hooks, err := s.repo.FindHooksForEvent(ctx, e, userID)
+	if err != nil || len(hooks) == 0 {
+		return err
+	}
+
+	payload, err := json.Marshal(&webhook.Payload{
+		ID:        webhook.GenerateID(),
+		Event:     e,
+		CreatedAt: s.now().UTC(),
+		Data:      data,
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, h := range hooks {
+		if err := s.repo.SaveDelivery(ctx, s.newDelivery(h.ID, e, payload)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Service) newDelivery(hookID string, e webhook.Event, payload []byte) *webhook.Delivery {
+	now := valueutil.TimePointer(s.now().UTC())
+	return &webhook.Delivery{
+		ID:            webhook.GenerateID(),
+		HookID:        hookID,
+		Event:         e,
+		Payload:       payload,
+		Status:        webhook.StatusPending,
+		NextAttemptAt: now,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+}
+
+// Run sends the due deliveries every interval until ctx is done.
+func (s *Service) Run(ctx context.Context, interval time.Duration) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if _, err := s.DeliverDue(ctx); err != nil {
+			log.FromContext(ctx).WithError(err).Error("webhook: failed delivering")
+		}
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// DeliverDue sends the deliveries that are due and returns how many
+// were attempted and recorded. A delivery that can't be recorded is logged and left
+// to be claimed again once its lease is over, the rest of the batch is
+// still sent.
+func (s *Service) DeliverDue(ctx context.Context) (int, error) {
+	// A delivery is leased for as long as sending it may take
+	lease := 2 * s.client.Timeout
+	if lease <= 0 {
+		lease = 2 * DefaultTimeout
+	}
+
+	deliveries, err := s.repo.ClaimDue(ctx, s.now().UTC(), lease, batchSize)
+	if err != nil {
+		return 0, err
+	}
+	delivered := 0
+	for _, d := range deliveries {
+		if err := s.deliver(ctx, d); err != nil {
+			log.FromContext(ctx).WithError(err).WithField("deliveryId", d.ID).Error("webhook: failed delivering")
+			continue
+		}
+		delivered++
+	}
+	return delivered, nil
+}
+
+// deliver posts d to its hook and records the outcome.
+func (s *Service) deliver(ctx context.Context, d *webhook.Delivery) error {
+	d.Attempts++
+	h, err := s.repo.FindHook(ctx, d.HookID)
+	switch err {
+	case nil:
+		d.StatusCode, err = s.send(ctx, h, d)
+	case webhook.ErrNotFound:
+		// The hook was deleted meanwhile, there's nowhere to retry
+		d.Attempts = s.maxAttempts
+	default:
+		return err
+	}
+
+	now := s.now().UTC()
+	d.UpdatedAt = &now
+	d.Error = ""
+	if err != nil {
+		d.Error = truncate(err.Error(), maxErrorLength)
+	}
+
+	switch {
+	case err == nil:
+		d.Status = webhook.StatusSucceeded
+		d.NextAttemptAt = nil
+	case d.Attempts >= s.maxAttempts:
+		d.Status = webhook.StatusFailed
+		d.NextAttemptAt = nil
+	default:
+		d.NextAttemptAt = valueutil.TimePointer(now.Add(s.Backoff(d.Attempts)))
+	}
+	return s.repo.UpdateDelivery(ctx, d)
+}
+
+func (s *Service) send(ctx context.Context, h *webhook.Hook, d *webhook.Delivery) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, h.URL, bytes.NewReader(d.Payload))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "Gophr-Webhook")
+	req.Header.Set(webhook.EventHeader, string(d.Event))
+	req.Header.Set(webhook.DeliveryHeader, d.ID)
+	req.Header.Set(webhook.SignatureHeader, webhook.Sign(h.Secret, d.Payload))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		// The errors of the transport stay in the logs of the server
+		log.FromContext(ctx).WithError(err).WithField("hookId", h.ID).Warn("webhook: failed reaching hook")
+		if errors.Is(err, webhook.ErrForbiddenAddress) {
+			return 0, webhook.ErrForbiddenAddress
+		}
+		return 0, webhook.ErrUnreachable
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	// Drain the body so that the connection can be reused
+	_, _ = io.Copy(ioutil.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("webhook: unexpected response %s", resp.Status)
+	}
+	return resp.StatusCode, nil
+}
+
+// Backoff returns how long to wait before the next attempt after the
+// given number of failed attempts.
+func (s *Service) Backoff(attempts int) time.Duration {
+	backoff := s.baseBackoff
+	for i := 1; i < attempts; i++ {
+		backoff *= 2
+		if backoff >= s.maxBackoff {
+			return s.maxBackoff
+		}
+	}
+	return backoff
+}
+
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n]
+}
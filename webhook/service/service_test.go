@@ -0,0 +1,298 @@
+//+build unit
+
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gophr.v2/event/bus"
+	"gophr.v2/image"
+	"gophr.v2/webhook"
+	"gophr.v2/webhook/mocks"
+	"gophr.v2/webhook/repository/memory"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+var dummyCtx = context.Background()
+
+// receiver records the requests of a hook endpoint answering with
+// the status it is set to.
+type receiver struct {
+	mu       sync.Mutex
+	status   int
+	requests []*http.Request
+	bodies   [][]byte
+}
+
+func (r *receiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	body, _ := ioutil.ReadAll(req.Body)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.requests = append(r.requests, req)
+	r.bodies = append(r.bodies, body)
+	w.WriteHeader(r.status)
+}
+
+// dialTo makes client connect to srv whatever the host of the URL, as
+// if srv was a hook on a public host.
+func dialTo(client *http.Client, srv *httptest.Server) *http.Client {
+	client.Transport.(*http.Transport).DialContext = func(ctx context.Context, network, _ string) (net.Conn, error) {
+		return (&net.Dialer{}).DialContext(ctx, network, srv.Listener.Addr().String())
+	}
+	return client
+}
+
+func newTestService(now *time.Time) *Service {
+	svc := New(memory.New(), WithRetries(3, time.Minute, 3*time.Minute))
+	svc.now = func() time.Time { return *now }
+	return svc
+}
+
+func TestService_Trigger(t *testing.T) {
+	now := time.Now()
+	svc := newTestService(&now)
+
+	mine := &webhook.Hook{UserID: "luffy", URL: "https://example.com/luffy", Events: []webhook.Event{webhook.EventImageCreated}}
+	global := &webhook.Hook{UserID: "admin", URL: "https://example.com/all", Events: []webhook.Event{webhook.EventImageCreated}, Global: true}
+	other := &webhook.Hook{UserID: "zoro", URL: "https://example.com/zoro", Events: []webhook.Event{webhook.EventImageCreated}}
+	for _, h := range []*webhook.Hook{mine, global, other} {
+		require.NoError(t, svc.CreateHook(dummyCtx, h))
+		assert.NotEmpty(t, h.Secret)
+	}
+
+	require.NoError(t, svc.Trigger(dummyCtx, webhook.EventImageCreated, "luffy", map[string]string{"imageId": "img1"}))
+
+	for hook, want := range map[*webhook.Hook]int{mine: 1, global: 1, other: 0} {
+		deliveries, err := svc.Deliveries(dummyCtx, hook.UserID, hook.ID, 0, 10)
+		require.NoError(t, err)
+		assert.Len(t, deliveries, want, hook.URL)
+	}
+}
+
+func TestService_Subscribe(t *testing.T) {
+	now := time.Now()
+	svc := newTestService(&now)
+	events := bus.New()
+	defer events.Close()
+	svc.Subscribe(events)
+
+	hook := &webhook.Hook{UserID: "luffy", URL: "https://example.com/luffy", Events: []webhook.Event{webhook.EventImageDeleted}}
+	require.NoError(t, svc.CreateHook(dummyCtx, hook))
+
+	img := &image.Image{ImageID: "img1", UserID: "luffy"}
+	require.NoError(t, events.Publish(dummyCtx, &image.Created{Image: img}, &image.Deleted{Image: img}))
+
+	deliveries, err := svc.Deliveries(dummyCtx, "luffy", hook.ID, 0, 10)
+	require.NoError(t, err)
+	require.Len(t, deliveries, 1)
+	assert.Equal(t, webhook.EventImageDeleted, deliveries[0].Event)
+}
+
+func TestService_DeliverDue(t *testing.T) {
+	now := time.Now()
+	svc := newTestService(&now)
+	recv := &receiver{status: http.StatusOK}
+	srv := httptest.NewServer(recv)
+	defer srv.Close()
+	svc.client = dialTo(svc.client, srv)
+
+	hook := &webhook.Hook{UserID: "luffy", URL: "http://hooks.example.com/luffy", Events: []webhook.Event{webhook.EventImageCreated}}
+	require.NoError(t, svc.CreateHook(dummyCtx, hook))
+
+	trigger := func(t *testing.T) *webhook.Delivery {
+		require.NoError(t, svc.Trigger(dummyCtx, webhook.EventImageCreated, "luffy", map[string]string{"imageId": "img1"}))
+		deliveries, err := svc.Deliveries(dummyCtx, "luffy", hook.ID, 0, 1)
+		require.NoError(t, err)
+		require.Len(t, deliveries, 1)
+		return deliveries[0]
+	}
+
+	find := func(t *testing.T, id string) *webhook.Delivery {
+		d, err := svc.repo.FindDelivery(dummyCtx, id)
+		require.NoError(t, err)
+		return d
+	}
+
+	t.Run("Signed Payload", func(t *testing.T) {
+		d := trigger(t)
+		n, err := svc.DeliverDue(dummyCtx)
+		require.NoError(t, err)
+		assert.Equal(t, 1, n)
+
+		require.Len(t, recv.requests, 1)
+		req, body := recv.requests[0], recv.bodies[0]
+		assert.Equal(t, "image.created", req.Header.Get(webhook.EventHeader))
+		assert.Equal(t, d.ID, req.Header.Get(webhook.DeliveryHeader))
+		assert.True(t, webhook.Verify(hook.Secret, body, req.Header.Get(webhook.SignatureHeader)))
+
+		var payload map[string]interface{}
+		require.NoError(t, json.Unmarshal(body, &payload))
+		assert.Equal(t, "image.created", payload["event"])
+		assert.Equal(t, map[string]interface{}{"imageId": "img1"}, payload["data"])
+
+		d = find(t, d.ID)
+		assert.Equal(t, webhook.StatusSucceeded, d.Status)
+		assert.Equal(t, http.StatusOK, d.StatusCode)
+		assert.Equal(t, 1, d.Attempts)
+	})
+
+	t.Run("Retries With Backoff", func(t *testing.T) {
+		recv.status = http.StatusInternalServerError
+		d := trigger(t)
+
+		_, err := svc.DeliverDue(dummyCtx)
+		require.NoError(t, err)
+		d = find(t, d.ID)
+		assert.Equal(t, webhook.StatusPending, d.Status)
+		assert.Equal(t, http.StatusInternalServerError, d.StatusCode)
+		assert.NotEmpty(t, d.Error)
+		assert.Equal(t, now.Add(time.Minute).UTC(), *d.NextAttemptAt)
+
+		// Not due yet
+		n, err := svc.DeliverDue(dummyCtx)
+		require.NoError(t, err)
+		assert.Equal(t, 0, n)
+
+		now = now.Add(time.Minute)
+		_, err = svc.DeliverDue(dummyCtx)
+		require.NoError(t, err)
+		d = find(t, d.ID)
+		assert.Equal(t, 2, d.Attempts)
+		assert.Equal(t, now.Add(2*time.Minute).UTC(), *d.NextAttemptAt)
+
+		now = now.Add(2 * time.Minute)
+		_, err = svc.DeliverDue(dummyCtx)
+		require.NoError(t, err)
+		d = find(t, d.ID)
+		assert.Equal(t, webhook.StatusFailed, d.Status)
+		assert.Equal(t, 3, d.Attempts)
+		assert.Nil(t, d.NextAttemptAt)
+
+		t.Run("Redeliver", func(t *testing.T) {
+			recv.status = http.StatusNoContent
+			redelivery, err := svc.Redeliver(dummyCtx, "luffy", d.ID)
+			require.NoError(t, err)
+			assert.NotEqual(t, d.ID, redelivery.ID)
+			assert.Equal(t, d.Payload, redelivery.Payload)
+
+			_, err = svc.DeliverDue(dummyCtx)
+			require.NoError(t, err)
+			assert.Equal(t, webhook.StatusSucceeded, find(t, redelivery.ID).Status)
+		})
+
+		t.Run("Redeliver Someone Else's", func(t *testing.T) {
+			_, err := svc.Redeliver(dummyCtx, "zoro", d.ID)
+			assert.Equal(t, webhook.ErrNotFound, err)
+		})
+	})
+}
+
+func TestService_DeliverDue_Failing(t *testing.T) {
+	failing := &webhook.Delivery{ID: "d1", HookID: "h1"}
+	orphan := &webhook.Delivery{ID: "d2", HookID: "h2"}
+	repo := new(mocks.Repository)
+	repo.On("ClaimDue", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+		Return([]*webhook.Delivery{failing, orphan}, nil).Once()
+	repo.On("FindHook", mock.Anything, "h1").Return(nil, errors.New("connection lost")).Once()
+	repo.On("FindHook", mock.Anything, "h2").Return(nil, webhook.ErrNotFound).Once()
+	repo.On("UpdateDelivery", mock.Anything, orphan).Return(nil).Once()
+
+	// The rest of the batch is delivered despite the failure
+	svc := New(repo)
+	n, err := svc.DeliverDue(dummyCtx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+	assert.Equal(t, webhook.StatusFailed, orphan.Status)
+	repo.AssertExpectations(t)
+}
+
+func TestService_Backoff(t *testing.T) {
+	svc := New(memory.New(), WithRetries(10, time.Second, 10*time.Second))
+	assert.Equal(t, time.Second, svc.Backoff(1))
+	assert.Equal(t, 2*time.Second, svc.Backoff(2))
+	assert.Equal(t, 8*time.Second, svc.Backoff(4))
+	assert.Equal(t, 10*time.Second, svc.Backoff(5))
+}
+
+func TestService_DeleteHook(t *testing.T) {
+	now := time.Now()
+	svc := newTestService(&now)
+	hook := &webhook.Hook{UserID: "luffy", URL: "https://example.com/hook", Events: []webhook.Event{webhook.EventUserRegistered}}
+	require.NoError(t, svc.CreateHook(dummyCtx, hook))
+
+	assert.Equal(t, webhook.ErrNotFound, svc.DeleteHook(dummyCtx, "zoro", hook.ID))
+	assert.NoError(t, svc.DeleteHook(dummyCtx, "luffy", hook.ID))
+
+	hooks, err := svc.ListHooks(dummyCtx, "luffy")
+	require.NoError(t, err)
+	assert.Empty(t, hooks)
+}
+
+func TestService_DeliverDue_Forbidden(t *testing.T) {
+	now := time.Now()
+	deliver := func(t *testing.T, svc *Service, hook *webhook.Hook) *webhook.Delivery {
+		t.Helper()
+		require.NoError(t, svc.Trigger(dummyCtx, webhook.EventImageCreated, "luffy", map[string]string{"imageId": "img1"}))
+		_, err := svc.DeliverDue(dummyCtx)
+		require.NoError(t, err)
+		deliveries, err := svc.Deliveries(dummyCtx, "luffy", hook.ID, 0, 1)
+		require.NoError(t, err)
+		require.Len(t, deliveries, 1)
+		return deliveries[0]
+	}
+
+	t.Run("Private Address", func(t *testing.T) {
+		recv := &receiver{status: http.StatusOK}
+		srv := httptest.NewServer(recv)
+		defer srv.Close()
+		svc := newTestService(&now)
+
+		// Like a host name resolving to the loopback address
+		hook := &webhook.Hook{ID: "hook1", UserID: "luffy", URL: srv.URL, Events: []webhook.Event{webhook.EventImageCreated}, Active: true}
+		require.NoError(t, svc.repo.SaveHook(dummyCtx, hook))
+
+		d := deliver(t, svc, hook)
+		assert.Empty(t, recv.requests)
+		assert.Equal(t, 0, d.StatusCode)
+		assert.Equal(t, webhook.ErrForbiddenAddress.Error(), d.Error)
+	})
+
+	t.Run("Redirect Not Followed", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			http.Redirect(w, r, "http://169.254.169.254/latest/meta-data/", http.StatusFound)
+		}))
+		defer srv.Close()
+		svc := newTestService(&now)
+		svc.client = dialTo(svc.client, srv)
+
+		hook := &webhook.Hook{UserID: "luffy", URL: "http://hooks.example.com/luffy", Events: []webhook.Event{webhook.EventImageCreated}}
+		require.NoError(t, svc.CreateHook(dummyCtx, hook))
+
+		d := deliver(t, svc, hook)
+		assert.Equal(t, http.StatusFound, d.StatusCode)
+		assert.Equal(t, webhook.StatusPending, d.Status)
+	})
+
+	t.Run("Transport Error Hidden", func(t *testing.T) {
+		srv := httptest.NewServer(&receiver{status: http.StatusOK})
+		svc := newTestService(&now)
+		svc.client = dialTo(svc.client, srv)
+		srv.Close()
+
+		hook := &webhook.Hook{UserID: "luffy", URL: "http://hooks.example.com/luffy", Events: []webhook.Event{webhook.EventImageCreated}}
+		require.NoError(t, svc.CreateHook(dummyCtx, hook))
+
+		d := deliver(t, svc, hook)
+		assert.Equal(t, webhook.ErrUnreachable.Error(), d.Error)
+	})
+}
@@ -0,0 +1,31 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+// Headers sent along with every payload.
+const (
+	SignatureHeader = "X-Gophr-Signature"
+	EventHeader     = "X-Gophr-Event"
+	DeliveryHeader  = "X-Gophr-Delivery"
+)
+
+const signaturePrefix = "sha256="
+
+// Sign returns the signature of payload sent in the SignatureHeader.
+// It's the hex encoded HMAC-SHA256 of the payload keyed with the
+// secret of the hook.
+func Sign(secret string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+	return signaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify tells whether signature is the valid signature of payload.
+// Receivers written in Go can use it to authenticate the requests.
+func Verify(secret string, payload []byte, signature string) bool {
+	return hmac.Equal([]byte(Sign(secret, payload)), []byte(signature))
+}
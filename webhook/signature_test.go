@@ -0,0 +1,56 @@
+//+build unit
+
+package webhook
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestSign(t *testing.T) {
+	payload := []byte(`{"event":"image.created"}`)
+	signature := Sign("s3cr3t", payload)
+
+	assert.Equal(t, "sha256=", signature[:7])
+	assert.True(t, Verify("s3cr3t", payload, signature))
+	assert.False(t, Verify("other", payload, signature))
+	assert.False(t, Verify("s3cr3t", []byte(`{"event":"image.deleted"}`), signature))
+}
+
+func TestHook_Validate(t *testing.T) {
+	tests := map[string]struct {
+		hook *Hook
+		want error
+	}{
+		"Valid":         {hook: &Hook{URL: "https://example.com/hook", Events: []Event{EventImageCreated}}, want: nil},
+		"Relative URL":  {hook: &Hook{URL: "/hook", Events: []Event{EventImageCreated}}, want: ErrInvalidURL},
+		"Other Scheme":  {hook: &Hook{URL: "ftp://example.com", Events: []Event{EventImageCreated}}, want: ErrInvalidURL},
+		"No Events":     {hook: &Hook{URL: "https://example.com/hook"}, want: ErrNoEvents},
+		"Loopback":      {hook: &Hook{URL: "http://127.0.0.1:8080/hook", Events: []Event{EventImageCreated}}, want: ErrForbiddenAddress},
+		"Localhost":     {hook: &Hook{URL: "http://localhost/hook", Events: []Event{EventImageCreated}}, want: ErrForbiddenAddress},
+		"Link Local":    {hook: &Hook{URL: "http://169.254.169.254/latest", Events: []Event{EventImageCreated}}, want: ErrForbiddenAddress},
+		"Private":       {hook: &Hook{URL: "https://10.1.2.3/hook", Events: []Event{EventImageCreated}}, want: ErrForbiddenAddress},
+		"IPv6 Loopback": {hook: &Hook{URL: "http://[::1]/hook", Events: []Event{EventImageCreated}}, want: ErrForbiddenAddress},
+		"Unspecified":   {hook: &Hook{URL: "http://0.0.0.0/hook", Events: []Event{EventImageCreated}}, want: ErrForbiddenAddress},
+		"Unknown Event": {hook: &Hook{URL: "https://example.com/hook", Events: []Event{"image.liked"}}, want: ErrUnknownEvent},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.hook.Validate())
+		})
+	}
+}
+
+func TestHook_Receives(t *testing.T) {
+	hook := &Hook{UserID: "luffy", Events: []Event{EventImageCreated}, Active: true}
+	assert.True(t, hook.Receives(EventImageCreated, "luffy"))
+	assert.False(t, hook.Receives(EventImageCreated, "zoro"))
+	assert.False(t, hook.Receives(EventImageDeleted, "luffy"))
+
+	hook.Global = true
+	assert.True(t, hook.Receives(EventImageCreated, "zoro"))
+
+	hook.Active = false
+	assert.False(t, hook.Receives(EventImageCreated, "luffy"))
+}
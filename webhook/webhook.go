@@ -0,0 +1,174 @@
+package webhook
+
+import (
+	"encoding/json"
+	"gophr.v2/util/randutil"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Event is a lifecycle event the hooks can subscribe to.
+type Event string
+
+const (
+	EventImageCreated   Event = "image.created"
+	EventImageDeleted   Event = "image.deleted"
+	EventUserRegistered Event = "user.registered"
+)
+
+// Events lists every event the hooks can subscribe to.
+var Events = []Event{EventImageCreated, EventImageDeleted, EventUserRegistered}
+
+func (e Event) IsValid() bool {
+	for _, event := range Events {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+// Hook is an endpoint receiving the events it subscribed to. The hooks
+// of a user only receive the events about that user, while the Global
+// ones registered by admins receive the events about everyone.
+type Hook struct {
+	ID        string     `json:"id,omitempty"`
+	UserID    string     `json:"userId,omitempty"`
+	URL       string     `json:"url,omitempty"`
+	Secret    string     `json:"-"`
+	Events    []Event    `json:"events,omitempty"`
+	Global    bool       `json:"global,omitempty"`
+	Active    bool       `json:"active"`
+	CreatedAt *time.Time `json:"createdAt,omitempty"`
+}
+
+// Subscribed tells whether h wants to receive e.
+func (h *Hook) Subscribed(e Event) bool {
+	for _, event := range h.Events {
+		if event == e {
+			return true
+		}
+	}
+	return false
+}
+
+// Receives tells whether h must be delivered e about the user
+// identified by userID.
+func (h *Hook) Receives(e Event, userID string) bool {
+	return h.Active && h.Subscribed(e) && (h.Global || h.UserID == userID)
+}
+
+// Validate checks h before it's registered. The hooks on a local
+// or private address are rejected right away, the host names are
+// checked once resolved when delivering.
+func (h *Hook) Validate() error {
+	u, err := url.Parse(h.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return ErrInvalidURL
+	}
+	host := strings.ToLower(u.Hostname())
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return ErrForbiddenAddress
+	}
+	if ip := net.ParseIP(host); ip != nil && !IsPublicIP(ip) {
+		return ErrForbiddenAddress
+	}
+	if len(h.Events) == 0 {
+		return ErrNoEvents
+	}
+	for _, e := range h.Events {
+		if !e.IsValid() {
+			return ErrUnknownEvent
+		}
+	}
+	return nil
+}
+
+// nonPublicNetworks are the networks the hooks can't be delivered to
+// besides the loopback, link-local, multicast and unspecified ones.
+var nonPublicNetworks = parseCIDRs(
+	"0.0.0.0/8",
+	"10.0.0.0/8",
+	"100.64.0.0/10",
+	"172.16.0.0/12",
+	"192.168.0.0/16",
+	"fc00::/7",
+)
+
+func parseCIDRs(cidrs ...string) []*net.IPNet {
+	networks := make([]*net.IPNet, 0, len(cidrs))
+	for _, cidr := range cidrs {
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			panic(err)
+		}
+		networks = append(networks, network)
+	}
+	return networks
+}
+
+// IsPublicIP tells whether the hooks can be delivered to ip, which
+// must be on neither a loopback, private, link-local, multicast nor
+// unspecified address.
+func IsPublicIP(ip net.IP) bool {
+	if ip.IsLoopback() || ip.IsUnspecified() || ip.IsLinkLocalUnicast() ||
+		ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() || ip.IsMulticast() {
+		return false
+	}
+	for _, network := range nonPublicNetworks {
+		if network.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
+
+// Status is the state of a delivery.
+type Status string
+
+const (
+	StatusPending   Status = "pending"
+	StatusSucceeded Status = "succeeded"
+	StatusFailed    Status = "failed"
+)
+
+// Delivery is an event queued for a hook along with the outcome
+// of its last attempt.
+type Delivery struct {
+	ID      string          `json:"id,omitempty"`
+	HookID  string          `json:"hookId,omitempty"`
+	Event   Event           `json:"event,omitempty"`
+	Payload json.RawMessage `json:"payload,omitempty"`
+	Status  Status          `json:"status,omitempty"`
+	// Attempts is the number of times the delivery was tried.
+	Attempts int `json:"attempts"`
+	// StatusCode is the response code of the last attempt. It's zero
+	// when the hook could not be reached.
+	StatusCode    int        `json:"statusCode,omitempty"`
+	Error         string     `json:"error,omitempty"`
+	NextAttemptAt *time.Time `json:"nextAttemptAt,omitempty"`
+	CreatedAt     *time.Time `json:"createdAt,omitempty"`
+	UpdatedAt     *time.Time `json:"updatedAt,omitempty"`
+}
+
+// Payload is the JSON body posted to the hooks.
+type Payload struct {
+	// ID identifies the event. It's the same for every hook and
+	// when the delivery is redelivered.
+	ID        string      `json:"id"`
+	Event     Event       `json:"event"`
+	CreatedAt time.Time   `json:"createdAt"`
+	Data      interface{} `json:"data"`
+}
+
+func GenerateID() string {
+	return randutil.GenerateID("webhook")
+}
+
+// GenerateSecret generates the key the payloads of a hook are
+// signed with.
+func GenerateSecret() string {
+	return randutil.GenerateToken(32)
+}